@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// checkAccessOptions holds the flags for the check-access command
+type checkAccessOptions struct {
+	*Options
+	allNamespaces bool
+}
+
+func newCheckAccessCommand(opts *Options) *cobra.Command {
+	accessOpts := &checkAccessOptions{Options: opts}
+
+	accessCmd := &cobra.Command{
+		Use:   "check-access",
+		Short: "Check which permissions pod-doctor has",
+		Long: `Check, with SelfSubjectAccessReviews, every permission pod-doctor uses
+and print which are allowed or denied, with what stops working without each.
+
+On restricted clusters, analyzers skip checks they are not allowed to make
+(node health, logs, events); run this first to see what diagnoses will miss.
+
+Exit codes: 0 all allowed, 1 some optional permissions denied, 2 permissions
+needed to diagnose pods at all are denied, 3 the check itself failed.
+
+Examples:
+  # What can I diagnose in the production namespace?
+  pod-doctor check-access -n production
+
+  # Check cluster-wide access, as needed by scan -A
+  pod-doctor check-access -A`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCheckAccess(cmd, accessOpts)
+		},
+	}
+
+	accessCmd.Flags().BoolVarP(&accessOpts.allNamespaces, "all-namespaces", "A", false, "check access across all namespaces")
+
+	return accessCmd
+}
+
+func runCheckAccess(cmd *cobra.Command, opts *checkAccessOptions) error {
+	if opts.OutputFormat == "sarif" {
+		return fmt.Errorf("check-access supports console, json and yaml output only")
+	}
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	namespace := opts.Namespace
+	scope := "namespace " + namespace
+	if opts.allNamespaces {
+		namespace = metav1.NamespaceAll
+		scope = "all namespaces"
+	}
+	results, err := client.CheckAccess(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := printStructured(cmd, opts.OutputFormat, results, func() {
+		output.PrintAccessMatrix(cmd.OutOrStdout(), results, scope)
+	}); err != nil {
+		return err
+	}
+
+	code := ExitHealthy
+	for _, r := range results {
+		switch {
+		case !r.Allowed && r.Required:
+			code = ExitCritical
+		case !r.Allowed && code == ExitHealthy:
+			code = ExitWarnings
+		}
+	}
+	if code != ExitHealthy {
+		return &exitStatus{code: code}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultAckDuration is how long an acknowledgement lasts unless --for says
+// otherwise
+const defaultAckDuration = 24 * time.Hour
+
+func newAckCommand(opts *Options) *cobra.Command {
+	ackCmd := &cobra.Command{
+		Use:   "ack",
+		Short: "Acknowledge pod issues for the whole team",
+		Long: `Acknowledge an issue of a pod, so it is left out of diagnoses until the
+acknowledgement expires, e.g. while a fix is rolled out.
+
+Acknowledgements are stored in the ` + kubernetes.AcknowledgementsConfigMap + ` ConfigMap of the pod's
+namespace, so every pod-doctor run against the cluster (the CLI, the TUI,
+serve and CI scans) silences the same issues. Silenced issues are listed
+under "Acknowledged" in the diagnosis, and -o json records them in
+acknowledged.
+
+An issue is named by its ID, such as PD-CRASHLOOP-001, or part of its title.
+
+Examples:
+  # Silence a known crash loop for a day
+  pod-doctor ack add web-7d9f8 PD-CRASHLOOP-001 -n production --reason "fix in #1234"
+
+  # For a week
+  pod-doctor ack add web-7d9f8 "Readiness probe failing" -n production --for 168h
+
+  # What is acknowledged in a namespace?
+  pod-doctor ack list -n production
+
+  # Stop silencing an issue
+  pod-doctor ack remove web-7d9f8 PD-CRASHLOOP-001 -n production`,
+	}
+
+	ackCmd.AddCommand(newAckAddCommand(opts))
+	ackCmd.AddCommand(newAckListCommand(opts))
+	ackCmd.AddCommand(newAckRemoveCommand(opts))
+
+	return ackCmd
+}
+
+func newAckAddCommand(opts *Options) *cobra.Command {
+	var duration time.Duration
+	var reason, by string
+
+	addCmd := &cobra.Command{
+		Use:   "add <pod-name> <issue>",
+		Short: "Acknowledge an issue of a pod until it expires",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if duration <= 0 {
+				return fmt.Errorf("--for must be positive")
+			}
+			now := time.Now()
+			ack := domain.Acknowledgement{
+				Pod:     args[0],
+				Issue:   args[1],
+				By:      by,
+				Reason:  reason,
+				Created: now,
+				Expires: now.Add(duration),
+			}
+
+			err := updateAcknowledgements(cmd, opts, func(acks []domain.Acknowledgement) []domain.Acknowledgement {
+				// Acknowledging again replaces the earlier acknowledgement
+				acks = withoutAcknowledgement(acks, ack.Pod, ack.Issue)
+				return append(acks, ack)
+			})
+			if err != nil {
+				return err
+			}
+			output.PrintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Acknowledged %s on %s/%s until %s",
+				ack.Issue, opts.Namespace, ack.Pod, ack.Expires.Local().Format("2006-01-02 15:04")))
+			return nil
+		},
+	}
+
+	addCmd.Flags().DurationVar(&duration, "for", defaultAckDuration, "how long the acknowledgement lasts")
+	addCmd.Flags().StringVar(&reason, "reason", "", "why the issue is acknowledged, e.g. a ticket")
+	addCmd.Flags().StringVar(&by, "by", os.Getenv("USER"), "who acknowledges the issue")
+
+	return addCmd
+}
+
+func newAckListCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the acknowledgements of a namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.OutputFormat == "sarif" {
+				return fmt.Errorf("ack list supports console, json and yaml output only")
+			}
+			client, err := newClient(cmd, opts)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+			acks, err := client.GetAcknowledgements(ctx, opts.Namespace)
+			if err != nil {
+				return err
+			}
+			if acks == nil {
+				acks = []domain.Acknowledgement{}
+			}
+			return printStructured(cmd, opts.OutputFormat, acks, func() {
+				output.PrintAcknowledgements(cmd.OutOrStdout(), opts.Namespace, acks)
+			})
+		},
+	}
+}
+
+func newAckRemoveCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <pod-name> <issue>",
+		Short: "Remove the acknowledgement of an issue of a pod",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pod, issue := args[0], args[1]
+			found := false
+			err := updateAcknowledgements(cmd, opts, func(acks []domain.Acknowledgement) []domain.Acknowledgement {
+				remaining := withoutAcknowledgement(acks, pod, issue)
+				found = len(remaining) < len(acks)
+				return remaining
+			})
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("%s on %s/%s is not acknowledged", issue, opts.Namespace, pod)
+			}
+			output.PrintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Removed the acknowledgement of %s on %s/%s", issue, opts.Namespace, pod))
+			return nil
+		},
+	}
+}
+
+// updateAcknowledgements changes the acknowledgements of the namespace,
+// dropping the expired ones on the way
+func updateAcknowledgements(cmd *cobra.Command, opts *Options, update func([]domain.Acknowledgement) []domain.Acknowledgement) error {
+	client, err := newClient(cmd, opts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	return client.UpdateAcknowledgements(ctx, opts.Namespace, func(acks []domain.Acknowledgement) []domain.Acknowledgement {
+		var active []domain.Acknowledgement
+		for _, a := range acks {
+			if !a.Expired(now) {
+				active = append(active, a)
+			}
+		}
+		return update(active)
+	})
+}
+
+// withoutAcknowledgement returns acks without those of an issue of a pod
+func withoutAcknowledgement(acks []domain.Acknowledgement, pod, issue string) []domain.Acknowledgement {
+	var remaining []domain.Acknowledgement
+	for _, a := range acks {
+		if a.Pod != pod || a.Issue != issue {
+			remaining = append(remaining, a)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newBenchCommand(opts *Options) *cobra.Command {
+	var pods int
+
+	benchCmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure the throughput and allocations of each analyzer",
+		Long: `Run every analyzer over a set of pods and report how long each takes
+and how much it allocates per pod, slowest first, to spot performance
+regressions as analyzers are added or changed.
+
+By default the pods are synthetic: a mix of healthy, crash looping,
+OOMKilled, image pull, unschedulable, not ready, sidecar and evicted pods.
+No cluster is needed; the analyzers' API lookups find nothing, so the
+numbers measure their own work. With --replay, the pods and API responses
+of a recording made with --record are used instead, repeated up to --pods.
+
+Analyzers run one at a time over all pods, and the flags selecting them
+(--analyzers, --skip-analyzers, --rules) apply as in a diagnosis.
+
+Examples:
+  # Benchmark the analyzers on 500 synthetic pods
+  pod-doctor bench --pods 500
+
+  # On a recorded diagnosis, as JSON for comparison between versions
+  pod-doctor bench --replay ./capture -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if pods <= 0 {
+				return fmt.Errorf("--pods must be positive")
+			}
+			if opts.OutputFormat == "sarif" {
+				return fmt.Errorf("bench supports console, json and yaml output only")
+			}
+
+			var client *kubernetes.Client
+			var fixtures []corev1.Pod
+			source := "synthetic"
+			var err error
+			if opts.ReplayDir != "" {
+				client, err = kubernetes.NewReplayClient(opts.ReplayDir)
+				if err != nil {
+					return err
+				}
+				recorded, err := kubernetes.RecordedPods(opts.ReplayDir)
+				if err != nil {
+					return err
+				}
+				if len(recorded) == 0 {
+					return fmt.Errorf("no pods in recording %s", opts.ReplayDir)
+				}
+				for len(fixtures) < pods {
+					fixtures = append(fixtures, recorded[len(fixtures)%len(recorded)])
+				}
+				source = "recorded"
+			} else {
+				client, err = kubernetes.NewEmptyClient()
+				if err != nil {
+					return err
+				}
+				fixtures = analyzer.SyntheticPods(pods)
+			}
+
+			podAnalyzer, err := newPodAnalyzer(opts, client)
+			if err != nil {
+				return err
+			}
+			if opts.OutputFormat == "console" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Benchmarking analyzers on %d %s pods...\n", len(fixtures), source)
+			}
+			report := podAnalyzer.Bench(cmd.Context(), fixtures, source)
+			return printStructured(cmd, opts.OutputFormat, report, func() {
+				output.PrintBench(cmd.OutOrStdout(), report)
+			})
+		},
+	}
+
+	benchCmd.Flags().IntVar(&pods, "pods", 500, "number of pods to run each analyzer over")
+
+	return benchCmd
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/bundle"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// bundleOptions holds the flags for the bundle command
+type bundleOptions struct {
+	*Options
+	file     string
+	logLines int64
+}
+
+func newBundleCommand(opts *Options) *cobra.Command {
+	bundleOpts := &bundleOptions{Options: opts}
+
+	bundleCmd := &cobra.Command{
+		Use:   "bundle <pod-name>",
+		Short: "Collect a pod's manifest, events, logs, node and diagnosis into an archive",
+		Long: `Collect everything support needs to investigate a pod into one
+timestamped tar.gz, a must-gather for a single pod:
+
+  pod.yaml            the pod, with credentials redacted
+  events.yaml         the pod's events
+  logs/               the last --log-lines lines of each container, and of
+                      its previous run if it restarted
+  node.yaml           the pod's node
+  node-describe.txt   kubectl describe node, if kubectl is on PATH
+  diagnosis.json      the diagnosis, as diagnose -o json prints it
+  errors.txt          the parts that could not be collected, if any
+
+Values of environment variables named like passwords, tokens or keys are
+replaced with REDACTED, and so are credentials found in log lines and
+other values: password and token settings, authorization headers,
+passwords in URLs, AWS access keys, JSON web tokens and private keys.
+Redaction is best effort; review the bundle before sharing it.
+
+Examples:
+  # Bundle a pod for a support ticket
+  pod-doctor bundle web-7d9f8 -n production
+
+  # More log lines, to a chosen file
+  pod-doctor bundle web-7d9f8 -n production --log-lines 5000 -f web.tar.gz`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBundle(cmd, bundleOpts, args[0])
+		},
+	}
+
+	bundleCmd.Flags().StringVarP(&bundleOpts.file, "file", "f", "", "archive to write (default: pod-doctor-<namespace>-<pod>-<timestamp>.tar.gz)")
+	bundleCmd.Flags().Int64Var(&bundleOpts.logLines, "log-lines", 1000, "log lines to collect per container")
+
+	return bundleCmd
+}
+
+func runBundle(cmd *cobra.Command, opts *bundleOptions, podName string) error {
+	if opts.logLines <= 0 {
+		return fmt.Errorf("--log-lines must be positive")
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+	pod, err := client.GetPod(ctx, opts.Namespace, podName)
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	now := time.Now()
+	root := fmt.Sprintf("pod-doctor-%s-%s-%s", pod.Namespace, pod.Name, now.Format("20060102-150405"))
+	file := opts.file
+	if file == "" {
+		file = root + ".tar.gz"
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Collecting pod %s/%s...\n", pod.Namespace, pod.Name)
+
+	b := bundle.New(root, now)
+	collectPod(b, pod)
+	collectEvents(ctx, b, client, pod)
+	collectLogs(ctx, b, client, pod, opts.logLines)
+	if pod.Spec.NodeName != "" {
+		collectNode(ctx, b, client, pod.Spec.NodeName, opts.ReplayDir == "")
+	}
+
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+	collectDiagnosis(ctx, b, podAnalyzer, pod)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	if err := b.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	for _, e := range b.Errors() {
+		output.PrintWarning(out, "Not collected: "+e)
+	}
+	output.PrintSuccess(out, fmt.Sprintf("Wrote %s (%d files); review it for sensitive data before sharing", file, len(b.Files())))
+	return nil
+}
+
+// collectDiagnosis adds the pod's diagnosis, without credentials quoted
+// from its logs and events
+func collectDiagnosis(ctx context.Context, b *bundle.Bundle, podAnalyzer *analyzer.PodAnalyzer, pod *corev1.Pod) {
+	diagnosis, err := podAnalyzer.Diagnose(ctx, pod.Namespace, pod.Name)
+	if err != nil {
+		b.Fail("diagnosis.json", err)
+		return
+	}
+	bundle.RedactDiagnosis(diagnosis)
+	data, err := json.MarshalIndent(diagnosis, "", "  ")
+	if err != nil {
+		b.Fail("diagnosis.json", err)
+		return
+	}
+	b.Add("diagnosis.json", data)
+}
+
+// collectPod adds the pod's manifest, without credentials
+func collectPod(b *bundle.Bundle, pod *corev1.Pod) {
+	redacted := pod.DeepCopy()
+	redacted.APIVersion = "v1"
+	redacted.Kind = "Pod"
+	bundle.RedactPod(redacted)
+	b.AddYAML("pod.yaml", redacted)
+}
+
+// collectEvents adds the events of the pod
+func collectEvents(ctx context.Context, b *bundle.Bundle, client *kubernetes.Client, pod *corev1.Pod) {
+	events, err := client.ListNamespaceEvents(ctx, pod.Namespace)
+	if err != nil {
+		b.Fail("events.yaml", err)
+		return
+	}
+	list := corev1.EventList{}
+	list.APIVersion = "v1"
+	list.Kind = "List"
+	for _, e := range events {
+		if e.InvolvedObject.Kind == "Pod" && e.InvolvedObject.Name == pod.Name {
+			e.ManagedFields = nil
+			e.Message = bundle.RedactLog(e.Message)
+			list.Items = append(list.Items, e)
+		}
+	}
+	b.AddYAML("events.yaml", list)
+}
+
+// collectLogs adds the recent logs of every container, and of its previous
+// run if it restarted, without credentials
+func collectLogs(ctx context.Context, b *bundle.Bundle, client *kubernetes.Client, pod *corev1.Pod, lines int64) {
+	statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Waiting != nil && cs.LastTerminationState.Terminated == nil {
+			// Never ran, so there are no logs to get
+			continue
+		}
+		name := "logs/" + cs.Name + ".log"
+		if logs, err := client.GetPodLogs(ctx, pod.Namespace, pod.Name, cs.Name, lines, false); err != nil {
+			b.Fail(name, err)
+		} else {
+			b.Add(name, []byte(bundle.RedactLog(logs)))
+		}
+
+		if cs.RestartCount == 0 {
+			continue
+		}
+		name = "logs/" + cs.Name + ".previous.log"
+		if logs, err := client.GetPodLogs(ctx, pod.Namespace, pod.Name, cs.Name, lines, true); err != nil {
+			b.Fail(name, err)
+		} else {
+			b.Add(name, []byte(bundle.RedactLog(logs)))
+		}
+	}
+}
+
+// collectNode adds the pod's node, and kubectl describe node when kubectl
+// is available and the client talks to a live cluster
+func collectNode(ctx context.Context, b *bundle.Bundle, client *kubernetes.Client, nodeName string, describe bool) {
+	node, err := client.GetNode(ctx, nodeName)
+	if err != nil {
+		b.Fail("node.yaml", err)
+	} else {
+		node = node.DeepCopy()
+		node.APIVersion = "v1"
+		node.Kind = "Node"
+		node.ManagedFields = nil
+		b.AddYAML("node.yaml", node)
+	}
+
+	if !describe {
+		return
+	}
+	args := client.KubectlCommand("describe", "node", nodeName)
+	kubectl, err := exec.LookPath(args[0])
+	if err != nil {
+		b.Fail("node-describe.txt", fmt.Errorf("kubectl not found on PATH"))
+		return
+	}
+	var stdout, stderr bytes.Buffer
+	describeCmd := exec.CommandContext(ctx, kubectl, args[1:]...)
+	describeCmd.Stdout = &stdout
+	describeCmd.Stderr = &stderr
+	if err := describeCmd.Run(); err != nil {
+		b.Fail("node-describe.txt", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String())))
+		return
+	}
+	b.Add("node-describe.txt", stdout.Bytes())
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newCompareCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "compare <pod-a> [pod-b]",
+		Short: "Show how a failing pod differs from a healthy replica",
+		Long: `Diagnose two pods, usually replicas of one workload, and show what
+differs between them: the revision they were created from, image tags and
+digests, commands, environment variables, resource requests and limits,
+node placement and node condition, container state, restart counts and the
+issues found on only one of them. The pod in worse health is marked as
+failing, and its issues are listed first.
+
+When one replica of a Deployment fails and the others do not, the cause
+is usually in what sets it apart: a node under pressure, an image tag
+that resolved to another digest, or a rollout that left it on an older
+revision.
+
+With one pod, it is compared with the healthiest other replica of its
+workload: a ready one with the fewest restarts.
+
+Examples:
+  # Compare a failing pod with a healthy replica of its workload
+  pod-doctor compare web-7d9f8c6b5-x2k4p -n production
+
+  # Compare two pods
+  pod-doctor compare web-7d9f8c6b5-x2k4p web-7d9f8c6b5-q8n7m -n production
+
+  # As JSON
+  pod-doctor compare web-7d9f8c6b5-x2k4p -o json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompare(cmd, opts, args)
+		},
+	}
+}
+
+func runCompare(cmd *cobra.Command, opts *Options, args []string) error {
+	if opts.OutputFormat == "sarif" {
+		return fmt.Errorf("compare supports console, json and yaml output only")
+	}
+
+	client, err := newClient(cmd, opts)
+	if err != nil {
+		return err
+	}
+	podAnalyzer, err := newPodAnalyzer(opts, client)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+	defer cancel()
+
+	nameA := args[0]
+	nameB := ""
+	if len(args) == 2 {
+		nameB = args[1]
+	} else {
+		nameB, err = podAnalyzer.HealthyReplica(ctx, opts.Namespace, nameA)
+		if err != nil {
+			return err
+		}
+		if opts.OutputFormat == "console" {
+			output.PrintInfo(cmd.OutOrStdout(), fmt.Sprintf("Comparing with replica %s", nameB))
+		}
+	}
+
+	comparison, err := podAnalyzer.Compare(ctx, opts.Namespace, nameA, nameB)
+	if err != nil {
+		return err
+	}
+	return printStructured(cmd, opts.OutputFormat, comparison, func() {
+		output.PrintComparison(cmd.OutOrStdout(), comparison)
+	})
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/config"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clusterScanTimeout bounds the scan of each cluster in a multi-context
+// scan, like the timeout of a single scan
+const clusterScanTimeout = 2 * time.Minute
+
+// clusterSettings are the settings a multi-context scan uses for one kube
+// context: the global flags with that context selected, and the scan flags
+// that may differ per cluster
+type clusterSettings struct {
+	opts          Options
+	allNamespaces bool
+	labelSelector string
+	concurrency   int
+}
+
+// resolveClusterSettings returns the settings of a kube context. Flags set
+// on the command line apply to every cluster; the others come from their
+// environment variable, else the config file with the context's section
+// selected, else their default.
+func resolveClusterSettings(cmd *cobra.Command, opts *scanOptions, file *config.File, name string) (clusterSettings, error) {
+	file.UseContext(name)
+	s := clusterSettings{opts: *opts.Options}
+	s.opts.Context = name
+	s.opts.Suppressions = file.Suppressions()
+
+	var err error
+	value := func(flag string) string {
+		return contextFlag(cmd, opts.Options, file, flag)
+	}
+	invalid := func(flag string, parseErr error) error {
+		return fmt.Errorf("invalid value %q for --%s in context %s: %w", value(flag), flag, name, parseErr)
+	}
+
+	s.opts.Namespace = value("namespace")
+	s.labelSelector = value("selector")
+	if s.allNamespaces, err = strconv.ParseBool(value("all-namespaces")); err != nil {
+		return s, invalid("all-namespaces", err)
+	}
+	if s.concurrency, err = strconv.Atoi(value("concurrency")); err != nil {
+		return s, invalid("concurrency", err)
+	}
+	qps, err := strconv.ParseFloat(value("qps"), 32)
+	if err != nil {
+		return s, invalid("qps", err)
+	}
+	s.opts.QPS = float32(qps)
+	if s.opts.Burst, err = strconv.Atoi(value("burst")); err != nil {
+		return s, invalid("burst", err)
+	}
+	return s, nil
+}
+
+// contextFlag returns the value of a flag in the scan of one kube context:
+// as set on the command line, else from its environment variable or the
+// config file, whose context section is selected, else its default
+func contextFlag(cmd *cobra.Command, opts *Options, file *config.File, name string) string {
+	flag := cmd.Flags().Lookup(name)
+	if opts.commandLine[name] {
+		return flag.Value.String()
+	}
+	if v, ok := os.LookupEnv(config.EnvName(name)); ok {
+		return v
+	}
+	if v, ok := file.Lookup(cmd.Name(), name); ok {
+		return v
+	}
+	return flag.DefValue
+}
+
+// runMultiContextScan scans the clusters of several kube contexts at once.
+// Each cluster gets its own client, concurrency, rate limit and namespace
+// filters, so a slow or sensitive cluster neither holds up the others nor
+// gets the load meant for a bigger one.
+func runMultiContextScan(cmd *cobra.Command, opts *scanOptions) error {
+	switch {
+	case opts.watch:
+		return fmt.Errorf("--contexts cannot be used with --watch")
+	case opts.failFast:
+		return fmt.Errorf("--contexts cannot be used with --fail-fast")
+	case opts.RecordDir != "" || opts.ReplayDir != "":
+		return fmt.Errorf("--contexts cannot be used with --record or --replay")
+	case opts.OutputFormat == "sarif":
+		return fmt.Errorf("--contexts supports console, wide, json and yaml output")
+	case opts.notify.enabled():
+		return fmt.Errorf("--notify-slack and --notify-webhook need --watch")
+	}
+
+	file, err := config.Load(opts.ConfigPath)
+	if err != nil {
+		return err
+	}
+	settings := make([]clusterSettings, len(opts.contexts))
+	for i, name := range opts.contexts {
+		if settings[i], err = resolveClusterSettings(cmd, opts, file, name); err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	console := opts.OutputFormat == "console" || opts.OutputFormat == "wide"
+	if console {
+		fmt.Fprintf(out, "Scanning %d contexts...\n", len(settings))
+	}
+
+	scans := make([]output.ClusterScan, len(settings))
+	var wg sync.WaitGroup
+	for i := range settings {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			scans[i] = scanCluster(cmd, opts, settings[i])
+		}(i)
+	}
+	wg.Wait()
+
+	// The exit code reflects every scanned pod, like a single scan's. A
+	// cluster that could not be scanned, or scanned only in part, is an
+	// error unless something critical was found.
+	var all []*domain.Diagnosis
+	incomplete := false
+	for _, s := range scans {
+		all = append(all, s.Diagnoses...)
+		if s.Error != "" || s.FailedPods > 0 || len(s.SkippedNamespaces) > 0 {
+			incomplete = true
+		}
+	}
+	findings := findingsError(all)
+	if incomplete && findingsExitCode(all) != ExitCritical {
+		findings = &exitStatus{code: ExitError}
+	}
+
+	if opts.onlyUnhealthy {
+		all = nil
+		for i := range scans {
+			var filtered []*domain.Diagnosis
+			for _, d := range scans[i].Diagnoses {
+				if !d.IsHealthy() {
+					filtered = append(filtered, d)
+				}
+			}
+			scans[i].Diagnoses = filtered
+			all = append(all, filtered...)
+		}
+	}
+	if err := exportFixes(cmd, opts.exportFixes, all...); err != nil {
+		return err
+	}
+
+	switch opts.OutputFormat {
+	case "json":
+		data, err := json.MarshalIndent(scans, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(scans)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	default:
+		output.PrintClusterScans(out, scans, opts.scanTable())
+	}
+	if !console {
+		// Structured output stays parseable; failures go to stderr instead
+		for _, s := range scans {
+			output.PrintScanFailures(cmd.ErrOrStderr(), s.Failures, len(s.Diagnoses)+len(s.Failures))
+		}
+	}
+	return findings
+}
+
+// scanCluster scans the cluster of one kube context with its settings. A
+// cluster that cannot be reached or listed is reported in the result's
+// Error rather than failing the whole scan.
+func scanCluster(cmd *cobra.Command, opts *scanOptions, s clusterSettings) (scan output.ClusterScan) {
+	start := time.Now()
+	namespace := s.opts.Namespace
+	if s.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	scan = output.ClusterScan{
+		Context:       s.opts.Context,
+		Namespace:     namespace,
+		LabelSelector: s.labelSelector,
+		Concurrency:   s.concurrency,
+		QPS:           s.opts.QPS,
+		Burst:         s.opts.Burst,
+	}
+	defer func() { scan.Duration = time.Since(start) }()
+
+	client, err := newClient(cmd, &s.opts)
+	if err != nil {
+		scan.Error = err.Error()
+		return scan
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), clusterScanTimeout)
+	defer cancel()
+	client = client.WithSnapshot(ctx, namespace)
+	podAnalyzer, err := newPodAnalyzer(&s.opts, client)
+	if err != nil {
+		scan.Error = err.Error()
+		return scan
+	}
+
+	pods, stream := streamPods(ctx, client, namespace, s.labelSelector, opts.samplePerWorkload)
+	diagnoses, failures := scanPodStream(ctx, podAnalyzer, pods, s.concurrency, scanHooks{})
+	if stream.err != nil {
+		scan.Error = stream.err.Error()
+	}
+	scan.Listed = stream.listed
+	scan.Diagnoses = diagnoses
+	scan.Failures = describeScanFailures(failures)
+	scan.FailedPods = len(failures)
+	scan.SkippedNamespaces = stream.namespaces
+	return scan
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/spf13/cobra"
+)
+
+// debugStartTimeout bounds creating the debug container and waiting for
+// its image to be pulled and started
+const debugStartTimeout = 2 * time.Minute
+
+// debugOptions holds the flags for the debug command
+type debugOptions struct {
+	*Options
+	image    string
+	target   string
+	noAttach bool
+}
+
+func newDebugCommand(opts *Options) *cobra.Command {
+	debugOpts := &debugOptions{Options: opts}
+
+	debugCmd := &cobra.Command{
+		Use:   "debug <pod-name> [-- command...]",
+		Short: "Open a shell in an ephemeral debug container",
+		Long: `Add an ephemeral debug container to a running pod, like kubectl debug,
+and attach a terminal to it.
+
+The debug container shares the process namespace of the target container
+(-c, by default the first container that is not ready), so its processes
+are visible with ps and its filesystem under /proc/1/root, and the pod's
+network namespace, so you can test probes and connections as the pod sees
+them. Exiting the shell stops the debug container; it stays listed in the
+pod until the pod is replaced.
+
+Attaching runs kubectl attach, so kubectl must be on PATH; otherwise, or
+with --no-attach, the attach command is printed instead.
+
+Examples:
+  # Open a shell next to the failing container
+  pod-doctor debug my-pod -n production
+
+  # Bring network tools (curl, dig, nc, tcpdump) to test a readiness probe
+  pod-doctor debug my-pod -c api --image nicolaka/netshoot
+
+  # Run a specific command instead of the image's shell
+  pod-doctor debug my-pod -- sh -c 'wget -qO- localhost:8080/healthz'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDebug(cmd, debugOpts, args[0], args[1:])
+		},
+	}
+
+	debugCmd.Flags().StringVar(&debugOpts.image, "image", kubernetes.DefaultDebugImage, "image of the debug container")
+	debugCmd.Flags().StringVarP(&debugOpts.target, "container", "c", "", "container whose processes the debug container shares (default: the first container that is not ready)")
+	debugCmd.Flags().BoolVar(&debugOpts.noAttach, "no-attach", false, "create the debug container and print the attach command instead of attaching")
+
+	return debugCmd
+}
+
+func runDebug(cmd *cobra.Command, opts *debugOptions, podName string, command []string) error {
+	out := cmd.OutOrStdout()
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), debugStartTimeout)
+	defer cancel()
+
+	target := opts.target
+	if target == "" {
+		pod, err := client.GetPod(ctx, opts.Namespace, podName)
+		if err != nil {
+			return fmt.Errorf("failed to get pod: %w", err)
+		}
+		target = kubernetes.DebugTarget(pod)
+	}
+
+	name, err := client.CreateDebugContainer(ctx, opts.Namespace, podName, kubernetes.DebugContainer{
+		Image:   opts.image,
+		Target:  target,
+		Command: command,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create debug container: %w", err)
+	}
+	fmt.Fprintf(out, "Created debug container %s (%s) targeting %s in pod %s/%s, waiting for it to start...\n",
+		name, opts.image, target, opts.Namespace, podName)
+
+	if err := client.WaitForDebugContainer(ctx, opts.Namespace, podName, name); err != nil {
+		return err
+	}
+
+	attach := client.AttachCommand(opts.Namespace, podName, name)
+	kubectl, lookErr := exec.LookPath(attach[0])
+	if opts.noAttach || lookErr != nil || !isInteractive() {
+		if lookErr != nil && !opts.noAttach {
+			fmt.Fprintln(out, "kubectl not found on PATH; attach from a machine that has it:")
+		} else {
+			fmt.Fprintln(out, "Attach with:")
+		}
+		fmt.Fprintf(out, "  %s\n", strings.Join(attach, " "))
+		return nil
+	}
+
+	attachCmd := exec.CommandContext(cmd.Context(), kubectl, attach[1:]...)
+	attachCmd.Stdin = os.Stdin
+	attachCmd.Stdout = os.Stdout
+	attachCmd.Stderr = os.Stderr
+	if err := attachCmd.Run(); err != nil {
+		return fmt.Errorf("failed to attach to debug container %s: %w", name, err)
+	}
+	return nil
+}
@@ -1,23 +1,46 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
 	"github.com/pavanInnamuri/pod-doctor/internal/output"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
-var diagnoseCmd = &cobra.Command{
-	Use:   "diagnose <pod-name>",
-	Short: "Diagnose a specific pod",
-	Long: `Diagnose a specific pod to identify issues and get recommendations.
+// diagnoseOptions holds the flags for the diagnose command
+type diagnoseOptions struct {
+	*Options
+	allowMissing  bool
+	allNamespaces bool
+	filename      string
+	labelSelector string
+	concurrency   int
+	at            string
+	exportFixes   string
+}
+
+func newDiagnoseCommand(opts *Options) *cobra.Command {
+	diagOpts := &diagnoseOptions{Options: opts}
+
+	diagnoseCmd := &cobra.Command{
+		Use:   "diagnose <pod-name> | <kind>/<workload> | -l <selector> | -f <file>",
+		Short: "Diagnose a specific pod or workload",
+		Long: `Diagnose a specific pod to identify issues and get recommendations.
 
 This command analyzes:
   - Pod and container status
@@ -34,59 +57,346 @@ Examples:
   pod-doctor diagnose my-pod -n production
 
   # Output as JSON
-  pod-doctor diagnose my-pod -o json`,
-	Args: cobra.ExactArgs(1),
-	Run:  runDiagnose,
-}
+  pod-doctor diagnose my-pod -o json
+
+  # Not sure of the generated name? Close matches are offered
+  pod-doctor diagnose web -n production
+
+  # Also look for close matches in other namespaces
+  pod-doctor diagnose web -A
+
+  # Reconstruct what happened to a pod that was already deleted
+  pod-doctor diagnose my-job-x7k2p --allow-missing
+
+  # Diagnose a list of pods (namespace/name per line, or a JSON array)
+  pod-doctor diagnose -f pods.txt
+
+  # Diagnose pods selected by another tool
+  kubectl get pods -n production -o name | pod-doctor diagnose -n production -f -
+
+  # Diagnose every replica of a workload and see which issues they share
+  pod-doctor diagnose deployment/web -n production
+
+  # The same for the pods matching a label selector
+  pod-doctor diagnose -l app=web -n production
 
-func init() {
-	rootCmd.AddCommand(diagnoseCmd)
+  # See which analyzers make a diagnosis slow
+  pod-doctor diagnose my-pod --verbose
+
+  # Write the recommended commands to a script to review and run
+  pod-doctor diagnose deployment/web -n production --export-fixes fixes.sh
+
+  # What did the pod look like during last night's incident?
+  pod-doctor diagnose my-pod -n production --at 2024-05-01T02:30:00Z
+
+Workloads may be deployments, statefulsets, daemonsets or jobs, named as
+with kubectl (deploy/web, sts/db, ds/agent, job/migrate). Their pods are
+diagnosed together, and issues every pod has are reported apart from those
+only some pods have.
+
+Analyzers and lookups such as events and node health run concurrently,
+each bounded by --analyzer-timeout; one that overruns it is skipped and
+noted in the diagnosis. --verbose adds how long each one took, and logs
+to stderr the errors that left the diagnosis incomplete.
+
+With --at, the pod's state at a past time is reconstructed from the last
+diagnosis recorded at or before then and the events that remain since,
+or from the events alone if none was recorded. Events are usually kept for
+only an hour, so diagnoses recorded by earlier diagnose and diff runs are
+what make post-incident analysis possible hours later.
+
+Each diagnosis is recorded for the history and diff commands unless
+--no-history is set; reconstructions with --at are not recorded.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case diagOpts.filename != "" && diagOpts.labelSelector != "":
+				return fmt.Errorf("cannot combine --selector with --filename")
+			case diagOpts.filename != "" && len(args) > 0:
+				return fmt.Errorf("cannot combine a pod name with --filename")
+			case diagOpts.labelSelector != "" && len(args) > 0:
+				return fmt.Errorf("cannot combine a pod name with --selector")
+			case diagOpts.at != "" && (diagOpts.filename != "" || diagOpts.labelSelector != ""):
+				return fmt.Errorf("--at applies to a single pod")
+			case diagOpts.at != "" && diagOpts.exportFixes != "":
+				return fmt.Errorf("--export-fixes cannot be used with --at: a reconstructed past state has nothing left to fix")
+			case diagOpts.filename != "" || diagOpts.labelSelector != "":
+				return nil
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case diagOpts.filename != "":
+				return runDiagnoseBatch(cmd, diagOpts)
+			case diagOpts.labelSelector != "":
+				return runDiagnoseWorkload(cmd, diagOpts, "", "")
+			}
+			if kind, name, ok := kubernetes.ParseWorkloadRef(args[0]); ok {
+				if diagOpts.at != "" {
+					return fmt.Errorf("--at applies to a single pod")
+				}
+				return runDiagnoseWorkload(cmd, diagOpts, kind, name)
+			}
+			if diagOpts.at != "" {
+				return runDiagnoseAt(cmd, diagOpts, args[0])
+			}
+			return runDiagnose(cmd, diagOpts, args[0])
+		},
+	}
+
+	diagnoseCmd.Flags().StringVarP(&diagOpts.filename, "filename", "f", "", "file with pod references to diagnose, one namespace/name per line or a JSON array (- for stdin)")
+	diagnoseCmd.Flags().StringVarP(&diagOpts.labelSelector, "selector", "l", "", "diagnose the pods matching a label selector together")
+	diagnoseCmd.Flags().IntVar(&diagOpts.concurrency, "concurrency", 5, "number of concurrent diagnoses with -f, -l or a workload")
+	diagnoseCmd.Flags().BoolVarP(&diagOpts.allNamespaces, "all-namespaces", "A", false, "if the pod is not found, look for similarly named pods in all namespaces")
+	diagnoseCmd.Flags().BoolVar(&diagOpts.allowMissing, "allow-missing", false, "if the pod no longer exists, diagnose it from remaining events and its owner's status")
+	diagnoseCmd.Flags().StringVar(&diagOpts.exportFixes, "export-fixes", "", "write the recommended commands to a shell script that asks before running each one")
+	opts.addIssueFilterFlags(diagnoseCmd.Flags())
+	diagnoseCmd.Flags().StringVar(&diagOpts.at, "at", "", "reconstruct the pod's state at a past time (RFC 3339 timestamp, \"2006-01-02 15:04\" local time, or a duration ago like 2h) from recorded diagnoses and remaining events")
+
+	return diagnoseCmd
 }
 
-func runDiagnose(cmd *cobra.Command, args []string) {
-	podName := args[0]
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// diagnoseTimeout bounds diagnosing one pod, and looking for pods named
+// like one that was not found
+const diagnoseTimeout = 30 * time.Second
+
+func runDiagnose(cmd *cobra.Command, opts *diagnoseOptions, podName string) error {
+	out := cmd.OutOrStdout()
 
 	// Create Kubernetes client
-	client, err := kubernetes.NewClient(kubeconfigPath)
+	client, err := newClient(cmd, opts.Options)
 	if err != nil {
-		output.PrintError(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
-		os.Exit(1)
+		return err
 	}
 
 	// Create analyzer
-	podAnalyzer := analyzer.NewPodAnalyzer(client)
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
 
 	// Show loading message for console output
-	if outputFormat == "console" {
-		fmt.Printf("Diagnosing pod %s/%s...\n", namespace, podName)
+	if opts.OutputFormat == "console" {
+		fmt.Fprintf(out, "Diagnosing pod %s/%s...\n", opts.Namespace, podName)
 	}
 
 	// Run diagnosis
-	diagnosis, err := podAnalyzer.Diagnose(ctx, namespace, podName)
+	ctx, cancel := context.WithTimeout(cmd.Context(), diagnoseTimeout)
+	diagnosis, err := diagnosePod(ctx, podAnalyzer, opts, podRef{namespace: opts.Namespace, name: podName})
+	cancel()
+	if apierrors.IsNotFound(err) && !opts.allowMissing {
+		// The user may take a while to pick a pod, so the prompt runs
+		// without a deadline and the chosen pod gets a timeout of its own
+		var ref podRef
+		ref, err = choosePodMatch(cmd.Context(), cmd, client, opts, podName, err)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(cmd.Context(), diagnoseTimeout)
+			diagnosis, err = diagnosePod(ctx, podAnalyzer, opts, ref)
+			cancel()
+		}
+	}
 	if err != nil {
-		output.PrintError(fmt.Sprintf("Failed to diagnose pod: %v", err))
-		os.Exit(1)
+		return fmt.Errorf("failed to diagnose pod: %w", err)
+	}
+	recordHistory(cmd, opts.Options, diagnosis)
+	if err := exportFixes(cmd, opts.exportFixes, diagnosis); err != nil {
+		return err
 	}
 
 	// Output results
-	switch outputFormat {
+	switch opts.OutputFormat {
 	case "json":
 		data, err := json.MarshalIndent(diagnosis, "", "  ")
 		if err != nil {
-			output.PrintError(fmt.Sprintf("Failed to marshal JSON: %v", err))
-			os.Exit(1)
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(out, string(data))
 	case "yaml":
 		data, err := yaml.Marshal(diagnosis)
 		if err != nil {
-			output.PrintError(fmt.Sprintf("Failed to marshal YAML: %v", err))
-			os.Exit(1)
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "sarif":
+		if err := output.WriteSARIF(out, []*domain.Diagnosis{diagnosis}, Version); err != nil {
+			return fmt.Errorf("failed to write SARIF: %w", err)
 		}
-		fmt.Println(string(data))
 	default:
-		output.PrintDiagnosis(diagnosis)
+		output.PrintDiagnosis(out, diagnosis)
+		if opts.Verbose {
+			output.PrintTimings(out, diagnosis.Timings)
+		}
+	}
+
+	return nil
+}
+
+// diagnosePod diagnoses one pod, falling back to its events with --allow-missing
+func diagnosePod(ctx context.Context, podAnalyzer *analyzer.PodAnalyzer, opts *diagnoseOptions, ref podRef) (*domain.Diagnosis, error) {
+	diagnosis, err := podAnalyzer.Diagnose(ctx, ref.namespace, ref.name)
+	if apierrors.IsNotFound(err) && opts.allowMissing {
+		diagnosis, err = podAnalyzer.DiagnoseMissing(ctx, ref.namespace, ref.name)
+	}
+	return diagnosis, err
+}
+
+// maxPodMatches bounds the similar pods offered when a pod is not found
+const maxPodMatches = 5
+
+// choosePodMatch looks for pods named like a pod that was not found, with a
+// single pod list. In an interactive console session the user picks one to
+// diagnose; otherwise, or when the user picks none, the not-found error is
+// returned with the matches as suggestions. Only the search is bounded by
+// diagnoseTimeout, not the wait for the user's answer.
+func choosePodMatch(ctx context.Context, cmd *cobra.Command, client *kubernetes.Client, opts *diagnoseOptions, podName string, notFound error) (podRef, error) {
+	findCtx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	matches, err := client.FindSimilarPods(findCtx, opts.Namespace, podName, opts.allNamespaces, maxPodMatches)
+	cancel()
+	if err != nil || len(matches) == 0 {
+		return podRef{}, notFound
+	}
+	var apiErr *kubernetes.APIError
+	if errors.As(notFound, &apiErr) {
+		apiErr.Suggestions = matches
+	}
+	if opts.OutputFormat != "console" || !isInteractive() {
+		return podRef{}, notFound
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Pod %s/%s not found. Did you mean:\n", opts.Namespace, podName)
+	for i, match := range matches {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, match)
+	}
+	fmt.Fprintf(out, "Diagnose which pod? [1-%d, Enter to cancel]: ", len(matches))
+
+	line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(matches) {
+		return podRef{}, notFound
+	}
+
+	ref, err := parsePodRef(matches[choice-1], opts.Namespace)
+	if err != nil {
+		return podRef{}, err
+	}
+	fmt.Fprintf(out, "Diagnosing pod %s/%s...\n", ref.namespace, ref.name)
+	return ref, nil
+}
+
+// printBatchError reports a pod of a batch that could not be diagnosed, with
+// hints, on stderr for structured formats so it does not corrupt them
+func printBatchError(cmd *cobra.Command, format string, ref podRef, err error) {
+	msg := fmt.Sprintf("%s/%s: %v", ref.namespace, ref.name, err)
+	hints := kubernetes.ErrorHints(err)
+	if format != "console" {
+		errOut := cmd.ErrOrStderr()
+		fmt.Fprintln(errOut, "Error: "+msg)
+		for _, hint := range hints {
+			fmt.Fprintln(errOut, "  • "+hint)
+		}
+		return
+	}
+	output.PrintError(cmd.OutOrStdout(), msg)
+	for _, hint := range hints {
+		output.PrintInfo(cmd.OutOrStdout(), "  • "+hint)
+	}
+}
+
+// isInteractive reports whether both stdin and stdout are terminals, so the
+// user can answer a prompt
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// runDiagnoseBatch diagnoses every pod listed in --filename, keeping the
+// input order in the output
+func runDiagnoseBatch(cmd *cobra.Command, opts *diagnoseOptions) error {
+	out := cmd.OutOrStdout()
+
+	refs, err := readPodRefsFile(opts.filename, cmd.InOrStdin(), opts.Namespace)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+
+	if opts.OutputFormat == "console" {
+		fmt.Fprintf(out, "Diagnosing %d pods...\n", len(refs))
+	}
+
+	concurrency := opts.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]*domain.Diagnosis, len(refs))
+	errs := make([]error, len(refs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ref podRef) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			results[i], errs[i] = diagnosePod(ctx, podAnalyzer, opts, ref)
+		}(i, ref)
+	}
+	wg.Wait()
+
+	var diagnoses []*domain.Diagnosis
+	var failed int
+	for i, ref := range refs {
+		if errs[i] != nil {
+			failed++
+			printBatchError(cmd, opts.OutputFormat, ref, errs[i])
+			continue
+		}
+		diagnoses = append(diagnoses, results[i])
+	}
+	recordHistory(cmd, opts.Options, diagnoses...)
+	if err := exportFixes(cmd, opts.exportFixes, diagnoses...); err != nil {
+		return err
+	}
+
+	switch opts.OutputFormat {
+	case "json":
+		data, err := json.MarshalIndent(diagnoses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(diagnoses)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "sarif":
+		if err := output.WriteSARIF(out, diagnoses, Version); err != nil {
+			return fmt.Errorf("failed to write SARIF: %w", err)
+		}
+	default:
+		for _, d := range diagnoses {
+			output.PrintDiagnosis(out, d)
+		}
+		output.PrintScanSummary(out, diagnoses, output.ScanTable{})
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to diagnose %d of %d pods", failed, len(refs))
 	}
+	return nil
 }
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eventsOptions holds the flags for the events command
+type eventsOptions struct {
+	*Options
+	allNamespaces bool
+	since         time.Duration
+	sortBy        string
+	limit         int
+}
+
+func newEventsCommand(opts *Options) *cobra.Command {
+	eventsOpts := &eventsOptions{Options: opts}
+
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show grouped warning events",
+		Long: `Show what is unhappy right now from warning events.
+
+Warning events are grouped by involved object and reason, so a container
+restarting 200 times shows up once with its total count and latest message.
+Groups are classified and colored like issues in a diagnosis.
+
+Examples:
+  # Warning events in the current namespace from the last hour
+  pod-doctor events -n production
+
+  # Across the cluster, noisiest first
+  pod-doctor events -A --sort count
+
+  # Everything the API server still has, as JSON
+  pod-doctor events -A --since 0 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEvents(cmd, eventsOpts)
+		},
+	}
+
+	eventsCmd.Flags().BoolVarP(&eventsOpts.allNamespaces, "all-namespaces", "A", false, "show events from all namespaces")
+	eventsCmd.Flags().DurationVar(&eventsOpts.since, "since", time.Hour, "only show events seen within this duration (0 = all)")
+	eventsCmd.Flags().StringVar(&eventsOpts.sortBy, "sort", "recent", "sort groups by recent or count")
+	eventsCmd.Flags().IntVar(&eventsOpts.limit, "limit", 0, "show at most this many groups (0 = all)")
+
+	return eventsCmd
+}
+
+func runEvents(cmd *cobra.Command, opts *eventsOptions) error {
+	if opts.sortBy != "recent" && opts.sortBy != "count" {
+		return fmt.Errorf("invalid --sort %q (use recent or count)", opts.sortBy)
+	}
+	if opts.OutputFormat == "sarif" {
+		return fmt.Errorf("events supports console, json and yaml output only")
+	}
+
+	out := cmd.OutOrStdout()
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	namespace := opts.Namespace
+	if opts.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	events, err := client.ListWarningEvents(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if opts.since > 0 {
+		since = time.Now().Add(-opts.since)
+	}
+	groups := analyzer.GroupWarningEvents(events, since)
+
+	if opts.sortBy == "count" {
+		sort.SliceStable(groups, func(i, j int) bool {
+			return groups[i].Count > groups[j].Count
+		})
+	}
+	if opts.limit > 0 && len(groups) > opts.limit {
+		groups = groups[:opts.limit]
+	}
+
+	switch opts.OutputFormat {
+	case "json":
+		data, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(groups)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	default:
+		output.PrintEventGroups(out, groups)
+	}
+
+	return nil
+}
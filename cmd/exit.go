@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// Exit codes, so pipelines can tell findings apart from failures
+const (
+	ExitHealthy  = 0 // no findings
+	ExitWarnings = 1 // warnings found, nothing critical
+	ExitCritical = 2 // critical issues found
+	ExitError    = 3 // the command itself failed, or a scan missed pods
+)
+
+// exitStatus is returned by commands whose findings decide the exit code.
+// The findings have already been printed, so Execute exits without an
+// error message.
+type exitStatus struct {
+	code int
+}
+
+func (e *exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// findingsExitCode returns ExitCritical if any diagnosis has a critical
+// issue, ExitWarnings if any pod is otherwise unhealthy, and ExitHealthy if
+// all pods are healthy. Expected failures from chaos experiments or node
+// upgrades and info issues do not count.
+func findingsExitCode(diagnoses []*domain.Diagnosis) int {
+	code := ExitHealthy
+	for _, d := range diagnoses {
+		if d.IsExpectedFailure() {
+			continue
+		}
+		if d.HasCriticalIssues() {
+			return ExitCritical
+		}
+		if _, warning, _ := d.IssueCount(); warning > 0 || d.Status != domain.StatusHealthy {
+			code = ExitWarnings
+		}
+	}
+	return code
+}
+
+// findingsError returns an exitStatus for the findings in diagnoses, or nil
+// if all pods are healthy
+func findingsError(diagnoses []*domain.Diagnosis) error {
+	if code := findingsExitCode(diagnoses); code != ExitHealthy {
+		return &exitStatus{code: code}
+	}
+	return nil
+}
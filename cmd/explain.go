@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/kb"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newExplainCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain [issue-id]",
+		Short: "Explain an issue type: causes, debugging steps and links",
+		Long: `Explain an issue type from the knowledge base bundled with pod-doctor.
+
+Every issue in a diagnosis carries a stable ID such as PD-CRASHLOOP-001,
+shown next to its title and in the id field of -o json. IDs do not change
+between releases, so they can be used in runbooks, alerts and rule packs.
+Without an ID, every issue type is listed. No cluster access is needed.
+
+Examples:
+  # Explain an issue from a diagnosis
+  pod-doctor explain PD-CRASHLOOP-001
+
+  # List every issue type
+  pod-doctor explain
+
+  # Explain an issue as JSON
+  pod-doctor explain PD-OOM-001 -o json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				articles := kb.Articles()
+				return printStructured(cmd, opts.OutputFormat, articles, func() {
+					output.PrintArticleList(cmd.OutOrStdout(), articles)
+				})
+			}
+
+			article, ok := kb.Lookup(args[0])
+			if !ok {
+				return fmt.Errorf("unknown issue ID %q; run pod-doctor explain to list them", args[0])
+			}
+			return printStructured(cmd, opts.OutputFormat, article, func() {
+				output.PrintArticle(cmd.OutOrStdout(), article)
+			})
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// exportFixes writes the recommended commands of diagnoses as a shell
+// script to path, for --export-fixes
+func exportFixes(cmd *cobra.Command, path string, diagnoses ...*domain.Diagnosis) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to export fixes: %w", err)
+	}
+	commands, err := output.WriteFixScript(f, diagnoses, Version)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export fixes: %w", err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d commands to %s; review it, then run sh %s\n", commands, path, path)
+	return nil
+}
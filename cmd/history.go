@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/history"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// historyOptions holds the flags for the history command
+type historyOptions struct {
+	*Options
+	limit int
+}
+
+func newHistoryCommand(opts *Options) *cobra.Command {
+	historyOpts := &historyOptions{Options: opts}
+
+	historyCmd := &cobra.Command{
+		Use:   "history <pod-name>",
+		Short: "List recorded diagnoses of a pod",
+		Long: `List the diagnoses recorded for a pod, oldest first.
+
+Every diagnose run records its result under --history-dir
+(default ~/.pod-doctor/history) unless --no-history is set. The most
+recent ` + fmt.Sprint(history.MaxEntries) + ` diagnoses are kept per pod.
+
+Examples:
+  # How has this pod's health evolved?
+  pod-doctor history my-pod -n production
+
+  # Every recorded diagnosis as JSON
+  pod-doctor history my-pod --limit 0 -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(cmd, historyOpts, args[0])
+		},
+	}
+
+	historyCmd.Flags().IntVar(&historyOpts.limit, "limit", 20, "show at most this many of the most recent diagnoses (0 = all)")
+
+	return historyCmd
+}
+
+func runHistory(cmd *cobra.Command, opts *historyOptions, podName string) error {
+	if opts.OutputFormat == "sarif" {
+		return fmt.Errorf("history supports console, json and yaml output only")
+	}
+
+	store, err := historyStore(opts.Options)
+	if err != nil {
+		return err
+	}
+	entries, err := store.List(opts.Namespace, podName)
+	if err != nil {
+		return err
+	}
+	if opts.limit > 0 && len(entries) > opts.limit {
+		entries = entries[len(entries)-opts.limit:]
+	}
+
+	diagnoses := make([]*domain.Diagnosis, 0, len(entries))
+	for _, e := range entries {
+		diagnoses = append(diagnoses, e.Diagnosis)
+	}
+
+	return printStructured(cmd, opts.OutputFormat, diagnoses, func() {
+		if len(diagnoses) == 0 {
+			output.PrintInfo(cmd.OutOrStdout(), fmt.Sprintf("No recorded diagnoses of %s/%s; run pod-doctor diagnose %s -n %s to record one",
+				opts.Namespace, podName, podName, opts.Namespace))
+			return
+		}
+		output.PrintHistory(cmd.OutOrStdout(), diagnoses)
+	})
+}
+
+// diffOptions holds the flags for the diff command
+type diffOptions struct {
+	*Options
+	recorded bool
+}
+
+func newDiffCommand(opts *Options) *cobra.Command {
+	diffOpts := &diffOptions{Options: opts}
+
+	diffCmd := &cobra.Command{
+		Use:   "diff <pod-name>",
+		Short: "Show what changed since a pod's last recorded diagnosis",
+		Long: `Diagnose a pod and show what changed since its last recorded diagnosis:
+new and resolved issues, status and health score changes, and how often
+each container restarted in between. Useful when a pod flaps between
+healthy and failing.
+
+The new diagnosis is recorded too (unless --no-history is set), so
+running diff repeatedly shows the changes since the previous run.
+
+Examples:
+  # What changed since the last diagnose or diff?
+  pod-doctor diff my-pod -n production
+
+  # Compare the two most recent recorded diagnoses, without the cluster
+  pod-doctor diff my-pod --recorded`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(cmd, diffOpts, args[0])
+		},
+	}
+
+	diffCmd.Flags().BoolVar(&diffOpts.recorded, "recorded", false, "compare the two most recent recorded diagnoses instead of diagnosing the pod now")
+
+	return diffCmd
+}
+
+func runDiff(cmd *cobra.Command, opts *diffOptions, podName string) error {
+	if opts.OutputFormat == "sarif" {
+		return fmt.Errorf("diff supports console, json and yaml output only")
+	}
+
+	store, err := historyStore(opts.Options)
+	if err != nil {
+		return err
+	}
+	entries, err := store.List(opts.Namespace, podName)
+	if err != nil {
+		return err
+	}
+
+	var prev, curr *domain.Diagnosis
+	if opts.recorded {
+		if len(entries) < 2 {
+			return fmt.Errorf("need two recorded diagnoses of %s/%s to compare, found %d", opts.Namespace, podName, len(entries))
+		}
+		prev, curr = entries[len(entries)-2].Diagnosis, entries[len(entries)-1].Diagnosis
+	} else {
+		curr, err = diagnoseNow(cmd, opts.Options, podName)
+		if err != nil {
+			return err
+		}
+		recordHistory(cmd, opts.Options, curr)
+
+		if len(entries) == 0 {
+			if opts.OutputFormat == "console" {
+				output.PrintInfo(cmd.OutOrStdout(), fmt.Sprintf("No earlier diagnosis of %s/%s recorded; this one was recorded for the next diff", opts.Namespace, podName))
+				output.PrintDiagnosis(cmd.OutOrStdout(), curr)
+			}
+			return nil
+		}
+		prev = entries[len(entries)-1].Diagnosis
+	}
+
+	diff := history.Compare(prev, curr)
+	return printStructured(cmd, opts.OutputFormat, diff, func() {
+		output.PrintDiff(cmd.OutOrStdout(), diff)
+	})
+}
+
+// diagnoseNow diagnoses a pod for the diff command
+func diagnoseNow(cmd *cobra.Command, opts *Options, podName string) (*domain.Diagnosis, error) {
+	client, err := newClient(cmd, opts)
+	if err != nil {
+		return nil, err
+	}
+	podAnalyzer, err := newPodAnalyzer(opts, client)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	diagnosis, err := podAnalyzer.Diagnose(ctx, opts.Namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diagnose pod: %w", err)
+	}
+	return diagnosis, nil
+}
+
+// historyStore opens the diagnosis history under --history-dir
+func historyStore(opts *Options) (*history.Store, error) {
+	if opts.HistoryDir == "" {
+		return nil, fmt.Errorf("cannot locate the home directory; set --history-dir")
+	}
+	return history.NewStore(opts.HistoryDir), nil
+}
+
+// recordHistory records diagnoses for the history and diff commands. A
+// failure to record is reported but does not fail the command.
+func recordHistory(cmd *cobra.Command, opts *Options, diagnoses ...*domain.Diagnosis) {
+	if opts.NoHistory {
+		return
+	}
+	store, err := historyStore(opts)
+	if err == nil {
+		for _, d := range diagnoses {
+			if err = store.Record(d); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: diagnosis not recorded in history: %v\n", err)
+	}
+}
+
+// printStructured prints v as JSON or YAML, or calls printConsole for
+// console output
+func printStructured(cmd *cobra.Command, format string, v interface{}, printConsole func()) error {
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	default:
+		printConsole()
+	}
+	return nil
+}
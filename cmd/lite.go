@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// litePrompt asks the questions of --interactive-lite as plain numbered
+// lists, one line of input per answer
+type litePrompt struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prints a prompt and returns the trimmed answer, or false once input
+// ends
+func (p litePrompt) ask(prompt string) (string, bool) {
+	fmt.Fprint(p.out, prompt)
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(p.out)
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// runInteractiveLite picks a namespace and a pod with sequential prompts,
+// then prints the pod's diagnosis as the diagnose command does. It stands in
+// for the TUI where a full-screen interface is unusable, such as limited
+// terminals and screen readers.
+func runInteractiveLite(cmd *cobra.Command, opts *Options, allNamespaces bool) error {
+	client, err := newClient(cmd, opts)
+	if err != nil {
+		return err
+	}
+	podAnalyzer, err := newPodAnalyzer(opts, client)
+	if err != nil {
+		return err
+	}
+
+	p := litePrompt{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+	namespace := opts.Namespace
+	for {
+		if !allNamespaces {
+			var ok bool
+			if namespace, ok = chooseLiteNamespace(cmd.Context(), client, p, namespace); !ok {
+				return nil
+			}
+		}
+		switch liteDiagnoseLoop(cmd, opts, client, podAnalyzer, p, namespace, allNamespaces) {
+		case liteQuit:
+			return nil
+		case liteChangeNamespace:
+			allNamespaces = false
+		}
+	}
+}
+
+// liteNext is what the user asked for after the pod list
+type liteNext int
+
+const (
+	liteQuit liteNext = iota
+	liteChangeNamespace
+)
+
+// chooseLiteNamespace lists the namespaces and returns the one picked by
+// number or name, or current on Enter
+func chooseLiteNamespace(ctx context.Context, client *kubernetes.Client, p litePrompt, current string) (string, bool) {
+	listCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	namespaces, err := client.GetNamespaces(listCtx)
+	cancel()
+	if err != nil {
+		// Without permission to list namespaces, the name can still be typed
+		fmt.Fprintf(p.out, "Could not list namespaces: %v\n", err)
+	}
+
+	fmt.Fprintln(p.out, "Namespaces:")
+	for i, ns := range namespaces {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, ns)
+	}
+	for {
+		answer, ok := p.ask(fmt.Sprintf("Namespace? [number or name, Enter for %s, q to quit]: ", current))
+		switch {
+		case !ok || answer == "q":
+			return "", false
+		case answer == "":
+			return current, true
+		}
+		if n, err := strconv.Atoi(answer); err == nil {
+			if n >= 1 && n <= len(namespaces) {
+				return namespaces[n-1], true
+			}
+			fmt.Fprintf(p.out, "No namespace %d.\n", n)
+			continue
+		}
+		return answer, true
+	}
+}
+
+// liteDiagnoseLoop lists the pods of a namespace, or of all namespaces,
+// and diagnoses the ones picked until the user quits or asks for another
+// namespace
+func liteDiagnoseLoop(cmd *cobra.Command, opts *Options, client *kubernetes.Client, podAnalyzer *analyzer.PodAnalyzer, p litePrompt, namespace string, allNamespaces bool) liteNext {
+	for {
+		listCtx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		var list *corev1.PodList
+		var err error
+		if allNamespaces {
+			list, err = client.ListAllPods(listCtx)
+		} else {
+			list, err = client.ListPods(listCtx, namespace, "")
+		}
+		cancel()
+		if err != nil {
+			fmt.Fprintf(p.out, "Could not list pods: %v\n", err)
+			return liteChangeNamespace
+		}
+
+		pods := list.Items
+		if len(pods) == 0 {
+			if allNamespaces {
+				fmt.Fprintln(p.out, "No pods found.")
+			} else {
+				fmt.Fprintf(p.out, "No pods in namespace %s.\n", namespace)
+			}
+			return liteChangeNamespace
+		}
+		fmt.Fprintln(p.out, "Pods:")
+		for i := range pods {
+			fmt.Fprintf(p.out, "  %d) %s\n", i+1, describeLitePod(&pods[i], allNamespaces))
+		}
+
+		var pod *corev1.Pod
+	choose:
+		for {
+			answer, ok := p.ask(fmt.Sprintf("Diagnose which pod? [1-%d or name, Enter to refresh, n for another namespace, q to quit]: ", len(pods)))
+			switch {
+			case !ok || answer == "q":
+				return liteQuit
+			case answer == "n":
+				return liteChangeNamespace
+			case answer == "":
+				break choose
+			}
+			if pod = pickLitePod(pods, answer); pod != nil {
+				break choose
+			}
+			fmt.Fprintf(p.out, "No pod %s.\n", answer)
+		}
+		if pod == nil {
+			continue
+		}
+
+		fmt.Fprintf(p.out, "Diagnosing pod %s/%s...\n", pod.Namespace, pod.Name)
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		diagnosis, err := podAnalyzer.Diagnose(ctx, pod.Namespace, pod.Name)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(p.out, "Failed to diagnose pod: %v\n", err)
+		} else {
+			recordHistory(cmd, opts, diagnosis)
+			output.PrintDiagnosis(p.out, diagnosis)
+		}
+
+		answer, ok := p.ask("Enter for the pod list, q to quit: ")
+		if !ok || answer == "q" {
+			return liteQuit
+		}
+	}
+}
+
+// describeLitePod renders a pod as one line of the pod list, in words a
+// screen reader reads out as is
+func describeLitePod(pod *corev1.Pod, withNamespace bool) string {
+	name := pod.Name
+	if withNamespace {
+		name = pod.Namespace + "/" + pod.Name
+	}
+	info := kubernetes.ExtractPodInfo(pod)
+	return fmt.Sprintf("%s, %s, %d restarts", name, analyzer.DetectPodStatus(pod), info.Restarts)
+}
+
+// pickLitePod returns the pod an answer names by number, name or
+// namespace/name, or nil
+func pickLitePod(pods []corev1.Pod, answer string) *corev1.Pod {
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n >= 1 && n <= len(pods) {
+			return &pods[n-1]
+		}
+		return nil
+	}
+	for i := range pods {
+		if pods[i].Name == answer || pods[i].Namespace+"/"+pods[i].Name == answer {
+			return &pods[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/notify"
+	"github.com/spf13/pflag"
+)
+
+// notifyOptions holds the flags for notifications from continuous modes
+type notifyOptions struct {
+	slackURL   string
+	webhookURL string
+}
+
+// addFlags registers the notification flags
+func (n *notifyOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&n.slackURL, "notify-slack", "", "Slack incoming webhook URL to notify when a pod becomes unhealthy or gets a new critical issue")
+	flags.StringVar(&n.webhookURL, "notify-webhook", "", "HTTP endpoint to POST a JSON event to when a pod becomes unhealthy or gets a new critical issue")
+}
+
+// enabled reports whether any notification backend is configured
+func (n notifyOptions) enabled() bool {
+	return n.slackURL != "" || n.webhookURL != ""
+}
+
+// notifyQueueSize is how many events wait for delivery before new ones
+// are dropped, so a slow backend cannot hold up diagnoses
+const notifyQueueSize = 64
+
+// podNotifier sends notifications as diagnosed pods change health. A nil
+// podNotifier does nothing, so callers need not check whether
+// notifications are enabled.
+type podNotifier struct {
+	notifiers notify.Notifiers
+	tracker   *notify.Tracker
+	errOut    io.Writer
+	events    chan notify.Event
+	dropped   atomic.Int64 // events dropped since the last report
+}
+
+// newPodNotifier creates a notifier for the configured backends, or
+// returns nil if none are configured. Delivery failures are written to
+// errOut.
+func newPodNotifier(opts notifyOptions, errOut io.Writer) (*podNotifier, error) {
+	var notifiers notify.Notifiers
+	if opts.slackURL != "" {
+		if err := validateNotifyURL("--notify-slack", opts.slackURL); err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notify.NewSlack(opts.slackURL))
+	}
+	if opts.webhookURL != "" {
+		if err := validateNotifyURL("--notify-webhook", opts.webhookURL); err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notify.NewWebhook(opts.webhookURL))
+	}
+	if len(notifiers) == 0 {
+		return nil, nil
+	}
+	return &podNotifier{
+		notifiers: notifiers,
+		tracker:   notify.NewTracker(),
+		errOut:    errOut,
+		events:    make(chan notify.Event, notifyQueueSize),
+	}, nil
+}
+
+// start delivers queued events in the background until ctx is cancelled
+func (n *podNotifier) start(ctx context.Context) {
+	if n == nil {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-n.events:
+				if err := n.notifiers.Notify(ctx, event); err != nil {
+					fmt.Fprintf(n.errOut, "Error: failed to notify about %s/%s: %v\n", event.Namespace, event.Pod, err)
+				}
+				if dropped := n.dropped.Swap(0); dropped > 0 {
+					fmt.Fprintf(n.errOut, "Warning: dropped %d notifications while delivery was backed up\n", dropped)
+				}
+			}
+		}
+	}()
+}
+
+// observe queues a notification about d if the pod became unhealthy or has
+// a new critical issue. The event is dropped and counted if the queue is
+// full.
+func (n *podNotifier) observe(d *domain.Diagnosis) {
+	if n == nil {
+		return
+	}
+	event, ok := n.tracker.Observe(d)
+	if !ok {
+		return
+	}
+	select {
+	case n.events <- event:
+	default:
+		n.dropped.Add(1)
+	}
+}
+
+// forget drops a deleted pod, so a new pod with the same name starts fresh
+func (n *podNotifier) forget(namespace, name string) {
+	if n == nil {
+		return
+	}
+	n.tracker.Forget(namespace, name)
+}
+
+// validateNotifyURL rejects values that are not http(s) URLs
+func validateNotifyURL(flag, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL", flag)
+	}
+	return nil
+}
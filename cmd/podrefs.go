@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readPodRefsFile reads pod references from a file, or from stdin for "-"
+func readPodRefsFile(path string, stdin io.Reader, defaultNamespace string) ([]podRef, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pod list: %w", err)
+	}
+
+	refs, err := parsePodRefs(data, defaultNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod list %s: %w", path, err)
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("pod list %s is empty", path)
+	}
+	return refs, nil
+}
+
+// parsePodRefs parses a JSON array of references, or one reference per
+// line. A reference is namespace/name, name, or kubectl's pod/name; names
+// without a namespace use defaultNamespace. Blank lines and # comments are
+// ignored.
+func parsePodRefs(data []byte, defaultNamespace string) ([]podRef, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return parsePodRefsJSON(trimmed, defaultNamespace)
+	}
+
+	var refs []podRef
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		ref, err := parsePodRef(text, defaultNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, scanner.Err()
+}
+
+// parsePodRefsJSON parses an array of "namespace/name" strings or
+// {"namespace": ..., "name": ...} objects
+func parsePodRefsJSON(data []byte, defaultNamespace string) ([]podRef, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	refs := make([]podRef, 0, len(items))
+	for i, item := range items {
+		var s string
+		if err := json.Unmarshal(item, &s); err == nil {
+			ref, err := parsePodRef(s, defaultNamespace)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			refs = append(refs, ref)
+			continue
+		}
+
+		var obj struct {
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+		}
+		if err := json.Unmarshal(item, &obj); err != nil || obj.Name == "" {
+			return nil, fmt.Errorf("item %d: expected \"namespace/name\" or {\"namespace\", \"name\"}", i)
+		}
+		if obj.Namespace == "" {
+			obj.Namespace = defaultNamespace
+		}
+		refs = append(refs, podRef{namespace: obj.Namespace, name: obj.Name})
+	}
+	return refs, nil
+}
+
+// parsePodRef parses a single pod reference
+func parsePodRef(s, defaultNamespace string) (podRef, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "pod/"), "pods/")
+	parts := strings.Split(s, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return podRef{namespace: defaultNamespace, name: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return podRef{namespace: parts[0], name: parts[1]}, nil
+	}
+	return podRef{}, fmt.Errorf("invalid pod reference %q, expected namespace/name or name", s)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reportOptions holds the flags for the report command
+type reportOptions struct {
+	*Options
+	allNamespaces bool
+	labelSelector string
+	concurrency   int
+	file          string
+	title         string
+}
+
+func newReportCommand(opts *Options) *cobra.Command {
+	reportOpts := &reportOptions{Options: opts}
+
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Scan pods and write an HTML report",
+		Long: `Scan pods and write a standalone HTML report.
+
+The report groups issues by namespace, charts issues by severity and pods
+by status, and includes each pod's issues, recommendations and events.
+It has no external assets, so it can be attached to incident tickets.
+
+Examples:
+  # Report on the production namespace
+  pod-doctor report -n production
+
+  # Report on all namespaces to a custom file
+  pod-doctor report -A -f incident-4711.html --title "INC-4711 cluster state"
+
+  # Write the report to stdout
+  pod-doctor report -f -`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReport(cmd, reportOpts)
+		},
+	}
+
+	reportCmd.Flags().BoolVarP(&reportOpts.allNamespaces, "all-namespaces", "A", false, "scan all namespaces")
+	reportCmd.Flags().StringVarP(&reportOpts.labelSelector, "selector", "l", "", "label selector to filter pods")
+	reportCmd.Flags().IntVar(&reportOpts.concurrency, "concurrency", 5, "number of concurrent diagnoses")
+	reportCmd.Flags().StringVarP(&reportOpts.file, "file", "f", "pod-doctor-report.html", "file to write the report to (- for stdout)")
+	reportCmd.Flags().StringVar(&reportOpts.title, "title", "", "report title (default: pod-doctor report with the scanned scope)")
+
+	return reportCmd
+}
+
+func runReport(cmd *cobra.Command, opts *reportOptions) error {
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+
+	namespace := opts.Namespace
+	if opts.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	// Diagnoses reuse the listed pods, and list events and nodes once
+	client = client.WithSnapshot(ctx, namespace)
+
+	podList, skipped, err := listPods(ctx, client, opts.allNamespaces, opts.Namespace, opts.labelSelector)
+	if err != nil {
+		return err
+	}
+
+	pods := make([]podRef, 0, len(podList))
+	for _, pod := range podList {
+		pods = append(pods, podRef{namespace: pod.Namespace, name: pod.Name})
+	}
+
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+
+	diagnoses, failures := scanPods(ctx, podAnalyzer, pods, opts.concurrency)
+
+	title := opts.title
+	if title == "" {
+		scope := "namespace " + opts.Namespace
+		if opts.allNamespaces {
+			scope = "all namespaces"
+		}
+		title = "pod-doctor report: " + scope
+	}
+
+	var w io.Writer = cmd.OutOrStdout()
+	if opts.file != "-" {
+		f, err := os.Create(opts.file)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := output.WriteHTMLReport(w, title, diagnoses, analyzer.FindFailingDependencies(diagnoses, 2), skipped); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if opts.file != "-" {
+		output.PrintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Report for %d pods written to %s", len(diagnoses), opts.file))
+	}
+	output.PrintScanFailures(cmd.ErrOrStderr(), describeScanFailures(failures), len(pods))
+	output.PrintSkippedNamespaces(cmd.ErrOrStderr(), skipped)
+	return nil
+}
@@ -1,23 +1,76 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/config"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/history"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/logging"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/pavanInnamuri/pod-doctor/internal/provenance"
+	"github.com/pavanInnamuri/pod-doctor/internal/rules"
 	"github.com/pavanInnamuri/pod-doctor/internal/tui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
-var (
-	kubeconfigPath string
-	namespace      string
-	outputFormat   string
-)
+// Options holds the global flags shared by all commands
+type Options struct {
+	ConfigPath     string
+	KubeconfigPath string
+	Context        string
+	QPS            float32
+	Burst          int
+	MaxRetries     int
+	RetryBackoff   time.Duration
+	Namespace      string
+	OutputFormat   string
+	Wide           bool
+	ExpandEvents   bool
+	TextIndicators bool
+	RulePacks      []string
+	TrustPolicy    string
+	RecordDir      string
+	ReplayDir      string
+	HistoryDir     string
+	NoHistory      bool
+	Verbose        bool
+	Debug          bool
+	LogFile        string
+	MinSeverity    string
+	Categories     []string
+	Analyzers      analyzer.Config
+
+	// Suppressions from the config file, applied like a rule pack's
+	Suppressions []rules.Match
+
+	// commandLine names the flags set on the command line, before the
+	// config filled in the others
+	commandLine map[string]bool
+}
+
+// NewRootCommand creates the root command with all subcommands attached.
+// Each call returns a command tree with its own options, so trees can be
+// built and run one after another in one process. They must not run
+// concurrently: running one sets process-wide state, the output and TUI
+// display settings (--wide, --expand-events, --text-indicators) and the
+// default logger.
+func NewRootCommand() *cobra.Command {
+	opts := &Options{}
+	var allNamespaces, interactiveLite bool
 
-var rootCmd = &cobra.Command{
-	Use:   "pod-doctor",
-	Short: "Diagnose Kubernetes pod issues",
-	Long: `pod-doctor is a CLI tool for diagnosing Kubernetes pod issues.
+	rootCmd := &cobra.Command{
+		Use:   "pod-doctor",
+		Short: "Diagnose Kubernetes pod issues",
+		Long: `pod-doctor is a CLI tool for diagnosing Kubernetes pod issues.
 
 It analyzes pod status, container states, events, logs, and node health
 to identify problems and provide actionable recommendations.
@@ -28,6 +81,12 @@ Examples:
   # Launch interactive TUI
   pod-doctor
 
+  # Launch the TUI on the pods of every namespace
+  pod-doctor -A
+
+  # Pick a pod from plain numbered prompts instead of the TUI
+  pod-doctor --interactive-lite
+
   # Diagnose a specific pod
   pod-doctor diagnose my-pod -n default
 
@@ -35,25 +94,345 @@ Examples:
   pod-doctor scan -n production
 
   # Scan all namespaces
-  pod-doctor scan --all-namespaces`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := tui.Run(kubeconfigPath); err != nil {
-			fmt.Fprintln(os.Stderr, "Error:", err)
-			os.Exit(1)
-		}
-	},
+  pod-doctor scan --all-namespaces
+
+Flag defaults can be persisted in ~/.pod-doctor.yaml (keys are flag names,
+with optional per-command and per-kube-context sections) and overridden with
+POD_DOCTOR_* environment variables, e.g. POD_DOCTOR_NAMESPACE=production.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyConfig(cmd, opts); err != nil {
+				return err
+			}
+			if err := logging.Setup(cmd.ErrOrStderr(), opts.Verbose, opts.Debug, opts.LogFile); err != nil {
+				return err
+			}
+			output.SetWide(opts.Wide)
+			output.SetExpandEvents(opts.ExpandEvents)
+			tui.SetExpandEvents(opts.ExpandEvents)
+			output.SetTextIndicators(opts.TextIndicators)
+			tui.SetTextIndicators(opts.TextIndicators)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interactiveLite {
+				return runInteractiveLite(cmd, opts, allNamespaces)
+			}
+			if err := opts.checkTUILogging(); err != nil {
+				return err
+			}
+			client, err := newClient(cmd, opts)
+			if err != nil {
+				return err
+			}
+			podAnalyzer, err := newPodAnalyzer(opts, client)
+			if err != nil {
+				return err
+			}
+			return tui.Run(client, podAnalyzer, allNamespaces, tuiConnector(cmd, opts))
+		},
+	}
+
+	// Not persistent: scan, events and diagnose define their own -A
+	rootCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "open the TUI on the pods of all namespaces")
+	rootCmd.Flags().BoolVar(&interactiveLite, "interactive-lite", false, "instead of the TUI, pick a namespace and pod from numbered lists and print its diagnosis, for limited terminals and screen readers")
+
+	rootCmd.PersistentFlags().StringVar(&opts.ConfigPath, "config", os.Getenv(config.EnvName("config")), "config file with flag defaults (default: ~/"+config.DefaultFileName+")")
+	rootCmd.PersistentFlags().StringVar(&opts.KubeconfigPath, "kubeconfig", "", "path to kubeconfig file (default: the files in $KUBECONFIG, or ~/.kube/config)")
+	rootCmd.PersistentFlags().StringVar(&opts.Context, "context", "", "kubeconfig context to use (default: the current context)")
+	rootCmd.PersistentFlags().Float32Var(&opts.QPS, "qps", 0, "maximum queries per second to the API server (default: client-go's 5)")
+	rootCmd.PersistentFlags().IntVar(&opts.Burst, "burst", 0, "maximum burst of queries to the API server above --qps (default: client-go's 10)")
+	rootCmd.PersistentFlags().IntVar(&opts.MaxRetries, "max-retries", kubernetes.DefaultMaxRetries, "times to retry an API read that was throttled (429) or failed transiently, 0 to never retry")
+	rootCmd.PersistentFlags().DurationVar(&opts.RetryBackoff, "retry-backoff", kubernetes.DefaultRetryBackoff, "wait before the first retry of an API read, doubled with jitter before each next one")
+	rootCmd.PersistentFlags().StringVarP(&opts.Namespace, "namespace", "n", "default", "kubernetes namespace")
+	rootCmd.PersistentFlags().StringVarP(&opts.OutputFormat, "output", "o", "console", "output format (console, json, yaml, sarif; scan also takes wide)")
+	rootCmd.PersistentFlags().BoolVar(&opts.Wide, "wide", false, "do not wrap long lines in console output")
+	rootCmd.PersistentFlags().BoolVar(&opts.ExpandEvents, "expand-events", false, "list every warning event in a diagnosis instead of grouping them by reason")
+	rootCmd.PersistentFlags().BoolVar(&opts.TextIndicators, "text-indicators", false, "show text labels like [OK]/[WARN]/[CRIT] instead of colored icons")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.RulePacks, "rules", nil, "rule pack file or URL to load (repeatable)")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.Analyzers.Enabled, "analyzers", nil, "analyzers to run (default: all): "+strings.Join(analyzer.AnalyzerNames(), ","))
+	rootCmd.PersistentFlags().StringSliceVar(&opts.Analyzers.Disabled, "skip-analyzers", nil, "analyzers to skip")
+	rootCmd.PersistentFlags().Int64Var(&opts.Analyzers.Logs.TailLines, "log-tail-lines", analyzer.DefaultConfig().Logs.TailLines, "number of recent log lines the logs analyzer scans per container")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.Analyzers.Logs.PatternFiles, "log-pattern-file", nil, "file of custom log patterns (regex, title, severity, description) to add (repeatable)")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.Analyzers.Logs.DisabledPatterns, "disable-log-pattern", nil, "title of a built-in log pattern to disable, e.g. \"Process killed\" (repeatable)")
+	rootCmd.PersistentFlags().Int32Var(&opts.Analyzers.Status.RestartThreshold, "restart-threshold", analyzer.DefaultConfig().Status.RestartThreshold, "restart count above which the status analyzer flags a container")
+	rootCmd.PersistentFlags().DurationVar(&opts.Analyzers.Status.InitTimeout, "init-timeout", analyzer.DefaultConfig().Status.InitTimeout, "how long an init container may run before it is reported stuck, unless the pod's "+analyzer.InitExpectedDurationAnnotation+" annotation says otherwise")
+	rootCmd.PersistentFlags().StringVar(&opts.Analyzers.Workload.ProductionSelector, "production-namespace-selector", analyzer.DefaultConfig().Workload.ProductionSelector, "label selector for production namespaces, where an unhealthy single-replica deployment is reported as critical")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.Analyzers.Conformance.RequiredLabels, "require-labels", nil, "labels the conformance analyzer requires on pods and their workloads, e.g. owner,app.kubernetes.io/*")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.Analyzers.Conformance.RequiredAnnotations, "require-annotations", nil, "annotations the conformance analyzer requires on pods and their workloads")
+	rootCmd.PersistentFlags().StringVar(&opts.Analyzers.Conformance.Severity, "conformance-severity", analyzer.DefaultConfig().Conformance.Severity, "severity of missing required labels and annotations: info or warning")
+	rootCmd.PersistentFlags().BoolVar(&opts.Analyzers.Plugins.Enabled, "plugins", false, "run analyzer plugins ("+analyzer.PluginPrefix+"* executables on PATH)")
+	rootCmd.PersistentFlags().DurationVar(&opts.Analyzers.Timeout, "analyzer-timeout", analyzer.DefaultConfig().Timeout, "time limit for each analyzer in a diagnosis; one that overruns it is skipped with a warning")
+	rootCmd.PersistentFlags().DurationVar(&opts.Analyzers.Plugins.Timeout, "plugin-timeout", analyzer.DefaultConfig().Plugins.Timeout, "time limit for each analyzer plugin run")
+	rootCmd.PersistentFlags().StringVar(&opts.TrustPolicy, "trust-policy", "", "trust policy file with trusted registries and cosign keys for image provenance checks")
+
+	rootCmd.PersistentFlags().StringVar(&opts.RecordDir, "record", "", "save every API response to this directory, for replaying the run with --replay")
+	rootCmd.PersistentFlags().StringVar(&opts.ReplayDir, "replay", "", "run offline against API responses recorded with --record in this directory")
+
+	rootCmd.PersistentFlags().StringVar(&opts.HistoryDir, "history-dir", history.DefaultDir(), "directory where diagnoses are recorded for the history and diff commands")
+	rootCmd.PersistentFlags().BoolVar(&opts.NoHistory, "no-history", false, "do not record diagnoses in the history")
+
+	rootCmd.PersistentFlags().BoolVar(&opts.Verbose, "verbose", false, "log analyzer runs and the errors that leave a diagnosis incomplete to stderr; with diagnose, also show how long each analyzer took")
+	rootCmd.PersistentFlags().BoolVar(&opts.Debug, "debug", false, "log every API call too, implies --verbose")
+	rootCmd.PersistentFlags().StringVar(&opts.LogFile, "log-file", "", "write the --verbose or --debug log to this file instead of stderr (implies --verbose)")
+
+	rootCmd.AddCommand(newDiagnoseCommand(opts))
+	rootCmd.AddCommand(newScanCommand(opts))
+	rootCmd.AddCommand(newTopCommand(opts))
+	rootCmd.AddCommand(newEventsCommand(opts))
+	rootCmd.AddCommand(newDebugCommand(opts))
+	rootCmd.AddCommand(newCheckAccessCommand(opts))
+	rootCmd.AddCommand(newHistoryCommand(opts))
+	rootCmd.AddCommand(newDiffCommand(opts))
+	rootCmd.AddCommand(newCompareCommand(opts))
+	rootCmd.AddCommand(newReportCommand(opts))
+	rootCmd.AddCommand(newServeCommand(opts))
+	rootCmd.AddCommand(newRulesCommand())
+	rootCmd.AddCommand(newSchemaCommand())
+	rootCmd.AddCommand(newExplainCommand(opts))
+	rootCmd.AddCommand(newAckCommand(opts))
+	rootCmd.AddCommand(newBenchCommand(opts))
+	rootCmd.AddCommand(newBundleCommand(opts))
+	rootCmd.AddCommand(newVersionCommand())
+
+	return rootCmd
 }
 
+// preflightTimeout bounds the initial connectivity check against the cluster
+const preflightTimeout = 5 * time.Second
+
 // Execute runs the root command
 func Execute() {
+	rootCmd := NewRootCommand()
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		var status *exitStatus
+		if errors.As(err, &status) {
+			os.Exit(status.code)
+		}
+
+		output.PrintError(rootCmd.ErrOrStderr(), err.Error())
+
+		for _, hint := range kubernetes.ErrorHints(err) {
+			output.PrintInfo(rootCmd.ErrOrStderr(), "  • "+hint)
+		}
+		os.Exit(ExitError)
 	}
 }
 
-func init() {
-	rootCmd.PersistentFlags().StringVar(&kubeconfigPath, "kubeconfig", "", "path to kubeconfig file (default: ~/.kube/config)")
-	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default", "kubernetes namespace")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "console", "output format (console, json, yaml)")
+// newClient creates a Kubernetes client and verifies the cluster is
+// reachable, so commands fail fast instead of timing out one call at a time
+func newClient(cmd *cobra.Command, opts *Options) (*kubernetes.Client, error) {
+	var client *kubernetes.Client
+	var err error
+	switch {
+	case opts.MaxRetries > 0 && opts.RetryBackoff <= 0:
+		return nil, fmt.Errorf("--retry-backoff must be positive when --max-retries is above 0")
+	case opts.RecordDir != "" && opts.ReplayDir != "":
+		return nil, fmt.Errorf("--record and --replay cannot be used together")
+	case opts.ReplayDir != "":
+		client, err = kubernetes.NewReplayClient(opts.ReplayDir)
+	case opts.RecordDir != "":
+		client, err = kubernetes.NewRecordingClient(opts.KubeconfigPath, opts.Context, opts.rateLimit(), opts.RecordDir)
+	default:
+		client, err = kubernetes.NewClient(opts.KubeconfigPath, opts.Context, opts.rateLimit())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), preflightTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// rateLimit returns the client-side rate limit and retries set by --qps,
+// --burst, --max-retries and --retry-backoff
+func (o *Options) rateLimit() kubernetes.RateLimit {
+	return kubernetes.RateLimit{QPS: o.QPS, Burst: o.Burst, MaxRetries: o.MaxRetries, RetryBackoff: o.RetryBackoff}
+}
+
+// addIssueFilterFlags adds --min-severity and --category, which diagnose
+// and scan share
+func (o *Options) addIssueFilterFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.MinSeverity, "min-severity", "", "only report issues at least this severe: critical, warning or info (default: all)")
+	flags.StringSliceVar(&o.Categories, "category", nil, "only report issues of these categories, e.g. resources,probes (default: all)")
+}
+
+// issueFilter returns the issues --min-severity and --category select
+func (o *Options) issueFilter() (domain.IssueFilter, error) {
+	f := domain.IssueFilter{Categories: o.Categories}
+	if o.MinSeverity != "" {
+		severity, err := domain.ParseSeverity(o.MinSeverity)
+		if err != nil {
+			return f, fmt.Errorf("invalid --min-severity: %w", err)
+		}
+		f.MinSeverity = severity
+	}
+	return f, nil
+}
+
+// checkTUILogging refuses to log to stderr under the TUI, whose screen
+// the log would garble
+func (o *Options) checkTUILogging() error {
+	if (o.Verbose || o.Debug) && o.LogFile == "" {
+		return fmt.Errorf("--verbose and --debug need --log-file with the TUI")
+	}
+	return nil
+}
+
+// tuiConnector returns how the TUI connects to another kubeconfig context,
+// with the same flags otherwise, or nil when replaying or recording a
+// session, which are tied to one cluster
+func tuiConnector(cmd *cobra.Command, opts *Options) tui.Connector {
+	if opts.ReplayDir != "" || opts.RecordDir != "" {
+		return nil
+	}
+	return func(contextName string) (*kubernetes.Client, *analyzer.PodAnalyzer, error) {
+		switched := *opts
+		switched.Context = contextName
+		client, err := newClient(cmd, &switched)
+		if err != nil {
+			return nil, nil, err
+		}
+		podAnalyzer, err := newPodAnalyzer(&switched, client)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, podAnalyzer, nil
+	}
+}
+
+// lookupFlag returns the value a flag will have once the config is applied:
+// as set on the command line, else from its environment variable or the
+// config file, else its current value
+func lookupFlag(cmd *cobra.Command, file *config.File, name, value string) string {
+	if cmd.Flags().Changed(name) {
+		return value
+	}
+	if v, ok := os.LookupEnv(config.EnvName(name)); ok {
+		return v
+	}
+	if v, ok := file.Lookup(cmd.Name(), name); ok {
+		return v
+	}
+	return value
+}
+
+// applyConfig fills in flags that were not set on the command line, first
+// from POD_DOCTOR_* environment variables and then from the config file,
+// where the section of the current kube context wins over the rest
+func applyConfig(cmd *cobra.Command, opts *Options) error {
+	file, err := config.Load(opts.ConfigPath)
+	if err != nil {
+		return err
+	}
+	for _, key := range file.UnknownKeys(func(command, flag string) bool { return hasFlag(cmd.Root(), command, flag) }) {
+		output.PrintWarning(cmd.ErrOrStderr(), fmt.Sprintf("Ignoring unknown key %s in %s", key, file.Path))
+	}
+
+	// The kubeconfig and --context decide the context, so resolve them
+	// before the others
+	kubeconfig := lookupFlag(cmd, file, "kubeconfig", opts.KubeconfigPath)
+	if opts.ReplayDir == "" {
+		contextName := lookupFlag(cmd, file, "context", opts.Context)
+		if contextName == "" {
+			contextName = kubernetes.CurrentContext(kubeconfig)
+		}
+		file.UseContext(contextName)
+	}
+	opts.Suppressions = file.Suppressions()
+
+	opts.commandLine = make(map[string]bool)
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		opts.commandLine[flag.Name] = true
+	})
+
+	var applyErr error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if applyErr != nil || flag.Changed || flag.Name == "config" {
+			return
+		}
+
+		value, ok := os.LookupEnv(config.EnvName(flag.Name))
+		source := config.EnvName(flag.Name)
+		if !ok {
+			value, ok = file.Lookup(cmd.Name(), flag.Name)
+			source = file.Path
+		}
+		if !ok {
+			return
+		}
+
+		if err := cmd.Flags().Set(flag.Name, value); err != nil {
+			applyErr = fmt.Errorf("invalid value %q for --%s from %s: %w", value, flag.Name, source, err)
+		}
+	})
+	return applyErr
+}
+
+// hasFlag reports whether a command named command, or any command when it
+// is empty, in the tree under root has the flag
+func hasFlag(root *cobra.Command, command, flag string) bool {
+	if command == "" || root.Name() == command {
+		for _, flags := range []*pflag.FlagSet{root.Flags(), root.PersistentFlags(), root.InheritedFlags()} {
+			if flags.Lookup(flag) != nil {
+				return true
+			}
+		}
+	}
+	for _, child := range root.Commands() {
+		if hasFlag(child, command, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// newPodAnalyzer creates a pod analyzer running the analyzers selected by
+// --analyzers and --skip-analyzers, with the rule packs from --rules and the
+// --trust-policy loaded, reporting the issues --min-severity and --category
+// select
+func newPodAnalyzer(opts *Options, client *kubernetes.Client) (*analyzer.PodAnalyzer, error) {
+	podAnalyzer, err := analyzer.NewPodAnalyzerWithConfig(client, opts.Analyzers)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range opts.RulePacks {
+		pack, err := rules.Load(source)
+		if err != nil {
+			return nil, err
+		}
+		if err := podAnalyzer.UseRules(pack); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(opts.Suppressions) > 0 {
+		if err := podAnalyzer.UseRules(&rules.Pack{Name: "config", Suppressions: opts.Suppressions}); err != nil {
+			return nil, err
+		}
+	}
+
+	filter, err := opts.issueFilter()
+	if err != nil {
+		return nil, err
+	}
+	podAnalyzer.UseIssueFilter(filter)
+
+	if opts.TrustPolicy != "" {
+		policy, err := provenance.LoadPolicy(opts.TrustPolicy)
+		if err != nil {
+			return nil, err
+		}
+		podAnalyzer.UseTrustPolicy(policy)
+	}
+
+	return podAnalyzer, nil
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/pavanInnamuri/pod-doctor/internal/rules"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newRulesCommand() *cobra.Command {
+	rulesCmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Manage analyzer rule packs",
+		Long: `Manage analyzer rule packs.
+
+A rule pack is a YAML bundle of custom log patterns, severity remaps,
+suppressions and recommendations that can be versioned and shared
+between teams. Load packs with --rules on any command.
+
+Examples:
+  # Export the built-in log patterns as a starting point
+  pod-doctor rules export --name platform-rules > platform-rules.yaml
+
+  # Check a rule pack before sharing it
+  pod-doctor rules validate ./platform-rules.yaml
+
+  # Diagnose using a shared rule pack
+  pod-doctor diagnose my-pod --rules https://example.com/platform-rules.yaml`,
+	}
+
+	rulesCmd.AddCommand(newRulesExportCommand())
+	rulesCmd.AddCommand(newRulesValidateCommand())
+
+	return rulesCmd
+}
+
+func newRulesExportCommand() *cobra.Command {
+	var name, file string
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the built-in log patterns as a rule pack",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pack := rules.Pack{
+				Name:        name,
+				Version:     "1.0.0",
+				LogPatterns: analyzer.DefaultLogPatterns(),
+			}
+
+			data, err := yaml.Marshal(pack)
+			if err != nil {
+				return fmt.Errorf("failed to marshal YAML: %w", err)
+			}
+
+			if file == "" {
+				_, err = cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := os.WriteFile(file, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file, err)
+			}
+			output.PrintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Exported %d log patterns to %s", len(pack.LogPatterns), file))
+			return nil
+		},
+	}
+
+	exportCmd.Flags().StringVar(&name, "name", "default-rules", "name of the exported rule pack")
+	exportCmd.Flags().StringVarP(&file, "file", "f", "", "write the rule pack to a file instead of stdout")
+
+	return exportCmd
+}
+
+func newRulesValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate <file-or-url>",
+		Short: "Validate a rule pack",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pack, err := rules.Load(args[0])
+			if err != nil {
+				return err
+			}
+			output.PrintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Rule pack %s %s is valid: %d log patterns, %d severity remaps, %d suppressions, %d recommendations",
+				pack.Name, pack.Version, len(pack.LogPatterns), len(pack.SeverityRemaps), len(pack.Suppressions), len(pack.Recommendations)))
+			return nil
+		},
+	}
+}
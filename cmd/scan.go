@@ -3,30 +3,56 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
 	"github.com/pavanInnamuri/pod-doctor/internal/domain"
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
 	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/pavanInnamuri/pod-doctor/internal/webui"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-var (
+// scanOptions holds the flags for the scan command
+type scanOptions struct {
+	*Options
 	allNamespaces bool
 	onlyUnhealthy bool
 	labelSelector string
 	concurrency   int
-)
+	watch         bool
+	failFast      bool
+	exportFixes   string
+	sortBy        string
+	limit         int
+	contexts      []string
+	serveReport   string
+
+	samplePerWorkload int
+	slo               analyzer.SLOThresholds
+	notify            notifyOptions
+}
 
-var scanCmd = &cobra.Command{
-	Use:   "scan",
-	Short: "Scan pods for issues",
-	Long: `Scan multiple pods for issues.
+func newScanCommand(opts *Options) *cobra.Command {
+	scanOpts := &scanOptions{Options: opts}
+	defaultSLO := analyzer.DefaultSLOThresholds()
+
+	scanCmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan pods for issues",
+		Long: `Scan multiple pods for issues.
 
 By default, scans all pods in the specified namespace and shows
 a summary of healthy/unhealthy pods.
@@ -45,68 +71,194 @@ Examples:
   pod-doctor scan --unhealthy
 
   # Filter by label selector
-  pod-doctor scan -l app=nginx`,
-	Run: runScan,
+  pod-doctor scan -l app=nginx
+
+  # Diagnose at most 2 healthy-looking replicas per workload
+  pod-doctor scan -A --sample-per-workload 2
+
+  # Keep watching during a rollout and show a live summary
+  pod-doctor scan -n production --watch
+
+  # Watch with a stricter restart budget and flag crash loops after 5 minutes
+  pod-doctor scan -n production --watch --slo-restarts-per-hour 2 --slo-max-crashloop 5m
+
+  # Post to Slack when a pod becomes unhealthy during a rollout
+  pod-doctor scan -n production --watch --notify-slack https://hooks.slack.com/services/...
+
+  # Write the recommended commands for every unhealthy pod to a script
+  pod-doctor scan -n production --export-fixes fixes.sh
+
+  # The 10 pods with the most restarts, with their node and age
+  pod-doctor scan -A --sort-by restarts --limit 10 -o wide
+
+  # Scan two clusters at once, each with the settings of its context in the
+  # config file, and compare how long each took
+  pod-doctor scan --contexts prod-eu,prod-us
+
+  # Fail a pipeline step as soon as any pod has a critical issue
+  pod-doctor scan -n production --fail-fast
+
+  # Review the results in a browser at http://localhost:8080
+  pod-doctor scan -A --serve-report localhost:8080
+
+Exit codes: 0 all pods healthy, 1 warnings found, 2 critical issues found,
+3 the scan failed, or some pods could not be diagnosed and nothing critical
+was found. Pods disrupted by chaos experiments are ignored.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScan(cmd, scanOpts)
+		},
+	}
+
+	scanCmd.Flags().BoolVarP(&scanOpts.allNamespaces, "all-namespaces", "A", false, "scan all namespaces")
+	scanCmd.Flags().BoolVar(&scanOpts.onlyUnhealthy, "unhealthy", false, "only show unhealthy pods")
+	scanCmd.Flags().StringVarP(&scanOpts.labelSelector, "selector", "l", "", "label selector to filter pods")
+	scanCmd.Flags().IntVar(&scanOpts.concurrency, "concurrency", 5, "number of concurrent diagnoses")
+	scanCmd.Flags().BoolVarP(&scanOpts.watch, "watch", "w", false, "keep watching pods and re-diagnose them as their status changes")
+	scanCmd.Flags().IntVar(&scanOpts.slo.RestartsPerHour, "slo-restarts-per-hour", defaultSLO.RestartsPerHour, "with --watch, flag workloads with more container restarts per hour (0 = off)")
+	scanCmd.Flags().IntVar(&scanOpts.slo.ReadinessFlapsPerDay, "slo-flaps-per-day", defaultSLO.ReadinessFlapsPerDay, "with --watch, flag workloads whose pods lose readiness more often per day (0 = off)")
+	scanCmd.Flags().DurationVar(&scanOpts.slo.MaxCrashLoop, "slo-max-crashloop", defaultSLO.MaxCrashLoop, "with --watch, flag workloads with a pod in CrashLoopBackOff for longer (0 = off)")
+	scanCmd.Flags().StringVar(&scanOpts.exportFixes, "export-fixes", "", "write the recommended commands to a shell script that asks before running each one")
+	scanCmd.Flags().StringVar(&scanOpts.sortBy, "sort-by", "score", "sort the table of unhealthy pods by: "+strings.Join(output.ScanSortColumns, ", "))
+	scanCmd.Flags().IntVar(&scanOpts.limit, "limit", 0, "show at most N unhealthy pods in the table (0 = all)")
+	scanCmd.Flags().BoolVar(&scanOpts.failFast, "fail-fast", false, "stop scanning at the first pod with a critical issue")
+	scanCmd.Flags().StringSliceVar(&scanOpts.contexts, "contexts", nil, "scan the clusters of these kubeconfig contexts at once, each with its own settings from the config file's contexts section")
+	scanCmd.Flags().IntVar(&scanOpts.samplePerWorkload, "sample-per-workload", 0, "only diagnose N representative pods per owning workload, plus any pod that looks unhealthy (0 = all)")
+	scanCmd.Flags().StringVar(&scanOpts.serveReport, "serve-report", "", "after scanning, serve the results as an interactive web page on this address, e.g. localhost:8080, until interrupted; an address without a host listens on localhost only")
+	scanOpts.notify.addFlags(scanCmd.Flags())
+	opts.addIssueFilterFlags(scanCmd.Flags())
+
+	return scanCmd
 }
 
-func init() {
-	scanCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "scan all namespaces")
-	scanCmd.Flags().BoolVar(&onlyUnhealthy, "unhealthy", false, "only show unhealthy pods")
-	scanCmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "label selector to filter pods")
-	scanCmd.Flags().IntVar(&concurrency, "concurrency", 5, "number of concurrent diagnoses")
-	rootCmd.AddCommand(scanCmd)
+// scanTable returns how the console summary tables unhealthy pods
+func (o *scanOptions) scanTable() output.ScanTable {
+	return output.ScanTable{SortBy: o.sortBy, Limit: o.limit, Wide: o.OutputFormat == "wide"}
 }
 
-func runScan(cmd *cobra.Command, args []string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
+func runScan(cmd *cobra.Command, opts *scanOptions) error {
+	out := cmd.OutOrStdout()
+	if !output.IsScanSortColumn(opts.sortBy) {
+		return fmt.Errorf("invalid --sort-by %q (available: %s)", opts.sortBy, strings.Join(output.ScanSortColumns, ", "))
+	}
+	if opts.limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	if opts.serveReport != "" && (opts.watch || len(opts.contexts) > 0) {
+		return fmt.Errorf("--serve-report cannot be used with --watch or --contexts")
+	}
+	if len(opts.contexts) > 0 {
+		return runMultiContextScan(cmd, opts)
+	}
+	// -o wide is console output with node and age columns
+	console := opts.OutputFormat == "console" || opts.OutputFormat == "wide"
 
 	// Create Kubernetes client
-	client, err := kubernetes.NewClient(kubeconfigPath)
+	client, err := newClient(cmd, opts.Options)
 	if err != nil {
-		output.PrintError(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
-		os.Exit(1)
+		return err
 	}
 
-	// Get pods
-	var pods []podRef
-	if allNamespaces {
-		podList, err := client.ListAllPods(ctx)
-		if err != nil {
-			output.PrintError(fmt.Sprintf("Failed to list pods: %v", err))
-			os.Exit(1)
+	if opts.watch {
+		if opts.OutputFormat == "yaml" || opts.OutputFormat == "sarif" {
+			return fmt.Errorf("--watch supports console and json output only")
 		}
-		for _, pod := range podList.Items {
-			pods = append(pods, podRef{namespace: pod.Namespace, name: pod.Name})
+		if opts.failFast {
+			return fmt.Errorf("--fail-fast cannot be used with --watch")
 		}
-	} else {
-		podList, err := client.ListPods(ctx, namespace, labelSelector)
-		if err != nil {
-			output.PrintError(fmt.Sprintf("Failed to list pods: %v", err))
-			os.Exit(1)
-		}
-		for _, pod := range podList.Items {
-			pods = append(pods, podRef{namespace: pod.Namespace, name: pod.Name})
+		if opts.exportFixes != "" {
+			return fmt.Errorf("--export-fixes cannot be used with --watch")
 		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return runWatch(ctx, opts, client, out, cmd.ErrOrStderr())
 	}
-
-	if len(pods) == 0 {
-		output.PrintInfo("No pods found")
-		return
+	if opts.notify.enabled() {
+		return fmt.Errorf("--notify-slack and --notify-webhook need --watch")
 	}
 
-	if outputFormat == "console" {
-		fmt.Printf("Scanning %d pods...\n", len(pods))
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+	// With --fail-fast, the first critical diagnosis cancels listing and
+	// the diagnoses still running
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	namespace := opts.Namespace
+	if opts.allNamespaces {
+		namespace = metav1.NamespaceAll
 	}
+	// Pods, events and nodes are listed once for the whole scan, instead
+	// of fetched again for every pod
+	client = client.WithSnapshot(ctx, namespace)
 
 	// Create analyzer
-	podAnalyzer := analyzer.NewPodAnalyzer(client)
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+
+	if console {
+		fmt.Fprintln(out, "Scanning pods...")
+	}
+
+	// Diagnose pods concurrently as each page of the pod list arrives
+	var (
+		firstCritical *domain.Diagnosis
+		criticalOnce  sync.Once
+		hooks         scanHooks
+	)
+	if opts.failFast {
+		hooks.onDiagnosis = func(d *domain.Diagnosis) {
+			if d.HasCriticalIssues() && !d.IsExpectedFailure() {
+				criticalOnce.Do(func() {
+					firstCritical = d
+					stop()
+				})
+			}
+		}
+	}
+	if console && term.IsTerminal(int(os.Stderr.Fd())) {
+		hooks.progress = output.NewProgress(cmd.ErrOrStderr(), "pods")
+	}
+
+	pods, stream := streamPods(ctx, client, namespace, opts.labelSelector, opts.samplePerWorkload)
+	diagnoses, failures := scanPodStream(ctx, podAnalyzer, pods, opts.concurrency, hooks)
+	if hooks.progress != nil {
+		hooks.progress.Finish()
+	}
+	if stream.err != nil && firstCritical == nil {
+		return stream.err
+	}
+
+	if stream.listed == 0 {
+		output.PrintInfo(out, "No pods found")
+		if len(stream.namespaces) > 0 {
+			if err := printSkippedNamespaces(cmd, opts.OutputFormat, stream.namespaces); err != nil {
+				return err
+			}
+			return &exitStatus{code: ExitError}
+		}
+		return nil
+	}
+	if console && firstCritical != nil {
+		fmt.Fprintf(out, "Stopped at the first critical issue (%s/%s), %d pods diagnosed\n",
+			firstCritical.Pod.Namespace, firstCritical.Pod.Name, len(diagnoses))
+	} else if console && stream.skipped > 0 {
+		fmt.Fprintf(out, "Scanned %d of %d pods (%d similar replicas skipped, sampling %d per workload)\n",
+			stream.listed-stream.skipped, stream.listed, stream.skipped, opts.samplePerWorkload)
+	}
 
-	// Scan pods concurrently
-	diagnoses := scanPods(ctx, podAnalyzer, pods)
+	// The exit code reflects every scanned pod, even those filtered out
+	// below. A scan that missed pods or namespaces is an error unless it
+	// already found something critical.
+	findings := findingsError(diagnoses)
+	incomplete := len(failures) > 0 || len(stream.namespaces) > 0
+	if incomplete && findingsExitCode(diagnoses) != ExitCritical {
+		findings = &exitStatus{code: ExitError}
+	}
 
 	// Filter if only unhealthy
-	if onlyUnhealthy {
+	if opts.onlyUnhealthy {
 		var filtered []*domain.Diagnosis
 		for _, d := range diagnoses {
 			if !d.IsHealthy() {
@@ -115,26 +267,211 @@ func runScan(cmd *cobra.Command, args []string) {
 		}
 		diagnoses = filtered
 	}
+	if err := exportFixes(cmd, opts.exportFixes, diagnoses...); err != nil {
+		return err
+	}
 
 	// Output results
-	switch outputFormat {
+	switch opts.OutputFormat {
+	case "json":
+		data, err := json.MarshalIndent(newScanResult(diagnoses), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(newScanResult(diagnoses))
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "sarif":
+		if err := output.WriteSARIF(out, diagnoses, Version, skippedNamespaceNotes(stream.namespaces)...); err != nil {
+			return fmt.Errorf("failed to write SARIF: %w", err)
+		}
+	default:
+		output.PrintScanSummary(out, diagnoses, opts.scanTable())
+		output.PrintDependencyFailures(out, analyzer.FindFailingDependencies(diagnoses, 2))
+	}
+
+	// Structured output stays parseable; failures go to stderr instead
+	failuresOut := cmd.ErrOrStderr()
+	if console {
+		failuresOut = out
+	}
+	output.PrintScanFailures(failuresOut, describeScanFailures(failures), len(diagnoses)+len(failures))
+	if opts.OutputFormat != "sarif" {
+		if err := printSkippedNamespaces(cmd, opts.OutputFormat, stream.namespaces); err != nil {
+			return err
+		}
+	}
+
+	if opts.serveReport != "" {
+		if err := serveScanReport(cmd, opts, diagnoses, stream.namespaces); err != nil {
+			return err
+		}
+	}
+	return findings
+}
+
+// scanResult is the structured output of a scan
+type scanResult struct {
+	APIVersion string              `json:"apiVersion" yaml:"apiVersion"`
+	Diagnoses  []*domain.Diagnosis `json:"diagnoses" yaml:"diagnoses"`
+	// Dependencies several pods fail to reach; their per-pod log issues
+	// are left out of the diagnoses
+	Dependencies []domain.DependencyFailure `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// newScanResult aggregates the dependencies several pods fail to reach and
+// collapses the log issues they cover
+func newScanResult(diagnoses []*domain.Diagnosis) scanResult {
+	dependencies := analyzer.FindFailingDependencies(diagnoses, 2)
+	return scanResult{
+		APIVersion:   domain.APIVersion,
+		Diagnoses:    analyzer.CollapseDependencyIssues(diagnoses, dependencies),
+		Dependencies: dependencies,
+	}
+}
+
+// serveScanReport serves the results of a scan as a web page until
+// interrupted
+func serveScanReport(cmd *cobra.Command, opts *scanOptions, diagnoses []*domain.Diagnosis, skipped []kubernetes.SkippedNamespace) error {
+	scope := "namespace " + opts.Namespace
+	if opts.allNamespaces {
+		scope = "all namespaces"
+	}
+	report := &webui.Report{
+		APIVersion:   domain.APIVersion,
+		Title:        "pod-doctor scan: " + scope,
+		GeneratedAt:  time.Now(),
+		Diagnoses:    diagnoses,
+		Dependencies: analyzer.FindFailingDependencies(diagnoses, 2),
+		Skipped:      skipped,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return webui.Serve(ctx, opts.serveReport, report, func(url string) {
+		message := fmt.Sprintf("Serving the scan results on %s, press Ctrl+C to stop", url)
+		if opts.OutputFormat == "console" || opts.OutputFormat == "wide" {
+			output.PrintInfo(cmd.OutOrStdout(), message)
+		} else {
+			// Keep structured output on stdout parseable
+			fmt.Fprintln(cmd.ErrOrStderr(), message)
+		}
+	})
+}
+
+// printSkippedNamespaces reports the namespaces an all-namespaces scan was
+// denied: as a section after console output, or, to keep stdout parseable,
+// as a {"skippedNamespaces": [...]} document on stderr for json and yaml
+func printSkippedNamespaces(cmd *cobra.Command, format string, skipped []kubernetes.SkippedNamespace) error {
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	doc := struct {
+		SkippedNamespaces []kubernetes.SkippedNamespace `json:"skippedNamespaces" yaml:"skippedNamespaces"`
+	}{skipped}
+	switch format {
 	case "json":
-		data, err := json.MarshalIndent(diagnoses, "", "  ")
+		data, err := json.MarshalIndent(doc, "", "  ")
 		if err != nil {
-			output.PrintError(fmt.Sprintf("Failed to marshal JSON: %v", err))
-			os.Exit(1)
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(cmd.ErrOrStderr(), string(data))
 	case "yaml":
-		data, err := yaml.Marshal(diagnoses)
+		data, err := yaml.Marshal(doc)
 		if err != nil {
-			output.PrintError(fmt.Sprintf("Failed to marshal YAML: %v", err))
-			os.Exit(1)
+			return fmt.Errorf("failed to marshal YAML: %w", err)
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(cmd.ErrOrStderr(), string(data))
 	default:
-		output.PrintScanSummary(diagnoses)
+		output.PrintSkippedNamespaces(cmd.OutOrStdout(), skipped)
+	}
+	return nil
+}
+
+// skippedNamespaceNotes describes denied namespaces for SARIF's tool
+// execution notifications
+func skippedNamespaceNotes(skipped []kubernetes.SkippedNamespace) []string {
+	if len(skipped) == 0 {
+		return nil
 	}
+	return []string{fmt.Sprintf("skipped %d namespaces due to permissions: [%s]", len(skipped), skippedNamespaceNames(skipped))}
+}
+
+// skippedNamespaceNames lists denied namespaces, comma separated
+func skippedNamespaceNames(skipped []kubernetes.SkippedNamespace) string {
+	names := make([]string, len(skipped))
+	for i, s := range skipped {
+		names[i] = s.Namespace
+	}
+	return strings.Join(names, ", ")
+}
+
+// listPods lists the pods in a namespace, or across all namespaces, where
+// namespaces the user may not list are skipped and returned. Errors are
+// kubernetes.APIErrors that already describe the failed request.
+func listPods(ctx context.Context, client *kubernetes.Client, allNamespaces bool, namespace, labelSelector string) ([]corev1.Pod, []kubernetes.SkippedNamespace, error) {
+	if !allNamespaces {
+		podList, err := client.ListPods(ctx, namespace, labelSelector)
+		if err != nil {
+			return nil, nil, err
+		}
+		return podList.Items, nil, nil
+	}
+
+	var pods []corev1.Pod
+	skipped, err := client.ListPermittedPodPages(ctx, labelSelector, func(page []corev1.Pod) error {
+		pods = append(pods, page...)
+		return nil
+	})
+	return pods, skipped, err
+}
+
+// podStreamResult describes a finished pod stream. It is safe to read once
+// the stream's channel is closed.
+type podStreamResult struct {
+	listed     int
+	skipped    int
+	namespaces []kubernetes.SkippedNamespace // denied in an all-namespaces scan
+	err        error
+}
+
+// streamPods lists pods page by page and sends each one that survives
+// sampling on the returned channel, which is closed when listing ends
+func streamPods(ctx context.Context, client *kubernetes.Client, namespace, labelSelector string, samplePerWorkload int) (<-chan podRef, *podStreamResult) {
+	pods := make(chan podRef)
+	result := &podStreamResult{}
+	sampler := newPodSampler(samplePerWorkload)
+
+	go func() {
+		defer close(pods)
+		send := func(page []corev1.Pod) error {
+			for _, pod := range page {
+				result.listed++
+				if !sampler.keep(pod) {
+					result.skipped++
+					continue
+				}
+				select {
+				case pods <- podRef{namespace: pod.Namespace, name: pod.Name}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		}
+		if namespace == metav1.NamespaceAll {
+			result.namespaces, result.err = client.ListPermittedPodPages(ctx, labelSelector, send)
+			return
+		}
+		result.err = client.ListPodPages(ctx, namespace, labelSelector, send)
+	}()
+
+	return pods, result
 }
 
 type podRef struct {
@@ -142,34 +479,172 @@ type podRef struct {
 	name      string
 }
 
-func scanPods(ctx context.Context, podAnalyzer *analyzer.PodAnalyzer, pods []podRef) []*domain.Diagnosis {
+// scanFailure is a pod that could not be diagnosed
+type scanFailure struct {
+	ref podRef
+	err error
+}
+
+// scanHooks observe a scan while it runs. Unset hooks are skipped.
+type scanHooks struct {
+	progress    *output.Progress        // counts queued and finished pods
+	onDiagnosis func(*domain.Diagnosis) // called as each diagnosis completes
+}
+
+// scanPods diagnoses a fixed list of pods concurrently
+func scanPods(ctx context.Context, podAnalyzer *analyzer.PodAnalyzer, pods []podRef, concurrency int) ([]*domain.Diagnosis, []scanFailure) {
+	refs := make(chan podRef)
+	go func() {
+		defer close(refs)
+		for _, pod := range pods {
+			refs <- pod
+		}
+	}()
+	return scanPodStream(ctx, podAnalyzer, refs, concurrency, scanHooks{})
+}
+
+// scanPodStream diagnoses pods concurrently as they arrive on the channel
+// and returns once it is closed and every diagnosis has finished, along with
+// the pods that failed to diagnose. Pods abandoned because ctx was canceled
+// are not failures. Nodes are fetched once for the whole run, unless the
+// analyzer's client already caches them.
+func scanPodStream(ctx context.Context, podAnalyzer *analyzer.PodAnalyzer, pods <-chan podRef, concurrency int, hooks scanHooks) ([]*domain.Diagnosis, []scanFailure) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	podAnalyzer = podAnalyzer.WithNodeCache(ctx)
+
 	var (
 		diagnoses []*domain.Diagnosis
+		failures  []scanFailure
 		mu        sync.Mutex
 		wg        sync.WaitGroup
 		sem       = make(chan struct{}, concurrency)
 	)
 
-	for _, pod := range pods {
+	for pod := range pods {
+		if hooks.progress != nil {
+			hooks.progress.Add(1)
+		}
 		wg.Add(1)
 		sem <- struct{}{} // Acquire semaphore
 
 		go func(p podRef) {
 			defer wg.Done()
 			defer func() { <-sem }() // Release semaphore
+			if hooks.progress != nil {
+				defer hooks.progress.Done()
+			}
 
 			diagnosis, err := podAnalyzer.Diagnose(ctx, p.namespace, p.name)
 			if err != nil {
-				// Skip pods that fail to diagnose
+				if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+					return
+				}
+				mu.Lock()
+				failures = append(failures, scanFailure{ref: p, err: err})
+				mu.Unlock()
 				return
 			}
 
 			mu.Lock()
 			diagnoses = append(diagnoses, diagnosis)
 			mu.Unlock()
+
+			if hooks.onDiagnosis != nil {
+				hooks.onDiagnosis(diagnosis)
+			}
 		}(pod)
 	}
 
 	wg.Wait()
-	return diagnoses
+	// Diagnoses finish in any order; list them by pod like the failures
+	domain.SortDiagnoses(diagnoses)
+	sort.Slice(failures, func(i, j int) bool {
+		return podKey(failures[i].ref.namespace, failures[i].ref.name) < podKey(failures[j].ref.namespace, failures[j].ref.name)
+	})
+	return diagnoses, failures
+}
+
+// describeScanFailures classifies why pods failed to diagnose
+func describeScanFailures(failures []scanFailure) []output.ScanFailure {
+	described := make([]output.ScanFailure, 0, len(failures))
+	for _, f := range failures {
+		reason := "error"
+		var apiErr *kubernetes.APIError
+		switch {
+		case errors.As(f.err, &apiErr) && apiErr.Kind == kubernetes.ErrorForbidden:
+			reason = "RBAC denied"
+		case errors.As(f.err, &apiErr) && apiErr.Kind == kubernetes.ErrorNotFound:
+			reason = "not found (deleted during the scan)"
+		case errors.As(f.err, &apiErr) && apiErr.Kind == kubernetes.ErrorTimeout,
+			errors.Is(f.err, context.DeadlineExceeded):
+			reason = "timeout"
+		}
+		described = append(described, output.ScanFailure{
+			Pod:    podKey(f.ref.namespace, f.ref.name),
+			Reason: reason,
+			Error:  f.err.Error(),
+			Hints:  kubernetes.ErrorHints(f.err),
+		})
+	}
+	return described
+}
+
+// podSampler keeps at most n pods per controlling owner. Pods that look
+// unhealthy are always kept so per-replica anomalies are still caught, and
+// pods without a controller are never sampled away. A zero n keeps every pod.
+type podSampler struct {
+	n    int
+	kept map[string]int
+}
+
+func newPodSampler(n int) *podSampler {
+	return &podSampler{n: n, kept: make(map[string]int)}
+}
+
+// keep reports whether the pod should be diagnosed
+func (s *podSampler) keep(pod corev1.Pod) bool {
+	if s.n <= 0 {
+		return true
+	}
+	owner := controllerKey(pod)
+	if owner == "" || podLooksUnhealthy(pod) {
+		return true
+	}
+	if s.kept[owner] < s.n {
+		s.kept[owner]++
+		return true
+	}
+	return false
+}
+
+// controllerKey returns a key identifying the pod's controlling owner
+func controllerKey(pod corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return string(ref.UID)
+		}
+	}
+	return ""
+}
+
+// podLooksUnhealthy is a cheap check, based only on the listed pod, for
+// pods that clearly differ from their healthy siblings
+func podLooksUnhealthy(pod corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return true
+	}
+	if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodSucceeded {
+		return true
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.RestartCount > 0 || cs.State.Waiting != nil || cs.LastTerminationState.Terminated != nil {
+			return true
+		}
+		if !cs.Ready && pod.Status.Phase == corev1.PodRunning {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of diagnosis output",
+		Long: `Print the JSON Schema (draft 2020-12) of a diagnosis as emitted by -o json.
+
+Every serialized diagnosis carries an apiVersion field (currently ` + domain.APIVersion + `).
+It only changes on breaking changes such as removed or renamed fields;
+new fields may appear within a version. diagnose -f -o json prints an
+array of diagnoses; scan -o json prints a document with the apiVersion,
+the array of diagnoses under diagnoses, and the dependencies several pods
+fail to reach under dependencies.
+
+The schema describes one diagnosis: validate diagnose output against it,
+and each element of scan's diagnoses.
+
+Examples:
+  # Save the schema
+  pod-doctor schema > diagnosis.schema.json
+
+  # Extract the diagnoses of a scan, one per line, to validate each
+  pod-doctor scan -o json | jq -c '.diagnoses[]'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := schema.Diagnosis()
+			if err != nil {
+				return fmt.Errorf("failed to generate schema: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+}
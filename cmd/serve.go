@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/exporter"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// serveOptions holds the flags for the serve command
+type serveOptions struct {
+	*Options
+	namespaces    []string
+	allNamespaces bool
+	labelSelector string
+	concurrency   int
+	interval      time.Duration
+	listenAddr    string
+	slo           analyzer.SLOThresholds
+	notify        notifyOptions
+}
+
+func newServeCommand(opts *Options) *cobra.Command {
+	serveOpts := &serveOptions{Options: opts}
+	defaultSLO := analyzer.DefaultSLOThresholds()
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Scan pods periodically and export Prometheus metrics",
+		Long: `Run as a Prometheus exporter.
+
+Scans the configured namespaces at a fixed interval and exposes the results
+on /metrics: issues per pod by severity and category, pod status, health
+score and breached workload SLOs. /healthz reports whether scans are
+succeeding. With --notify-slack or --notify-webhook, pods that become
+unhealthy or get a new critical issue are also reported there.
+
+Examples:
+  # Export metrics for the default namespace on :8080
+  pod-doctor serve
+
+  # Scan two namespaces every 30 seconds
+  pod-doctor serve --namespaces production,staging --interval 30s
+
+  # Scan all namespaces and listen on another port
+  pod-doctor serve -A --listen :9102`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(cmd, serveOpts)
+		},
+	}
+
+	serveCmd.Flags().StringSliceVar(&serveOpts.namespaces, "namespaces", nil, "namespaces to scan (default: the --namespace flag)")
+	serveCmd.Flags().BoolVarP(&serveOpts.allNamespaces, "all-namespaces", "A", false, "scan all namespaces")
+	serveCmd.Flags().StringVarP(&serveOpts.labelSelector, "selector", "l", "", "label selector to filter pods")
+	serveCmd.Flags().IntVar(&serveOpts.concurrency, "concurrency", 5, "number of concurrent diagnoses")
+	serveCmd.Flags().DurationVar(&serveOpts.interval, "interval", time.Minute, "time between scans")
+	serveCmd.Flags().StringVar(&serveOpts.listenAddr, "listen", ":8080", "address to serve /metrics and /healthz on")
+	serveCmd.Flags().IntVar(&serveOpts.slo.RestartsPerHour, "slo-restarts-per-hour", defaultSLO.RestartsPerHour, "flag workloads with more container restarts per hour (0 = off)")
+	serveCmd.Flags().IntVar(&serveOpts.slo.ReadinessFlapsPerDay, "slo-flaps-per-day", defaultSLO.ReadinessFlapsPerDay, "flag workloads whose pods lose readiness more often per day (0 = off)")
+	serveCmd.Flags().DurationVar(&serveOpts.slo.MaxCrashLoop, "slo-max-crashloop", defaultSLO.MaxCrashLoop, "flag workloads with a pod in CrashLoopBackOff for longer (0 = off)")
+	serveOpts.notify.addFlags(serveCmd.Flags())
+
+	return serveCmd
+}
+
+// podServer periodically scans pods and feeds the results to the exporter
+type podServer struct {
+	opts        *serveOptions
+	client      *kubernetes.Client
+	podAnalyzer *analyzer.PodAnalyzer
+	exporter    *exporter.Exporter
+	slo         *analyzer.SLOTracker
+	notifier    *podNotifier
+	out         io.Writer // where scan errors are printed
+	started     time.Time
+	seen        map[string]bool
+}
+
+func runServe(cmd *cobra.Command, opts *serveOptions) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+	notifier, err := newPodNotifier(opts.notify, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	s := &podServer{
+		opts:        opts,
+		client:      client,
+		podAnalyzer: podAnalyzer,
+		exporter:    exporter.New(),
+		slo:         analyzer.NewSLOTracker(opts.slo),
+		notifier:    notifier,
+		out:         cmd.OutOrStdout(),
+		started:     time.Now(),
+		seen:        make(map[string]bool),
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(s.exporter, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", s.healthz)
+	server := &http.Server{
+		Addr:              opts.listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	notifier.start(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- fmt.Errorf("failed to serve metrics: %w", err)
+		}
+	}()
+	output.PrintInfo(cmd.OutOrStdout(), fmt.Sprintf("Serving metrics on %s/metrics, scanning every %s", opts.listenAddr, opts.interval))
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for {
+		s.scan(ctx)
+
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		case err := <-serveErr:
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan diagnoses every pod in scope once and publishes the results
+func (s *podServer) scan(ctx context.Context) {
+	start := time.Now()
+	scanCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	pods, err := s.listPods(scanCtx)
+	if err != nil {
+		s.exporter.ScanFailed()
+		output.PrintError(s.out, err.Error())
+		return
+	}
+
+	// Scans see every pod once per interval, which is enough for the SLO
+	// tracker to count restarts and readiness flaps between scans
+	refs := make([]podRef, 0, len(pods))
+	current := make(map[string]bool, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		s.slo.Observe(pod)
+		current[podKey(pod.Namespace, pod.Name)] = true
+		refs = append(refs, podRef{namespace: pod.Namespace, name: pod.Name})
+	}
+	for key := range s.seen {
+		if !current[key] {
+			namespace, name, _ := strings.Cut(key, "/")
+			s.slo.Forget(namespace, name)
+			s.notifier.forget(namespace, name)
+		}
+	}
+	s.seen = current
+	s.slo.Evaluate()
+
+	diagnoses, failures := scanPods(scanCtx, s.podAnalyzer, refs, s.opts.concurrency)
+	if len(failures) > 0 {
+		// Failed pods are retried on the next scan
+		output.PrintError(s.out, fmt.Sprintf("failed to diagnose %d of %d pods, e.g. %s: %v",
+			len(failures), len(refs), podKey(failures[0].ref.namespace, failures[0].ref.name), failures[0].err))
+	}
+	s.exporter.Update(diagnoses, s.slo.Breaches(), time.Since(start))
+	for _, d := range diagnoses {
+		s.notifier.observe(d)
+	}
+}
+
+// listPods lists the pods in every configured namespace
+func (s *podServer) listPods(ctx context.Context) ([]corev1.Pod, error) {
+	if s.opts.allNamespaces {
+		pods, skipped, err := listPods(ctx, s.client, true, "", s.opts.labelSelector)
+		if err != nil {
+			return nil, err
+		}
+		if len(skipped) > 0 {
+			output.PrintError(s.out, fmt.Sprintf("skipped %d namespaces due to permissions: [%s]", len(skipped), skippedNamespaceNames(skipped)))
+		}
+		return pods, nil
+	}
+
+	namespaces := s.opts.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{s.opts.Namespace}
+	}
+	var pods []corev1.Pod
+	for _, namespace := range namespaces {
+		podList, _, err := listPods(ctx, s.client, false, namespace, s.opts.labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("namespace %s: %w", namespace, err)
+		}
+		pods = append(pods, podList...)
+	}
+	return pods, nil
+}
+
+// healthz reports unhealthy once no scan has succeeded for three intervals
+func (s *podServer) healthz(w http.ResponseWriter, r *http.Request) {
+	last := s.exporter.LastScan()
+	if last.IsZero() {
+		last = s.started
+	}
+	if age := time.Since(last); age > 3*s.opts.interval {
+		http.Error(w, fmt.Sprintf("no successful scan for %s", age.Round(time.Second)), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
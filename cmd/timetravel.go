@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/history"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// runDiagnoseAt reconstructs a pod's state at the time given with --at from
+// its recorded diagnoses and remaining events. The result is not recorded,
+// so it never stands in for a real diagnosis in the history.
+func runDiagnoseAt(cmd *cobra.Command, opts *diagnoseOptions, podName string) error {
+	at, err := parseAt(opts.at, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	out := cmd.OutOrStdout()
+
+	var snapshot *domain.Diagnosis
+	if opts.HistoryDir != "" {
+		snapshot, err = history.NewStore(opts.HistoryDir).At(opts.Namespace, podName, at)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+
+	if opts.OutputFormat == "console" {
+		fmt.Fprintf(out, "Reconstructing pod %s/%s at %s...\n", opts.Namespace, podName, at.Local().Format(time.RFC3339))
+	}
+
+	diagnosis, err := podAnalyzer.DiagnoseAt(ctx, opts.Namespace, podName, at, snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to diagnose pod: %w", err)
+	}
+
+	if opts.OutputFormat == "sarif" {
+		if err := output.WriteSARIF(out, []*domain.Diagnosis{diagnosis}, Version); err != nil {
+			return fmt.Errorf("failed to write SARIF: %w", err)
+		}
+		return nil
+	}
+	return printStructured(cmd, opts.OutputFormat, diagnosis, func() {
+		output.PrintDiagnosis(out, diagnosis)
+	})
+}
+
+// parseAt parses a --at value: an RFC 3339 timestamp, a local date and
+// time like 2006-01-02 15:04, or a duration before now like 90m or 2h
+func parseAt(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid --at %q: durations count back from now and must not be negative", value)
+		}
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return checkAt(value, t, now)
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return checkAt(value, t, now)
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q: want a timestamp like 2006-01-02T15:04:05Z or \"2006-01-02 15:04\", or a duration ago like 2h", value)
+}
+
+// checkAt rejects --at times in the future
+func checkAt(value string, t, now time.Time) (time.Time, error) {
+	if t.After(now) {
+		return time.Time{}, fmt.Errorf("invalid --at %q: time is in the future", value)
+	}
+	return t, nil
+}
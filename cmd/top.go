@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// topOptions holds the flags for the top command
+type topOptions struct {
+	*Options
+	interval time.Duration
+}
+
+func newTopCommand(opts *Options) *cobra.Command {
+	topOpts := &topOptions{Options: opts}
+
+	topCmd := &cobra.Command{
+		Use:   "top",
+		Short: "Live dashboard of unhealthy pods",
+		Long: `Open a live dashboard of the unhealthy pods in every namespace.
+
+The list refreshes itself and is sorted worst first: critical statuses such
+as CrashLoopBackOff before warnings such as Pending, then by restarts per
+hour. Healthy pods show up while they keep restarting. Press enter to
+diagnose the selected pod and esc to return to the dashboard.
+
+Examples:
+  # Watch the cluster
+  pod-doctor top
+
+  # Refresh every 15 seconds on a large cluster
+  pod-doctor top --interval 15s`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTop(cmd, topOpts)
+		},
+	}
+
+	topCmd.Flags().DurationVar(&topOpts.interval, "interval", tui.DefaultTopInterval, "how often to refresh the pod list")
+
+	return topCmd
+}
+
+func runTop(cmd *cobra.Command, opts *topOptions) error {
+	if opts.interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	if err := opts.checkTUILogging(); err != nil {
+		return err
+	}
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+	return tui.RunTop(client, podAnalyzer, opts.interval, tuiConnector(cmd, opts.Options))
+}
@@ -13,16 +13,15 @@ var (
 	BuildDate = "unknown"
 )
 
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("pod-doctor %s\n", Version)
-		fmt.Printf("  Commit: %s\n", Commit)
-		fmt.Printf("  Built: %s\n", BuildDate)
-	},
-}
-
-func init() {
-	rootCmd.AddCommand(versionCmd)
+func newVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print version information",
+		Run: func(cmd *cobra.Command, args []string) {
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "pod-doctor %s\n", Version)
+			fmt.Fprintf(out, "  Commit: %s\n", Commit)
+			fmt.Fprintf(out, "  Built: %s\n", BuildDate)
+		},
+	}
 }
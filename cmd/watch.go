@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// watchRefreshInterval is how often the live summary is redrawn when results change
+const watchRefreshInterval = 2 * time.Second
+
+// watchMaxRetries is how often a pod whose diagnosis failed is retried,
+// with growing delays, before waiting for its next status change
+const watchMaxRetries = 5
+
+// podWatcher re-diagnoses pods as their status changes
+type podWatcher struct {
+	opts        *scanOptions
+	client      *kubernetes.Client
+	podAnalyzer *analyzer.PodAnalyzer
+	out         io.Writer
+	queue       workqueue.TypedRateLimitingInterface[string]
+	slo         *analyzer.SLOTracker
+	notifier    *podNotifier
+
+	mu           sync.Mutex
+	diagnoses    map[string]*domain.Diagnosis
+	fingerprints map[string]string
+	dirty        bool
+}
+
+// runWatch watches pods and keeps a live summary until ctx is cancelled.
+// Notification failures are written to errOut.
+func runWatch(ctx context.Context, opts *scanOptions, client *kubernetes.Client, out, errOut io.Writer) error {
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+	notifier, err := newPodNotifier(opts.notify, errOut)
+	if err != nil {
+		return err
+	}
+
+	namespace := opts.Namespace
+	if opts.allNamespaces {
+		namespace = ""
+	}
+
+	w := &podWatcher{
+		opts:         opts,
+		client:       client,
+		podAnalyzer:  podAnalyzer,
+		out:          out,
+		queue:        workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]()),
+		slo:          analyzer.NewSLOTracker(opts.slo),
+		notifier:     notifier,
+		diagnoses:    make(map[string]*domain.Diagnosis),
+		fingerprints: make(map[string]string),
+	}
+	defer w.queue.ShutDown()
+	notifier.start(ctx)
+
+	informer := client.NewPodInformer(namespace, opts.labelSelector, 0)
+	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.onChange,
+		UpdateFunc: func(_, obj interface{}) { w.onChange(obj) },
+		DeleteFunc: w.onDelete,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch pods: %w", err)
+	}
+
+	go informer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync pod cache")
+	}
+
+	concurrency := opts.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	for i := 0; i < concurrency; i++ {
+		go w.worker(ctx)
+	}
+
+	ticker := time.NewTicker(watchRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.reportSLOBreaches(w.slo.Evaluate())
+			w.render()
+		}
+	}
+}
+
+// onChange queues a pod for diagnosis if its status meaningfully changed
+func (w *podWatcher) onChange(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	key := podKey(pod.Namespace, pod.Name)
+	fp := statusFingerprint(pod)
+	w.reportSLOBreaches(w.slo.Observe(pod))
+
+	w.mu.Lock()
+	changed := w.fingerprints[key] != fp
+	w.fingerprints[key] = fp
+	w.mu.Unlock()
+
+	if changed {
+		w.queue.Add(key)
+	}
+}
+
+// onDelete drops a deleted pod from the summary
+func (w *podWatcher) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	key := podKey(pod.Namespace, pod.Name)
+	w.slo.Forget(pod.Namespace, pod.Name)
+	w.notifier.forget(pod.Namespace, pod.Name)
+
+	w.mu.Lock()
+	delete(w.diagnoses, key)
+	delete(w.fingerprints, key)
+	w.dirty = true
+	w.mu.Unlock()
+}
+
+// worker diagnoses queued pods until the queue shuts down. Failed
+// diagnoses are retried with backoff while the pod exists.
+func (w *podWatcher) worker(ctx context.Context) {
+	for {
+		key, shutdown := w.queue.Get()
+		if shutdown {
+			return
+		}
+
+		namespace, name, _ := strings.Cut(key, "/")
+		diagCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		diagnosis, err := w.podAnalyzer.Diagnose(diagCtx, namespace, name)
+		cancel()
+
+		// A pod deleted while it was diagnosed must not come back as a
+		// ghost row; onDelete already dropped its fingerprint
+		w.mu.Lock()
+		_, exists := w.fingerprints[key]
+		if err == nil && exists {
+			w.diagnoses[key] = diagnosis
+			w.dirty = true
+		}
+		w.mu.Unlock()
+
+		switch {
+		case err != nil && exists && w.queue.NumRequeues(key) < watchMaxRetries:
+			w.queue.AddRateLimited(key)
+		case err == nil && exists:
+			w.queue.Forget(key)
+			if w.opts.OutputFormat == "json" && (!w.opts.onlyUnhealthy || !diagnosis.IsHealthy()) {
+				w.emitJSON(diagnosis)
+			}
+			w.notifier.observe(diagnosis)
+		default:
+			w.queue.Forget(key)
+		}
+		w.queue.Done(key)
+	}
+}
+
+// reportSLOBreaches emits newly breached SLO indicators as JSON lines, or
+// marks the console summary for redraw
+func (w *podWatcher) reportSLOBreaches(breaches []domain.Issue) {
+	if len(breaches) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dirty = true
+
+	if w.opts.OutputFormat != "json" {
+		return
+	}
+	for _, issue := range breaches {
+		data, err := json.Marshal(struct {
+			SLOBreach domain.Issue `json:"sloBreach"`
+		}{issue})
+		if err != nil {
+			continue
+		}
+		fmt.Fprintln(w.out, string(data))
+	}
+}
+
+// emitJSON writes a diagnosis as a single JSON line
+func (w *podWatcher) emitJSON(d *domain.Diagnosis) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintln(w.out, string(data))
+}
+
+// render redraws the console summary if any results changed
+func (w *podWatcher) render() {
+	if w.opts.OutputFormat == "json" {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.dirty {
+		return
+	}
+	w.dirty = false
+
+	diagnoses := make([]*domain.Diagnosis, 0, len(w.diagnoses))
+	for _, d := range w.diagnoses {
+		if w.opts.onlyUnhealthy && d.IsHealthy() {
+			continue
+		}
+		diagnoses = append(diagnoses, d)
+	}
+	sort.Slice(diagnoses, func(i, j int) bool {
+		return podKey(diagnoses[i].Pod.Namespace, diagnoses[i].Pod.Name) < podKey(diagnoses[j].Pod.Namespace, diagnoses[j].Pod.Name)
+	})
+
+	// Clear the screen and redraw from the top
+	fmt.Fprint(w.out, "\033[H\033[2J")
+	fmt.Fprintf(w.out, "Watching pods (updated %s, Ctrl+C to stop)\n", time.Now().Format("15:04:05"))
+	output.PrintScanSummary(w.out, diagnoses, w.opts.scanTable())
+	output.PrintDependencyFailures(w.out, analyzer.FindFailingDependencies(diagnoses, 2))
+	output.PrintSLOBreaches(w.out, w.slo.Breaches())
+}
+
+// statusFingerprint summarizes the parts of a pod's status that affect its
+// diagnosis, so unrelated updates don't trigger a re-diagnosis
+func statusFingerprint(pod *corev1.Pod) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s|%s|%t", pod.Status.Phase, pod.Status.Reason, pod.DeletionTimestamp != nil)
+	for _, cond := range pod.Status.Conditions {
+		fmt.Fprintf(&b, "|%s=%s", cond.Type, cond.Status)
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		fmt.Fprintf(&b, "|%s:%t:%d", cs.Name, cs.Ready, cs.RestartCount)
+		switch {
+		case cs.State.Waiting != nil:
+			fmt.Fprintf(&b, ":waiting:%s", cs.State.Waiting.Reason)
+		case cs.State.Terminated != nil:
+			fmt.Fprintf(&b, ":terminated:%s", cs.State.Terminated.Reason)
+		case cs.State.Running != nil:
+			b.WriteString(":running")
+		}
+	}
+	return b.String()
+}
+
+// podKey returns the namespace/name key of a pod
+func podKey(namespace, name string) string {
+	return namespace + "/" + name
+}
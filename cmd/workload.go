@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// runDiagnoseWorkload diagnoses every pod of a workload, or every pod
+// matching --selector if kind is empty, and prints an aggregated report
+func runDiagnoseWorkload(cmd *cobra.Command, opts *diagnoseOptions, kind, name string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+
+	out := cmd.OutOrStdout()
+
+	client, err := newClient(cmd, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	podAnalyzer, err := newPodAnalyzer(opts.Options, client)
+	if err != nil {
+		return err
+	}
+
+	report := &domain.WorkloadReport{Kind: kind, Name: name, Namespace: opts.Namespace, Selector: opts.labelSelector}
+	if kind != "" {
+		report.Selector, err = client.WorkloadSelector(ctx, opts.Namespace, kind, name)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", report.Ref(), err)
+		}
+	}
+
+	podList, err := client.ListPods(ctx, opts.Namespace, report.Selector)
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	if len(podList.Items) == 0 {
+		return fmt.Errorf("no pods in namespace %s match %s", opts.Namespace, report.Ref())
+	}
+
+	if opts.OutputFormat == "console" {
+		fmt.Fprintf(out, "Diagnosing %d pods of %s in %s...\n", len(podList.Items), report.Ref(), opts.Namespace)
+	}
+
+	refs := make([]podRef, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		refs = append(refs, podRef{namespace: pod.Namespace, name: pod.Name})
+	}
+	diagnoses, failures := scanPods(ctx, podAnalyzer, refs, opts.concurrency)
+	sort.Slice(diagnoses, func(i, j int) bool {
+		return diagnoses[i].Pod.Name < diagnoses[j].Pod.Name
+	})
+	recordHistory(cmd, opts.Options, diagnoses...)
+	if err := exportFixes(cmd, opts.exportFixes, diagnoses...); err != nil {
+		return err
+	}
+	analyzer.AggregateWorkload(report, diagnoses)
+
+	switch opts.OutputFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "sarif":
+		if err := output.WriteSARIF(out, diagnoses, Version); err != nil {
+			return fmt.Errorf("failed to write SARIF: %w", err)
+		}
+	default:
+		output.PrintWorkloadReport(out, report)
+	}
+
+	// Structured output stays parseable; failures go to stderr instead
+	failuresOut := cmd.ErrOrStderr()
+	if opts.OutputFormat == "console" {
+		failuresOut = out
+	}
+	output.PrintScanFailures(failuresOut, describeScanFailures(failures), len(refs))
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to diagnose %d of %d pods", len(failures), len(refs))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// ackCacheTTL is how long a namespace's acknowledgements are reused, so a
+// scan does not read its ConfigMap once per pod
+const ackCacheTTL = 30 * time.Second
+
+// ackCache holds the acknowledgements of each namespace, shared by all
+// diagnoses
+type ackCache struct {
+	mu         sync.Mutex
+	namespaces map[string]ackEntry
+}
+
+// ackEntry is the acknowledgements of one namespace and when they were read
+type ackEntry struct {
+	fetched time.Time
+	acks    []domain.Acknowledgement
+}
+
+// acknowledgements reads the acknowledgements of a namespace into the cache
+// when the cached ones are stale. On error, the last known ones are kept
+// and read again after the TTL.
+func (p *PodAnalyzer) acknowledgements(ctx context.Context, namespace string) error {
+	p.acks.mu.Lock()
+	defer p.acks.mu.Unlock()
+
+	entry, ok := p.acks.namespaces[namespace]
+	if ok && time.Since(entry.fetched) < ackCacheTTL {
+		return nil
+	}
+	acks, err := p.client.GetAcknowledgements(ctx, namespace)
+	if err == nil {
+		entry.acks = acks
+	}
+	entry.fetched = time.Now()
+	if p.acks.namespaces == nil {
+		p.acks.namespaces = make(map[string]ackEntry)
+	}
+	p.acks.namespaces[namespace] = entry
+	return err
+}
+
+// cachedAcknowledgements returns the acknowledgements last read for a
+// namespace, without reading them
+func (p *PodAnalyzer) cachedAcknowledgements(namespace string) []domain.Acknowledgement {
+	p.acks.mu.Lock()
+	defer p.acks.mu.Unlock()
+	return p.acks.namespaces[namespace].acks
+}
@@ -2,11 +2,17 @@ package analyzer
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kb"
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/provenance"
+	"github.com/pavanInnamuri/pod-doctor/internal/rules"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -22,72 +28,154 @@ type Analyzer interface {
 type PodAnalyzer struct {
 	client    *kubernetes.Client
 	analyzers []Analyzer
+	logs      *LogAnalyzer
+	rulePacks []*rules.Pack
+	filter    domain.IssueFilter // issues to report, set by UseIssueFilter
+	chaos     *chaosCache        // shared with the analyzers WithNodeCache derives
+	acks      *ackCache
+	timeout   time.Duration // bounds each section of a diagnosis
 }
 
-// NewPodAnalyzer creates a new PodAnalyzer with default analyzers
+// NewPodAnalyzer creates a new PodAnalyzer with all registered analyzers
 func NewPodAnalyzer(client *kubernetes.Client) *PodAnalyzer {
-	return &PodAnalyzer{
-		client: client,
-		analyzers: []Analyzer{
-			NewStatusAnalyzer(),
-			NewEventAnalyzer(),
-			NewLogAnalyzer(),
-			NewNodeAnalyzer(),
-			NewResourceAnalyzer(),
-			NewProbeAnalyzer(),
-		},
-	}
+	podAnalyzer, _ := NewPodAnalyzerWithConfig(client, DefaultConfig())
+	return podAnalyzer
 }
 
-// Diagnose performs a complete diagnosis on a pod
-func (p *PodAnalyzer) Diagnose(ctx context.Context, namespace, name string) (*domain.Diagnosis, error) {
-	// Get the pod
-	pod, err := p.client.GetPod(ctx, namespace, name)
+// NewPodAnalyzerWithConfig creates a PodAnalyzer running the analyzers
+// selected by cfg. It fails if cfg names an analyzer that is not registered.
+func NewPodAnalyzerWithConfig(client *kubernetes.Client, cfg Config) (*PodAnalyzer, error) {
+	analyzers, cfg, err := buildAnalyzers(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	// Extract pod info
-	podInfo := kubernetes.ExtractPodInfo(pod)
-	diagnosis := domain.NewDiagnosis(podInfo)
-
-	// Detect overall status
-	diagnosis.Status = detectPodStatus(pod)
-
-	// Run all analyzers
-	for _, analyzer := range p.analyzers {
-		issues, err := analyzer.Analyze(ctx, pod, p.client)
-		if err != nil {
-			// Log warning but continue with other analyzers
-			continue
+	p := &PodAnalyzer{
+		client:    client,
+		analyzers: analyzers,
+		timeout:   cfg.Timeout,
+		chaos:     &chaosCache{},
+		acks:      &ackCache{},
+	}
+	for _, a := range analyzers {
+		if logAnalyzer, ok := a.(*LogAnalyzer); ok {
+			p.logs = logAnalyzer
 		}
-		for _, issue := range issues {
-			diagnosis.AddIssue(issue)
+	}
+	if p.logs == nil {
+		// Keep log patterns for MatchLogLine even when log analysis is off
+		p.logs = newConfiguredLogAnalyzer(cfg.Logs)
+	}
+	return p, nil
+}
+
+// UseRules loads rule packs into the analyzer. Log patterns are added to the
+// log analyzer; suppressions, severity remaps and recommendations are
+// applied to every diagnosis.
+func (p *PodAnalyzer) UseRules(packs ...*rules.Pack) error {
+	for _, pack := range packs {
+		for _, lp := range pack.LogPatterns {
+			re, err := regexp.Compile(lp.Pattern)
+			if err != nil {
+				return fmt.Errorf("rule pack %s: %w", pack.Name, err)
+			}
+			p.logs.AddPattern(re, lp.Title, lp.Description, lp.Severity, lp.Category)
 		}
+		p.rulePacks = append(p.rulePacks, pack)
 	}
+	return nil
+}
+
+// UseIssueFilter limits the issues of every diagnosis to those the filter
+// keeps. Like suppressed issues, those filtered out get no recommendations
+// and do not count toward the health score.
+func (p *PodAnalyzer) UseIssueFilter(f domain.IssueFilter) {
+	p.filter = f
+}
+
+// UseTrustPolicy enables image provenance checks against a trust policy
+func (p *PodAnalyzer) UseTrustPolicy(policy *provenance.TrustPolicy) {
+	p.analyzers = append(p.analyzers, NewProvenanceAnalyzer(policy))
+}
+
+// WithNodeCache returns an analyzer for one scan run that fetches all nodes
+// in a single call and shares them across the pods it diagnoses. It keeps
+// every other setting of p.
+func (p *PodAnalyzer) WithNodeCache(ctx context.Context) *PodAnalyzer {
+	cp := *p
+	cp.client = p.client.WithNodeCache(ctx)
+	return &cp
+}
 
-	// Get events
-	events, err := p.client.GetPodEvents(ctx, namespace, name)
-	if err == nil {
-		diagnosis.Events = events
+// MatchLogLine reports whether a log line matches one of the log patterns,
+// including those added by rule packs, and with which severity
+func (p *PodAnalyzer) MatchLogLine(line string) (domain.Severity, bool) {
+	return p.logs.MatchLine(line)
+}
+
+// Diagnose performs a complete diagnosis on a pod
+func (p *PodAnalyzer) Diagnose(ctx context.Context, namespace, name string) (*domain.Diagnosis, error) {
+	return p.DiagnoseWithProgress(ctx, namespace, name, nil)
+}
+
+// degradedFeatures names what an analyzer provides, for warnings about
+// checks that could not run
+var degradedFeatures = map[string]string{
+	"node":             "node health",
+	"scheduling":       "scheduling explanation",
+	"networkpolicy":    "network policy analysis",
+	"acknowledgements": "shared acknowledgements",
+}
+
+// degradedWarning turns an analyzer error caused by missing RBAC
+// permissions into a diagnosis warning. Other errors are not reported.
+func degradedWarning(analyzerName string, err error) (domain.AnalyzerWarning, bool) {
+	if !kubernetes.IsForbidden(err) {
+		return domain.AnalyzerWarning{}, false
+	}
+	feature, ok := degradedFeatures[analyzerName]
+	if !ok {
+		feature = analyzerName + " analysis"
 	}
+	return domain.AnalyzerWarning{
+		Analyzer: analyzerName,
+		Message:  fmt.Sprintf("%s unavailable (RBAC): %v", feature, err),
+	}, true
+}
 
-	// Get node health if pod is scheduled
-	if pod.Spec.NodeName != "" {
-		nodeHealth, err := p.client.GetNodeHealth(ctx, pod.Spec.NodeName)
-		if err == nil {
-			diagnosis.Node = nodeHealth
-		}
+// finalize assigns issue IDs, links issues to the objects they involve,
+// applies rule packs and acknowledgements, puts issues and events in a
+// fixed order, generates recommendations and scores the diagnosis
+func (p *PodAnalyzer) finalize(diagnosis *domain.Diagnosis) {
+	// Issue IDs come first so rule packs and recommendations can rely on them
+	kb.Assign(diagnosis.Issues)
+	linkObjects(diagnosis)
+
+	for _, pack := range p.rulePacks {
+		pack.Filter(diagnosis)
 	}
+	diagnosis.Acknowledge(p.cachedAcknowledgements(diagnosis.Pod.Namespace), time.Now())
+	diagnosis.FilterIssues(p.filter)
+	// Rule packs may change severities, so order issues after them
+	domain.SortIssues(diagnosis.Issues)
+	domain.SortEvents(diagnosis.Events)
 
 	// Generate recommendations
 	diagnosis.Recommendations = generateRecommendations(diagnosis)
+	for _, pack := range p.rulePacks {
+		diagnosis.Recommendations = append(diagnosis.Recommendations, pack.Recommend(diagnosis)...)
+	}
+	sort.SliceStable(diagnosis.Recommendations, func(i, j int) bool {
+		return diagnosis.Recommendations[i].Priority < diagnosis.Recommendations[j].Priority
+	})
 
-	return diagnosis, nil
+	// Score overall health
+	diagnosis.HealthScore = diagnosis.CalculateHealthScore()
 }
 
-// detectPodStatus determines the high-level status of a pod
-func detectPodStatus(pod *corev1.Pod) domain.PodStatus {
+// DetectPodStatus determines the high-level status of a pod from its status
+// alone, without running the analyzers
+func DetectPodStatus(pod *corev1.Pod) domain.PodStatus {
 	// Check if pod is being deleted
 	if pod.DeletionTimestamp != nil {
 		return domain.StatusTerminating
@@ -145,7 +233,7 @@ func generateRecommendations(diagnosis *domain.Diagnosis) []domain.Recommendatio
 	seenRecs := make(map[string]bool)
 
 	for _, issue := range diagnosis.Issues {
-		newRecs := getRecommendationsForIssue(issue, diagnosis.Pod)
+		newRecs := getRecommendationsForIssue(issue, diagnosis.Pod, diagnosis.Workload)
 		for _, rec := range newRecs {
 			if !seenRecs[rec.Title] {
 				recs = append(recs, rec)
@@ -154,34 +242,192 @@ func generateRecommendations(diagnosis *domain.Diagnosis) []domain.Recommendatio
 		}
 	}
 
-	// Sort by priority
-	sort.Slice(recs, func(i, j int) bool {
+	// Sort by priority; recommendations of equal priority keep the order of
+	// the issues they came from
+	sort.SliceStable(recs, func(i, j int) bool {
 		return recs[i].Priority < recs[j].Priority
 	})
 
 	return recs
 }
 
+// placeholderTarget stands for the workload in commands when it is unknown
+const placeholderTarget = "deployment/<deployment-name>"
+
 // getRecommendationsForIssue returns recommendations for a specific issue
-func getRecommendationsForIssue(issue domain.Issue, pod domain.PodInfo) []domain.Recommendation {
+func getRecommendationsForIssue(issue domain.Issue, pod domain.PodInfo, workload *domain.WorkloadInfo) []domain.Recommendation {
+	if workload == nil || workloadKinds[strings.ToLower(workload.Kind)] != "" {
+		return issueRecommendations(issue, pod, workload)
+	}
+	// A pod owned by something that is not a workload, e.g. the Node of a
+	// static pod, has no pod template to edit, so leave out the commands
+	// that would edit one
+	recs := issueRecommendations(issue, pod, nil)
+	for i := range recs {
+		if strings.Contains(recs[i].Command, placeholderTarget) {
+			recs[i].Command = ""
+		}
+	}
+	return recs
+}
+
+// issueRecommendations returns the recommendations for an issue of a pod
+// owned by workload, or by no workload if it is nil
+func issueRecommendations(issue domain.Issue, pod domain.PodInfo, workload *domain.WorkloadInfo) []domain.Recommendation {
 	var recs []domain.Recommendation
 
+	// Name the real workload and container in commands where known
+	target := placeholderTarget
+	if workload != nil && workload.Kind != "Job" && workload.Kind != "CronJob" {
+		target = workload.Ref()
+	}
+	container := "<container>"
+	if c := issue.Details["container"]; c != "" {
+		container = c
+	}
+
 	switch issue.Category {
 	case "container":
+		switch dep := issue.Details["dependency"]; {
+		case strings.Contains(issue.Title, "may start before sidecar"):
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Add a startup probe to sidecar " + dep,
+				Description: "The kubelet then starts " + container + " only once " + dep + " passes it, instead of as soon as its process runs",
+			})
+		case strings.Contains(issue.Title, "started before its dependency"):
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Start " + dep + " before " + container,
+				Description: "Make " + dep + " a native sidecar (an init container with restartPolicy: Always) with a startup probe, or have " + container + " retry connecting at startup",
+			})
+		}
+		if strings.HasPrefix(issue.Title, "Readiness gate") {
+			rec := domain.Recommendation{
+				Priority:    1,
+				Title:       "Find out why " + issue.Details["condition_type"] + " is not posted",
+				Description: "Check that the controller owning the readiness gate runs and watches this pod, and read its logs for errors about the pod",
+				Command:     "kubectl get pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -o jsonpath='{.status.conditions}'",
+			}
+			switch issue.Details["controller"] {
+			case "AWS Load Balancer Controller":
+				rec.Description = "Check that TargetGroupBinding " + issue.Details["target"] + " still exists and the AWS Load Balancer Controller is running; a pod whose binding was deleted stays NotReady until it is recreated"
+				rec.Command = "kubectl get targetgroupbinding " + domain.ShellArg(issue.Details["target"]) + " -n " + domain.ShellArg(pod.Namespace)
+			case "GKE NEG controller":
+				rec.Description = "Check that the service still has the cloud.google.com/neg annotation and its network endpoint groups list the pod"
+				rec.Command = "kubectl get servicenetworkendpointgroups -n " + domain.ShellArg(pod.Namespace)
+			}
+			recs = append(recs, rec)
+		}
 		if issue.Title == "CrashLoopBackOff" || containsReason(issue, "CrashLoopBackOff") {
 			recs = append(recs, domain.Recommendation{
 				Priority:    1,
 				Title:       "Check container logs",
 				Description: "Review container logs to identify the crash cause",
-				Command:     "kubectl logs " + pod.Name + " -n " + pod.Namespace + " --previous",
+				Command:     "kubectl logs " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " --previous",
 			})
 		}
-		if containsReason(issue, "ImagePullBackOff") || containsReason(issue, "ErrImagePull") {
+		if issue.Details["type"] == "init" && issue.Details["sequence"] != "" {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Check init container logs",
+				Description: "The pod is blocked on " + container + " (" + issue.Details["sequence"] + "); its logs show what it is waiting for or why it failed",
+				Command:     "kubectl logs " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container),
+			})
+		}
+		if issue.Title == "Pod is not ready" {
+			if containers := issue.Details["probe_containers"]; containers != "" {
+				recs = append(recs, domain.Recommendation{
+					Priority:    1,
+					Title:       "Debug readiness probe",
+					Description: "Check why the probe of " + containers + " is failing; the last probe error is listed under Why Not Ready",
+					Command:     "kubectl describe pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " | grep -A10 'Readiness'",
+				})
+				probed, _, _ := strings.Cut(containers, ",")
+				recs = append(recs, domain.Recommendation{
+					Priority:    2,
+					Title:       "Probe the endpoint from inside the pod",
+					Description: "Open a shell with network tools next to " + probed + " and call the probe endpoint yourself",
+					Command:     debugCommand(pod, strings.TrimSpace(probed)),
+				})
+			}
+			if gates := issue.Details["readiness_gates"]; gates != "" {
+				recs = append(recs, domain.Recommendation{
+					Priority:    1,
+					Title:       "Check readiness gate controller",
+					Description: "The controller that owns " + gates + " (e.g. a load balancer controller) has not marked the pod ready; check its logs",
+					Command:     "kubectl get pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -o jsonpath='{.status.conditions}'",
+				})
+			}
+		}
+		pullFailure := issue.Details["pull_failure"]
+		switch {
+		case pullFailure == pullFailureRateLimited:
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Use a registry mirror or pull-through cache",
+				Description: "The registry is rate limiting image pulls; pull through a mirror or cache (e.g. Harbor, ECR/GCR/ACR pull-through cache) instead of the public registry",
+			})
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Authenticate image pulls",
+				Description: "Anonymous pulls have much lower rate limits; add an imagePullSecret with registry credentials",
+				Command:     "kubectl create secret docker-registry regcred -n " + domain.ShellArg(pod.Namespace) + " --docker-server=<registry> --docker-username=<user> --docker-password=<token>",
+			})
+		case pullFailure == pullFailureNotFound:
+			image := issue.Details["image"]
+			if image == "" {
+				image = "<image>"
+			}
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Check the image tag",
+				Description: "The tag or repository does not exist in the registry; check for typos or a tag that was never pushed",
+				Command:     "docker manifest inspect " + domain.ShellArg(image),
+			})
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Deploy an existing tag",
+				Description: "Point the workload at a tag that was pushed",
+				Command:     "kubectl set image " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " " + domain.ShellArg(container) + "=<image>:<tag>",
+			})
+		case pullFailure == pullFailureAuth:
+			registry := issue.Details["registry"]
+			if registry == "" {
+				registry = "<registry>"
+			}
+			if secrets := issue.Details["image_pull_secrets"]; secrets != "" {
+				secret, _, _ := strings.Cut(secrets, ",")
+				recs = append(recs, domain.Recommendation{
+					Priority:    1,
+					Title:       "Check image pull secret credentials",
+					Description: "The registry rejected the credentials in " + secrets + "; check they are for " + registry + " and have not expired",
+					Command:     "kubectl get secret " + domain.ShellArg(secret) + " -n " + domain.ShellArg(pod.Namespace) + " -o jsonpath='{.data.\\.dockerconfigjson}' | base64 -d",
+				})
+			} else {
+				recs = append(recs, domain.Recommendation{
+					Priority:    1,
+					Title:       "Add an image pull secret",
+					Description: "The pod has no imagePullSecrets; create one for " + registry + " and reference it from the pod or its service account",
+					Command:     "kubectl create secret docker-registry regcred -n " + domain.ShellArg(pod.Namespace) + " --docker-server=" + domain.ShellArg(registry) + " --docker-username=<user> --docker-password=<token>",
+				})
+			}
+		case pullFailure == pullFailureRegistryDNS:
+			registry := issue.Details["registry"]
+			if registry == "" {
+				registry = "the registry host"
+			}
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Check the registry hostname",
+				Description: "The node cannot resolve " + registry + "; check the image name for typos, and the node's DNS and egress to the registry",
+			})
+		case containsReason(issue, "ImagePullBackOff") || containsReason(issue, "ErrImagePull"):
 			recs = append(recs, domain.Recommendation{
 				Priority:    1,
 				Title:       "Verify image exists",
 				Description: "Check if the image exists and is accessible",
-				Command:     "kubectl describe pod " + pod.Name + " -n " + pod.Namespace,
+				Command:     "kubectl describe pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace),
 			})
 			recs = append(recs, domain.Recommendation{
 				Priority:    2,
@@ -189,22 +435,107 @@ func getRecommendationsForIssue(issue domain.Issue, pod domain.PodInfo) []domain
 				Description: "Ensure imagePullSecrets are configured if using a private registry",
 			})
 		}
-
-	case "resources":
-		if containsReason(issue, "OOMKilled") {
+		if secret := issue.Details["secret"]; secret != "" && issue.Title == "Image pull secret "+secret+" not found" {
 			recs = append(recs, domain.Recommendation{
 				Priority:    1,
-				Title:       "Increase memory limit",
-				Description: "Container exceeded memory limit; consider increasing it",
-				Command:     "kubectl set resources deployment/<deployment-name> -c <container> --limits=memory=<new-limit>",
+				Title:       "Create image pull secret " + secret,
+				Description: "Create the secret as type kubernetes.io/dockerconfigjson in the pod's namespace",
+				Command:     "kubectl create secret docker-registry " + domain.ShellArg(secret) + " -n " + domain.ShellArg(pod.Namespace) + " --docker-server=<registry> --docker-username=<user> --docker-password=<token>",
 			})
 		}
+		if strings.Contains(issue.Title, "uses :latest with IfNotPresent") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    3,
+				Title:       "Pin image versions",
+				Description: "Deploy an immutable tag or digest instead of :latest, or set imagePullPolicy: Always",
+				Command:     "kubectl set image " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " " + domain.ShellArg(container) + "=<image>:<version>",
+			})
+		}
+
+	case "resources":
+		if containsReason(issue, "OOMKilled") {
+			recs = append(recs, oomRecommendation(issue, target, pod.Namespace, container))
+		}
+		if rec, ok := runtimeRecommendation(issue, target, pod.Namespace, container); ok && isMemoryIssue(issue) {
+			recs = append(recs, rec)
+		}
 		if strings.Contains(issue.Title, "No resource limits") {
 			recs = append(recs, domain.Recommendation{
 				Priority:    2,
 				Title:       "Add resource limits",
 				Description: "Set resource limits to prevent resource contention",
-				Command:     "kubectl set resources deployment/<deployment-name> -c <container> --limits=cpu=500m,memory=256Mi",
+				Command:     "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container) + " --limits=cpu=500m,memory=256Mi",
+			})
+		}
+		if strings.Contains(issue.Title, "Memory near limit") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Raise memory limit before OOMKill",
+				Description: "Container is close to its memory limit; raise the limit or investigate a leak",
+				Command:     "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container) + " --limits=memory=<new-limit>",
+			})
+		}
+		if strings.Contains(issue.Title, "CPU throttled") {
+			if rec, ok := cpuQuotaRecommendation(issue, target, pod.Namespace, container); ok {
+				recs = append(recs, rec)
+			}
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Raise CPU limit",
+				Description: "Container is using its full CPU limit and is being throttled; raise or remove the limit",
+				Command:     "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container) + " --limits=cpu=<new-limit>",
+			})
+		}
+		if noisy := issue.Details["noisy_neighbors"]; noisy != "" {
+			resourceName := issue.Details["resource"]
+			rec := domain.Recommendation{
+				Priority:    1,
+				Title:       "Set " + resourceName + " requests and limits on " + noisy,
+				Description: "They use far more " + resourceName + " than they request; requests at their usage make the scheduler account for it, and a limit caps what they can take from the node",
+			}
+			if neighbor := issue.Details["noisy_workload"]; neighbor != "" {
+				rec.Title = "Set " + resourceName + " requests and limits on " + neighbor
+				rec.Command = "kubectl set resources " + domain.ShellArg(neighbor) + " -n " + domain.ShellArg(issue.Details["noisy_namespace"]) + " --requests=" + domain.ShellArg(resourceName) + "=<request> --limits=" + domain.ShellArg(resourceName) + "=<limit>"
+			}
+			recs = append(recs, rec)
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Keep the pod away from its noisy neighbors",
+				Description: "Add pod anti-affinity against the labels of " + noisy + ", or raise the pod's own requests so it is scheduled on a node where they fit",
+			})
+		}
+		if strings.Contains(issue.Title, "VPA recommendation") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Adopt VPA recommendation",
+				Description: "Set requests to the values the Vertical Pod Autoscaler recommends from observed usage",
+				Command:     "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container) + " --requests=" + domain.ShellArg(issue.Details["vpa_requests"]),
+			})
+		}
+		if issue.Details["cause"] == "ephemeral storage limit exceeded" {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Curb the pod's local disk usage",
+				Description: "Find what fills the container filesystem or emptyDir volumes, then clean it up, move it to a persistent volume or raise the ephemeral-storage limit",
+				Command:     "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container) + " --limits=ephemeral-storage=<new-limit>",
+			})
+		} else if strings.HasPrefix(issue.Details["cause"], "node ") {
+			rec := domain.Recommendation{
+				Priority:    2,
+				Title:       "Check the node under pressure",
+				Description: "Pods using the most above their requests are evicted first; set requests close to real usage and check what else runs on the node",
+			}
+			if node := issue.Details["node"]; node != "" {
+				rec.Command = "kubectl describe node " + domain.ShellArg(node)
+			}
+			recs = append(recs, rec)
+		}
+		if budget := issue.Details["budget"]; budget != "" && strings.HasSuffix(issue.Title, "allows no disruptions") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Let the budget allow a disruption",
+				Description: "Lower minAvailable or set maxUnavailable to 1, or run more replicas, so drains and upgrades can evict a pod",
+				Command:     "kubectl edit pdb " + domain.ShellArg(budget) + " -n " + domain.ShellArg(pod.Namespace),
 			})
 		}
 		if strings.Contains(issue.Title, "BestEffort QoS") {
@@ -221,7 +552,7 @@ func getRecommendationsForIssue(issue domain.Issue, pod domain.PodInfo) []domain
 				Priority:    1,
 				Title:       "Check probe endpoint",
 				Description: "Verify the probe endpoint is responding correctly",
-				Command:     "kubectl exec " + pod.Name + " -n " + pod.Namespace + " -- curl -v localhost:<port>/<path>",
+				Command:     "kubectl exec " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -- curl -v localhost:<port>/<path>",
 			})
 		}
 		if strings.Contains(issue.Title, "No health probes") {
@@ -236,11 +567,107 @@ func getRecommendationsForIssue(issue domain.Issue, pod domain.PodInfo) []domain
 				Priority:    1,
 				Title:       "Debug readiness probe",
 				Description: "Check why readiness probe is failing",
-				Command:     "kubectl describe pod " + pod.Name + " -n " + pod.Namespace + " | grep -A10 'Readiness'",
+				Command:     "kubectl describe pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " | grep -A10 'Readiness'",
+			})
+		}
+		switch issue.Details["probe_config"] {
+		case probeConfigNamedPort:
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Fix the probe port",
+				Description: fmt.Sprintf("Name a container port %q, or point the %s probe at one of the declared ports or a port number", issue.Details["port"], issue.Details["probe"]),
+				Command:     "kubectl get pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -o jsonpath=" + domain.ShellArg("{.spec.containers[?(@.name==\""+issue.Details["container"]+"\")].ports}"),
+			})
+		case probeConfigTLSOnPlain:
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Use scheme HTTP for the probe",
+				Description: fmt.Sprintf("Port %s serves plain HTTP; set httpGet.scheme to HTTP, or point the %s probe at the app's TLS port", issue.Details["port"], issue.Details["probe"]),
+			})
+		case probeConfigHostHeader:
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Fix the probe Host header",
+				Description: fmt.Sprintf("Set the Host header to a virtual host the app serves, or remove it so the probe reaches the default host; the app answered %s to %q", issue.Details["status_code"], issue.Details["host_header"]),
 			})
 		}
 
 	case "scheduling":
+		firstNode, _, _ := strings.Cut(issue.Details["nodes"], ", ")
+		switch issue.Details["rejection"] {
+		case rejectionInsufficient:
+			resourceName := issue.Details["resource"]
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Lower " + resourceName + " requests",
+				Description: "The pod requests " + issue.Details["requested"] + " " + resourceName + ", more than any node has free; lower the request if the workload does not need it",
+				Command:     "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container) + " --requests=" + domain.ShellArg(resourceName) + "=<value>",
+			})
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Add " + resourceName + " capacity",
+				Description: "Add nodes or scale up the node group, or free capacity by removing idle workloads",
+				Command:     "kubectl describe nodes | grep -A5 'Allocated resources'",
+			})
+		case rejectionTaint:
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Tolerate taint " + issue.Details["taint"],
+				Description: "If the pod is meant to run on " + issue.Details["nodes"] + ", add a matching toleration to the pod spec; otherwise make room on untainted nodes",
+				Command:     "kubectl get nodes -o custom-columns=NAME:.metadata.name,TAINTS:.spec.taints",
+			})
+		case rejectionNodeSelector:
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Fix nodeSelector",
+				Description: "No schedulable node carries all of " + issue.Details["selector"] + "; correct the selector or label the intended nodes",
+				Command:     "kubectl label node " + domain.ShellArg(firstNode) + " " + domain.ShellArgs(strings.Split(issue.Details["selector"], ",")),
+			})
+		case rejectionNodeAffinity:
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Relax required node affinity",
+				Description: "Compare the pod's requiredDuringSchedulingIgnoredDuringExecution terms with the node labels, or use preferred affinity",
+				Command:     "kubectl get nodes --show-labels",
+			})
+		case rejectionCordoned:
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Uncordon nodes",
+				Description: "Cordoned nodes accept no new pods; uncordon them once maintenance is done",
+				Command:     "kubectl uncordon " + domain.ShellArg(firstNode),
+			})
+		case rejectionNotReady:
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Investigate NotReady nodes",
+				Description: "Check the kubelet and node conditions of the nodes that are not ready",
+				Command:     "kubectl describe node " + domain.ShellArg(firstNode),
+			})
+		}
+		if issue.Details["rejection"] != "" || issue.Details["rejections"] != "" {
+			break
+		}
+		if strings.Contains(issue.Title, "will not scale up") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Unblock cluster autoscaler",
+				Description: "Raise the node group maximum or add a node group whose labels, taints and size fit the pod",
+				Command:     "kubectl -n kube-system describe configmap cluster-autoscaler-status",
+			})
+		}
+		if strings.Contains(issue.Title, "preempted") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Protect workload from preemption",
+				Description: "Give the workload a higher PriorityClass, or reserve capacity so higher-priority pods do not need to evict it",
+				Command:     "kubectl get priorityclass",
+			})
+			break
+		}
+		if strings.Contains(issue.Title, "scale-up in progress") {
+			break
+		}
 		recs = append(recs, domain.Recommendation{
 			Priority:    1,
 			Title:       "Check node resources",
@@ -254,25 +681,345 @@ func getRecommendationsForIssue(issue domain.Issue, pod domain.PodInfo) []domain
 		})
 
 	case "node":
+		if upgrade := issue.Details["node_upgrade"]; upgrade != "" {
+			recs = append(recs, domain.Recommendation{
+				Priority:    3,
+				Title:       "Wait for the node upgrade to finish",
+				Description: "The " + upgrade + " replaces the node on purpose; give the workload enough replicas and a PodDisruptionBudget to ride out upgrades without downtime",
+				Command:     "kubectl get nodes -o wide",
+			})
+			break
+		}
 		recs = append(recs, domain.Recommendation{
 			Priority:    1,
 			Title:       "Check node status",
 			Description: "Review node conditions and events",
-			Command:     "kubectl describe node " + pod.Node,
+			Command:     "kubectl describe node " + domain.ShellArg(pod.Node),
 		})
+		if rank := issue.Details["eviction_rank"]; rank != "" {
+			description := "Requests at or above the pod's usage keep it out of the front of the line; the kubelet evicts pods using more than they request first"
+			command := ""
+			if request := issue.Details["suggested_request"]; request != "" {
+				description = "The pod uses " + issue.Details["memory_usage"] + " but requests " + issue.Details["memory_request"] + "; requesting " + request + " moves it behind every pod that exceeds its requests"
+				command = "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " -c " + domain.ShellArg(container) + " --requests=memory=" + domain.ShellArg(request)
+			}
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Raise memory requests",
+				Description: description,
+				Command:     command,
+			})
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Move the workload off " + issue.Details["node"],
+				Description: "Schedule it on nodes with spare memory with node affinity or topology spread constraints, or give it a higher PriorityClass so other pods are evicted first",
+			})
+		}
+		if node := issue.Details["node"]; node != "" && issue.Details["drain_checked"] == "true" {
+			if risks := issue.Details["drain_risks"]; risks == "" {
+				recs = append(recs, domain.Recommendation{
+					Priority:    2,
+					Title:       "Drain node " + node,
+					Description: "Move its pods to healthy nodes: every PodDisruptionBudget allows the evictions and no workload loses all its ready replicas (emptyDir data of evicted pods is lost)",
+					Command:     "kubectl drain " + domain.ShellArg(node) + " --ignore-daemonsets --delete-emptydir-data",
+				})
+			} else {
+				recs = append(recs, domain.Recommendation{
+					Priority:    2,
+					Title:       "Cordon node " + node + " but do not drain it yet",
+					Description: "Cordoning keeps new pods off the node; draining it now is unsafe: " + risks,
+					Command:     "kubectl cordon " + domain.ShellArg(node),
+				})
+			}
+		}
+
+	case "network":
+		if strings.Contains(issue.Title, "headless") || strings.Contains(issue.Title, "Governing service") ||
+			strings.Contains(issue.Title, "serviceName") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Fix headless service for peer discovery",
+				Description: "Ensure a headless service (clusterIP: None) exists and the pod's subdomain and hostname match it; StatefulSets set these from spec.serviceName",
+				Command:     "kubectl get svc -n " + domain.ShellArg(pod.Namespace) + " -o wide",
+			})
+		}
+		if strings.Contains(issue.Title, "Pod removed from service") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Restore the pod's readiness",
+				Description: "Only " + issue.Details["endpoints"] + " endpoints of service " + issue.Details["service"] + " remain; fix what keeps the pod from becoming ready, or scale out so the remaining ones are not overloaded",
+				Command:     "kubectl get endpointslices -n " + domain.ShellArg(pod.Namespace) + " -l kubernetes.io/service-name=" + domain.ShellArg(issue.Details["service"]),
+			})
+		}
+		if strings.Contains(issue.Title, "no ready endpoints") || strings.Contains(issue.Title, "targets unknown port") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Check service endpoints",
+				Description: "Verify the service selector and targetPort match ready pods",
+				Command:     "kubectl get endpointslices -n " + domain.ShellArg(pod.Namespace) + " -l kubernetes.io/service-name=" + domain.ShellArg(issue.Details["service"]),
+			})
+		}
+		if strings.Contains(issue.Title, "DNS") && !strings.Contains(issue.Title, "not published") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Review pod DNS settings",
+				Description: "Check dnsPolicy and dnsConfig; hostNetwork pods need ClusterFirstWithHostNet and dnsPolicy None requires nameservers",
+				Command:     "kubectl exec " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -- cat /etc/resolv.conf",
+			})
+		}
+		if strings.Contains(issue.Title, "Host port") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Resolve host port conflict",
+				Description: "Change one pod's port or use anti-affinity so hostNetwork pods binding the same port land on different nodes",
+			})
+		}
+		if strings.Contains(issue.Title, "No route to host") || strings.Contains(issue.Title, "Network unreachable") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Check network path",
+				Description: "Verify NetworkPolicies, CNI health and that the destination is up",
+				Command:     "kubectl get networkpolicy -n " + domain.ShellArg(pod.Namespace),
+			})
+		}
+		if strings.Contains(issue.Title, "not published") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Publish not-ready addresses",
+				Description: "Clustered apps often need to resolve peers before they become ready; set publishNotReadyAddresses: true on the headless service",
+			})
+		}
+		if strings.Contains(issue.Title, "Readiness probe port") && strings.Contains(issue.Title, "NetworkPolicy") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Allow the readiness probe port",
+				Description: "Add an ingress rule for port " + issue.Details["port"] + " from the nodes (an ipBlock with the node CIDR), or check that the CNI plugin lets the kubelet's probes through",
+				Command:     "kubectl describe networkpolicy -n " + domain.ShellArg(pod.Namespace),
+			})
+		}
+		if strings.Contains(issue.Title, "Egress to kube-dns blocked") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Allow DNS egress",
+				Description: "Add an egress rule allowing UDP and TCP 53 to pods labeled " + issue.Details["dns_selector"] + " in namespace " + issue.Details["dns_namespace"],
+				Command:     "kubectl get networkpolicy -n " + domain.ShellArg(pod.Namespace) + " -o yaml",
+			})
+		}
+
+	case "workload":
+		if ref := issue.Details["workload"]; ref != "" {
+			switch {
+			case strings.Contains(issue.Title, "paused"):
+				recs = append(recs, domain.Recommendation{
+					Priority:    2,
+					Title:       "Resume rollout",
+					Description: "Resume the paused rollout once the pending changes are ready to ship",
+					Command:     "kubectl rollout resume " + domain.ShellArg(ref) + " -n " + domain.ShellArg(pod.Namespace),
+				})
+			case strings.Contains(issue.Title, "Rollout of") && strings.Contains(issue.Title, "failed"):
+				recs = append(recs, domain.Recommendation{
+					Priority:    1,
+					Title:       "Inspect or roll back failed rollout",
+					Description: "The rollout exceeded its progress deadline; check the new pods or roll back to the previous revision",
+					Command:     "kubectl rollout undo " + domain.ShellArg(ref) + " -n " + domain.ShellArg(pod.Namespace),
+				})
+			case strings.Contains(issue.Title, "Single-replica"):
+				recs = append(recs, domain.Recommendation{
+					Priority:    1,
+					Title:       "Scale out single-replica workload",
+					Description: "A single replica leaves no capacity to fail over to; run at least 2 replicas (and add a PodDisruptionBudget) so one bad pod does not take the service down",
+					Command:     "kubectl scale " + domain.ShellArg(ref) + " -n " + domain.ShellArg(pod.Namespace) + " --replicas=2",
+				})
+			case strings.Contains(issue.Title, "missing replicas"):
+				recs = append(recs, domain.Recommendation{
+					Priority:    2,
+					Title:       "Check workload status",
+					Description: "Review why some replicas are not available",
+					Command:     "kubectl rollout status " + domain.ShellArg(ref) + " -n " + domain.ShellArg(pod.Namespace),
+				})
+			}
+		}
+		if job := issue.Details["job"]; job != "" && strings.Contains(issue.Title, "failed") {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Inspect failed job",
+				Description: "Review the job's conditions, backoff limit and remaining pods",
+				Command:     "kubectl describe job " + domain.ShellArg(job) + " -n " + domain.ShellArg(pod.Namespace),
+			})
+		}
+
+	case "storage":
+		switch {
+		case issue.Details["claim"] != "" && (strings.Contains(issue.Title, "not found") || strings.Contains(issue.Title, "Pending")):
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Check persistent volume claim",
+				Description: "Review the claim's events for provisioning errors and confirm its storage class exists",
+				Command:     "kubectl describe pvc " + domain.ShellArg(issue.Details["claim"]) + " -n " + domain.ShellArg(pod.Namespace),
+			})
+		case strings.Contains(issue.Details["cause"], "another node"):
+			// Volume attachments name the PV bound to the claim, which
+			// differs from the pod's volume name
+			pv := ""
+			if name := issue.Details["persistent_volume"]; name != "" {
+				pv = domain.ShellArg(name)
+			} else if claim, ok := strings.CutPrefix(issue.Details["source"], "pvc/"); ok {
+				pv = "$(kubectl get pvc " + domain.ShellArg(claim) + " -n " + domain.ShellArg(pod.Namespace) + " -o jsonpath='{.spec.volumeName}')"
+			}
+			rec := domain.Recommendation{
+				Priority:    1,
+				Title:       "Release volume from the previous node",
+				Description: "A ReadWriteOnce volume can only attach to one node; wait for or delete the old pod still using it",
+			}
+			if pv != "" {
+				rec.Command = "kubectl get volumeattachments | grep " + pv
+			}
+			recs = append(recs, rec)
+		case strings.Contains(issue.Title, "failed to"):
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Fix volume source",
+				Description: "Create the missing ConfigMap/Secret or fix the volume definition so it can be mounted",
+				Command:     "kubectl describe pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace),
+			})
+		}
+
+	case "security":
+		switch issue.Details["security_context"] {
+		case securityPrivilegedPort:
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Allow binding port " + issue.Details["port"],
+				Description: "Listen on a port of 1024 or above and map it with the Service's targetPort, or set the safe sysctl " + unprivilegedPortSysctl + "=" + issue.Details["port"] + " under the pod's securityContext.sysctls",
+				Command:     "kubectl patch " + domain.ShellArg(target) + " -n " + domain.ShellArg(pod.Namespace) + " --type=merge -p " + domain.ShellArg(`{"spec":{"template":{"spec":{"securityContext":{"sysctls":[{"name":"`+unprivilegedPortSysctl+`","value":"`+issue.Details["port"]+`"}]}}}}}`),
+			})
+		case securityCapability:
+			description := "Add " + issue.Details["capability"] + " under securityContext.capabilities.add of " + container + " (and remove it from drop), if the workload really needs it"
+			if issue.Details["non_root"] == "true" {
+				description = "Capabilities are not effective for non-root processes: run " + container + " as root with " + issue.Details["capability"] + " added, or grant it to the binary with setcap in the image"
+			}
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Grant capability " + issue.Details["capability"],
+				Description: description,
+			})
+		case securitySysctl:
+			key := issue.Details["sysctl"]
+			description := "Set " + key + " under the pod's securityContext.sysctls"
+			switch issue.Details["sysctl_scope"] {
+			case "unsafe":
+				description += "; it is not a safe sysctl, so the kubelet must also allow it with --allowed-unsafe-sysctls=" + key
+			case "node":
+				description = key + " is not namespaced and applies to the whole node; set it on the nodes (e.g. with a node tuning DaemonSet) and remove the write from the container"
+			}
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Set sysctl " + key,
+				Description: description,
+			})
+		case securityPortInUse:
+			description := "Move " + container + " or the node process holding port " + issue.Details["port"] + " to another port"
+			if holder := issue.Details["holder"]; holder != "" {
+				description = "Move " + container + " or " + holder + " to another port; containers of a pod cannot listen on the same one"
+			}
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Resolve the port conflict",
+				Description: description,
+			})
+		}
+
+		image := issue.Details["image"]
+		switch {
+		case strings.Contains(issue.Title, "Untrusted registry"):
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Use an image from a trusted registry",
+				Description: "Mirror the image into one of the trusted registries (" + issue.Details["trusted_registries"] + ") and update the workload",
+			})
+		case strings.Contains(issue.Title, "Unsigned image") || strings.Contains(issue.Title, "Invalid image signature"):
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Sign the image",
+				Description: "Sign the image digest with the key trusted for its registry in your release pipeline",
+				Command:     "cosign sign --key cosign.key " + domain.ShellArg(strings.SplitN(image, "@", 2)[0]+"@"+issue.Details["digest"]),
+			})
+		case strings.Contains(issue.Title, "unverifiable"):
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Verify the signature manually",
+				Description: "Check registry reachability, or verify with cosign using registry credentials",
+				Command:     "cosign verify --key cosign.pub " + domain.ShellArg(strings.SplitN(image, "@", 2)[0]+"@"+issue.Details["digest"]),
+			})
+		}
+
+	case "conformance":
+		field := issue.Details["field"]
+		verb := "label"
+		if field == "annotations" {
+			verb = "annotate"
+		}
+		var assignments []string
+		for _, key := range strings.Split(issue.Details["missing"], ",") {
+			if !strings.ContainsAny(key, "*?[") {
+				assignments = append(assignments, domain.ShellArg(key)+"=<value>")
+			}
+		}
+		object := issue.Details["object"]
+		rec := domain.Recommendation{
+			Priority:    3,
+			Title:       "Add required " + field + " to " + object,
+			Description: "Set " + strings.ReplaceAll(issue.Details["missing"], ",", ", ") + " on " + object,
+		}
+		if strings.HasPrefix(object, "pod/") && workload != nil {
+			// Labels set on a pod are lost when it is replaced
+			rec.Title = "Add required " + field + " to the pod template"
+			rec.Description = "Set " + strings.ReplaceAll(issue.Details["missing"], ",", ", ") + " under spec.template.metadata." + field + " of " + workload.Ref() + " so every new pod has them"
+		} else if len(assignments) > 0 {
+			rec.Command = "kubectl " + verb + " " + domain.ShellArg(object) + " -n " + domain.ShellArg(pod.Namespace) + " " + strings.Join(assignments, " ")
+		}
+		recs = append(recs, rec)
 
 	case "logs":
 		recs = append(recs, domain.Recommendation{
 			Priority:    2,
 			Title:       "Review full logs",
 			Description: "Check complete container logs for more context",
-			Command:     "kubectl logs " + pod.Name + " -n " + pod.Namespace + " --tail=100",
+			Command:     "kubectl logs " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " --tail=100",
 		})
+		if target := issue.Details["target"]; target != "" {
+			recs = append(recs, domain.Recommendation{
+				Priority:    2,
+				Title:       "Test the connection from inside the pod",
+				Description: "Open a shell with network tools in the pod's network namespace and try to reach " + target,
+				Command:     debugCommand(pod, issue.Details["container"]),
+			})
+		}
+
+	case "serviceaccount":
+		recs = append(recs, tokenRecommendations(issue, pod, workload)...)
+
+	case "spec":
+		recs = append(recs, specSizeRecommendations(issue, pod, workload)...)
 	}
 
 	return recs
 }
 
+// debugNetworkImage is the debug container image suggested for network
+// problems, as it ships curl, dig, nc and tcpdump
+const debugNetworkImage = "nicolaka/netshoot"
+
+// debugCommand returns the pod-doctor debug command that opens a shell with
+// network tools next to a container
+func debugCommand(pod domain.PodInfo, container string) string {
+	command := "pod-doctor debug " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace)
+	if container != "" {
+		command += " -c " + domain.ShellArg(container)
+	}
+	return command + " --image " + debugNetworkImage
+}
+
 // containsReason checks if the issue contains a specific reason
 func containsReason(issue domain.Issue, reason string) bool {
 	if issue.Details != nil {
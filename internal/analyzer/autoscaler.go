@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// autoscalerStatusNamespace and autoscalerStatusConfigMap locate the
+	// status the cluster autoscaler publishes
+	autoscalerStatusNamespace = "kube-system"
+	autoscalerStatusConfigMap = "cluster-autoscaler-status"
+
+	// typicalNodeProvisionTime is a rough estimate of how long a new node
+	// takes to join the cluster after a scale-up is triggered
+	typicalNodeProvisionTime = 5 * time.Minute
+)
+
+// scaleUpStatePattern extracts the cluster-wide scale-up state from the
+// status configmap, in both the legacy text and the YAML formats
+var scaleUpStatePattern = regexp.MustCompile(`(?is)scaleUp:\s*(?:status:\s*)?(\w+)`)
+
+// AutoscalerAnalyzer explains whether the cluster autoscaler will add
+// capacity for a pending pod
+type AutoscalerAnalyzer struct{}
+
+// NewAutoscalerAnalyzer creates a new AutoscalerAnalyzer
+func NewAutoscalerAnalyzer() *AutoscalerAnalyzer {
+	return &AutoscalerAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (a *AutoscalerAnalyzer) Name() string {
+	return "autoscaler"
+}
+
+// Analyze reports the autoscaler's view of an unschedulable pod
+func (a *AutoscalerAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return nil, nil
+	}
+
+	events, err := client.GetPodEvents(ctx, pod.Namespace, pod.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the most recent autoscaler decision about this pod
+	var latest *domain.EventInfo
+	for i := range events {
+		e := &events[i]
+		if e.Source != "cluster-autoscaler" {
+			continue
+		}
+		if latest == nil || e.LastSeen.After(latest.LastSeen) {
+			latest = e
+		}
+	}
+
+	scaleUpState := ""
+	if cm, err := client.GetConfigMap(ctx, autoscalerStatusNamespace, autoscalerStatusConfigMap); err == nil {
+		if m := scaleUpStatePattern.FindStringSubmatch(cm.Data["status"]); m != nil {
+			scaleUpState = m[1]
+		}
+	}
+
+	if latest == nil {
+		if scaleUpState == "" {
+			return []domain.Issue{{
+				Severity:    domain.SeverityInfo,
+				Category:    "scheduling",
+				Title:       "No cluster autoscaler activity",
+				Description: "No cluster autoscaler was detected for this pod; it will only schedule once existing capacity frees up",
+				Details:     map[string]string{},
+			}}, nil
+		}
+		return nil, nil
+	}
+
+	switch latest.Reason {
+	case "TriggeredScaleUp":
+		return []domain.Issue{a.scaleUpInProgress(*latest, scaleUpState)}, nil
+	case "NotTriggerScaleUp":
+		return []domain.Issue{a.scaleUpBlocked(*latest)}, nil
+	default:
+		if strings.Contains(latest.Reason, "Failed") {
+			return []domain.Issue{{
+				Severity:    domain.SeverityCritical,
+				Category:    "scheduling",
+				Title:       "Cluster autoscaler scale-up failed",
+				Description: latest.Message,
+				Details: map[string]string{
+					"autoscaler_reason": latest.Reason,
+					"last_seen":         latest.LastSeen.Format("2006-01-02 15:04:05"),
+				},
+			}}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// scaleUpInProgress reports a triggered scale-up with a rough ETA
+func (a *AutoscalerAnalyzer) scaleUpInProgress(event domain.EventInfo, state string) domain.Issue {
+	elapsed := time.Since(event.LastSeen).Round(time.Second)
+	eta := "should be ready within a few minutes"
+	if remaining := typicalNodeProvisionTime - elapsed; remaining > 0 {
+		eta = fmt.Sprintf("new node typically ready in ~%s", remaining.Round(time.Minute))
+	} else {
+		eta = fmt.Sprintf("scale-up triggered %s ago, longer than usual; check the cloud provider for provisioning errors", elapsed)
+	}
+
+	issue := domain.Issue{
+		Severity:    domain.SeverityInfo,
+		Category:    "scheduling",
+		Title:       "Cluster autoscaler scale-up in progress",
+		Description: fmt.Sprintf("Waiting should be enough: %s", eta),
+		Details: map[string]string{
+			"autoscaler_reason": event.Reason,
+			"scale_up":          event.Message,
+			"triggered":         event.LastSeen.Format("2006-01-02 15:04:05"),
+		},
+	}
+	if state != "" {
+		issue.Details["scale_up_state"] = state
+	}
+	return issue
+}
+
+// scaleUpBlocked explains why the autoscaler will not add a node for the pod
+func (a *AutoscalerAnalyzer) scaleUpBlocked(event domain.EventInfo) domain.Issue {
+	msg := strings.ToLower(event.Message)
+
+	blocker := "no node group can fit the pod"
+	switch {
+	case strings.Contains(msg, "max node group size reached"), strings.Contains(msg, "max cluster"), strings.Contains(msg, "maxnodestotal"):
+		blocker = "maximum node count reached"
+	case strings.Contains(msg, "didn't match") || strings.Contains(msg, "node affinity") || strings.Contains(msg, "taint"):
+		blocker = "no node group matches the pod's selectors, affinity or tolerations"
+	case strings.Contains(msg, "insufficient"):
+		blocker = "pod requests more than a node in any group can provide"
+	}
+
+	return domain.Issue{
+		Severity:    domain.SeverityCritical,
+		Category:    "scheduling",
+		Title:       "Cluster autoscaler will not scale up",
+		Description: fmt.Sprintf("Waiting will not help: %s", blocker),
+		Details: map[string]string{
+			"autoscaler_reason": event.Reason,
+			"message":           event.Message,
+			"blocker":           blocker,
+		},
+	}
+}
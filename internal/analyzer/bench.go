@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Bench runs each analyzer over every pod in turn and measures its time
+// and allocations per pod, slowest analyzer first. Analyzers run one at a
+// time, so the allocations counted are their own.
+func (p *PodAnalyzer) Bench(ctx context.Context, pods []corev1.Pod, source string) *domain.BenchReport {
+	report := &domain.BenchReport{APIVersion: domain.APIVersion, Source: source, Pods: len(pods)}
+	if len(pods) == 0 {
+		return report
+	}
+
+	var before, after runtime.MemStats
+	for _, a := range p.analyzers {
+		result := domain.AnalyzerBench{Name: a.Name()}
+
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		for i := range pods {
+			issues, err := a.Analyze(ctx, &pods[i], p.client)
+			if err != nil {
+				result.Errors++
+			}
+			result.Issues += len(issues)
+		}
+		result.Duration = time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		n := len(pods)
+		result.PerPod = result.Duration / time.Duration(n)
+		if result.Duration > 0 {
+			result.PodsPerSecond = float64(n) / result.Duration.Seconds()
+		}
+		result.AllocsPerPod = (after.Mallocs - before.Mallocs) / uint64(n)
+		result.BytesPerPod = (after.TotalAlloc - before.TotalAlloc) / uint64(n)
+		report.Duration += result.Duration
+		report.Analyzers = append(report.Analyzers, result)
+	}
+
+	sort.SliceStable(report.Analyzers, func(i, j int) bool {
+		return report.Analyzers[i].Duration > report.Analyzers[j].Duration
+	})
+	return report
+}
+
+// benchScenarios builds the pods SyntheticPods cycles through, each a
+// common state the analyzers have to explain
+var benchScenarios = []func(pod *corev1.Pod){
+	// Healthy
+	func(pod *corev1.Pod) {},
+	// Crash looping after exiting with an error
+	func(pod *corev1.Pod) {
+		cs := &pod.Status.ContainerStatuses[0]
+		cs.Ready = false
+		cs.RestartCount = 12
+		cs.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 5m0s restarting failed container"}}
+		cs.LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}
+	},
+	// OOMKilled
+	func(pod *corev1.Pod) {
+		cs := &pod.Status.ContainerStatuses[0]
+		cs.RestartCount = 3
+		cs.LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}}
+	},
+	// Image pull failing
+	func(pod *corev1.Pod) {
+		pod.Status.Conditions = nil
+		cs := &pod.Status.ContainerStatuses[0]
+		cs.Ready = false
+		cs.Started = nil
+		cs.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: `Back-off pulling image "registry.example.com/web:v2"`}}
+	},
+	// Unschedulable
+	func(pod *corev1.Pod) {
+		pod.Spec.NodeName = ""
+		pod.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector.",
+			}},
+		}
+	},
+	// Not ready: failing readiness probe and a readiness gate without a condition
+	func(pod *corev1.Pod) {
+		pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: "target-health.elbv2.k8s.aws/k8s-bench-web-0123456789"}}
+		pod.Status.ContainerStatuses[0].Ready = false
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+	},
+	// Native sidecar with an app that started before it
+	func(pod *corev1.Pod) {
+		always := corev1.ContainerRestartPolicyAlways
+		pod.Spec.InitContainers = []corev1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.31.0", RestartPolicy: &always}}
+		started := true
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+			Name:    "proxy",
+			Ready:   true,
+			Started: &started,
+			State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(pod.CreationTimestamp.Add(20 * time.Second))}},
+		}}
+		pod.Status.ContainerStatuses[0].RestartCount = 2
+	},
+	// Evicted for node memory pressure
+	func(pod *corev1.Pod) {
+		pod.Status = corev1.PodStatus{
+			Phase:   corev1.PodFailed,
+			Reason:  "Evicted",
+			Message: "The node was low on resource: memory. Threshold quantity: 100Mi, available: 90Mi. Container web was using 1.2Gi, request is 256Mi, has larger consumption of memory.",
+		}
+	},
+}
+
+// SyntheticPods generates n pods of a Deployment in namespace bench, cycling
+// through healthy, crash looping, OOMKilled, image pull, unschedulable, not
+// ready, sidecar and evicted pods, for benchmarking the analyzers without a
+// cluster
+func SyntheticPods(n int) []corev1.Pod {
+	created := time.Now().Add(-time.Hour)
+	pods := make([]corev1.Pod, 0, n)
+	for i := 0; i < n; i++ {
+		pod := syntheticPod(i, created)
+		benchScenarios[i%len(benchScenarios)](&pod)
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+// syntheticPod returns a healthy running pod with resources and probes
+func syntheticPod(i int, created time.Time) corev1.Pod {
+	controller := true
+	started := true
+	probe := &corev1.Probe{
+		ProbeHandler:  corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)}},
+		PeriodSeconds: 10,
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("web-7d9f8c6b5-%05d", i),
+			GenerateName:      "web-7d9f8c6b5-",
+			Namespace:         "bench",
+			Labels:            map[string]string{"app": "web", "pod-template-hash": "7d9f8c6b5"},
+			CreationTimestamp: metav1.NewTime(created),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "web-7d9f8c6b5",
+				Controller: &controller,
+			}},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:           fmt.Sprintf("node-%d", i%3),
+			ServiceAccountName: "web",
+			Containers: []corev1.Container{{
+				Name:  "web",
+				Image: "registry.example.com/web:v1",
+				Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+				Env:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				},
+				LivenessProbe:  probe,
+				ReadinessProbe: probe,
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase:     corev1.PodRunning,
+			StartTime: &metav1.Time{Time: created},
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(30 * time.Second))},
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:    "web",
+				Image:   "registry.example.com/web:v1",
+				Ready:   true,
+				Started: &started,
+				State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(created.Add(10 * time.Second))}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// expectedFailureAnnotation lets teams mark pods that are expected to fail,
+// e.g. during a manual game day without a chaos tool. Its value names the
+// experiment.
+const expectedFailureAnnotation = "pod-doctor.io/expected-failure"
+
+// chaosCacheTTL is how long the list of running experiments is reused, so a
+// scan does not list every chaos CRD once per pod
+const chaosCacheTTL = 30 * time.Second
+
+// chaosCache holds the running chaos experiments shared by all diagnoses
+type chaosCache struct {
+	mu          sync.Mutex
+	fetched     time.Time
+	experiments []kubernetes.ChaosExperiment
+}
+
+// expectedFailure returns the experiment disrupting the pod, or the upgrade
+// of its node, or "" if none
+func (p *PodAnalyzer) expectedFailure(ctx context.Context, pod *corev1.Pod) string {
+	if name := pod.Annotations[expectedFailureAnnotation]; name != "" {
+		return "experiment " + name
+	}
+
+	key := pod.Namespace + "/" + pod.Name
+	for _, exp := range p.activeExperiments(ctx) {
+		for _, target := range exp.Pods {
+			if target == key || strings.HasPrefix(target, key+"/") {
+				return "experiment " + exp.Ref()
+			}
+		}
+		if exp.TargetSelector != nil && exp.TargetNamespace == pod.Namespace && exp.TargetSelector.Matches(labels.Set(pod.Labels)) {
+			return "experiment " + exp.Ref()
+		}
+	}
+	return p.nodeUpgradeFailure(ctx, pod)
+}
+
+// activeExperiments returns the running chaos experiments, refreshing the
+// cached list when it is stale
+func (p *PodAnalyzer) activeExperiments(ctx context.Context) []kubernetes.ChaosExperiment {
+	p.chaos.mu.Lock()
+	defer p.chaos.mu.Unlock()
+
+	if time.Since(p.chaos.fetched) < chaosCacheTTL {
+		return p.chaos.experiments
+	}
+
+	// On error, keep the last known experiments and retry after the TTL
+	// rather than on every pod
+	if experiments, err := p.client.ListActiveChaosExperiments(ctx); err == nil {
+		p.chaos.experiments = experiments
+	} else {
+		slog.Warn("failed to list chaos experiments", "error", err)
+	}
+	p.chaos.fetched = time.Now()
+	return p.chaos.experiments
+}
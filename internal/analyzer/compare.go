@@ -0,0 +1,401 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// revisionLabels name the revision of its workload a pod was created from
+var revisionLabels = []string{"pod-template-hash", "controller-revision-hash"}
+
+// Compare diagnoses two pods of a namespace and returns how they differ
+func (p *PodAnalyzer) Compare(ctx context.Context, namespace, nameA, nameB string) (*domain.PodComparison, error) {
+	if nameA == nameB {
+		return nil, fmt.Errorf("cannot compare pod %s with itself", nameA)
+	}
+
+	pods := make([]*corev1.Pod, 2)
+	diagnoses := make([]*domain.Diagnosis, 2)
+	for i, name := range []string{nameA, nameB} {
+		pod, err := p.client.GetPod(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		diagnosis, err := p.Diagnose(ctx, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diagnose pod %s: %w", name, err)
+		}
+		pods[i], diagnoses[i] = pod, diagnosis
+	}
+	return comparePods(pods[0], pods[1], diagnoses[0], diagnoses[1]), nil
+}
+
+// HealthyReplica returns the name of the healthiest other pod of a pod's
+// workload, to compare a failing replica with: a ready one with the fewest
+// restarts, the longest running first
+func (p *PodAnalyzer) HealthyReplica(ctx context.Context, namespace, name string) (string, error) {
+	pod, err := p.client.GetPod(ctx, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	workload, err := p.client.ResolveWorkload(ctx, pod)
+	if err != nil {
+		return "", err
+	}
+	if workload == nil {
+		return "", fmt.Errorf("pod %s has no controller, so it has no replicas to compare with; name the pod to compare it with", name)
+	}
+	selector, err := p.client.WorkloadSelector(ctx, namespace, workload.Kind, workload.Name)
+	if err != nil {
+		return "", fmt.Errorf("cannot find the replicas of %s: %w; name the pod to compare it with", workload.Ref(), err)
+	}
+	list, err := p.client.ListPods(ctx, namespace, selector)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []*corev1.Pod
+	for i := range list.Items {
+		c := &list.Items[i]
+		if c.Name != name && c.DeletionTimestamp == nil {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%s has no other replica to compare %s with", workload.Ref(), name)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if ra, rb := isPodReady(a), isPodReady(b); ra != rb {
+			return ra
+		}
+		if ra, rb := podRestarts(a), podRestarts(b); ra != rb {
+			return ra < rb
+		}
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	})
+	return candidates[0].Name, nil
+}
+
+// comparePods lists how two pods differ in revision, placement, the spec
+// and state of each container, and the issues found
+func comparePods(a, b *corev1.Pod, da, db *domain.Diagnosis) *domain.PodComparison {
+	c := &domain.PodComparison{
+		APIVersion: domain.APIVersion,
+		Namespace:  a.Namespace,
+		A:          comparedPod(a, da),
+		B:          comparedPod(b, db),
+	}
+	ca, cb := metav1.GetControllerOf(a), metav1.GetControllerOf(b)
+	c.SameWorkload = c.A.Workload != "" && c.A.Workload == c.B.Workload ||
+		ca != nil && cb != nil && ca.UID == cb.UID
+	switch {
+	case da.HealthScore < db.HealthScore:
+		c.Failing = a.Name
+	case db.HealthScore < da.HealthScore:
+		c.Failing = b.Name
+	}
+
+	diff := func(field, container, va, vb string) {
+		if va != vb {
+			c.Differences = append(c.Differences, domain.PodDifference{Field: field, Container: container, A: va, B: vb})
+		}
+	}
+	for _, label := range revisionLabels {
+		diff("revision ("+label+")", "", a.Labels[label], b.Labels[label])
+	}
+	diff("node", "", a.Spec.NodeName, b.Spec.NodeName)
+	diff("node condition", "", nodeCondition(da.Node), nodeCondition(db.Node))
+	diff("QoS class", "", string(a.Status.QOSClass), string(b.Status.QOSClass))
+
+	specsA, specsB := containerSpecs(a), containerSpecs(b)
+	for _, name := range containerNames(a, b) {
+		sa, okA := specsA[name]
+		sb, okB := specsB[name]
+		if !okA || !okB {
+			diff("container", name, presence(okA), presence(okB))
+			continue
+		}
+		statusA, statusB := anyContainerStatus(a, name), anyContainerStatus(b, name)
+
+		diff("image", name, sa.Image, sb.Image)
+		if sa.Image == sb.Image {
+			// The same tag may have resolved to different images
+			diff("image digest", name, imageID(statusA), imageID(statusB))
+		}
+		diff("command", name, strings.Join(append(append([]string(nil), sa.Command...), sa.Args...), " "),
+			strings.Join(append(append([]string(nil), sb.Command...), sb.Args...), " "))
+		for _, r := range []struct {
+			field string
+			list  func(corev1.ResourceRequirements) corev1.ResourceList
+			name  corev1.ResourceName
+		}{
+			{"cpu request", requestsOf, corev1.ResourceCPU},
+			{"cpu limit", limitsOf, corev1.ResourceCPU},
+			{"memory request", requestsOf, corev1.ResourceMemory},
+			{"memory limit", limitsOf, corev1.ResourceMemory},
+		} {
+			diff(r.field, name, quantity(r.list(sa.Resources), r.name), quantity(r.list(sb.Resources), r.name))
+		}
+
+		envA, envB := envValues(sa), envValues(sb)
+		for _, key := range unionKeys(envA, envB) {
+			diff("env "+key, name, valueOrUnset(envA[key]), valueOrUnset(envB[key]))
+		}
+		diff("envFrom", name, envFromSources(sa), envFromSources(sb))
+
+		diff("state", name, containerState(statusA), containerState(statusB))
+		diff("last termination", name, lastTermination(statusA), lastTermination(statusB))
+		diff("restarts", name, restartCount(statusA), restartCount(statusB))
+	}
+
+	c.OnlyA = subtractIssues(da.Issues, db.Issues)
+	c.OnlyB = subtractIssues(db.Issues, da.Issues)
+	c.Shared = make([]domain.Issue, 0)
+	inB := issueSet(db.Issues)
+	for _, issue := range da.Issues {
+		if inB[comparedIssueKey(issue)] {
+			c.Shared = append(c.Shared, issue)
+		}
+	}
+	if c.Differences == nil {
+		c.Differences = make([]domain.PodDifference, 0)
+	}
+	return c
+}
+
+// comparedPod summarizes a pod for a comparison
+func comparedPod(pod *corev1.Pod, d *domain.Diagnosis) domain.ComparedPod {
+	cp := domain.ComparedPod{
+		Name:        pod.Name,
+		Status:      d.Status,
+		HealthScore: d.HealthScore,
+		Node:        pod.Spec.NodeName,
+		Restarts:    podRestarts(pod),
+	}
+	if d.Workload != nil {
+		cp.Workload = d.Workload.Ref()
+	}
+	return cp
+}
+
+// containerNames lists the init and app containers of either pod, those of
+// the first pod first
+func containerNames(a, b *corev1.Pod) []string {
+	var names []string
+	for _, pod := range []*corev1.Pod{a, b} {
+		for _, c := range pod.Spec.InitContainers {
+			names = appendMissing(names, c.Name)
+		}
+		for _, c := range pod.Spec.Containers {
+			names = appendMissing(names, c.Name)
+		}
+	}
+	return names
+}
+
+// anyContainerStatus returns the status of an init or app container, or nil
+func anyContainerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	if s := findStatus(pod.Status.ContainerStatuses, name); s != nil {
+		return s
+	}
+	return findStatus(pod.Status.InitContainerStatuses, name)
+}
+
+func requestsOf(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests }
+
+func limitsOf(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits }
+
+// quantity formats a resource of a list, or "none"
+func quantity(list corev1.ResourceList, name corev1.ResourceName) string {
+	if q, ok := list[name]; ok {
+		return q.String()
+	}
+	return "none"
+}
+
+// envValues describes each environment variable of a container by its
+// value or the source it is read from
+func envValues(c corev1.Container) map[string]string {
+	values := make(map[string]string, len(c.Env))
+	for _, e := range c.Env {
+		values[e.Name] = envSource(e)
+	}
+	return values
+}
+
+// envSource describes where an environment variable gets its value
+func envSource(e corev1.EnvVar) string {
+	from := e.ValueFrom
+	switch {
+	case from == nil:
+		return fmt.Sprintf("%q", truncateLine(e.Value, 60))
+	case from.SecretKeyRef != nil:
+		return fmt.Sprintf("secret %s key %s", from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+	case from.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configmap %s key %s", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key)
+	case from.FieldRef != nil:
+		return "field " + from.FieldRef.FieldPath
+	case from.ResourceFieldRef != nil:
+		return "resource " + from.ResourceFieldRef.Resource
+	}
+	return "unknown source"
+}
+
+// envFromSources lists the ConfigMaps and Secrets a container imports
+// environment variables from
+func envFromSources(c corev1.Container) string {
+	var sources []string
+	for _, s := range c.EnvFrom {
+		switch {
+		case s.ConfigMapRef != nil:
+			sources = append(sources, s.Prefix+"configmap/"+s.ConfigMapRef.Name)
+		case s.SecretRef != nil:
+			sources = append(sources, s.Prefix+"secret/"+s.SecretRef.Name)
+		}
+	}
+	return strings.Join(sources, ", ")
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueOrUnset(v string) string {
+	if v == "" {
+		return "(unset)"
+	}
+	return v
+}
+
+func presence(ok bool) string {
+	if ok {
+		return "present"
+	}
+	return "missing"
+}
+
+// imageID returns the digest a container's image resolved to
+func imageID(s *corev1.ContainerStatus) string {
+	if s == nil {
+		return ""
+	}
+	if _, digest, ok := strings.Cut(s.ImageID, "@"); ok {
+		return digest
+	}
+	return s.ImageID
+}
+
+// containerState describes a container's current state, e.g. "waiting
+// (CrashLoopBackOff)"
+func containerState(s *corev1.ContainerStatus) string {
+	switch {
+	case s == nil:
+		return "unknown"
+	case s.State.Running != nil:
+		if s.Ready {
+			return "running, ready"
+		}
+		return "running, not ready"
+	case s.State.Waiting != nil:
+		return "waiting (" + s.State.Waiting.Reason + ")"
+	case s.State.Terminated != nil:
+		return fmt.Sprintf("terminated (%s, exit code %d)", s.State.Terminated.Reason, s.State.Terminated.ExitCode)
+	}
+	return "unknown"
+}
+
+// lastTermination describes how a container last terminated, if it did
+func lastTermination(s *corev1.ContainerStatus) string {
+	if s == nil || s.LastTerminationState.Terminated == nil {
+		return "none"
+	}
+	t := s.LastTerminationState.Terminated
+	return fmt.Sprintf("%s (exit code %d)", t.Reason, t.ExitCode)
+}
+
+func restartCount(s *corev1.ContainerStatus) string {
+	if s == nil {
+		return "0"
+	}
+	return fmt.Sprintf("%d", s.RestartCount)
+}
+
+// nodeCondition summarizes the health of a pod's node
+func nodeCondition(n *domain.NodeHealth) string {
+	if n == nil {
+		return "unknown"
+	}
+	var conditions []string
+	if !n.Ready {
+		conditions = append(conditions, "NotReady")
+	}
+	if n.MemoryPressure {
+		conditions = append(conditions, "MemoryPressure")
+	}
+	if n.DiskPressure {
+		conditions = append(conditions, "DiskPressure")
+	}
+	if n.PIDPressure {
+		conditions = append(conditions, "PIDPressure")
+	}
+	if n.NetworkUnavail {
+		conditions = append(conditions, "NetworkUnavailable")
+	}
+	if len(conditions) == 0 {
+		return "Ready"
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// podRestarts sums the restarts of a pod's containers
+func podRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, s := range pod.Status.ContainerStatuses {
+		restarts += s.RestartCount
+	}
+	return restarts
+}
+
+// subtractIssues returns the issues in a that are not in b
+func subtractIssues(a, b []domain.Issue) []domain.Issue {
+	inB := issueSet(b)
+	result := make([]domain.Issue, 0)
+	for _, issue := range a {
+		if !inB[comparedIssueKey(issue)] {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+func issueSet(issues []domain.Issue) map[string]bool {
+	set := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		set[comparedIssueKey(issue)] = true
+	}
+	return set
+}
+
+// comparedIssueKey identifies an issue across two pods by its category and
+// title, which name the container but not the pod
+func comparedIssueKey(issue domain.Issue) string {
+	return issue.Category + "\x00" + issue.Title
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ConformanceAnalyzer checks that pods and their owning workloads carry the
+// labels and annotations a platform team requires, e.g. owner and
+// cost-center
+type ConformanceAnalyzer struct {
+	labels      []string
+	annotations []string
+	severity    domain.Severity
+}
+
+// NewConformanceAnalyzer creates a ConformanceAnalyzer from its configuration
+func NewConformanceAnalyzer(cfg ConformanceConfig) *ConformanceAnalyzer {
+	return &ConformanceAnalyzer{
+		labels:      cfg.RequiredLabels,
+		annotations: cfg.RequiredAnnotations,
+		severity:    domain.Severity(cfg.Severity),
+	}
+}
+
+// Name returns the analyzer name
+func (c *ConformanceAnalyzer) Name() string {
+	return "conformance"
+}
+
+// Analyze reports required labels and annotations missing from the pod and
+// from its top-level workload
+func (c *ConformanceAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	if len(c.labels) == 0 && len(c.annotations) == 0 {
+		return nil, nil
+	}
+
+	issues := c.check("Pod", pod.Name, pod.Labels, pod.Annotations)
+
+	workload, err := client.ResolveWorkload(ctx, pod)
+	if err != nil || workload == nil || workloadKinds[strings.ToLower(workload.Kind)] == "" {
+		// Static pods are owned by their Node, which is no workload
+		return issues, err
+	}
+	owner, err := client.GetWorkloadMeta(ctx, pod.Namespace, workload.Kind, workload.Name)
+	if err != nil {
+		return issues, err
+	}
+	return append(issues, c.check(workload.Kind, workload.Name, owner.GetLabels(), owner.GetAnnotations())...), nil
+}
+
+// check reports the required labels and annotations an object lacks
+func (c *ConformanceAnalyzer) check(kind, name string, labels, annotations map[string]string) []domain.Issue {
+	var issues []domain.Issue
+	if missing := missingKeys(c.labels, labels); len(missing) > 0 {
+		issues = append(issues, c.issue(kind, name, "labels", missing))
+	}
+	if missing := missingKeys(c.annotations, annotations); len(missing) > 0 {
+		issues = append(issues, c.issue(kind, name, "annotations", missing))
+	}
+	return issues
+}
+
+func (c *ConformanceAnalyzer) issue(kind, name, field string, missing []string) domain.Issue {
+	return domain.NewIssue(
+		c.severity,
+		"conformance",
+		fmt.Sprintf("%s missing required %s", kind, field),
+		fmt.Sprintf("%s %s has no %s %s", kind, name, strings.Join(missing, ", "), field),
+	).WithDetail("object", strings.ToLower(kind)+"/"+name).
+		WithDetail("field", field).
+		WithDetail("missing", strings.Join(missing, ","))
+}
+
+// missingKeys returns the required keys absent from m. A key with a glob,
+// such as app.kubernetes.io/*, is satisfied by any matching key.
+func missingKeys(required []string, m map[string]string) []string {
+	var missing []string
+	for _, key := range required {
+		if !strings.ContainsAny(key, "*?[") {
+			if _, ok := m[key]; !ok {
+				missing = append(missing, key)
+			}
+			continue
+		}
+		found := false
+		for existing := range m {
+			if ok, _ := path.Match(key, existing); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// FindFailingDependencies groups connection-refused log issues by namespace
+// and target, returning the targets that at least minPods pods fail to reach
+func FindFailingDependencies(diagnoses []*domain.Diagnosis, minPods int) []domain.DependencyFailure {
+	type key struct{ namespace, target string }
+	affected := make(map[key][]string)
+
+	for _, d := range diagnoses {
+		seen := make(map[string]bool)
+		for _, issue := range d.Issues {
+			if issue.Category != "logs" {
+				continue
+			}
+			target := issue.Details["target"]
+			if target == "" || seen[target] {
+				continue
+			}
+			seen[target] = true
+			k := key{d.Pod.Namespace, target}
+			affected[k] = append(affected[k], d.Pod.Name)
+		}
+	}
+
+	var failures []domain.DependencyFailure
+	for k, pods := range affected {
+		if len(pods) < minPods {
+			continue
+		}
+		sort.Strings(pods)
+		failures = append(failures, domain.DependencyFailure{
+			Namespace: k.namespace,
+			Target:    k.target,
+			Pods:      pods,
+		})
+	}
+
+	// Most widespread failures first
+	sort.Slice(failures, func(i, j int) bool {
+		if len(failures[i].Pods) != len(failures[j].Pods) {
+			return len(failures[i].Pods) > len(failures[j].Pods)
+		}
+		if failures[i].Namespace != failures[j].Namespace {
+			return failures[i].Namespace < failures[j].Namespace
+		}
+		return failures[i].Target < failures[j].Target
+	})
+
+	return failures
+}
+
+// CollapseDependencyIssues returns the diagnoses without the log issues
+// that failures already report, so a dependency several pods fail to reach
+// is listed once rather than once per pod. Diagnoses that change are
+// copied; the others are returned as they are.
+func CollapseDependencyIssues(diagnoses []*domain.Diagnosis, failures []domain.DependencyFailure) []*domain.Diagnosis {
+	if len(failures) == 0 {
+		return diagnoses
+	}
+	type key struct{ namespace, target string }
+	reported := make(map[key]bool, len(failures))
+	for _, f := range failures {
+		reported[key{f.Namespace, f.Target}] = true
+	}
+
+	collapsed := make([]*domain.Diagnosis, len(diagnoses))
+	for i, d := range diagnoses {
+		collapsed[i] = d
+		var kept []domain.Issue
+		for j, issue := range d.Issues {
+			if issue.Category == "logs" && reported[key{d.Pod.Namespace, issue.Details["target"]}] {
+				if kept == nil {
+					kept = append(make([]domain.Issue, 0, len(d.Issues)), d.Issues[:j]...)
+				}
+				continue
+			}
+			if kept != nil {
+				kept = append(kept, issue)
+			}
+		}
+		if kept != nil {
+			cp := *d
+			cp.Issues = kept
+			collapsed[i] = &cp
+		}
+	}
+	return collapsed
+}
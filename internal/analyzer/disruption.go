@@ -0,0 +1,259 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// evictionLookback bounds how far back evictions of sibling pods are
+// attributed to the current pod's workload
+const evictionLookback = time.Hour
+
+// evictionUsagePattern matches the kubelet's note of what a container used
+// when it was evicted for node pressure, e.g. "Container web was using
+// 1.2Gi, request is 512Mi, has larger consumption of memory."
+var evictionUsagePattern = regexp.MustCompile(`Container \S+ was using [^,]+, request is [^,]+`)
+
+// disruptionTargetReasons describes the reasons of a pod's DisruptionTarget
+// condition, set before it is deleted, except preemption, which the
+// preemption analyzer explains
+var disruptionTargetReasons = map[string]string{
+	"EvictionByEvictionAPI":  "evicted through the eviction API, e.g. by a node drain or the cluster autoscaler",
+	"DeletionByTaintManager": "deleted because its node got a NoExecute taint it does not tolerate",
+	"DeletionByPodGC":        "deleted by pod garbage collection, as its node no longer exists",
+	"TerminationByKubelet":   "terminated by the kubelet, for node pressure or a node shutdown",
+}
+
+// DisruptionAnalyzer explains why a pod was evicted or is about to be
+// disrupted, whether earlier pods of its workload were evicted, and how its
+// PodDisruptionBudgets shape voluntary disruptions
+type DisruptionAnalyzer struct{}
+
+// NewDisruptionAnalyzer creates a new DisruptionAnalyzer
+func NewDisruptionAnalyzer() *DisruptionAnalyzer {
+	return &DisruptionAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (a *DisruptionAnalyzer) Name() string {
+	return "disruption"
+}
+
+// Analyze breaks down the pod's eviction, its pending disruption and the
+// recent evictions of pods it replaced, then checks the budgets selecting it
+func (a *DisruptionAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	if pod.Status.Reason == "Evicted" {
+		issues = append(issues, evictionIssue(pod.Name, pod.Spec.NodeName, pod.Status.Message, ""))
+	} else if issue, ok := disruptionTargetIssue(pod); ok {
+		issues = append(issues, issue)
+	}
+
+	// A replacement pod looks like a sudden restart; check whether its
+	// predecessors from the same controller were evicted
+	if len(issues) == 0 && pod.GenerateName != "" {
+		if issue, ok := siblingEvictionIssue(ctx, pod, client); ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	pdbs, err := client.ListPodDisruptionBudgets(ctx, pod.Namespace)
+	if err != nil {
+		return issues, err
+	}
+	return append(issues, budgetIssues(pod, pdbs)...), nil
+}
+
+// evictionCause classifies the message of an eviction. ownLimit is true
+// when the pod went over its own ephemeral storage limit rather than the
+// node running short.
+func evictionCause(message string) (cause string, ownLimit bool) {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "ephemeral local storage usage exceeds"),
+		strings.Contains(lower, "exceeded its local ephemeral storage limit"),
+		strings.Contains(lower, "usage of emptydir volume"):
+		return "ephemeral storage limit exceeded", true
+	case strings.Contains(lower, "low on resource: memory"), strings.Contains(lower, "[memorypressure]"):
+		return "node memory pressure", false
+	case strings.Contains(lower, "low on resource: ephemeral-storage"),
+		strings.Contains(lower, "low on resource: nodefs"),
+		strings.Contains(lower, "low on resource: imagefs"),
+		strings.Contains(lower, "[diskpressure]"):
+		return "node disk pressure", false
+	case strings.Contains(lower, "low on resource: pids"), strings.Contains(lower, "[pidpressure]"):
+		return "node PID pressure", false
+	}
+	return "unknown cause", false
+}
+
+// evictionIssue explains the eviction of a pod, or of the earlier pod
+// victim when it is not the diagnosed one
+func evictionIssue(victim, node, message, diagnosed string) domain.Issue {
+	cause, ownLimit := evictionCause(message)
+
+	title := "Evicted for " + cause
+	description := "The kubelet evicted the pod to reclaim resources on its node"
+	if ownLimit {
+		description = "The kubelet evicted the pod because it wrote more to local storage than its ephemeral-storage limit allows"
+	}
+	if diagnosed != "" && victim != diagnosed {
+		title = fmt.Sprintf("Previous pod %s was evicted for %s", victim, cause)
+		description = "This pod replaced one that the kubelet evicted; the restart is not an application failure"
+	}
+
+	details := map[string]string{
+		"evicted_pod": victim,
+		"cause":       cause,
+	}
+	if node != "" {
+		details["node"] = node
+	}
+	if usage := evictionUsagePattern.FindAllString(message, -1); len(usage) > 0 {
+		details["usage"] = strings.Join(usage, "; ")
+	}
+	if message != "" {
+		details["message"] = message
+	}
+
+	severity := domain.SeverityWarning
+	if ownLimit {
+		// The pod will be evicted again unless its limit or usage changes
+		severity = domain.SeverityCritical
+	}
+	return domain.Issue{
+		Severity:    severity,
+		Category:    "resources",
+		Title:       title,
+		Description: description,
+		Details:     details,
+	}
+}
+
+// disruptionTargetIssue explains a pod being taken down, from the
+// DisruptionTarget condition set before its deletion
+func disruptionTargetIssue(pod *corev1.Pod) (domain.Issue, bool) {
+	for _, c := range pod.Status.Conditions {
+		if c.Type != corev1.DisruptionTarget || c.Status != corev1.ConditionTrue {
+			continue
+		}
+		reason, ok := disruptionTargetReasons[c.Reason]
+		if !ok {
+			return domain.Issue{}, false
+		}
+		details := map[string]string{"reason": c.Reason}
+		if c.Message != "" {
+			details["message"] = c.Message
+		}
+		if !c.LastTransitionTime.IsZero() {
+			details["disrupted_at"] = c.LastTransitionTime.Format("2006-01-02 15:04:05")
+		}
+		return domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "resources",
+			Title:       "Pod is being disrupted: " + c.Reason,
+			Description: "The pod was " + reason,
+			Details:     details,
+		}, true
+	}
+	return domain.Issue{}, false
+}
+
+// siblingEvictionIssue reports the latest recent eviction of another pod
+// created by the same controller, with how many there were
+func siblingEvictionIssue(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) (domain.Issue, bool) {
+	events, err := client.ListNamespaceEvents(ctx, pod.Namespace)
+	if err != nil {
+		return domain.Issue{}, false
+	}
+
+	var latest *corev1.Event
+	count := 0
+	for i := range events {
+		e := &events[i]
+		obj := e.InvolvedObject
+		if e.Reason != "Evicted" || obj.Kind != "Pod" || obj.Name == pod.Name {
+			continue
+		}
+		if !strings.HasPrefix(obj.Name, pod.GenerateName) || time.Since(e.LastTimestamp.Time) > evictionLookback {
+			continue
+		}
+		count++
+		if latest == nil || e.LastTimestamp.After(latest.LastTimestamp.Time) {
+			latest = e
+		}
+	}
+	if latest == nil {
+		return domain.Issue{}, false
+	}
+
+	issue := evictionIssue(latest.InvolvedObject.Name, latest.Source.Host, latest.Message, pod.Name)
+	issue.Details["evicted_at"] = latest.LastTimestamp.Format("2006-01-02 15:04:05")
+	if count > 1 {
+		issue.Details["evictions"] = fmt.Sprintf("%d in the last hour", count)
+	}
+	return issue, true
+}
+
+// budgetIssues checks the PodDisruptionBudgets selecting a pod: several
+// budgets make the eviction API refuse it, and a budget allowing no
+// disruptions blocks node drains and autoscaler scale-downs
+func budgetIssues(pod *corev1.Pod, pdbs []policyv1.PodDisruptionBudget) []domain.Issue {
+	var selecting []policyv1.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			selecting = append(selecting, pdb)
+		}
+	}
+
+	var issues []domain.Issue
+	if len(selecting) > 1 {
+		names := make([]string, 0, len(selecting))
+		for _, pdb := range selecting {
+			names = append(names, pdb.Name)
+		}
+		issues = append(issues, domain.NewIssue(domain.SeverityWarning, "resources",
+			fmt.Sprintf("Pod selected by %d PodDisruptionBudgets", len(selecting)),
+			"The eviction API refuses to evict a pod covered by more than one budget, so node drains and autoscaler scale-downs stall on it",
+		).WithDetail("budgets", strings.Join(names, ", ")))
+	}
+
+	for i := range selecting {
+		pdb := &selecting[i]
+		status := pdb.Status
+		if status.DisruptionsAllowed > 0 || status.ExpectedPods == 0 {
+			continue
+		}
+		issue := domain.NewIssue(domain.SeverityInfo, "resources",
+			fmt.Sprintf("PodDisruptionBudget %s is blocking disruptions", pdb.Name),
+			fmt.Sprintf("%d of %d pods are healthy and the budget needs %d, so no pod may be evicted until more are ready", status.CurrentHealthy, status.ExpectedPods, status.DesiredHealthy),
+		)
+		if status.DesiredHealthy >= status.ExpectedPods {
+			issue = domain.NewIssue(domain.SeverityWarning, "resources",
+				fmt.Sprintf("PodDisruptionBudget %s allows no disruptions", pdb.Name),
+				fmt.Sprintf("The budget needs all %d pods healthy, so node drains, upgrades and autoscaler scale-downs block on its pods even when they are all ready", status.ExpectedPods),
+			)
+		}
+		issue = issue.
+			WithDetail("budget", pdb.Name).
+			WithDetail("healthy", fmt.Sprintf("%d/%d", status.CurrentHealthy, status.ExpectedPods)).
+			WithDetail("desired_healthy", fmt.Sprintf("%d", status.DesiredHealthy))
+		issues = append(issues, issue.WithObject(objectRef("PodDisruptionBudget", pdb)))
+	}
+	return issues
+}
@@ -0,0 +1,227 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DNSAnalyzer checks the per-pod DNS records published through headless
+// services, which clustered apps (Kafka, Cassandra, etcd, ...) rely on for
+// peer discovery
+type DNSAnalyzer struct{}
+
+// NewDNSAnalyzer creates a new DNSAnalyzer
+func NewDNSAnalyzer() *DNSAnalyzer {
+	return &DNSAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (d *DNSAnalyzer) Name() string {
+	return "dns"
+}
+
+// Analyze checks hostname/subdomain configuration and DNS record publishing
+func (d *DNSAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	// StatefulSets publish peer DNS names through their governing service
+	if sts := ownerOfKind(pod, "StatefulSet"); sts != "" {
+		issues = append(issues, d.analyzeStatefulSet(ctx, pod, sts, client)...)
+	}
+
+	services, err := client.ListServices(ctx, pod.Namespace)
+	if err != nil {
+		return issues, err
+	}
+
+	subdomainFound := pod.Spec.Subdomain == ""
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+			continue
+		}
+		if svc.Name == pod.Spec.Subdomain {
+			subdomainFound = true
+		}
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		issues = append(issues, d.analyzeHeadlessService(ctx, pod, svc, client)...)
+	}
+
+	if !subdomainFound {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "network",
+			Title:       fmt.Sprintf("No headless service for subdomain %s", pod.Spec.Subdomain),
+			Description: "Pod sets a subdomain but no headless service with that name exists, so its hostname does not resolve",
+			Details: map[string]string{
+				"subdomain": pod.Spec.Subdomain,
+			},
+		})
+	}
+
+	return issues, nil
+}
+
+// analyzeStatefulSet verifies the StatefulSet's governing service exists and is headless
+func (d *DNSAnalyzer) analyzeStatefulSet(ctx context.Context, pod *corev1.Pod, name string, client *kubernetes.Client) []domain.Issue {
+	sts, err := client.GetStatefulSet(ctx, pod.Namespace, name)
+	if err != nil {
+		return nil
+	}
+
+	serviceName := sts.Spec.ServiceName
+	if serviceName == "" {
+		return []domain.Issue{{
+			Severity:    domain.SeverityWarning,
+			Category:    "network",
+			Title:       fmt.Sprintf("StatefulSet %s has no serviceName", name),
+			Description: "Without a governing headless service, replicas get no stable DNS names for peer discovery",
+			Details: map[string]string{
+				"statefulset": name,
+			},
+		}}
+	}
+
+	svc, err := client.GetService(ctx, pod.Namespace, serviceName)
+	if errors.IsNotFound(err) {
+		return []domain.Issue{{
+			Severity:    domain.SeverityCritical,
+			Category:    "network",
+			Title:       fmt.Sprintf("Governing service %s not found", serviceName),
+			Description: fmt.Sprintf("StatefulSet %s references headless service %s, which does not exist; peer DNS names will not resolve", name, serviceName),
+			Details: map[string]string{
+				"statefulset": name,
+				"service":     serviceName,
+			},
+		}}
+	}
+	if err != nil {
+		return nil
+	}
+
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		return []domain.Issue{{
+			Severity:    domain.SeverityWarning,
+			Category:    "network",
+			Title:       fmt.Sprintf("Governing service %s is not headless", serviceName),
+			Description: "StatefulSet's governing service has a cluster IP, so per-pod DNS records are not published",
+			Details: map[string]string{
+				"statefulset": name,
+				"service":     serviceName,
+				"cluster_ip":  svc.Spec.ClusterIP,
+			},
+		}}
+	}
+
+	return nil
+}
+
+// analyzeHeadlessService checks the pod's DNS record for a headless service selecting it
+func (d *DNSAnalyzer) analyzeHeadlessService(ctx context.Context, pod *corev1.Pod, svc *corev1.Service, client *kubernetes.Client) []domain.Issue {
+	var issues []domain.Issue
+
+	if pod.Spec.Subdomain != svc.Name {
+		// Many headless services are only used for client-side load balancing,
+		// so this is informational rather than a warning
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityInfo,
+			Category:    "network",
+			Title:       fmt.Sprintf("Pod subdomain does not match headless service %s", svc.Name),
+			Description: "Pod is selected by a headless service but its subdomain is not set to the service name, so no per-pod DNS record is created",
+			Details: map[string]string{
+				"service":   svc.Name,
+				"subdomain": valueOrNone(pod.Spec.Subdomain),
+			},
+		})
+		return issues
+	}
+
+	if pod.Spec.Hostname == "" {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "network",
+			Title:       fmt.Sprintf("Pod hostname not set for headless service %s", svc.Name),
+			Description: "Pod sets a subdomain but no hostname, so no <hostname>.<subdomain> DNS record is published",
+			Details: map[string]string{
+				"service":   svc.Name,
+				"subdomain": pod.Spec.Subdomain,
+			},
+		})
+		return issues
+	}
+
+	expected := fmt.Sprintf("%s.%s.%s.svc", pod.Spec.Hostname, svc.Name, pod.Namespace)
+
+	slices, err := client.ListEndpointSlices(ctx, pod.Namespace, svc.Name)
+	if err != nil {
+		return issues
+	}
+
+	published := false
+	for _, slice := range slices.Items {
+		for _, ep := range slice.Endpoints {
+			if ep.TargetRef == nil || ep.TargetRef.Name != pod.Name {
+				continue
+			}
+			ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+			if ep.Hostname != nil && *ep.Hostname != "" && (ready || svc.Spec.PublishNotReadyAddresses) {
+				published = true
+			}
+		}
+	}
+
+	if !published {
+		description := "Pod is not listed in the headless service's endpoints, so its DNS name does not resolve"
+		if !isPodReady(pod) && !svc.Spec.PublishNotReadyAddresses {
+			description = "Pod is not ready and the headless service does not publish not-ready addresses, so peers cannot resolve it during bootstrap"
+		}
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "network",
+			Title:       fmt.Sprintf("DNS name %s not published", expected),
+			Description: description,
+			Details: map[string]string{
+				"service":                     svc.Name,
+				"expected_dns":                expected,
+				"publish_not_ready_addresses": fmt.Sprintf("%t", svc.Spec.PublishNotReadyAddresses),
+			},
+		})
+	}
+
+	return issues
+}
+
+// ownerOfKind returns the name of the pod's controlling owner if it has the given kind
+func ownerOfKind(pod *corev1.Pod, kind string) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == kind && ref.Controller != nil && *ref.Controller {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// isPodReady returns true if the pod's Ready condition is true
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,226 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// mirrorPodAnnotation marks the API copies of static pods, which a drain
+// leaves alone
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// drainCacheTTL is how long the drain check of a node is reused, so a scan
+// checks each unhealthy node once rather than once per pod on it
+const drainCacheTTL = 30 * time.Second
+
+// drainCache holds the drain checks of recently checked nodes
+type drainCache struct {
+	mu    sync.Mutex
+	nodes map[string]*drainEntry // keyed by kube context and node name
+}
+
+// drainEntry is the drain check of one node; done is closed once it ran
+type drainEntry struct {
+	done    chan struct{}
+	checked time.Time
+	risks   []string
+	err     error
+}
+
+// risks returns the drain risks of a node, checking it at most once per
+// TTL. Concurrent diagnoses of pods on the node wait for the one check.
+// Failed checks are not kept, so the next pod retries.
+func (c *drainCache) risks(ctx context.Context, client *kubernetes.Client, nodeName string) ([]string, error) {
+	key := client.Context() + "/" + nodeName
+	c.mu.Lock()
+	e, ok := c.nodes[key]
+	if ok && !e.expired() {
+		c.mu.Unlock()
+		select {
+		case <-e.done:
+			return e.risks, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	for k, other := range c.nodes {
+		if other.expired() {
+			delete(c.nodes, k)
+		}
+	}
+	e = &drainEntry{done: make(chan struct{})}
+	c.nodes[key] = e
+	c.mu.Unlock()
+
+	e.risks, e.err = drainRisks(ctx, client, nodeName)
+	e.checked = time.Now()
+	close(e.done)
+	if e.err != nil {
+		c.mu.Lock()
+		if c.nodes[key] == e {
+			delete(c.nodes, key)
+		}
+		c.mu.Unlock()
+	}
+	return e.risks, e.err
+}
+
+// expired reports whether a finished check is older than the TTL; a check
+// still running is not
+func (e *drainEntry) expired() bool {
+	select {
+	case <-e.done:
+		return time.Since(e.checked) >= drainCacheTTL
+	default:
+		return false
+	}
+}
+
+// drainGroup is the pods on a node that belong to one controller
+type drainGroup struct {
+	kind, name, namespace string
+	onNode, ready         int
+	covered               bool // selected by a PodDisruptionBudget
+}
+
+// drainRisks checks what draining a node would do to the pods it runs. It
+// returns a description of each risk: a PodDisruptionBudget that would
+// block or stall the drain, a single-replica workload or one whose ready
+// replicas all run on the node, pods without a controller, and several
+// replicas without a budget that would be evicted at once. No risks means
+// the node can be drained safely.
+func drainRisks(ctx context.Context, client *kubernetes.Client, nodeName string) ([]string, error) {
+	list, err := client.ListPodsOnNode(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var risks []string
+	byNamespace := make(map[string][]*corev1.Pod)
+	groups := make(map[string]*drainGroup)
+	var groupKeys []string
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if !evictedByDrain(pod) {
+			continue
+		}
+		byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], pod)
+
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil {
+			risks = append(risks, fmt.Sprintf("pod %s/%s has no controller, so it would be deleted for good (kubectl drain refuses without --force)", pod.Namespace, pod.Name))
+			continue
+		}
+		key := pod.Namespace + "/" + ref.Kind + "/" + ref.Name
+		g, ok := groups[key]
+		if !ok {
+			g = &drainGroup{kind: ref.Kind, name: ref.Name, namespace: pod.Namespace}
+			groups[key] = g
+			groupKeys = append(groupKeys, key)
+		}
+		g.onNode++
+		if isPodReady(pod) {
+			g.ready++
+		}
+	}
+
+	namespaces := make([]string, 0, len(byNamespace))
+	for ns := range byNamespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	covered := make(map[string]bool)
+	for _, ns := range namespaces {
+		pdbs, err := client.ListPodDisruptionBudgets(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		for _, pdb := range pdbs {
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil {
+				continue
+			}
+			onNode := 0
+			for _, pod := range byNamespace[ns] {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					onNode++
+					if ref := metav1.GetControllerOf(pod); ref != nil {
+						covered[ns+"/"+ref.Kind+"/"+ref.Name] = true
+					}
+				}
+			}
+
+			allowed := int(pdb.Status.DisruptionsAllowed)
+			switch {
+			case onNode == 0:
+			case allowed == 0:
+				risks = append(risks, fmt.Sprintf("PodDisruptionBudget %s/%s allows no disruptions, so the drain would block on its %d pod(s) on the node", ns, pdb.Name, onNode))
+			case allowed < onNode:
+				risks = append(risks, fmt.Sprintf("PodDisruptionBudget %s/%s allows %d disruption(s) but %d of its pods run on the node, so the drain stalls until replacements are ready", ns, pdb.Name, allowed, onNode))
+			}
+		}
+	}
+
+	for _, key := range groupKeys {
+		g := groups[key]
+		desired, ready, ok, err := client.ControllerReplicas(ctx, g.namespace, g.kind, g.name)
+		if err != nil {
+			return nil, err
+		}
+		ref := fmt.Sprintf("%s %s/%s", g.kind, g.namespace, g.name)
+		switch {
+		case !ok:
+		case desired == 1:
+			risks = append(risks, fmt.Sprintf("%s runs a single replica, on this node, and is down until it is rescheduled", ref))
+		case g.ready > 0 && g.ready >= int(ready):
+			risks = append(risks, fmt.Sprintf("all %d ready replicas of %s run on this node, so it is down until they are rescheduled", g.ready, ref))
+		case g.onNode > 1 && !covered[key]:
+			risks = append(risks, fmt.Sprintf("%d replicas of %s run on this node and no PodDisruptionBudget covers them, so they are evicted at once", g.onNode, ref))
+		}
+	}
+
+	return risks, nil
+}
+
+// evictedByDrain reports whether kubectl drain evicts a pod: DaemonSet
+// pods, static pods and pods that already finished stay
+func evictedByDrain(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+	if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
+
+// addDrainDetails records on node issues whether draining the node is safe,
+// for the node recommendations. Nothing is recorded if the check cannot run,
+// e.g. without permission to list PodDisruptionBudgets.
+func addDrainDetails(ctx context.Context, client *kubernetes.Client, drains *drainCache, nodeName string, issues []domain.Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	risks, err := drains.risks(ctx, client, nodeName)
+	if err != nil {
+		return
+	}
+	for _, issue := range issues {
+		issue.Details["drain_checked"] = "true"
+		issue.Details["drain_risks"] = strings.Join(risks, "; ")
+	}
+}
@@ -3,7 +3,9 @@ package analyzer
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pavanInnamuri/pod-doctor/internal/domain"
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
@@ -33,6 +35,10 @@ func (e *EventAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *ku
 	}
 
 	for _, event := range events {
+		// VolumeAnalyzer reports these with the affected volume
+		if volumeEventReasons[event.Reason] {
+			continue
+		}
 		if event.Type == "Warning" {
 			issue := e.analyzeWarningEvent(event)
 			if issue != nil {
@@ -71,6 +77,8 @@ func (e *EventAnalyzer) analyzeWarningEvent(event domain.EventInfo) *domain.Issu
 		category = "container"
 	case strings.Contains(event.Reason, "OOM"):
 		category = "resources"
+	case isNetworkEvent(event.Message):
+		category = "network"
 	}
 
 	// Skip certain non-actionable events
@@ -78,7 +86,7 @@ func (e *EventAnalyzer) analyzeWarningEvent(event domain.EventInfo) *domain.Issu
 		return nil
 	}
 
-	return &domain.Issue{
+	issue := &domain.Issue{
 		Severity:    severity,
 		Category:    category,
 		Title:       event.Reason,
@@ -89,6 +97,121 @@ func (e *EventAnalyzer) analyzeWarningEvent(event domain.EventInfo) *domain.Issu
 			"last_seen": event.LastSeen.Format("2006-01-02 15:04:05"),
 		},
 	}
+
+	// Classify image pull failures so rate limits get their own advice
+	if isImagePullEvent(event.Reason, event.Message) {
+		if pullFailure := classifyImagePullFailure(event.Message); pullFailure != pullFailureUnknown {
+			issue.Category = "container"
+			issue.Details["pull_failure"] = pullFailure
+			if pullFailure == pullFailureRateLimited {
+				issue.Title = "Image pull rate limited"
+			}
+		}
+	}
+
+	return issue
+}
+
+// GroupWarningEvents deduplicates warning events by namespace, involved
+// object and reason, classifying each group like the events analyzer does.
+// Groups are sorted by most recent occurrence first. Events last seen before
+// since are dropped; a zero since keeps all.
+func GroupWarningEvents(events []corev1.Event, since time.Time) []domain.EventGroup {
+	e := NewEventAnalyzer()
+	groups := make(map[string]*domain.EventGroup)
+	var order []string
+
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		info := kubernetes.ExtractEventInfo(event)
+		first, last := eventTimes(event)
+		if !since.IsZero() && last.Before(since) {
+			continue
+		}
+
+		issue := e.analyzeWarningEvent(info)
+		if issue == nil {
+			continue
+		}
+
+		count := event.Count
+		if event.Series != nil && event.Series.Count > count {
+			count = event.Series.Count
+		}
+		if count < 1 {
+			count = 1
+		}
+
+		object := event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name
+		key := event.Namespace + "/" + object + "/" + event.Reason
+		group, ok := groups[key]
+		if !ok {
+			group = &domain.EventGroup{
+				Namespace: event.Namespace,
+				Object:    object,
+				Reason:    event.Reason,
+				Severity:  issue.Severity,
+				Category:  issue.Category,
+				FirstSeen: first,
+			}
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		group.Count += count
+		if first.Before(group.FirstSeen) {
+			group.FirstSeen = first
+		}
+		if !last.Before(group.LastSeen) {
+			group.LastSeen = last
+			group.Message = event.Message
+		}
+		if issue.Severity == domain.SeverityCritical {
+			group.Severity = domain.SeverityCritical
+		}
+	}
+
+	result := make([]domain.EventGroup, 0, len(order))
+	for _, key := range order {
+		result = append(result, *groups[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	return result
+}
+
+// eventTimes returns when an event was first and last seen, falling back to
+// the newer events API fields and the creation time
+func eventTimes(event corev1.Event) (first, last time.Time) {
+	first = event.FirstTimestamp.Time
+	last = event.LastTimestamp.Time
+	if event.Series != nil && event.Series.LastObservedTime.After(last) {
+		last = event.Series.LastObservedTime.Time
+	}
+	if last.IsZero() {
+		last = event.EventTime.Time
+	}
+	if last.IsZero() {
+		last = event.CreationTimestamp.Time
+	}
+	if first.IsZero() {
+		first = last
+	}
+	return first, last
+}
+
+// isNetworkEvent returns true if the event message points at a networking failure
+func isNetworkEvent(message string) bool {
+	msg := strings.ToLower(message)
+	for _, marker := range []string{"cni", "network plugin", "no route to host", "network is unreachable", "failed to set up sandbox container", "free ports"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
 }
 
 func formatCount(count int32) string {
@@ -0,0 +1,194 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// evictionCandidate is a pod the kubelet may evict from a node under
+// memory pressure
+type evictionCandidate struct {
+	namespace, name string
+	qos             corev1.PodQOSClass
+	priority        int32
+	request         int64 // memory requests of its containers, in bytes
+	usage           int64 // live memory usage in bytes, -1 if unknown
+}
+
+// exceedsRequests reports whether the pod uses more memory than it
+// requests. Without live usage, only BestEffort pods are known to.
+func (c evictionCandidate) exceedsRequests() bool {
+	if c.usage < 0 {
+		return c.qos == corev1.PodQOSBestEffort
+	}
+	return c.usage > c.request
+}
+
+// aboveRequests returns how far the pod's usage is above its requests, or 0
+// without live usage
+func (c evictionCandidate) aboveRequests() int64 {
+	if c.usage < 0 {
+		return 0
+	}
+	return c.usage - c.request
+}
+
+// rankEvictionCandidates orders pods the way the kubelet picks them for
+// eviction under memory pressure: pods using more than they request first,
+// then by ascending priority, then by how far usage is above requests
+func rankEvictionCandidates(candidates []evictionCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.exceedsRequests() != b.exceedsRequests() {
+			return a.exceedsRequests()
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		return a.aboveRequests() > b.aboveRequests()
+	})
+}
+
+// evictionRankIssue ranks a Burstable or BestEffort pod against the other
+// pods on its node, which is under memory pressure, and reports its place in
+// line for eviction. Guaranteed pods are not evicted while they stay within
+// their requests, so they are ranked but not reported.
+func evictionRankIssue(ctx context.Context, client *kubernetes.Client, pod *corev1.Pod) *domain.Issue {
+	if pod.Status.QOSClass == corev1.PodQOSGuaranteed {
+		return nil
+	}
+
+	list, err := client.ListPodsOnNode(ctx, pod.Spec.NodeName)
+	if err != nil {
+		return nil
+	}
+
+	usage := livePodUsage(ctx, client, list.Items)
+	var candidates []evictionCandidate
+	for i := range list.Items {
+		p := &list.Items[i]
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		c := evictionCandidate{
+			namespace: p.Namespace,
+			name:      p.Name,
+			qos:       p.Status.QOSClass,
+			priority:  podPriority(p),
+			request:   podMemoryRequest(p),
+			usage:     -1,
+		}
+		if u, ok := usage[p.Namespace+"/"+p.Name]; ok {
+			c.usage = u.memory
+		}
+		candidates = append(candidates, c)
+	}
+	rankEvictionCandidates(candidates)
+
+	rank := -1
+	for i, c := range candidates {
+		if c.namespace == pod.Namespace && c.name == pod.Name {
+			rank = i
+			break
+		}
+	}
+	if rank < 0 {
+		return nil
+	}
+	self := candidates[rank]
+
+	var ahead []string
+	for _, c := range candidates[:rank] {
+		if len(ahead) == 3 {
+			ahead = append(ahead, fmt.Sprintf("%d more", rank-3))
+			break
+		}
+		ahead = append(ahead, c.namespace+"/"+c.name)
+	}
+
+	request := resource.NewQuantity(self.request, resource.BinarySI)
+	description := fmt.Sprintf("Node %s is under memory pressure. The kubelet evicts pods using more memory than they request first, then lower priority ones, then those furthest above their requests; this %s pod has priority %d and requests %s",
+		pod.Spec.NodeName, self.qos, self.priority, formatMemory(request))
+	if self.usage >= 0 {
+		description += fmt.Sprintf(" and uses %s", formatMemory(resource.NewQuantity(self.usage, resource.BinarySI)))
+	} else {
+		description += " (ranked without live usage: metrics-server is not available)"
+	}
+
+	issue := domain.NewIssue(
+		domain.SeverityWarning,
+		"node",
+		fmt.Sprintf("Pod is #%d of %d in line for eviction from %s", rank+1, len(candidates), pod.Spec.NodeName),
+		description,
+	).WithDetail("node", pod.Spec.NodeName).
+		WithDetail("eviction_rank", fmt.Sprintf("%d/%d", rank+1, len(candidates))).
+		WithDetail("qos_class", string(self.qos)).
+		WithDetail("priority", fmt.Sprintf("%d", self.priority)).
+		WithDetail("memory_request", formatMemory(request))
+	if self.usage >= 0 {
+		used := resource.NewQuantity(self.usage, resource.BinarySI)
+		issue = issue.WithDetail("memory_usage", formatMemory(used))
+		if self.exceedsRequests() {
+			// Requests at the usage, with headroom, take the pod out of
+			// the front of the line
+			issue = issue.WithDetail("suggested_request", formatMemory(suggestMemoryLimit(used, nil)))
+		}
+	}
+	if len(ahead) > 0 {
+		issue = issue.WithDetail("evicted_before", strings.Join(ahead, ", "))
+	}
+	if len(pod.Spec.Containers) == 1 {
+		issue = issue.WithDetail("container", pod.Spec.Containers[0].Name)
+	}
+	return &issue
+}
+
+// podMemoryRequest returns the sum of the memory requests of a pod's
+// containers, in bytes
+func podMemoryRequest(pod *corev1.Pod) int64 {
+	var total int64
+	for _, c := range pod.Spec.Containers {
+		total += c.Resources.Requests.Memory().Value()
+	}
+	return total
+}
+
+// liveUsage is the live usage of a pod's containers
+type liveUsage struct {
+	cpu    int64 // millicores
+	memory int64 // bytes
+}
+
+// livePodUsage returns the live usage of pods keyed by namespace/name. Pods
+// without metrics, or all of them if metrics-server is not installed, are
+// missing.
+func livePodUsage(ctx context.Context, client *kubernetes.Client, pods []corev1.Pod) map[string]liveUsage {
+	namespaces := make(map[string]bool)
+	for _, p := range pods {
+		namespaces[p.Namespace] = true
+	}
+
+	usage := make(map[string]liveUsage)
+	for ns := range namespaces {
+		metrics, err := client.ListPodMetrics(ctx, ns)
+		if err != nil {
+			continue
+		}
+		for _, m := range metrics {
+			var total liveUsage
+			for _, c := range m.Containers {
+				total.cpu += c.Usage.Cpu().MilliValue()
+				total.memory += c.Usage.Memory().Value()
+			}
+			usage[m.Namespace+"/"+m.Name] = total
+		}
+	}
+	return usage
+}
@@ -0,0 +1,292 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// imageRef is a container image reference split into its parts
+type imageRef struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseImageRef splits an image reference such as
+// registry.example.com:5000/team/app:1.2@sha256:... Short Docker Hub names
+// are expanded, e.g. nginx becomes docker.io/library/nginx. Tag is empty
+// when the reference has none.
+func parseImageRef(image string) imageRef {
+	var ref imageRef
+
+	name, digest, _ := strings.Cut(image, "@")
+	ref.Digest = digest
+
+	// A colon after the last slash separates the tag; earlier colons belong
+	// to a registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	switch {
+	case found && (strings.ContainsAny(first, ".:") || first == "localhost"):
+		ref.Registry, ref.Repository = first, rest
+	case found:
+		ref.Registry, ref.Repository = "docker.io", name
+	default:
+		ref.Registry, ref.Repository = "docker.io", "library/"+name
+	}
+	return ref
+}
+
+// mutableTag reports whether the reference resolves to whatever :latest
+// currently points at
+func (r imageRef) mutableTag() bool {
+	return r.Digest == "" && (r.Tag == "" || r.Tag == "latest")
+}
+
+// ImageAnalyzer finds out why images cannot be pulled: a missing tag, bad
+// registry credentials or an unresolvable registry host. It also checks the
+// pod's image pull secrets and risky tag and pull policy combinations.
+type ImageAnalyzer struct{}
+
+// NewImageAnalyzer creates a new ImageAnalyzer
+func NewImageAnalyzer() *ImageAnalyzer {
+	return &ImageAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (i *ImageAnalyzer) Name() string {
+	return "image"
+}
+
+// Analyze checks image pulls, pull secrets and pull policies
+func (i *ImageAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	var secretNames []string
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		secretNames = append(secretNames, ref.Name)
+	}
+
+	pullIssues := i.analyzePullFailures(ctx, pod, client, secretNames)
+	issues = append(issues, pullIssues...)
+
+	// Missing credentials only block pulls from private registries, so they
+	// are critical when a pull is failing on authentication
+	authFailing := false
+	for _, issue := range pullIssues {
+		if issue.Details["pull_failure"] == pullFailureAuth {
+			authFailing = true
+		}
+	}
+	issues = append(issues, i.analyzePullSecrets(ctx, pod, client, authFailing)...)
+
+	issues = append(issues, i.analyzePullPolicies(pod)...)
+
+	return issues, nil
+}
+
+// analyzePullFailures reports containers waiting on an image pull. The
+// waiting message is often just "Back-off pulling image", so the kubelet's
+// last pull event for the container is used when it says more.
+func (i *ImageAnalyzer) analyzePullFailures(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client, secretNames []string) []domain.Issue {
+	var issues []domain.Issue
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	var events []domain.EventInfo
+	eventsFetched := false
+	for _, cs := range statuses {
+		waiting := cs.State.Waiting
+		if waiting == nil || !isImagePullReason(waiting.Reason) {
+			continue
+		}
+		if !eventsFetched {
+			events, _ = client.GetPodEvents(ctx, pod.Namespace, pod.Name)
+			eventsFetched = true
+		}
+
+		message := waiting.Message
+		pullFailure := classifyImagePullFailure(message)
+		if pullFailure == pullFailureUnknown {
+			if eventMessage := lastPullFailureEvent(events, cs.Name); eventMessage != "" {
+				message = eventMessage
+				pullFailure = classifyImagePullFailure(eventMessage)
+			}
+		}
+
+		image := imageOf(pod, cs)
+		ref := parseImageRef(image)
+		title, description := describePullFailure(pullFailure, cs.Name, ref)
+		if message != "" {
+			description += ": " + message
+		}
+
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityCritical,
+			Category:    "container",
+			Title:       title,
+			Description: description,
+			Details: map[string]string{
+				"container":          cs.Name,
+				"reason":             waiting.Reason,
+				"image":              image,
+				"registry":           ref.Registry,
+				"repository":         ref.Repository,
+				"tag":                ref.Tag,
+				"pull_failure":       pullFailure,
+				"image_pull_secrets": strings.Join(secretNames, ","),
+			},
+		})
+	}
+
+	return issues
+}
+
+// describePullFailure returns the issue title and description for a pull
+// failure class
+func describePullFailure(pullFailure, container string, ref imageRef) (string, string) {
+	switch pullFailure {
+	case pullFailureRateLimited:
+		return fmt.Sprintf("Image pull rate limited for %s", container),
+			fmt.Sprintf("Registry %s is rate limiting pulls", ref.Registry)
+	case pullFailureNotFound:
+		if ref.Digest != "" {
+			return fmt.Sprintf("Image digest not found for %s", container),
+				fmt.Sprintf("Digest %s does not exist in %s/%s", ref.Digest, ref.Registry, ref.Repository)
+		}
+		tag := ref.Tag
+		if tag == "" {
+			tag = "latest"
+		}
+		return fmt.Sprintf("Image tag not found for %s", container),
+			fmt.Sprintf("Tag %q does not exist in %s/%s, or the repository does not exist", tag, ref.Registry, ref.Repository)
+	case pullFailureAuth:
+		return fmt.Sprintf("Registry authentication failed for %s", container),
+			fmt.Sprintf("Registry %s rejected the pull credentials for %s", ref.Registry, ref.Repository)
+	case pullFailureRegistryDNS:
+		return fmt.Sprintf("Registry host not resolvable for %s", container),
+			fmt.Sprintf("The node cannot resolve registry %s", ref.Registry)
+	}
+	return fmt.Sprintf("Cannot pull image for %s", container),
+		fmt.Sprintf("Pulling from %s failed", ref.Registry)
+}
+
+// lastPullFailureEvent returns the message of the most recent pull event
+// for a container that says why the pull failed. Back-off events only
+// repeat "Back-off pulling image" and are skipped.
+func lastPullFailureEvent(events []domain.EventInfo, container string) string {
+	var latest *domain.EventInfo
+	for idx := range events {
+		event := &events[idx]
+		if event.Type != "Warning" || !isImagePullEvent(event.Reason, event.Message) ||
+			classifyImagePullFailure(event.Message) == pullFailureUnknown {
+			continue
+		}
+		if !strings.HasSuffix(event.FieldPath, "{"+container+"}") {
+			continue
+		}
+		if latest == nil || event.LastSeen.After(latest.LastSeen) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+// analyzePullSecrets checks that the pod's image pull secrets exist and
+// hold registry credentials
+func (i *ImageAnalyzer) analyzePullSecrets(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client, authFailing bool) []domain.Issue {
+	var issues []domain.Issue
+
+	severity := domain.SeverityWarning
+	if authFailing {
+		severity = domain.SeverityCritical
+	}
+
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		secret, err := client.GetSecret(ctx, pod.Namespace, ref.Name)
+		if apierrors.IsNotFound(err) {
+			issues = append(issues, domain.Issue{
+				Severity:    severity,
+				Category:    "container",
+				Title:       fmt.Sprintf("Image pull secret %s not found", ref.Name),
+				Description: fmt.Sprintf("The pod references image pull secret %s, which does not exist in namespace %s", ref.Name, pod.Namespace),
+				Details: map[string]string{
+					"secret": ref.Name,
+				},
+			})
+			continue
+		}
+		if err != nil {
+			// Reading secrets is often not allowed; skip rather than guess
+			continue
+		}
+
+		if secret.Type != corev1.SecretTypeDockerConfigJson && secret.Type != corev1.SecretTypeDockercfg {
+			issues = append(issues, domain.Issue{
+				Severity:    severity,
+				Category:    "container",
+				Title:       fmt.Sprintf("Image pull secret %s has wrong type", ref.Name),
+				Description: fmt.Sprintf("Secret has type %s; the kubelet only reads registry credentials from %s secrets", secret.Type, corev1.SecretTypeDockerConfigJson),
+				Details: map[string]string{
+					"secret": ref.Name,
+					"type":   string(secret.Type),
+				},
+			})
+		}
+	}
+
+	return issues
+}
+
+// analyzePullPolicies flags containers running a mutable :latest tag with
+// imagePullPolicy IfNotPresent, where each node keeps whichever image it
+// pulled first and replicas silently run different versions
+func (i *ImageAnalyzer) analyzePullPolicies(pod *corev1.Pod) []domain.Issue {
+	var issues []domain.Issue
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		if c.ImagePullPolicy != corev1.PullIfNotPresent || !parseImageRef(c.Image).mutableTag() {
+			continue
+		}
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "container",
+			Title:       fmt.Sprintf("Container %s uses :latest with IfNotPresent", c.Name),
+			Description: "Nodes reuse whatever image they cached for :latest, so replicas on different nodes may run different versions and new pushes are not picked up",
+			Details: map[string]string{
+				"container":   c.Name,
+				"image":       c.Image,
+				"pull_policy": string(c.ImagePullPolicy),
+			},
+		})
+	}
+
+	return issues
+}
+
+// imageOf returns the image a container was configured with. The status
+// image may be rewritten by the runtime, so the spec is preferred.
+func imageOf(pod *corev1.Pod, cs corev1.ContainerStatus) string {
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		if c.Name == cs.Name {
+			return c.Image
+		}
+	}
+	return cs.Image
+}
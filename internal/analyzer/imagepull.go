@@ -0,0 +1,55 @@
+package analyzer
+
+import "strings"
+
+// Image pull failure classes, stored in the "pull_failure" issue detail
+const (
+	pullFailureRateLimited = "rate_limited"
+	pullFailureAuth        = "auth"
+	pullFailureNotFound    = "not_found"
+	pullFailureRegistryDNS = "registry_dns"
+	pullFailureUnknown     = "unknown"
+)
+
+// classifyImagePullFailure determines why an image pull failed based on the
+// message reported by the kubelet or container runtime
+func classifyImagePullFailure(message string) string {
+	msg := strings.ToLower(message)
+
+	switch {
+	case strings.Contains(msg, "toomanyrequests"),
+		strings.Contains(msg, "too many requests"),
+		strings.Contains(msg, "rate limit"):
+		return pullFailureRateLimited
+	case strings.Contains(msg, "unauthorized"),
+		strings.Contains(msg, "authentication required"),
+		strings.Contains(msg, "pull access denied"),
+		strings.Contains(msg, "403 forbidden"):
+		return pullFailureAuth
+	case strings.Contains(msg, "no such host"),
+		strings.Contains(msg, "server misbehaving"),
+		strings.Contains(msg, "dial tcp: lookup"):
+		return pullFailureRegistryDNS
+	case strings.Contains(msg, "not found"),
+		strings.Contains(msg, "manifest unknown"),
+		strings.Contains(msg, "does not exist"):
+		return pullFailureNotFound
+	}
+
+	return pullFailureUnknown
+}
+
+// isImagePullReason returns true if a container waiting reason means its
+// image could not be pulled
+func isImagePullReason(reason string) bool {
+	return reason == "ImagePullBackOff" || reason == "ErrImagePull"
+}
+
+// isImagePullEvent returns true if the event relates to pulling an image
+func isImagePullEvent(reason, message string) bool {
+	if strings.Contains(reason, "Pull") {
+		return true
+	}
+	msg := strings.ToLower(message)
+	return strings.Contains(msg, "pull image") || strings.Contains(msg, "pulling image")
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InitExpectedDurationAnnotation declares how long init containers are
+// expected to run: one duration for all of them ("5m"), or one per
+// container ("migrate=10m,wait-for-db=2m")
+const InitExpectedDurationAnnotation = "pod-doctor.io/init-expected-duration"
+
+// initStep is the init container a pod is on
+type initStep struct {
+	position  int // 1-based, in spec order
+	total     int
+	container corev1.Container
+	status    *corev1.ContainerStatus // nil until the kubelet reports it
+}
+
+// sequence describes the step's position, e.g. "init 2/4"
+func (s initStep) sequence() string {
+	return fmt.Sprintf("init %d/%d", s.position, s.total)
+}
+
+// started reports whether the init container has run, so it has logs
+func (s initStep) started() bool {
+	cs := s.status
+	return cs != nil && (cs.State.Running != nil || cs.State.Terminated != nil || cs.RestartCount > 0)
+}
+
+// currentInitContainer returns the init container a scheduled pod is on:
+// the first one that has not completed, or for a sidecar (restartPolicy
+// Always) not started. Init containers run one at a time in spec order, so
+// the app containers wait for it. False once every init container is done.
+func currentInitContainer(pod *corev1.Pod) (initStep, bool) {
+	if pod.Spec.NodeName == "" {
+		return initStep{}, false
+	}
+	for i, c := range pod.Spec.InitContainers {
+		var status *corev1.ContainerStatus
+		for j := range pod.Status.InitContainerStatuses {
+			if pod.Status.InitContainerStatuses[j].Name == c.Name {
+				status = &pod.Status.InitContainerStatuses[j]
+				break
+			}
+		}
+		if !initContainerDone(c, status) {
+			return initStep{position: i + 1, total: len(pod.Spec.InitContainers), container: c, status: status}, true
+		}
+	}
+	return initStep{}, false
+}
+
+// initContainerDone reports whether the pod has moved past an init
+// container
+func initContainerDone(c corev1.Container, status *corev1.ContainerStatus) bool {
+	if status == nil {
+		return false
+	}
+	if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+		return status.Started != nil && *status.Started
+	}
+	return status.State.Terminated != nil && status.State.Terminated.ExitCode == 0
+}
+
+// initPositions returns the sequence position of each init container by
+// name, e.g. "init 2/4"
+func initPositions(pod *corev1.Pod) map[string]string {
+	positions := make(map[string]string, len(pod.Spec.InitContainers))
+	for i, c := range pod.Spec.InitContainers {
+		positions[c.Name] = fmt.Sprintf("init %d/%d", i+1, len(pod.Spec.InitContainers))
+	}
+	return positions
+}
+
+// expectedInitDuration returns how long an init container is expected to
+// run according to the pod's annotation, or 0 if it does not say
+func expectedInitDuration(pod *corev1.Pod, container string) time.Duration {
+	value := strings.TrimSpace(pod.Annotations[InitExpectedDurationAnnotation])
+	if value == "" {
+		return 0
+	}
+	if !strings.Contains(value, "=") {
+		d, _ := time.ParseDuration(value)
+		return d
+	}
+	for _, entry := range strings.Split(value, ",") {
+		name, duration, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if ok && strings.TrimSpace(name) == container {
+			d, _ := time.ParseDuration(strings.TrimSpace(duration))
+			return d
+		}
+	}
+	return 0
+}
+
+// analyzeInitProgress reports the init container a pod is running, and
+// whether it has been running for longer than expected. Waiting and failed
+// init containers are reported by analyzeInitContainerStatus.
+func (s *StatusAnalyzer) analyzeInitProgress(pod *corev1.Pod, now time.Time) []domain.Issue {
+	step, ok := currentInitContainer(pod)
+	if !ok || step.status == nil || step.status.State.Running == nil {
+		return nil
+	}
+
+	name := step.container.Name
+	elapsed := now.Sub(step.status.State.Running.StartedAt.Time).Round(time.Second)
+	expected := expectedInitDuration(pod, name)
+	limit, basis := expected, "expected by "+InitExpectedDurationAnnotation
+	if limit <= 0 {
+		limit, basis = s.initTimeout, "init timeout"
+	}
+
+	issue := domain.NewIssue(
+		domain.SeverityInfo,
+		"container",
+		fmt.Sprintf("Init container %s running (%s)", name, step.sequence()),
+		fmt.Sprintf("The pod is initializing: %s has been running for %s; the app containers start once it and the init containers after it complete", name, elapsed),
+	).WithDetail("container", name).
+		WithDetail("type", "init").
+		WithDetail("sequence", step.sequence()).
+		WithDetail("running_for", elapsed.String())
+	if expected > 0 {
+		issue = issue.WithDetail("expected_duration", expected.String())
+	}
+
+	if limit > 0 && elapsed > limit {
+		issue.Severity = domain.SeverityWarning
+		issue.Title = fmt.Sprintf("Init container %s stuck (%s)", name, step.sequence())
+		issue.Description = fmt.Sprintf("%s has been running for %s, longer than the %s %s; the pod stays Pending and the app containers do not start until it completes",
+			name, elapsed, limit, basis)
+	}
+	return []domain.Issue{issue}
+}
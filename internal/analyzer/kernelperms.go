@@ -0,0 +1,405 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Kernel permission problems, set as the security_context detail
+const (
+	securityCapability     = "capability"
+	securityPrivilegedPort = "privileged_port"
+	securitySysctl         = "sysctl"
+	securityPortInUse      = "port_in_use"
+)
+
+// unprivilegedPortSysctl sets the lowest port non-root processes may bind
+const unprivilegedPortSysctl = "net.ipv4.ip_unprivileged_port_start"
+
+var (
+	// deniedLine matches log lines of a syscall refused by the kernel
+	deniedLine = regexp.MustCompile(`(?i)operation not permitted|permission denied|\bEPERM\b|\bEACCES\b`)
+	// bindLine matches a refused bind, e.g. "listen tcp :80: bind:
+	// permission denied" or "listen EACCES: permission denied 0.0.0.0:80"
+	bindLine = regexp.MustCompile(`(?i)\bbind\b|\blisten\b`)
+	// addrInUseLine matches a bind to a port something else holds
+	addrInUseLine = regexp.MustCompile(`(?i)address already in use|\bEADDRINUSE\b`)
+	// portInLine extracts the port from host:port, [::]:port or :port
+	portInLine = regexp.MustCompile(`:(\d{1,5})\b`)
+	// sysctlLine extracts the sysctl a container failed to set, from
+	// "/proc/sys/net/core/somaxconn" or "error setting key 'net.core.somaxconn'"
+	sysctlLine = regexp.MustCompile(`(?i)/proc/sys/([a-z0-9_/.-]+)|sysctl[^'"\n]*['"]([a-z0-9_]+(?:\.[a-z0-9_-]+)+)['"]`)
+	// sysctlDeniedLine matches a refused sysctl write; /proc/sys is mounted
+	// read-only in containers
+	sysctlDeniedLine = regexp.MustCompile(`(?i)read-only file system|\bEROFS\b|operation not permitted|permission denied`)
+)
+
+// capabilityHints maps log fragments of refused syscalls to the capability
+// the syscall needs, checked in order
+var capabilityHints = []struct {
+	pattern    *regexp.Regexp
+	capability string
+	operation  string
+}{
+	{regexp.MustCompile(`(?i)iptables|nftables|netlink|RTNETLINK|SO_MARK|/dev/net/tun|\bip (?:link|route|addr)`), "NET_ADMIN", "configuring the network"},
+	{regexp.MustCompile(`(?i)raw socket|SOCK_RAW|\bicmp\b|\bping\b`), "NET_RAW", "opening raw sockets"},
+	{regexp.MustCompile(`(?i)\b[lf]?chown\b`), "CHOWN", "changing file ownership"},
+	{regexp.MustCompile(`(?i)\bset(?:res|re|e)?uid\b|\bsetgroups\b`), "SETUID", "switching users"},
+	{regexp.MustCompile(`(?i)\bset(?:res|re|e)?gid\b`), "SETGID", "switching groups"},
+	{regexp.MustCompile(`(?i)\bmount\b|\bunshare\b|\bsetns\b`), "SYS_ADMIN", "mounting file systems or creating namespaces"},
+	{regexp.MustCompile(`(?i)\bmlock|memlock`), "IPC_LOCK", "locking memory"},
+	{regexp.MustCompile(`(?i)ptrace`), "SYS_PTRACE", "tracing processes"},
+	{regexp.MustCompile(`(?i)setrlimit|\bulimit\b|RLIMIT_`), "SYS_RESOURCE", "raising resource limits"},
+	{regexp.MustCompile(`(?i)chroot`), "SYS_CHROOT", "changing the root directory"},
+	{regexp.MustCompile(`(?i)setpriority|sched_setscheduler|\bnice\b`), "SYS_NICE", "raising process priority"},
+	{regexp.MustCompile(`(?i)settimeofday|clock_settime|adjtimex`), "SYS_TIME", "setting the system clock"},
+}
+
+// defaultCapabilities are granted by containerd and CRI-O unless dropped
+var defaultCapabilities = []string{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "NET_RAW", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// safeSysctls may be set by any pod; other namespaced sysctls must be
+// allowed on the kubelet with --allowed-unsafe-sysctls
+var safeSysctls = []string{
+	"kernel.shm_rmid_forced",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.ping_group_range",
+	"net.ipv4.tcp_fin_timeout",
+	"net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+	"net.ipv4.tcp_keepalive_time",
+	"net.ipv4.tcp_syncookies",
+}
+
+// namespacedSysctlPrefixes are the sysctls a pod can set for itself; all
+// others apply to the whole node
+var namespacedSysctlPrefixes = []string{"net.", "kernel.shm", "kernel.msg", "kernel.sem", "fs.mqueue."}
+
+// analyzeKernelPermissions explains refused syscalls in a container's logs
+// with its securityContext: the capability it lacks, the sysctl it tried to
+// set, or the container that already holds its port. It returns nothing for
+// lines it cannot attribute, leaving those to the generic log patterns.
+func analyzeKernelPermissions(pod *corev1.Pod, container corev1.Container, lines []string) []domain.Issue {
+	var issues []domain.Issue
+	seen := make(map[string]bool)
+	add := func(key string, issue domain.Issue) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		issue.Severity = kernelPermissionSeverity(pod, container.Name)
+		issue.Category = "security"
+		issue.Details["container"] = container.Name
+		issues = append(issues, issue)
+	}
+
+	for _, line := range lines {
+		sample := truncateLine(strings.TrimSpace(line), 200)
+
+		if addrInUseLine.MatchString(line) {
+			if port, ok := linePort(line); ok {
+				if issue, ok := portInUseIssue(pod, container, port, sample); ok {
+					add("port:"+strconv.Itoa(port), issue)
+				}
+			}
+			continue
+		}
+
+		if key := sysctlKey(line); key != "" && sysctlDeniedLine.MatchString(line) {
+			if !podSetsSysctl(pod, key) {
+				add("sysctl:"+key, sysctlIssue(container, key, sample))
+			}
+			continue
+		}
+
+		if !deniedLine.MatchString(line) {
+			continue
+		}
+
+		if bindLine.MatchString(line) {
+			if port, ok := linePort(line); ok && port < 1024 {
+				if issue, ok := privilegedPortIssue(pod, container, port, sample); ok {
+					add("bind:"+strconv.Itoa(port), issue)
+				}
+			}
+			continue
+		}
+
+		for _, hint := range capabilityHints {
+			if !hint.pattern.MatchString(line) {
+				continue
+			}
+			if issue, ok := capabilityIssue(pod, container, hint.capability, hint.operation, sample); ok {
+				add("cap:"+hint.capability, issue)
+			}
+			break
+		}
+	}
+
+	return issues
+}
+
+// privilegedPortIssue explains a refused bind to a port below 1024
+func privilegedPortIssue(pod *corev1.Pod, container corev1.Container, port int, sample string) (domain.Issue, bool) {
+	hasCap := hasCapability(container, "NET_BIND_SERVICE")
+	user, nonRoot := runAsUser(pod, container)
+	if hasCap && !nonRoot {
+		return domain.Issue{}, false
+	}
+	if start, ok := podSysctl(pod, unprivilegedPortSysctl); ok {
+		if n, err := strconv.Atoi(start); err == nil && n <= port {
+			return domain.Issue{}, false
+		}
+	}
+
+	reason := "its securityContext drops NET_BIND_SERVICE"
+	if nonRoot {
+		reason = fmt.Sprintf("it runs as non-root user %s, and capabilities added in the securityContext are not effective for non-root processes", user)
+	}
+	return domain.Issue{
+		Title:       fmt.Sprintf("%s cannot bind privileged port %d", container.Name, port),
+		Description: fmt.Sprintf("Binding port %d needs NET_BIND_SERVICE, but %s", port, reason),
+		Details: map[string]string{
+			"port":             strconv.Itoa(port),
+			"capability":       "NET_BIND_SERVICE",
+			"sysctl":           unprivilegedPortSysctl,
+			"non_root":         strconv.FormatBool(nonRoot),
+			"sample_match":     sample,
+			"security_context": securityPrivilegedPort,
+		},
+	}, true
+}
+
+// capabilityIssue explains a refused syscall that needs a capability the
+// container does not have, or cannot use as a non-root user
+func capabilityIssue(pod *corev1.Pod, container corev1.Container, capability, operation, sample string) (domain.Issue, bool) {
+	hasCap := hasCapability(container, capability)
+	user, nonRoot := runAsUser(pod, container)
+	if hasCap && !nonRoot {
+		// Seccomp, AppArmor or SELinux refused it; not attributable here
+		return domain.Issue{}, false
+	}
+
+	var reason string
+	switch {
+	case !hasCap && dropsCapability(container, capability):
+		reason = "its securityContext drops it"
+	case !hasCap:
+		reason = "it is not granted by default and the securityContext does not add it"
+	default:
+		reason = fmt.Sprintf("it runs as non-root user %s, for which added capabilities are not effective", user)
+	}
+	return domain.Issue{
+		Title:       fmt.Sprintf("%s lacks capability %s", container.Name, capability),
+		Description: fmt.Sprintf("The container failed %s, which needs CAP_%s, but %s", operation, capability, reason),
+		Details: map[string]string{
+			"capability":       capability,
+			"non_root":         strconv.FormatBool(nonRoot),
+			"sample_match":     sample,
+			"security_context": securityCapability,
+		},
+	}, true
+}
+
+// sysctlIssue explains a refused write to a sysctl
+func sysctlIssue(container corev1.Container, key, sample string) domain.Issue {
+	scope := "safe"
+	switch {
+	case !isNamespacedSysctl(key):
+		scope = "node"
+	case !containsString(safeSysctls, key):
+		scope = "unsafe"
+	}
+	return domain.Issue{
+		Title:       fmt.Sprintf("%s needs sysctl %s", container.Name, key),
+		Description: fmt.Sprintf("The container tried to set %s, but /proc/sys is read-only in containers; sysctls must be set in the pod spec or on the node", key),
+		Details: map[string]string{
+			"sysctl":           key,
+			"sysctl_scope":     scope,
+			"sample_match":     sample,
+			"security_context": securitySysctl,
+		},
+	}
+}
+
+// portInUseIssue explains "address already in use" when another container
+// of the pod, which shares its network namespace, declares the port, or the
+// pod uses the node's network
+func portInUseIssue(pod *corev1.Pod, container corev1.Container, port int, sample string) (domain.Issue, bool) {
+	for _, other := range pod.Spec.Containers {
+		if other.Name == container.Name {
+			continue
+		}
+		for _, p := range other.Ports {
+			if int(p.ContainerPort) != port {
+				continue
+			}
+			return domain.Issue{
+				Title:       fmt.Sprintf("%s port %d already used by %s", container.Name, port, other.Name),
+				Description: fmt.Sprintf("Containers of a pod share one network namespace, and %s already listens on port %d", other.Name, port),
+				Details: map[string]string{
+					"port":             strconv.Itoa(port),
+					"holder":           other.Name,
+					"sample_match":     sample,
+					"security_context": securityPortInUse,
+				},
+			}, true
+		}
+	}
+
+	if pod.Spec.HostNetwork {
+		return domain.Issue{
+			Title:       fmt.Sprintf("%s port %d already in use on node %s", container.Name, port, pod.Spec.NodeName),
+			Description: fmt.Sprintf("The pod uses the node's network, and another process or hostNetwork pod on the node already listens on port %d", port),
+			Details: map[string]string{
+				"port":             strconv.Itoa(port),
+				"node":             pod.Spec.NodeName,
+				"sample_match":     sample,
+				"security_context": securityPortInUse,
+			},
+		}, true
+	}
+	return domain.Issue{}, false
+}
+
+// kernelPermissionSeverity is critical when the container keeps failing,
+// which a refused syscall at startup usually causes
+func kernelPermissionSeverity(pod *corev1.Pod, container string) domain.Severity {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == container && (cs.RestartCount > 0 || cs.State.Waiting != nil) {
+			return domain.SeverityCritical
+		}
+	}
+	return domain.SeverityWarning
+}
+
+// hasCapability reports whether the container's bounding set includes a
+// capability: privileged containers have all, otherwise the runtime
+// defaults minus drops plus adds
+func hasCapability(container corev1.Container, capability string) bool {
+	sc := container.SecurityContext
+	if sc != nil && sc.Privileged != nil && *sc.Privileged {
+		return true
+	}
+	if sc != nil && sc.Capabilities != nil {
+		for _, c := range sc.Capabilities.Add {
+			if capabilityName(c) == capability || capabilityName(c) == "ALL" {
+				return true
+			}
+		}
+	}
+	if dropsCapability(container, capability) {
+		return false
+	}
+	return containsString(defaultCapabilities, capability)
+}
+
+// dropsCapability reports whether the container drops a capability,
+// directly or with ALL
+func dropsCapability(container corev1.Container, capability string) bool {
+	sc := container.SecurityContext
+	if sc == nil || sc.Capabilities == nil {
+		return false
+	}
+	for _, c := range sc.Capabilities.Drop {
+		if capabilityName(c) == capability || capabilityName(c) == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// capabilityName normalizes a capability, e.g. cap_net_admin -> NET_ADMIN
+func capabilityName(c corev1.Capability) string {
+	return strings.TrimPrefix(strings.ToUpper(string(c)), "CAP_")
+}
+
+// runAsUser returns the user the container runs as, and whether it is
+// known to be non-root, from the container's or else the pod's
+// securityContext
+func runAsUser(pod *corev1.Pod, container corev1.Container) (string, bool) {
+	var uid *int64
+	var nonRoot *bool
+	if psc := pod.Spec.SecurityContext; psc != nil {
+		uid, nonRoot = psc.RunAsUser, psc.RunAsNonRoot
+	}
+	if sc := container.SecurityContext; sc != nil {
+		if sc.RunAsUser != nil {
+			uid = sc.RunAsUser
+		}
+		if sc.RunAsNonRoot != nil {
+			nonRoot = sc.RunAsNonRoot
+		}
+	}
+
+	switch {
+	case uid != nil:
+		return strconv.FormatInt(*uid, 10), *uid != 0
+	case nonRoot != nil && *nonRoot:
+		return "(image default)", true
+	}
+	return "", false
+}
+
+// podSysctl returns the value of a sysctl set in the pod's securityContext
+func podSysctl(pod *corev1.Pod, key string) (string, bool) {
+	if pod.Spec.SecurityContext == nil {
+		return "", false
+	}
+	for _, s := range pod.Spec.SecurityContext.Sysctls {
+		if s.Name == key {
+			return s.Value, true
+		}
+	}
+	return "", false
+}
+
+// podSetsSysctl reports whether the pod's securityContext sets a sysctl
+func podSetsSysctl(pod *corev1.Pod, key string) bool {
+	_, ok := podSysctl(pod, key)
+	return ok
+}
+
+// isNamespacedSysctl reports whether a sysctl can be set per pod
+func isNamespacedSysctl(key string) bool {
+	for _, prefix := range namespacedSysctlPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sysctlKey returns the sysctl named in a log line, in dotted form
+func sysctlKey(line string) string {
+	m := sysctlLine.FindStringSubmatch(line)
+	switch {
+	case m == nil:
+		return ""
+	case m[1] != "":
+		return strings.ReplaceAll(strings.TrimRight(m[1], "/."), "/", ".")
+	}
+	return m[2]
+}
+
+// linePort returns the last port in a log line, e.g. 80 in
+// "listen tcp 0.0.0.0:80: bind: permission denied"
+func linePort(line string) (int, bool) {
+	matches := portInLine.FindAllStringSubmatch(line, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		if port, err := strconv.Atoi(matches[i][1]); err == nil && port > 0 && port < 65536 {
+			return port, true
+		}
+	}
+	return 0, false
+}
@@ -8,12 +8,14 @@ import (
 
 	"github.com/pavanInnamuri/pod-doctor/internal/domain"
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/rules"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // LogAnalyzer analyzes container logs for error patterns
 type LogAnalyzer struct {
-	patterns []errorPattern
+	patterns  []errorPattern
+	tailLines int64
 }
 
 type errorPattern struct {
@@ -21,33 +23,115 @@ type errorPattern struct {
 	Title       string
 	Description string
 	Severity    domain.Severity
+	Category    string
 }
 
 // NewLogAnalyzer creates a new LogAnalyzer with default patterns
 func NewLogAnalyzer() *LogAnalyzer {
 	return &LogAnalyzer{
+		tailLines: DefaultConfig().Logs.TailLines,
 		patterns: []errorPattern{
-			{regexp.MustCompile(`(?i)panic:`), "Panic detected", "Application panicked", domain.SeverityCritical},
-			{regexp.MustCompile(`(?i)fatal\s*(error)?:`), "Fatal error", "Fatal error occurred", domain.SeverityCritical},
-			{regexp.MustCompile(`(?i)out\s*of\s*memory`), "Out of memory", "Application ran out of memory", domain.SeverityCritical},
-			{regexp.MustCompile(`(?i)killed`), "Process killed", "Process was killed", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)connection\s*refused`), "Connection refused", "Cannot connect to a service", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)ECONNREFUSED`), "Connection refused", "TCP connection refused", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)permission\s*denied`), "Permission denied", "Insufficient permissions", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)access\s*denied`), "Access denied", "Access was denied", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)no\s*such\s*file`), "File not found", "Required file not found", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)timeout|timed?\s*out`), "Timeout", "Operation timed out", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)deadline\s*exceeded`), "Deadline exceeded", "Operation deadline was exceeded", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)certificate\s*(verify|validation)\s*failed`), "Certificate error", "TLS certificate validation failed", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)authentication\s*failed`), "Auth failed", "Authentication failed", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)unauthorized`), "Unauthorized", "Unauthorized access attempt", domain.SeverityWarning},
-			{regexp.MustCompile(`(?i)segmentation\s*fault`), "Segfault", "Segmentation fault occurred", domain.SeverityCritical},
-			{regexp.MustCompile(`(?i)stack\s*overflow`), "Stack overflow", "Stack overflow error", domain.SeverityCritical},
-			{regexp.MustCompile(`(?i)null\s*pointer`), "Null pointer", "Null pointer exception", domain.SeverityCritical},
+			{regexp.MustCompile(`(?i)panic:`), "Panic detected", "Application panicked", domain.SeverityCritical, "logs"},
+			{regexp.MustCompile(`(?i)fatal\s*(error)?:`), "Fatal error", "Fatal error occurred", domain.SeverityCritical, "logs"},
+			{regexp.MustCompile(`(?i)out\s*of\s*memory`), "Out of memory", "Application ran out of memory", domain.SeverityCritical, "logs"},
+			{regexp.MustCompile(`(?i)killed`), "Process killed", "Process was killed", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)connection\s*refused`), "Connection refused", "Cannot connect to a service", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)ECONNREFUSED`), "Connection refused", "TCP connection refused", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)permission\s*denied`), "Permission denied", "Insufficient permissions", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)access\s*denied`), "Access denied", "Access was denied", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)no\s*such\s*file`), "File not found", "Required file not found", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)timeout|timed?\s*out`), "Timeout", "Operation timed out", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)deadline\s*exceeded`), "Deadline exceeded", "Operation deadline was exceeded", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)certificate\s*(verify|validation)\s*failed`), "Certificate error", "TLS certificate validation failed", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)authentication\s*failed`), "Auth failed", "Authentication failed", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)unauthorized`), "Unauthorized", "Unauthorized access attempt", domain.SeverityWarning, "logs"},
+			{regexp.MustCompile(`(?i)(invalid|incorrect|unexpected)\s*(token\s*)?audience|InvalidIdentityToken|aud\s*claim`), "Token audience rejected", "A service account token was rejected for its audience", domain.SeverityCritical, "serviceaccount"},
+			{regexp.MustCompile(`(?i)token\s*(has|is)\s*expired|expired\s*token`), "Expired token", "A token was used after it expired", domain.SeverityWarning, "serviceaccount"},
+			{regexp.MustCompile(`(?i)segmentation\s*fault`), "Segfault", "Segmentation fault occurred", domain.SeverityCritical, "logs"},
+			{regexp.MustCompile(`(?i)stack\s*overflow`), "Stack overflow", "Stack overflow error", domain.SeverityCritical, "logs"},
+			{regexp.MustCompile(`(?i)null\s*pointer`), "Null pointer", "Null pointer exception", domain.SeverityCritical, "logs"},
+			{regexp.MustCompile(`(?i)no\s*route\s*to\s*host`), "No route to host", "Network path to a destination is unavailable", domain.SeverityWarning, "network"},
+			{regexp.MustCompile(`(?i)network\s*is\s*unreachable`), "Network unreachable", "Destination network is unreachable", domain.SeverityWarning, "network"},
+			{regexp.MustCompile(`(?i)no\s*such\s*host|temporary\s*failure\s*in\s*name\s*resolution|could\s*not\s*resolve\s*host|name\s*or\s*service\s*not\s*known`), "DNS resolution failed", "A hostname could not be resolved", domain.SeverityWarning, "network"},
 		},
 	}
 }
 
+// AddPattern registers an additional error pattern. A pattern with the same
+// title as an existing one replaces it.
+func (l *LogAnalyzer) AddPattern(pattern *regexp.Regexp, title, description string, severity domain.Severity, category string) {
+	if category == "" {
+		category = "logs"
+	}
+	p := errorPattern{pattern, title, description, severity, category}
+	for i := range l.patterns {
+		if l.patterns[i].Title == title {
+			l.patterns[i] = p
+			return
+		}
+	}
+	l.patterns = append(l.patterns, p)
+}
+
+// DisablePattern drops the patterns with the given title
+func (l *LogAnalyzer) DisablePattern(title string) {
+	patterns := l.patterns[:0]
+	for _, p := range l.patterns {
+		if p.Title != title {
+			patterns = append(patterns, p)
+		}
+	}
+	l.patterns = patterns
+}
+
+// newConfiguredLogAnalyzer creates a LogAnalyzer with the built-in patterns
+// adjusted by a resolved configuration
+func newConfiguredLogAnalyzer(cfg LogConfig) *LogAnalyzer {
+	l := NewLogAnalyzer()
+	if cfg.TailLines > 0 {
+		l.tailLines = cfg.TailLines
+	}
+	for _, title := range cfg.DisabledPatterns {
+		l.DisablePattern(title)
+	}
+	for _, lp := range cfg.Patterns {
+		// Patterns were validated when the configuration was resolved
+		l.AddPattern(regexp.MustCompile(lp.Pattern), lp.Title, lp.Description, lp.Severity, lp.Category)
+	}
+	return l
+}
+
+// DefaultLogPatterns returns the built-in log patterns as rule pack entries
+func DefaultLogPatterns() []rules.LogPattern {
+	var patterns []rules.LogPattern
+	for _, p := range NewLogAnalyzer().patterns {
+		patterns = append(patterns, rules.LogPattern{
+			Pattern:     p.Pattern.String(),
+			Title:       p.Title,
+			Description: p.Description,
+			Severity:    p.Severity,
+			Category:    p.Category,
+		})
+	}
+	return patterns
+}
+
+// MatchLine returns the severity of the most severe pattern matching line
+func (l *LogAnalyzer) MatchLine(line string) (domain.Severity, bool) {
+	var severity domain.Severity
+	matched := false
+	for _, pattern := range l.patterns {
+		if !pattern.Pattern.MatchString(line) {
+			continue
+		}
+		if !matched || pattern.Severity == domain.SeverityCritical {
+			severity = pattern.Severity
+		}
+		matched = true
+	}
+	return severity, matched
+}
+
 // Name returns the analyzer name
 func (l *LogAnalyzer) Name() string {
 	return "logs"
@@ -58,22 +142,38 @@ func (l *LogAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kube
 	var issues []domain.Issue
 
 	for _, container := range pod.Spec.Containers {
-		containerIssues, err := l.analyzeContainerLogs(ctx, client, pod.Namespace, pod.Name, container.Name, false)
+		containerIssues, err := l.analyzeContainerLogs(ctx, client, pod, container, false)
 		if err != nil {
 			// Try previous logs if current logs fail
-			containerIssues, _ = l.analyzeContainerLogs(ctx, client, pod.Namespace, pod.Name, container.Name, true)
+			containerIssues, _ = l.analyzeContainerLogs(ctx, client, pod, container, true)
 		}
 		issues = append(issues, containerIssues...)
 	}
 
+	// While the pod initializes, the errors are in the init container it
+	// is stuck on; the app containers have no logs yet
+	if step, ok := currentInitContainer(pod); ok && step.started() {
+		initIssues, err := l.analyzeContainerLogs(ctx, client, pod, step.container, false)
+		if err != nil || (len(initIssues) == 0 && step.status.RestartCount > 0) {
+			// A crash-looping init container's errors are in its last run
+			initIssues, _ = l.analyzeContainerLogs(ctx, client, pod, step.container, true)
+		}
+		for _, issue := range initIssues {
+			issue.Details["type"] = "init"
+			issue.Details["sequence"] = step.sequence()
+			issues = append(issues, issue)
+		}
+	}
+
 	return issues, nil
 }
 
 // analyzeContainerLogs analyzes logs from a specific container
-func (l *LogAnalyzer) analyzeContainerLogs(ctx context.Context, client *kubernetes.Client, namespace, podName, containerName string, previous bool) ([]domain.Issue, error) {
+func (l *LogAnalyzer) analyzeContainerLogs(ctx context.Context, client *kubernetes.Client, pod *corev1.Pod, container corev1.Container, previous bool) ([]domain.Issue, error) {
 	var issues []domain.Issue
+	containerName := container.Name
 
-	logs, err := client.GetPodLogs(ctx, namespace, podName, containerName, 100, previous)
+	logs, err := client.GetPodLogs(ctx, pod.Namespace, pod.Name, containerName, l.tailLines, previous)
 	if err != nil {
 		return nil, err
 	}
@@ -97,12 +197,20 @@ func (l *LogAnalyzer) analyzeContainerLogs(ctx context.Context, client *kubernet
 		}
 	}
 
+	// Refused syscalls explained by the securityContext replace the
+	// generic permission pattern
+	permissionIssues := analyzeKernelPermissions(pod, container, lines)
+	issues = append(issues, permissionIssues...)
+
 	// Create issues for matched patterns
 	for _, pattern := range l.patterns {
+		if len(permissionIssues) > 0 && pattern.Title == "Permission denied" {
+			continue
+		}
 		if matches, ok := matchedPatterns[pattern.Title]; ok {
 			issue := domain.Issue{
 				Severity:    pattern.Severity,
-				Category:    "logs",
+				Category:    pattern.Category,
 				Title:       fmt.Sprintf("[%s] %s", containerName, pattern.Title),
 				Description: pattern.Description,
 				Details: map[string]string{
@@ -114,6 +222,11 @@ func (l *LogAnalyzer) analyzeContainerLogs(ctx context.Context, client *kubernet
 			if len(matches) > 1 {
 				issue.Details["additional_matches"] = fmt.Sprintf("%d more occurrences", len(matches)-1)
 			}
+			if pattern.Title == "Connection refused" {
+				if target := extractTarget(matches); target != "" {
+					issue.Details["target"] = target
+				}
+			}
 			issues = append(issues, issue)
 		}
 	}
@@ -121,6 +234,29 @@ func (l *LogAnalyzer) analyzeContainerLogs(ctx context.Context, client *kubernet
 	return issues, nil
 }
 
+// targetPattern matches the host:port a connection was attempted to, as
+// printed by common runtimes (e.g. "dial tcp 10.0.0.5:5432", "connect to
+// redis:6379", "ECONNREFUSED 127.0.0.1:3306")
+var targetPattern = regexp.MustCompile(`(?i)(?:dial tcp|connect(?:ion)? to|ECONNREFUSED|connecting to)\s+([a-z0-9._-]+:\d+)`)
+
+// extractTarget returns the most common host:port found in the matched lines
+func extractTarget(lines []string) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, line := range lines {
+		m := targetPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		target := strings.ToLower(m[1])
+		counts[target]++
+		if counts[target] > counts[best] {
+			best = target
+		}
+	}
+	return best
+}
+
 // truncateLine truncates a line to maxLen characters
 func truncateLine(line string, maxLen int) string {
 	if len(line) <= maxLen {
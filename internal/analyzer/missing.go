@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ownerKinds are the controllers whose events mention the pods they manage
+var ownerKinds = map[string]bool{
+	"Job":         true,
+	"ReplicaSet":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// DiagnoseMissing reconstructs a diagnosis for a pod that no longer exists
+// from the events that outlive it and from its owner's status
+func (p *PodAnalyzer) DiagnoseMissing(ctx context.Context, namespace, name string) (*domain.Diagnosis, error) {
+	events, err := p.client.ListNamespaceEvents(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnosis := domain.NewDiagnosis(domain.PodInfo{
+		Name:       name,
+		Namespace:  namespace,
+		Phase:      "Deleted",
+		Containers: make([]domain.ContainerInfo, 0),
+	})
+	diagnosis.Status = domain.StatusDeleted
+
+	// Split events into the pod's own events and its owner's mentions of it
+	var ownerKind, ownerName string
+	for _, e := range events {
+		obj := e.InvolvedObject
+		if obj.Kind == "Pod" && obj.Name == name {
+			diagnosis.Events = append(diagnosis.Events, kubernetes.ExtractEventInfo(e))
+			if obj.FieldPath == "" && diagnosis.Pod.Node == "" && e.Reason == "Scheduled" {
+				diagnosis.Pod.Node = scheduledNode(e.Message)
+			}
+			continue
+		}
+		if ownerName == "" && ownerKinds[obj.Kind] && ownsPodName(obj.Name, name) && mentionsPod(e.Message, name) {
+			ownerKind, ownerName = obj.Kind, obj.Name
+		}
+	}
+
+	domain.SortEvents(diagnosis.Events)
+
+	if len(diagnosis.Events) == 0 && ownerName == "" {
+		return nil, fmt.Errorf("pod %s/%s not found and no events or owner references remain", namespace, name)
+	}
+
+	diagnosis.AddIssue(domain.Issue{
+		Severity:    domain.SeverityInfo,
+		Category:    "container",
+		Title:       "Pod no longer exists",
+		Description: "Diagnosis was reconstructed from remaining events and the owner's status",
+		Details: map[string]string{
+			"events": fmt.Sprintf("%d", len(diagnosis.Events)),
+		},
+	})
+
+	replayEvents(diagnosis, diagnosis.Events)
+
+	if ownerName != "" {
+		diagnosis.Workload = &domain.WorkloadInfo{
+			Kind:      ownerKind,
+			Name:      ownerName,
+			Namespace: namespace,
+			Chain:     []string{ownerKind + "/" + ownerName},
+		}
+		diagnosis.AddIssue(domain.Issue{
+			Severity:    domain.SeverityInfo,
+			Category:    "workload",
+			Title:       fmt.Sprintf("Owned by %s %s", ownerKind, ownerName),
+			Description: "Owner was identified from its events mentioning the pod",
+			Details: map[string]string{
+				"owner_kind": ownerKind,
+				"owner_name": ownerName,
+			},
+		})
+		if ownerKind == "Job" {
+			diagnosis.Issues = append(diagnosis.Issues, p.analyzeMissingPodJob(ctx, namespace, ownerName)...)
+		}
+	}
+
+	p.finalize(diagnosis)
+
+	return diagnosis, nil
+}
+
+// analyzeMissingPodJob reports the final status of the job that owned a deleted pod
+func (p *PodAnalyzer) analyzeMissingPodJob(ctx context.Context, namespace, name string) []domain.Issue {
+	job, err := p.client.GetJob(ctx, namespace, name)
+	if err != nil {
+		return nil
+	}
+
+	var issues []domain.Issue
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobFailed:
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "workload",
+				Title:       fmt.Sprintf("Job %s failed: %s", name, cond.Reason),
+				Description: cond.Message,
+				Details: map[string]string{
+					"job":       name,
+					"reason":    cond.Reason,
+					"failed":    fmt.Sprintf("%d", job.Status.Failed),
+					"succeeded": fmt.Sprintf("%d", job.Status.Succeeded),
+				},
+			})
+		case batchv1.JobComplete:
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityInfo,
+				Category:    "workload",
+				Title:       fmt.Sprintf("Job %s completed", name),
+				Description: fmt.Sprintf("Job finished with %d succeeded and %d failed pods", job.Status.Succeeded, job.Status.Failed),
+				Details: map[string]string{
+					"job": name,
+				},
+			})
+		}
+	}
+
+	return issues
+}
+
+// replayEvents adds the issues a pod's past events point to, running its
+// warning events through the event analyzer
+func replayEvents(diagnosis *domain.Diagnosis, events []domain.EventInfo) {
+	eventAnalyzer := NewEventAnalyzer()
+	for _, event := range events {
+		switch event.Reason {
+		case "Evicted":
+			diagnosis.AddIssue(domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "resources",
+				Title:       "Pod was evicted",
+				Description: event.Message,
+				Details: map[string]string{
+					"reason": "Evicted",
+				},
+			})
+			continue
+		case "Preempted":
+			diagnosis.AddIssue(domain.Issue{
+				Severity:    domain.SeverityWarning,
+				Category:    "scheduling",
+				Title:       "Pod was preempted by a higher-priority pod",
+				Description: event.Message,
+				Details: map[string]string{
+					"reason": "Preempted",
+				},
+			})
+			continue
+		}
+		if event.Type == "Warning" {
+			if issue := eventAnalyzer.analyzeWarningEvent(event); issue != nil {
+				diagnosis.AddIssue(*issue)
+			}
+		}
+	}
+}
+
+// ownsPodName reports whether a controller could have created a pod of
+// that name: its pods are named after it, e.g. web-7d9f8c6b5-x2k4p for
+// ReplicaSet web-7d9f8c6b5 or db-0 for StatefulSet db
+func ownsPodName(owner, pod string) bool {
+	return strings.HasPrefix(pod, owner+"-")
+}
+
+// mentionsPod reports whether an event message names the pod as a whole
+// word, e.g. "Created pod: web-0" but not "Created pod: web-01"
+func mentionsPod(message, name string) bool {
+	for _, word := range strings.Fields(message) {
+		if strings.Trim(word, `"':,.;()[]`) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduledNode extracts the node name from a "Scheduled" event message
+func scheduledNode(message string) string {
+	const marker = " to "
+	if i := strings.LastIndex(message, marker); i >= 0 {
+		return strings.TrimSpace(message[i+len(marker):])
+	}
+	return ""
+}
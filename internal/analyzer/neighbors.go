@@ -0,0 +1,251 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+const (
+	// topConsumers is how many of the heaviest pods on the node are listed
+	topConsumers = 3
+	// noisyRequestFactor is how far above its requests a co-located pod's
+	// usage has to be for it to be a likely noisy neighbor
+	noisyRequestFactor = 1.5
+	// noisyShare is the share of the node's allocatable resource a likely
+	// noisy neighbor uses at least
+	noisyShare = 0.1
+)
+
+// NeighborAnalyzer looks at the pods sharing a node with a pod that runs out
+// of memory or CPU, for co-located pods using far more than they request
+type NeighborAnalyzer struct{}
+
+// NewNeighborAnalyzer creates a new NeighborAnalyzer
+func NewNeighborAnalyzer() *NeighborAnalyzer {
+	return &NeighborAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (a *NeighborAnalyzer) Name() string {
+	return "neighbors"
+}
+
+// shortage is a resource a pod is short of, and the containers showing it
+type shortage struct {
+	resource   corev1.ResourceName
+	containers []string
+}
+
+// format renders an amount of the resource: millicores or mebibytes
+func (s shortage) format(amount int64) string {
+	if s.resource == corev1.ResourceCPU {
+		return fmt.Sprintf("%dm", amount)
+	}
+	return formatMemory(resource.NewQuantity(amount, resource.BinarySI))
+}
+
+// neighbor is a pod on the same node and its live usage and requests of the
+// resource a shortage is about
+type neighbor struct {
+	pod     *corev1.Pod
+	usage   int64
+	request int64
+}
+
+// noisy reports whether the pod uses far more than it requests, taking
+// what the scheduler counted as free for the other pods of the node
+func (n neighbor) noisy(allocatable int64) bool {
+	if float64(n.usage) <= float64(n.request)*noisyRequestFactor {
+		return false
+	}
+	return allocatable <= 0 || float64(n.usage) >= float64(allocatable)*noisyShare
+}
+
+func (n neighbor) String() string {
+	return n.pod.Namespace + "/" + n.pod.Name
+}
+
+// Analyze lists the heaviest pods on the node of a pod that was OOMKilled or
+// is throttled at its CPU limit, and flags those using far more than they
+// request. Without metrics-server the usage of the node's pods is unknown,
+// so nothing is reported.
+func (a *NeighborAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	if pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	metrics, err := client.GetPodMetrics(ctx, pod.Namespace, pod.Name)
+	if err != nil {
+		return nil, nil
+	}
+	shortages := podShortages(pod, metrics)
+	if len(shortages) == 0 {
+		return nil, nil
+	}
+
+	list, err := client.ListPodsOnNode(ctx, pod.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	usage := livePodUsage(ctx, client, list.Items)
+
+	// Shares of the node are left out if the user may not read nodes
+	var allocatable corev1.ResourceList
+	if node, err := client.GetNode(ctx, pod.Spec.NodeName); err == nil {
+		allocatable = node.Status.Allocatable
+	}
+
+	var issues []domain.Issue
+	for _, s := range shortages {
+		if issue := a.neighborIssue(ctx, client, pod, s, list.Items, usage, allocatable); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	return issues, nil
+}
+
+// podShortages returns the resources a pod is short of: memory if a
+// container was OOMKilled, CPU if one runs at its CPU limit
+func podShortages(pod *corev1.Pod, metrics *metricsv1beta1.PodMetrics) []shortage {
+	memory := shortage{resource: corev1.ResourceMemory}
+	for _, cs := range pod.Status.ContainerStatuses {
+		for _, t := range []*corev1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+			if t != nil && t.Reason == "OOMKilled" {
+				memory.containers = append(memory.containers, cs.Name)
+				break
+			}
+		}
+	}
+
+	cpu := shortage{resource: corev1.ResourceCPU}
+	limits := make(map[string]*resource.Quantity)
+	for _, c := range pod.Spec.Containers {
+		limits[c.Name] = c.Resources.Limits.Cpu()
+	}
+	for _, usage := range metrics.Containers {
+		limit, ok := limits[usage.Name]
+		if !ok || limit.IsZero() {
+			continue
+		}
+		if float64(usage.Usage.Cpu().MilliValue()) >= float64(limit.MilliValue())*usageThreshold {
+			cpu.containers = append(cpu.containers, usage.Name)
+		}
+	}
+
+	var shortages []shortage
+	for _, s := range []shortage{memory, cpu} {
+		if len(s.containers) > 0 {
+			shortages = append(shortages, s)
+		}
+	}
+	return shortages
+}
+
+// neighborIssue reports the heaviest users of a resource on the pod's node
+// other than the pod itself, as a warning naming the likely noisy
+// neighbors among them, if any
+func (a *NeighborAnalyzer) neighborIssue(ctx context.Context, client *kubernetes.Client, pod *corev1.Pod, s shortage, pods []corev1.Pod, usage map[string]liveUsage, allocatable corev1.ResourceList) *domain.Issue {
+	var neighbors []neighbor
+	var total int64
+	for i := range pods {
+		p := &pods[i]
+		u, ok := usage[p.Namespace+"/"+p.Name]
+		if !ok || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		amount, request := u.memory, podMemoryRequest(p)
+		if s.resource == corev1.ResourceCPU {
+			amount, request = u.cpu, podCPURequest(p)
+		}
+		total += amount
+		if p.Namespace == pod.Namespace && p.Name == pod.Name {
+			continue
+		}
+		neighbors = append(neighbors, neighbor{pod: p, usage: amount, request: request})
+	}
+	if len(neighbors) == 0 {
+		return nil
+	}
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		return neighbors[i].usage > neighbors[j].usage
+	})
+	if len(neighbors) > topConsumers {
+		neighbors = neighbors[:topConsumers]
+	}
+
+	capacity := allocatable.Memory().Value()
+	if s.resource == corev1.ResourceCPU {
+		capacity = allocatable.Cpu().MilliValue()
+	}
+
+	var consumers, noisy []string
+	var loudest *neighbor
+	for i, n := range neighbors {
+		consumers = append(consumers, fmt.Sprintf("%s %s (requests %s)", n, s.format(n.usage), s.format(n.request)))
+		if n.noisy(capacity) {
+			noisy = append(noisy, n.String())
+			if loudest == nil {
+				loudest = &neighbors[i]
+			}
+		}
+	}
+
+	symptom := fmt.Sprintf("%s was OOMKilled", strings.Join(s.containers, ", "))
+	if s.resource == corev1.ResourceCPU {
+		symptom = fmt.Sprintf("%s is throttled at its CPU limit", strings.Join(s.containers, ", "))
+	}
+	description := symptom + "."
+	if capacity > 0 {
+		description += fmt.Sprintf(" The pods on %s use %.0f%% of its allocatable %s.", pod.Spec.NodeName, float64(total)/float64(capacity)*100, s.resource)
+	}
+
+	issue := domain.NewIssue(
+		domain.SeverityInfo,
+		"resources",
+		fmt.Sprintf("Top %s consumers on %s", s.resource, pod.Spec.NodeName),
+		description+" None of the heaviest pods on the node uses much more than it requests, so the pod is more likely short of its own requests or limits",
+	).WithDetail("node", pod.Spec.NodeName).
+		WithDetail("resource", string(s.resource)).
+		WithDetail("top_consumers", strings.Join(consumers, "; "))
+	if capacity > 0 {
+		issue = issue.WithDetail("node_usage", fmt.Sprintf("%.0f%%", float64(total)/float64(capacity)*100))
+	}
+	if len(s.containers) == 1 {
+		issue = issue.WithDetail("container", s.containers[0])
+	}
+
+	if loudest != nil {
+		issue.Severity = domain.SeverityWarning
+		issue.Title = fmt.Sprintf("Likely noisy neighbor on %s: %s", pod.Spec.NodeName, loudest)
+		issue.Description = description + fmt.Sprintf(" %s on the same node use far more %s than they request, taking what the scheduler counted as free for this pod",
+			strings.Join(noisy, ", "), s.resource)
+		issue = issue.WithDetail("noisy_neighbors", strings.Join(noisy, ", ")).
+			WithDetail("noisy_namespace", loudest.pod.Namespace)
+		if workload, err := client.ResolveWorkload(ctx, loudest.pod); err == nil && workload != nil {
+			issue = issue.WithDetail("noisy_workload", workload.Ref())
+		}
+		for _, n := range neighbors {
+			if n.noisy(capacity) {
+				issue = issue.WithObject(objectRef("Pod", n.pod))
+			}
+		}
+	}
+	return &issue
+}
+
+// podCPURequest returns the sum of the CPU requests of a pod's containers,
+// in millicores
+func podCPURequest(pod *corev1.Pod) int64 {
+	var total int64
+	for _, c := range pod.Spec.Containers {
+		total += c.Resources.Requests.Cpu().MilliValue()
+	}
+	return total
+}
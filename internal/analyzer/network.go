@@ -0,0 +1,327 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NetworkAnalyzer analyzes service endpoints, DNS configuration and host
+// networking for connectivity problems
+type NetworkAnalyzer struct{}
+
+// NewNetworkAnalyzer creates a new NetworkAnalyzer
+func NewNetworkAnalyzer() *NetworkAnalyzer {
+	return &NetworkAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (n *NetworkAnalyzer) Name() string {
+	return "network"
+}
+
+// Analyze checks the pod's network configuration for issues
+func (n *NetworkAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	issues = append(issues, n.analyzeDNSConfig(pod)...)
+
+	if pod.Spec.HostNetwork && pod.Spec.NodeName != "" {
+		issues = append(issues, n.analyzeHostPorts(ctx, pod, client)...)
+	}
+
+	endpointIssues, err := n.analyzeServiceEndpoints(ctx, pod, client)
+	if err != nil {
+		return issues, err
+	}
+	issues = append(issues, endpointIssues...)
+
+	return issues, nil
+}
+
+// analyzeDNSConfig checks dnsPolicy and dnsConfig for common mistakes
+func (n *NetworkAnalyzer) analyzeDNSConfig(pod *corev1.Pod) []domain.Issue {
+	var issues []domain.Issue
+	dnsConfig := pod.Spec.DNSConfig
+
+	// dnsPolicy None means the pod only gets what dnsConfig provides
+	if pod.Spec.DNSPolicy == corev1.DNSNone && (dnsConfig == nil || len(dnsConfig.Nameservers) == 0) {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityCritical,
+			Category:    "network",
+			Title:       "No DNS nameservers configured",
+			Description: "dnsPolicy is None but dnsConfig has no nameservers, so the pod cannot resolve any names",
+			Details: map[string]string{
+				"dns_policy": string(pod.Spec.DNSPolicy),
+			},
+		})
+	}
+
+	// hostNetwork pods with the default policy use the node's resolver and cannot resolve cluster services
+	if pod.Spec.HostNetwork && (pod.Spec.DNSPolicy == corev1.DNSClusterFirst || pod.Spec.DNSPolicy == "") {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "network",
+			Title:       "hostNetwork pod cannot resolve cluster DNS",
+			Description: "Pods using hostNetwork with dnsPolicy ClusterFirst fall back to the node's resolver; use ClusterFirstWithHostNet to resolve services",
+			Details: map[string]string{
+				"dns_policy":   string(corev1.DNSClusterFirst),
+				"host_network": "true",
+			},
+		})
+	}
+
+	if dnsConfig == nil {
+		return issues
+	}
+
+	for _, opt := range dnsConfig.Options {
+		if opt.Name != "ndots" || opt.Value == nil {
+			continue
+		}
+		ndots, err := strconv.Atoi(*opt.Value)
+		switch {
+		case err != nil || ndots < 0:
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityWarning,
+				Category:    "network",
+				Title:       "Invalid DNS ndots option",
+				Description: "The ndots DNS option is not a non-negative integer and will be ignored by the resolver",
+				Details: map[string]string{
+					"ndots": *opt.Value,
+				},
+			})
+		case ndots > 5:
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityWarning,
+				Category:    "network",
+				Title:       "High DNS ndots value",
+				Description: "Names with fewer dots are tried against every search domain first, multiplying DNS queries and latency for external names",
+				Details: map[string]string{
+					"ndots": *opt.Value,
+				},
+			})
+		}
+	}
+
+	if len(dnsConfig.Searches) > 32 {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "network",
+			Title:       "Too many DNS search domains",
+			Description: "Kubernetes allows at most 32 search domains; the pod may fail to start or lookups may be truncated",
+			Details: map[string]string{
+				"search_domains": fmt.Sprintf("%d", len(dnsConfig.Searches)),
+			},
+		})
+	}
+
+	return issues
+}
+
+// analyzeHostPorts checks whether other hostNetwork pods on the node use the same ports
+func (n *NetworkAnalyzer) analyzeHostPorts(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) []domain.Issue {
+	var issues []domain.Issue
+
+	ports := hostPorts(pod)
+	if len(ports) == 0 {
+		return nil
+	}
+
+	nodePods, err := client.ListPodsOnNode(ctx, pod.Spec.NodeName)
+	if err != nil {
+		return nil
+	}
+
+	for i := range nodePods.Items {
+		other := &nodePods.Items[i]
+		if other.UID == pod.UID || !other.Spec.HostNetwork {
+			continue
+		}
+		if other.Status.Phase == corev1.PodSucceeded || other.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for port := range hostPorts(other) {
+			if !ports[port] {
+				continue
+			}
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "network",
+				Title:       fmt.Sprintf("Host port %s conflicts with %s/%s", port, other.Namespace, other.Name),
+				Description: "Another hostNetwork pod on the same node listens on this port, so only one of them can bind it",
+				Details: map[string]string{
+					"port":      port,
+					"node":      pod.Spec.NodeName,
+					"other_pod": other.Namespace + "/" + other.Name,
+				},
+			})
+		}
+	}
+
+	return issues
+}
+
+// analyzeServiceEndpoints checks that services selecting the pod have ready endpoints
+func (n *NetworkAnalyzer) analyzeServiceEndpoints(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	services, err := client.ListServices(ctx, pod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// A running pod that is not ready is taken out of its services'
+	// endpoints, unless they publish not-ready addresses
+	notReady := pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil && !isPodReady(pod)
+
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		slices, err := client.ListEndpointSlices(ctx, pod.Namespace, svc.Name)
+		if err != nil {
+			continue
+		}
+
+		counts := countEndpoints(slices.Items, pod)
+		ready := counts.ready
+
+		if ready == 0 {
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "network",
+				Title:       fmt.Sprintf("Service %s has no ready endpoints", svc.Name),
+				Description: "No pod backing this service is ready, so connections to it will fail",
+				Details: map[string]string{
+					"service": svc.Name,
+				},
+			}.WithObject(objectRef("Service", svc)))
+			continue
+		}
+
+		if notReady && !svc.Spec.PublishNotReadyAddresses && !counts.podReady {
+			issues = append(issues, removedFromService(svc, counts))
+		}
+
+		// Warn if the service targets a named port the pod does not expose
+		for _, port := range svc.Spec.Ports {
+			if port.TargetPort.StrVal != "" && !hasNamedPort(pod, port.TargetPort.StrVal) {
+				issues = append(issues, domain.Issue{
+					Severity:    domain.SeverityWarning,
+					Category:    "network",
+					Title:       fmt.Sprintf("Service %s targets unknown port %s", svc.Name, port.TargetPort.StrVal),
+					Description: "The service's targetPort names a port this pod does not declare, so traffic cannot reach it",
+					Details: map[string]string{
+						"service":     svc.Name,
+						"target_port": port.TargetPort.StrVal,
+					},
+				}.WithObject(objectRef("Service", svc)))
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// endpointCounts counts the endpoints of a service
+type endpointCounts struct {
+	ready    int
+	total    int  // including the pod, even if it is not listed
+	podReady bool // the pod is listed as a ready endpoint
+}
+
+// countEndpoints counts the endpoints of a service's EndpointSlices, once
+// each across the slices of different address families, and the ready ones
+func countEndpoints(slices []discoveryv1.EndpointSlice, pod *corev1.Pod) endpointCounts {
+	var counts endpointCounts
+	listed := false
+	seen := make(map[string]bool)
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			key := strings.Join(ep.Addresses, ",")
+			if ep.TargetRef != nil && ep.TargetRef.UID != "" {
+				key = string(ep.TargetRef.UID)
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			counts.total++
+			ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+			if ready {
+				counts.ready++
+			}
+			if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" && ep.TargetRef.Name == pod.Name {
+				listed = true
+				counts.podReady = ready
+			}
+		}
+	}
+	if !listed {
+		counts.total++
+	}
+	return counts
+}
+
+// removedFromService reports a not-ready pod taken out of a service's
+// endpoints, with how many remain to take its share of the traffic
+func removedFromService(svc *corev1.Service, counts endpointCounts) domain.Issue {
+	remaining := fmt.Sprintf("%d/%d", counts.ready, counts.total)
+	return domain.Issue{
+		Severity: domain.SeverityWarning,
+		Category: "network",
+		Title:    fmt.Sprintf("Pod removed from service %s endpoints: %s remain", svc.Name, remaining),
+		Description: fmt.Sprintf("The pod is running but not ready, so the service no longer sends it traffic. "+
+			"%d of %d endpoints remain for service %s, each taking about %.1f× its usual share of the traffic",
+			counts.ready, counts.total, svc.Name, float64(counts.total)/float64(counts.ready)),
+		Details: map[string]string{
+			"service":   svc.Name,
+			"endpoints": remaining,
+		},
+	}.WithObject(objectRef("Service", svc))
+}
+
+// hostPorts returns the ports a pod binds on the host, as "port/protocol"
+func hostPorts(pod *corev1.Pod) map[string]bool {
+	ports := make(map[string]bool)
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			port := p.HostPort
+			if port == 0 && pod.Spec.HostNetwork {
+				port = p.ContainerPort
+			}
+			if port == 0 {
+				continue
+			}
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			ports[fmt.Sprintf("%d/%s", port, protocol)] = true
+		}
+	}
+	return ports
+}
+
+// hasNamedPort returns true if any container in the pod declares the named port
+func hasNamedPort(pod *corev1.Pod, name string) bool {
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
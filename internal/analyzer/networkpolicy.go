@@ -0,0 +1,386 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// dnsNamespace and dnsSelector find the cluster DNS pods, labeled
+	// k8s-app=kube-dns by both kube-dns and CoreDNS deployments
+	dnsNamespace = "kube-system"
+	dnsSelector  = "k8s-app=kube-dns"
+	dnsPort      = 53
+)
+
+// NetworkPolicyAnalyzer reports the NetworkPolicies selecting a pod, the
+// traffic they let in and out, and policies blocking its readiness probe or
+// its DNS lookups
+type NetworkPolicyAnalyzer struct{}
+
+// NewNetworkPolicyAnalyzer creates a new NetworkPolicyAnalyzer
+func NewNetworkPolicyAnalyzer() *NetworkPolicyAnalyzer {
+	return &NetworkPolicyAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (a *NetworkPolicyAnalyzer) Name() string {
+	return "networkpolicy"
+}
+
+// policyIsolation is what the NetworkPolicies selecting a pod allow in one
+// direction. Without a policy isolating the pod in that direction, all
+// traffic is allowed.
+type policyIsolation struct {
+	isolated bool
+	ingress  []networkingv1.NetworkPolicyIngressRule
+	egress   []networkingv1.NetworkPolicyEgressRule
+}
+
+// Analyze lists the NetworkPolicies of the pod's namespace that select it.
+// hostNetwork pods are skipped: policies do not apply to them.
+func (a *NetworkPolicyAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	if pod.Spec.HostNetwork {
+		return nil, nil
+	}
+	policies, err := client.ListNetworkPolicies(ctx, pod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	selecting := selectingPolicies(pod, policies)
+	if len(selecting) == 0 {
+		return nil, nil
+	}
+
+	var ingress, egress policyIsolation
+	for _, p := range selecting {
+		for _, t := range policyTypes(p) {
+			switch t {
+			case networkingv1.PolicyTypeIngress:
+				ingress.isolated = true
+				ingress.ingress = append(ingress.ingress, p.Spec.Ingress...)
+			case networkingv1.PolicyTypeEgress:
+				egress.isolated = true
+				egress.egress = append(egress.egress, p.Spec.Egress...)
+			}
+		}
+	}
+
+	var names []string
+	for _, p := range selecting {
+		names = append(names, p.Name)
+	}
+	title := fmt.Sprintf("Selected by %d NetworkPolicies", len(selecting))
+	if len(selecting) == 1 {
+		title = "Selected by NetworkPolicy " + selecting[0].Name
+	}
+	summary := domain.NewIssue(
+		domain.SeverityInfo,
+		"network",
+		title,
+		fmt.Sprintf("Ingress: %s. Egress: %s", describeIngress(ingress), describeEgress(egress)),
+	).WithDetail("policies", strings.Join(names, ", ")).
+		WithDetail("ingress", describeIngress(ingress)).
+		WithDetail("egress", describeEgress(egress))
+
+	issues := []domain.Issue{summary}
+	if issue := a.readinessBlocked(pod, ingress); issue != nil {
+		issues = append(issues, *issue)
+	}
+	if issue := a.dnsBlocked(ctx, client, pod, egress); issue != nil {
+		issues = append(issues, *issue)
+	}
+
+	for i := range issues {
+		for _, p := range selecting {
+			issues[i] = issues[i].WithObject(objectRef("NetworkPolicy", p))
+		}
+	}
+	return issues, nil
+}
+
+// selectingPolicies returns the policies whose pod selector matches the pod
+func selectingPolicies(pod *corev1.Pod, policies []networkingv1.NetworkPolicy) []*networkingv1.NetworkPolicy {
+	var selecting []*networkingv1.NetworkPolicy
+	for i := range policies {
+		selector, err := metav1.LabelSelectorAsSelector(&policies[i].Spec.PodSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			selecting = append(selecting, &policies[i])
+		}
+	}
+	return selecting
+}
+
+// policyTypes returns the directions a policy isolates. Without policyTypes,
+// a policy isolates ingress, and egress too if it has egress rules.
+func policyTypes(p *networkingv1.NetworkPolicy) []networkingv1.PolicyType {
+	if len(p.Spec.PolicyTypes) > 0 {
+		return p.Spec.PolicyTypes
+	}
+	types := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	if len(p.Spec.Egress) > 0 {
+		types = append(types, networkingv1.PolicyTypeEgress)
+	}
+	return types
+}
+
+// describeIngress summarizes the ingress a pod's policies allow
+func describeIngress(iso policyIsolation) string {
+	var ports [][]networkingv1.NetworkPolicyPort
+	for _, rule := range iso.ingress {
+		ports = append(ports, rule.Ports)
+	}
+	return describeIsolation(iso.isolated, ports)
+}
+
+// describeEgress summarizes the egress a pod's policies allow
+func describeEgress(iso policyIsolation) string {
+	var ports [][]networkingv1.NetworkPolicyPort
+	for _, rule := range iso.egress {
+		ports = append(ports, rule.Ports)
+	}
+	return describeIsolation(iso.isolated, ports)
+}
+
+// describeIsolation renders one direction: not isolated, default-deny when
+// no rule allows anything, or the ports the rules allow
+func describeIsolation(isolated bool, rulePorts [][]networkingv1.NetworkPolicyPort) string {
+	if !isolated {
+		return "not isolated, all traffic allowed"
+	}
+	if len(rulePorts) == 0 {
+		return "default-deny, no traffic allowed"
+	}
+	var allowed []string
+	for _, ports := range rulePorts {
+		if len(ports) == 0 {
+			return "isolated, all ports allowed from the listed peers"
+		}
+		for _, pp := range ports {
+			if s := formatPolicyPort(pp); !containsString(allowed, s) {
+				allowed = append(allowed, s)
+			}
+		}
+	}
+	return "isolated, allowed ports " + strings.Join(allowed, ", ")
+}
+
+// formatPolicyPort renders a policy port as e.g. TCP 8080, UDP 53,
+// TCP 8000-8100 or TCP http
+func formatPolicyPort(pp networkingv1.NetworkPolicyPort) string {
+	protocol := policyProtocol(pp)
+	switch {
+	case pp.Port == nil:
+		return string(protocol) + " all"
+	case pp.EndPort != nil:
+		return fmt.Sprintf("%s %s-%d", protocol, pp.Port.String(), *pp.EndPort)
+	}
+	return fmt.Sprintf("%s %s", protocol, pp.Port.String())
+}
+
+// policyProtocol returns a policy port's protocol, TCP by default
+func policyProtocol(pp networkingv1.NetworkPolicyPort) corev1.Protocol {
+	if pp.Protocol != nil {
+		return *pp.Protocol
+	}
+	return corev1.ProtocolTCP
+}
+
+// portsAllow reports whether the ports of a rule allow a port and protocol
+// of the destination pod, whose containers resolve named ports. A rule
+// without ports allows all of them.
+func portsAllow(ports []networkingv1.NetworkPolicyPort, port int32, protocol corev1.Protocol, dest []corev1.Container) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, pp := range ports {
+		if policyProtocol(pp) != protocol {
+			continue
+		}
+		switch {
+		case pp.Port == nil:
+			return true
+		case pp.Port.Type == intstr.String:
+			if namedContainerPort(dest, pp.Port.StrVal, protocol) == port {
+				return true
+			}
+		case pp.EndPort != nil:
+			if port >= pp.Port.IntVal && port <= *pp.EndPort {
+				return true
+			}
+		case pp.Port.IntVal == port:
+			return true
+		}
+	}
+	return false
+}
+
+// namedContainerPort returns the number of a named container port, or 0
+func namedContainerPort(containers []corev1.Container, name string, protocol corev1.Protocol) int32 {
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			portProtocol := p.Protocol
+			if portProtocol == "" {
+				portProtocol = corev1.ProtocolTCP
+			}
+			if p.Name == name && portProtocol == protocol {
+				return p.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+// readinessBlocked reports a not-ready pod whose readiness probe port no
+// ingress rule allows from the node. The API exempts traffic from the node
+// the pod runs on, so the kubelet's probes pass, but not every CNI plugin
+// implements that exemption, and load balancer or mesh health checks coming
+// from elsewhere are blocked either way.
+func (a *NetworkPolicyAnalyzer) readinessBlocked(pod *corev1.Pod, ingress policyIsolation) *domain.Issue {
+	if !ingress.isolated || isPodReady(pod) {
+		return nil
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.ReadinessProbe == nil {
+			continue
+		}
+		target, _, _ := probeTarget(c.ReadinessProbe)
+		port := target.IntVal
+		if target.Type == intstr.String {
+			port = namedContainerPort([]corev1.Container{c}, target.StrVal, corev1.ProtocolTCP)
+		}
+		if port == 0 {
+			continue
+		}
+
+		allowed := false
+		for _, rule := range ingress.ingress {
+			if portsAllow(rule.Ports, port, corev1.ProtocolTCP, pod.Spec.Containers) && peersMayIncludeNode(rule.From) {
+				allowed = true
+				break
+			}
+		}
+		if allowed {
+			continue
+		}
+
+		issue := domain.NewIssue(
+			domain.SeverityWarning,
+			"network",
+			fmt.Sprintf("Readiness probe port %d of %s blocked by NetworkPolicy", port, c.Name),
+			fmt.Sprintf("The pod is not ready, and no ingress rule of the NetworkPolicies selecting it allows TCP %d from the node. "+
+				"Kubernetes lets the kubelet's probes through regardless, but CNI plugins that do not will fail the probe, "+
+				"and health checks from load balancers or other nodes are blocked", port),
+		).WithDetail("container", c.Name).
+			WithDetail("port", fmt.Sprintf("%d", port))
+		return &issue
+	}
+	return nil
+}
+
+// peersMayIncludeNode reports whether a rule's peers may include the node:
+// all sources, or an IP block that could cover the node's address
+func peersMayIncludeNode(peers []networkingv1.NetworkPolicyPeer) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// dnsBlocked reports a pod using cluster DNS whose egress rules allow no
+// UDP 53 to the kube-dns pods. IP blocks are taken to allow it, as the
+// addresses of the DNS pods are not compared.
+func (a *NetworkPolicyAnalyzer) dnsBlocked(ctx context.Context, client *kubernetes.Client, pod *corev1.Pod, egress policyIsolation) *domain.Issue {
+	if !egress.isolated || pod.Spec.DNSPolicy == corev1.DNSDefault || pod.Spec.DNSPolicy == corev1.DNSNone {
+		return nil
+	}
+
+	// Without access to kube-system, assume the labels of a standard install
+	nsLabels := labels.Set{"kubernetes.io/metadata.name": dnsNamespace}
+	if ns, err := client.GetNamespace(ctx, dnsNamespace); err == nil {
+		nsLabels = labels.Set(ns.Labels)
+	}
+	podLabels := labels.Set{"k8s-app": "kube-dns"}
+	dnsContainers := []corev1.Container{{Ports: []corev1.ContainerPort{
+		{Name: "dns", ContainerPort: dnsPort, Protocol: corev1.ProtocolUDP},
+		{Name: "dns-tcp", ContainerPort: dnsPort, Protocol: corev1.ProtocolTCP},
+	}}}
+	if list, err := client.ListPods(ctx, dnsNamespace, dnsSelector); err == nil && len(list.Items) > 0 {
+		podLabels = labels.Set(list.Items[0].Labels)
+		dnsContainers = list.Items[0].Spec.Containers
+	}
+
+	for _, rule := range egress.egress {
+		if !portsAllow(rule.Ports, dnsPort, corev1.ProtocolUDP, dnsContainers) {
+			continue
+		}
+		if peersMayIncludeDNS(rule.To, pod.Namespace, nsLabels, podLabels) {
+			return nil
+		}
+	}
+
+	issue := domain.NewIssue(
+		domain.SeverityWarning,
+		"network",
+		"Egress to kube-dns blocked by NetworkPolicy",
+		fmt.Sprintf("The NetworkPolicies selecting the pod isolate its egress, and no rule allows UDP %d to the kube-dns pods in %s, "+
+			"so every name lookup times out. Allow UDP and TCP %d to them", dnsPort, dnsNamespace, dnsPort),
+	).WithDetail("dns_namespace", dnsNamespace).
+		WithDetail("dns_selector", dnsSelector)
+	return &issue
+}
+
+// peersMayIncludeDNS reports whether a rule's peers include the DNS pods:
+// all destinations, an IP block, or a selector matching their namespace and
+// pod labels. A peer without a namespace selector only selects pods of the
+// policy's own namespace.
+func peersMayIncludeDNS(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, nsLabels, podLabels labels.Set) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			return true
+		}
+		if peer.NamespaceSelector == nil {
+			if policyNamespace == dnsNamespace && selectorMatches(peer.PodSelector, podLabels) {
+				return true
+			}
+			continue
+		}
+		if selectorMatches(peer.NamespaceSelector, nsLabels) && selectorMatches(peer.PodSelector, podLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorMatches reports whether a peer's label selector matches a set of
+// labels. A missing selector matches everything.
+func selectorMatches(selector *metav1.LabelSelector, set labels.Set) bool {
+	if selector == nil {
+		return true
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(set)
+}
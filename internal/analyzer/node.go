@@ -10,11 +10,13 @@ import (
 )
 
 // NodeAnalyzer analyzes the node where the pod is running
-type NodeAnalyzer struct{}
+type NodeAnalyzer struct {
+	drains *drainCache
+}
 
 // NewNodeAnalyzer creates a new NodeAnalyzer
 func NewNodeAnalyzer() *NodeAnalyzer {
-	return &NodeAnalyzer{}
+	return &NodeAnalyzer{drains: &drainCache{nodes: make(map[string]*drainEntry)}}
 }
 
 // Name returns the analyzer name
@@ -36,8 +38,25 @@ func (n *NodeAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kub
 		return nil, err
 	}
 
-	// Check if node is not ready
+	// Check if node is not ready. A node replaced by a managed upgrade goes
+	// down on purpose, so that is routine maintenance rather than an outage.
+	upgrade := ""
 	if !nodeHealth.Ready {
+		upgrade, _ = client.NodeUpgrade(ctx, nodeHealth.Name)
+	}
+	switch {
+	case upgrade != "":
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityInfo,
+			Category:    "node",
+			Title:       fmt.Sprintf("Node %s is not ready: node upgrade in progress", nodeHealth.Name),
+			Description: fmt.Sprintf("The node is being replaced by a %s; its pods are rescheduled on other nodes", upgrade),
+			Details: map[string]string{
+				"node":         nodeHealth.Name,
+				"node_upgrade": upgrade,
+			},
+		})
+	case !nodeHealth.Ready:
 		issues = append(issues, domain.Issue{
 			Severity:    domain.SeverityCritical,
 			Category:    "node",
@@ -105,5 +124,18 @@ func (n *NodeAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kub
 		})
 	}
 
+	// Check whether the node can be drained safely before recommending it,
+	// unless its upgrade is draining it already
+	if upgrade == "" {
+		addDrainDetails(ctx, client, n.drains, nodeHealth.Name, issues)
+	}
+
+	// Under memory pressure, tell the pod where it stands in line for eviction
+	if nodeHealth.MemoryPressure {
+		if issue := evictionRankIssue(ctx, client, pod); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
 	return issues, nil
 }
@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workloadKinds maps the kinds in kubectl resource references, e.g.
+// deployment/web, to API kinds
+var workloadKinds = map[string]string{
+	"deployment":  "Deployment",
+	"statefulset": "StatefulSet",
+	"daemonset":   "DaemonSet",
+	"replicaset":  "ReplicaSet",
+	"job":         "Job",
+	"cronjob":     "CronJob",
+}
+
+// sourceKinds maps the prefixes of volume sources, e.g. pvc/data, to kinds
+var sourceKinds = map[string]string{
+	"pvc":       "PersistentVolumeClaim",
+	"configmap": "ConfigMap",
+	"secret":    "Secret",
+}
+
+// objectRef references an object an analyzer fetched, with its UID
+func objectRef(kind string, obj metav1.Object) domain.ObjectRef {
+	return domain.ObjectRef{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		UID:       string(obj.GetUID()),
+	}
+}
+
+// withObject adds an involved object to each issue
+func withObject(issues []domain.Issue, ref domain.ObjectRef) []domain.Issue {
+	for i := range issues {
+		issues[i] = issues[i].WithObject(ref)
+	}
+	return issues
+}
+
+// linkObjects adds the objects each issue of a diagnosis involves: the pod,
+// or its container as a field of it, and every object its details name
+// (node, claim, storage class, service, secret, workload, ...). Analyzers
+// that fetched an object add it with its UID; the rest get the UIDs the
+// diagnosis knows, those of the pod and its node.
+func linkObjects(diagnosis *domain.Diagnosis) {
+	pod := diagnosis.Pod
+	namespaced := func(kind, name string) domain.ObjectRef {
+		return domain.ObjectRef{Kind: kind, Namespace: pod.Namespace, Name: name}
+	}
+
+	for i, issue := range diagnosis.Issues {
+		details := issue.Details
+		var refs []domain.ObjectRef
+
+		podRef := domain.ObjectRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID}
+		if container := details["container"]; container != "" {
+			field := "spec.containers"
+			if details["type"] == "init" {
+				field = "spec.initContainers"
+			}
+			podRef.FieldPath = field + "{" + container + "}"
+		}
+		refs = append(refs, podRef)
+
+		if node := details["node"]; node != "" {
+			ref := domain.ObjectRef{Kind: "Node", Name: node}
+			if diagnosis.Node != nil && diagnosis.Node.Name == node {
+				ref.UID = diagnosis.Node.UID
+			}
+			refs = append(refs, ref)
+		}
+		if claim := details["claim"]; claim != "" {
+			refs = append(refs, namespaced("PersistentVolumeClaim", claim))
+		}
+		if class := details["storage_class"]; class != "" {
+			refs = append(refs, domain.ObjectRef{Kind: "StorageClass", Name: class})
+		}
+		if kind, name, ok := strings.Cut(details["source"], "/"); ok && sourceKinds[kind] != "" {
+			refs = append(refs, namespaced(sourceKinds[kind], name))
+		}
+		if service := details["service"]; service != "" {
+			refs = append(refs, namespaced("Service", service))
+		}
+		if secret := details["secret"]; secret != "" {
+			refs = append(refs, namespaced("Secret", secret))
+		}
+		for _, secret := range strings.Split(details["image_pull_secrets"], ",") {
+			if secret = strings.TrimSpace(secret); secret != "" {
+				refs = append(refs, namespaced("Secret", secret))
+			}
+		}
+		if sts := details["statefulset"]; sts != "" {
+			refs = append(refs, namespaced("StatefulSet", sts))
+		}
+		if kind, name, ok := strings.Cut(details["workload"], "/"); ok && workloadKinds[kind] != "" {
+			refs = append(refs, namespaced(workloadKinds[kind], name))
+		}
+		if job := details["job"]; job != "" {
+			refs = append(refs, namespaced("Job", job))
+		}
+		if kind, name := details["owner_kind"], details["owner_name"]; kind != "" && name != "" {
+			refs = append(refs, namespaced(kind, name))
+		}
+		if vpa := details["vpa"]; vpa != "" {
+			refs = append(refs, namespaced("VerticalPodAutoscaler", vpa))
+		}
+
+		// The pod goes first, then what the analyzer added, then the rest
+		linked := domain.Issue{Objects: refs[:1]}
+		for _, ref := range issue.Objects {
+			linked = linked.WithObject(ref)
+		}
+		for _, ref := range refs[1:] {
+			linked = linked.WithObject(ref)
+		}
+		diagnosis.Issues[i].Objects = linked.Objects
+	}
+}
@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// oomLimitFactor is how much to raise the memory limit of a container
+	// that is OOMKilled
+	oomLimitFactor = 1.5
+	// recurringOOMs is how many OOMKills make a recurring pattern rather
+	// than a one-off spike
+	recurringOOMs = 2
+)
+
+// oomTrend summarizes how often a container is OOMKilled
+type oomTrend struct {
+	kills     int           // OOMKills known from events, or restarts if none were recorded
+	interval  time.Duration // average time between OOMKills, 0 if unknown
+	uptime    time.Duration // how long the container ran before its last OOMKill
+	estimated bool          // kills and interval are inferred from restarts
+}
+
+// recurring reports whether the container keeps running out of memory
+func (t oomTrend) recurring() bool {
+	return t.kills >= recurringOOMs
+}
+
+// addOOMTrends tells one-off OOMKills from recurring ones. The container
+// status only keeps the last termination, so the pod's OOM events are
+// counted, falling back to the restart count of a container whose last
+// restart was an OOMKill. Each OOMKilled issue gets the pattern, the time
+// between kills and a concrete memory limit to set, based on live usage
+// when metrics-server is available.
+func addOOMTrends(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client, issues []domain.Issue) {
+	var events []domain.EventInfo
+	usage := make(map[string]*resource.Quantity)
+	fetched := false
+	for i := range issues {
+		issue := &issues[i]
+		if !containsReason(*issue, "OOMKilled") || issue.Details["container"] == "" {
+			continue
+		}
+		if !fetched {
+			// Without events the trend is estimated from restarts alone,
+			// and without metrics the limit from the current one
+			events, _ = client.GetPodEvents(ctx, pod.Namespace, pod.Name)
+			if metrics, err := client.GetPodMetrics(ctx, pod.Namespace, pod.Name); err == nil {
+				for _, c := range metrics.Containers {
+					usage[c.Name] = c.Usage.Memory()
+				}
+			}
+			fetched = true
+		}
+
+		name := issue.Details["container"]
+		cs, spec := containerStatus(pod, name), containerSpec(pod, name)
+		if cs == nil || spec == nil {
+			continue
+		}
+		trend := containerOOMTrend(*cs, pod, events)
+		issue.Details["oom_pattern"] = "one-off"
+		if trend.recurring() {
+			issue.Details["oom_pattern"] = "recurring"
+		}
+		issue.Details["oom_kills"] = fmt.Sprintf("%d", trend.kills)
+		if trend.interval > 0 {
+			issue.Details["oom_interval"] = trend.interval.Round(time.Second).String()
+		}
+		if trend.uptime > 0 {
+			issue.Details["uptime_before_oom"] = trend.uptime.Round(time.Second).String()
+		}
+		if trend.estimated {
+			issue.Details["oom_estimated"] = "true"
+		}
+		issue.Description = trend.describe()
+
+		if limit := spec.Resources.Limits.Memory(); !limit.IsZero() {
+			issue.Details["memory_limit"] = formatMemory(limit)
+			issue.Details["suggested_memory_limit"] = formatMemory(suggestMemoryLimit(limit, usage[name]))
+			if u := usage[name]; u != nil {
+				issue.Details["memory_usage"] = formatMemory(u)
+			}
+			if request := spec.Resources.Requests.Memory(); request.Cmp(*limit) == 0 {
+				// Keep Guaranteed QoS by raising the request with the limit
+				issue.Details["memory_request_equals_limit"] = "true"
+			}
+		}
+	}
+}
+
+// describe explains the trend in an issue description
+func (t oomTrend) describe() string {
+	var b strings.Builder
+	b.WriteString("Container exceeded its memory limit and was killed")
+	switch {
+	case !t.recurring():
+		b.WriteString(" once; a one-off spike rather than a steady leak")
+	case t.estimated:
+		fmt.Fprintf(&b, " on its last restart and has restarted %d times", t.kills)
+	default:
+		fmt.Fprintf(&b, " %d times", t.kills)
+	}
+	if t.recurring() && t.interval > 0 {
+		fmt.Fprintf(&b, ", about every %s", t.interval.Round(time.Second))
+	}
+	if t.uptime > 0 {
+		fmt.Fprintf(&b, "; it ran %s before the last kill", t.uptime.Round(time.Second))
+	}
+	return b.String()
+}
+
+// containerOOMTrend works out how often a container is OOMKilled from the
+// pod's OOM events, or from its restarts if there are none
+func containerOOMTrend(cs corev1.ContainerStatus, pod *corev1.Pod, events []domain.EventInfo) oomTrend {
+	var trend oomTrend
+	if last := cs.LastTerminationState.Terminated; last != nil && !last.StartedAt.IsZero() {
+		trend.uptime = last.FinishedAt.Sub(last.StartedAt.Time)
+	}
+
+	var first, last time.Time
+	for _, e := range events {
+		if !isOOMEvent(e, cs.Name) {
+			continue
+		}
+		count := int(e.Count)
+		if count < 1 {
+			count = 1
+		}
+		trend.kills += count
+		if start := eventStart(e); first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if last.IsZero() || e.LastSeen.After(last) {
+			last = e.LastSeen
+		}
+	}
+	if trend.kills > 1 && last.After(first) {
+		trend.interval = last.Sub(first) / time.Duration(trend.kills-1)
+	}
+	if trend.kills > 0 {
+		return trend
+	}
+
+	// No OOM events: assume the restarts were OOMKills like the last one
+	trend.estimated = true
+	trend.kills = int(cs.RestartCount)
+	if trend.kills < 1 {
+		trend.kills = 1
+	}
+	if start := pod.Status.StartTime; start != nil && cs.RestartCount > 1 && cs.LastTerminationState.Terminated != nil {
+		span := cs.LastTerminationState.Terminated.FinishedAt.Sub(start.Time)
+		if span > 0 {
+			trend.interval = span / time.Duration(cs.RestartCount)
+		}
+	}
+	return trend
+}
+
+// isOOMEvent reports whether an event records an OOMKill of a container
+func isOOMEvent(e domain.EventInfo, container string) bool {
+	if !strings.Contains(e.Reason, "OOM") && !strings.Contains(e.Message, "OOMKilled") {
+		return false
+	}
+	// Events without a container field path apply to every container
+	return e.FieldPath == "" || strings.Contains(e.FieldPath, "{"+container+"}")
+}
+
+// suggestMemoryLimit returns the memory limit to set for an OOMKilled
+// container: the current limit raised by oomLimitFactor, or more if live
+// usage already needs it, rounded up to a whole MiB
+func suggestMemoryLimit(limit, usage *resource.Quantity) *resource.Quantity {
+	bytes := float64(limit.Value()) * oomLimitFactor
+	if usage != nil {
+		if fromUsage := float64(usage.Value()) * oomLimitFactor; fromUsage > bytes {
+			bytes = fromUsage
+		}
+	}
+	const mi = 1024 * 1024
+	mebibytes := int64(bytes+mi-1) / mi
+	return resource.NewQuantity(mebibytes*mi, resource.BinarySI)
+}
+
+// containerStatus returns the status of a container of the pod, or nil
+func containerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == name {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// containerSpec returns the spec of a container of the pod, or nil
+func containerSpec(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// oomRecommendation recommends a concrete memory limit for an OOMKilled
+// container, more urgently when it is OOMKilled again and again
+func oomRecommendation(issue domain.Issue, target, namespace, container string) domain.Recommendation {
+	rec := domain.Recommendation{
+		Priority:    1,
+		Title:       "Increase memory limit",
+		Description: "Container exceeded memory limit; consider increasing it",
+		Command:     "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(namespace) + " -c " + domain.ShellArg(container) + " --limits=memory=<new-limit>",
+	}
+
+	suggested := issue.Details["suggested_memory_limit"]
+	if suggested == "" {
+		return rec
+	}
+	resources := "--limits=memory=" + domain.ShellArg(suggested)
+	if issue.Details["memory_request_equals_limit"] == "true" {
+		resources += " --requests=memory=" + domain.ShellArg(suggested)
+	}
+	rec.Command = "kubectl set resources " + domain.ShellArg(target) + " -n " + domain.ShellArg(namespace) + " -c " + domain.ShellArg(container) + " " + resources
+
+	basis := fmt.Sprintf("%s × %.1f", issue.Details["memory_limit"], oomLimitFactor)
+	if usage := issue.Details["memory_usage"]; usage != "" {
+		basis += ", or current usage " + usage + " × " + fmt.Sprintf("%.1f", oomLimitFactor) + " if higher"
+	}
+	if issue.Details["oom_pattern"] == "recurring" {
+		rec.Description = fmt.Sprintf("Container is OOMKilled repeatedly; raise the limit from %s to %s (%s). If it still runs out of memory at the new limit, look for a leak instead of raising it again",
+			issue.Details["memory_limit"], suggested, basis)
+		return rec
+	}
+	rec.Priority = 2
+	rec.Description = fmt.Sprintf("A single OOMKill may be a load spike; raise the limit from %s to %s (%s) for headroom, or find what allocated the memory",
+		issue.Details["memory_limit"], suggested, basis)
+	return rec
+}
@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PluginPrefix is the file name prefix of analyzer plugins on PATH. A
+// plugin named pod-doctor-analyzer-cost runs as the analyzer "cost".
+const PluginPrefix = "pod-doctor-analyzer-"
+
+// maxPluginStderr bounds the plugin error output quoted in an issue
+const maxPluginStderr = 500
+
+// PluginInput is the JSON document a plugin reads on stdin
+type PluginInput struct {
+	APIVersion string             `json:"apiVersion"`
+	Pod        *corev1.Pod        `json:"pod"`
+	Events     []domain.EventInfo `json:"events"`
+	Node       *corev1.Node       `json:"node,omitempty"` // the node the pod runs on, if scheduled
+}
+
+// PluginOutput is the JSON document a plugin writes on stdout
+type PluginOutput struct {
+	Issues []domain.Issue `json:"issues"`
+}
+
+// Plugin is an analyzer plugin executable
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// DiscoverPlugins finds executables named pod-doctor-analyzer-<name> on
+// PATH, sorted by name. When several directories hold the same plugin, the
+// one found first on PATH wins, as with shell lookups.
+func DiscoverPlugins() []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), PluginPrefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, ".exe")
+			}
+			if !ok || name == "" || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// isExecutable reports whether path is a regular file the user may run
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// PluginAnalyzer runs an external plugin as an analyzer. The plugin gets
+// the pod, its events and its node as JSON on stdin and prints the issues
+// it found as JSON on stdout.
+type PluginAnalyzer struct {
+	plugin  Plugin
+	timeout time.Duration
+}
+
+// NewPluginAnalyzer creates an analyzer running the plugin, killing it if
+// it does not finish within timeout
+func NewPluginAnalyzer(plugin Plugin, timeout time.Duration) *PluginAnalyzer {
+	return &PluginAnalyzer{plugin: plugin, timeout: timeout}
+}
+
+// Name returns the plugin name
+func (p *PluginAnalyzer) Name() string {
+	return p.plugin.Name
+}
+
+// Analyze runs the plugin for the pod. A plugin that fails or prints
+// invalid output is reported as an issue so its author can see why.
+func (p *PluginAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	input := PluginInput{
+		APIVersion: domain.APIVersion,
+		Pod:        pod,
+		Events:     []domain.EventInfo{},
+	}
+	if events, err := client.GetPodEvents(ctx, pod.Namespace, pod.Name); err == nil {
+		input.Events = events
+	}
+	if pod.Spec.NodeName != "" {
+		if node, err := client.GetNode(ctx, pod.Spec.NodeName); err == nil {
+			input.Node = node
+		}
+	}
+
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin input: %w", err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(runCtx, p.plugin.Path)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if runCtx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", p.timeout)
+		}
+		return []domain.Issue{p.failure(err, stderr.String())}, nil
+	}
+
+	var output PluginOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return []domain.Issue{p.failure(fmt.Errorf("invalid output: %w", err), stderr.String())}, nil
+	}
+
+	issues := make([]domain.Issue, 0, len(output.Issues))
+	for i, issue := range output.Issues {
+		switch issue.Severity {
+		case domain.SeverityCritical, domain.SeverityWarning, domain.SeverityInfo:
+		default:
+			return []domain.Issue{p.failure(fmt.Errorf("issue %d: invalid severity %q (use critical, warning or info)", i+1, issue.Severity), "")}, nil
+		}
+		if issue.Title == "" {
+			return []domain.Issue{p.failure(fmt.Errorf("issue %d: title is required", i+1), "")}, nil
+		}
+		if issue.Category == "" {
+			issue.Category = "plugin"
+		}
+		issue = issue.WithDetail("plugin", p.plugin.Name)
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// failure describes a plugin run that produced no usable result
+func (p *PluginAnalyzer) failure(err error, stderr string) domain.Issue {
+	description := err.Error()
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		if len(stderr) > maxPluginStderr {
+			stderr = "..." + stderr[len(stderr)-maxPluginStderr:]
+		}
+		description += ": " + stderr
+	}
+	return domain.Issue{
+		Severity:    domain.SeverityInfo,
+		Category:    "plugin",
+		Title:       fmt.Sprintf("Analyzer plugin %s failed", p.plugin.Name),
+		Description: description,
+		Details: map[string]string{
+			"plugin": p.plugin.Name,
+			"path":   p.plugin.Path,
+		},
+	}
+}
@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// preemptionLookback bounds how far back preemptions of sibling pods are
+// attributed to the current pod's workload
+const preemptionLookback = time.Hour
+
+// preemptedPattern extracts the preemptor and node from a Preempted event,
+// e.g. "Preempted by pod 8d3c...-uid on node worker-1". Some scheduler
+// versions omit the preemptor and say "Preempted by a pod on node worker-1".
+var preemptedPattern = regexp.MustCompile(`Preempted by (?:pod )?(\S+)(?: pod)? on node (\S+)`)
+
+// PreemptionAnalyzer attributes disruptions to scheduler preemption by
+// higher-priority pods
+type PreemptionAnalyzer struct{}
+
+// NewPreemptionAnalyzer creates a new PreemptionAnalyzer
+func NewPreemptionAnalyzer() *PreemptionAnalyzer {
+	return &PreemptionAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (a *PreemptionAnalyzer) Name() string {
+	return "preemption"
+}
+
+// preemption describes one pod being displaced by the scheduler
+type preemption struct {
+	victim  string
+	node    string
+	message string
+	seen    time.Time
+}
+
+// Analyze reports preemption of the pod, or of earlier pods of the same
+// workload that it replaced
+func (a *PreemptionAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var found []preemption
+
+	events, err := client.GetPodEvents(ctx, pod.Namespace, pod.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if e.Reason == "Preempted" {
+			found = append(found, preemption{victim: pod.Name, node: pod.Spec.NodeName, message: e.Message, seen: e.LastSeen})
+		}
+	}
+
+	// The scheduler marks victims with a DisruptionTarget condition before
+	// the event is recorded
+	if len(found) == 0 {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.DisruptionTarget && c.Status == corev1.ConditionTrue && c.Reason == corev1.PodReasonPreemptionByScheduler {
+				found = append(found, preemption{victim: pod.Name, node: pod.Spec.NodeName, message: c.Message, seen: c.LastTransitionTime.Time})
+			}
+		}
+	}
+
+	// A replacement pod looks like a sudden restart; check whether its
+	// predecessors from the same controller were preempted
+	if len(found) == 0 && pod.GenerateName != "" {
+		found = a.siblingPreemptions(ctx, pod, client)
+	}
+
+	var issues []domain.Issue
+	for _, p := range found {
+		issues = append(issues, a.preemptionIssue(ctx, pod, p, client))
+	}
+
+	return issues, nil
+}
+
+// siblingPreemptions finds recent Preempted events for other pods created by
+// the same controller
+func (a *PreemptionAnalyzer) siblingPreemptions(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) []preemption {
+	events, err := client.ListNamespaceEvents(ctx, pod.Namespace)
+	if err != nil {
+		return nil
+	}
+
+	var found []preemption
+	for _, e := range events {
+		obj := e.InvolvedObject
+		if e.Reason != "Preempted" || obj.Kind != "Pod" || obj.Name == pod.Name {
+			continue
+		}
+		if !strings.HasPrefix(obj.Name, pod.GenerateName) || time.Since(e.LastTimestamp.Time) > preemptionLookback {
+			continue
+		}
+		found = append(found, preemption{victim: obj.Name, message: e.Message, seen: e.LastTimestamp.Time})
+	}
+	return found
+}
+
+// preemptionIssue builds the issue for a preemption, naming the preemptor
+// when it can be identified
+func (a *PreemptionAnalyzer) preemptionIssue(ctx context.Context, pod *corev1.Pod, p preemption, client *kubernetes.Client) domain.Issue {
+	preemptorRef := ""
+	if m := preemptedPattern.FindStringSubmatch(p.message); m != nil {
+		preemptorRef = m[1]
+		if p.node == "" {
+			p.node = m[2]
+		}
+	}
+	if preemptorRef == "a" {
+		preemptorRef = ""
+	}
+
+	title := "Pod was preempted by a higher-priority pod"
+	description := "The scheduler evicted this pod to make room for a higher-priority pod; this is not an application failure"
+	if p.victim != pod.Name {
+		title = fmt.Sprintf("Previous pod %s was preempted", p.victim)
+		description = "This pod replaced one that the scheduler evicted for a higher-priority pod; the restart is not an application failure"
+	}
+
+	details := map[string]string{
+		"preempted_pod": p.victim,
+		"priority":      fmt.Sprintf("%d", podPriority(pod)),
+		"preempted_at":  p.seen.Format("2006-01-02 15:04:05"),
+	}
+	if p.node != "" {
+		details["node"] = p.node
+	}
+
+	if preemptor := a.findPreemptor(ctx, pod, p.node, preemptorRef, client); preemptor != nil {
+		details["preemptor"] = preemptor.Namespace + "/" + preemptor.Name
+		details["preemptor_priority"] = fmt.Sprintf("%d", podPriority(preemptor))
+		if preemptor.Spec.PriorityClassName != "" {
+			details["preemptor_priority_class"] = preemptor.Spec.PriorityClassName
+		}
+	} else if preemptorRef != "" {
+		details["preemptor"] = preemptorRef
+	}
+
+	return domain.Issue{
+		Severity:    domain.SeverityWarning,
+		Category:    "scheduling",
+		Title:       title,
+		Description: description,
+		Details:     details,
+	}
+}
+
+// findPreemptor looks up the preempting pod on the victim's node, by
+// namespace/name or UID when the event names it, otherwise by picking the
+// highest-priority pod that outranks the victim
+func (a *PreemptionAnalyzer) findPreemptor(ctx context.Context, pod *corev1.Pod, node, ref string, client *kubernetes.Client) *corev1.Pod {
+	if node == "" {
+		return nil
+	}
+	pods, err := client.ListPodsOnNode(ctx, node)
+	if err != nil {
+		return nil
+	}
+
+	var best *corev1.Pod
+	for i := range pods.Items {
+		candidate := &pods.Items[i]
+		if ref != "" {
+			if string(candidate.UID) == ref || candidate.Namespace+"/"+candidate.Name == ref || candidate.Name == ref {
+				return candidate
+			}
+			continue
+		}
+		if podPriority(candidate) <= podPriority(pod) {
+			continue
+		}
+		if best == nil || podPriority(candidate) > podPriority(best) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// podPriority returns the resolved scheduling priority of a pod
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
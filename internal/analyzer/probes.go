@@ -27,13 +27,15 @@ func (p *ProbeAnalyzer) Name() string {
 func (p *ProbeAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
 	var issues []domain.Issue
 
-	// Analyze container probe configurations
+	events, err := client.GetPodEvents(ctx, pod.Namespace, pod.Name)
+
+	// Analyze container probe configurations and what they point at
 	for _, container := range pod.Spec.Containers {
 		issues = append(issues, p.analyzeContainerProbes(container)...)
+		issues = append(issues, p.analyzeProbeSpecs(container, events)...)
 	}
 
 	// Check events for probe failures
-	events, err := client.GetPodEvents(ctx, pod.Namespace, pod.Name)
 	if err == nil {
 		issues = append(issues, p.analyzeProbeEvents(events)...)
 	}
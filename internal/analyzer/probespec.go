@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Probe configuration problems, set as the probe_config detail
+const (
+	probeConfigNamedPort  = "named_port"
+	probeConfigTLSOnPlain = "https_plain_http"
+	probeConfigHostHeader = "host_header"
+)
+
+// tlsMismatchMessages are probe errors seen when an HTTPS probe talks to a
+// port that serves plain HTTP. A reset connection is not one of them: apps
+// that crash or restart under the probe reset connections too.
+var tlsMismatchMessages = []string{
+	"server gave HTTP response to HTTPS client",
+	"first record does not look like a TLS handshake",
+}
+
+// probeStatusCode extracts the status code from an HTTP probe failure, e.g.
+// "HTTP probe failed with statuscode: 404"
+var probeStatusCode = regexp.MustCompile(`statuscode: (\d{3})`)
+
+// namedProbe is a container probe with the name the kubelet uses for it in
+// events
+type namedProbe struct {
+	kind  string // Liveness, Readiness, Startup
+	probe *corev1.Probe
+}
+
+// containerProbes returns the probes configured on a container
+func containerProbes(c corev1.Container) []namedProbe {
+	var probes []namedProbe
+	for _, p := range []namedProbe{
+		{"Liveness", c.LivenessProbe},
+		{"Readiness", c.ReadinessProbe},
+		{"Startup", c.StartupProbe},
+	} {
+		if p.probe != nil {
+			probes = append(probes, p)
+		}
+	}
+	return probes
+}
+
+// analyzeProbeSpecs validates what a container's probes point at: named
+// ports that the container does not declare, HTTPS probes answered by plain
+// HTTP, and Host header overrides the app rejects. The last two are only
+// visible in the kubelet's probe failure events.
+func (p *ProbeAnalyzer) analyzeProbeSpecs(container corev1.Container, events []domain.EventInfo) []domain.Issue {
+	var issues []domain.Issue
+
+	for _, np := range containerProbes(container) {
+		kind := strings.ToLower(np.kind)
+		port, scheme, headers := probeTarget(np.probe)
+		failures := probeFailureMessages(events, container.Name, np.kind)
+
+		if port.Type == intstr.String && !hasContainerPort(container, port.StrVal) {
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "probes",
+				Title:       fmt.Sprintf("%s probe port %s not found in %s", np.kind, port.StrVal, container.Name),
+				Description: fmt.Sprintf("The %s probe targets the named port %q, but the container declares no port with that name, so the probe can never succeed", kind, port.StrVal),
+				Details: map[string]string{
+					"container":      container.Name,
+					"probe":          kind,
+					"port":           port.StrVal,
+					"declared_ports": strings.Join(containerPortNames(container), ","),
+					"probe_config":   probeConfigNamedPort,
+				},
+			})
+		}
+
+		if scheme == corev1.URISchemeHTTPS {
+			if message := firstMatching(failures, tlsMismatchMessages); message != "" {
+				issues = append(issues, domain.Issue{
+					Severity:    domain.SeverityCritical,
+					Category:    "probes",
+					Title:       fmt.Sprintf("HTTPS %s probe hits plain HTTP port on %s", kind, container.Name),
+					Description: fmt.Sprintf("The %s probe uses scheme HTTPS, but port %s answers with plain HTTP: %s", kind, port.String(), message),
+					Details: map[string]string{
+						"container":    container.Name,
+						"probe":        kind,
+						"port":         port.String(),
+						"scheme":       string(scheme),
+						"probe_config": probeConfigTLSOnPlain,
+					},
+				})
+			}
+		}
+
+		for _, h := range headers {
+			if !strings.EqualFold(h.Name, "Host") {
+				continue
+			}
+			code := rejectedHostStatus(failures)
+			if code == "" {
+				continue
+			}
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityWarning,
+				Category:    "probes",
+				Title:       fmt.Sprintf("%s probe Host header rejected by %s", np.kind, container.Name),
+				Description: fmt.Sprintf("The %s probe overrides the Host header with %q and the app answers %s; it likely only serves other virtual hosts", kind, h.Value, code),
+				Details: map[string]string{
+					"container":    container.Name,
+					"probe":        kind,
+					"host_header":  h.Value,
+					"status_code":  code,
+					"probe_config": probeConfigHostHeader,
+				},
+			})
+		}
+	}
+
+	return issues
+}
+
+// probeTarget returns the port, HTTP scheme and HTTP headers of a probe.
+// Exec probes have no port.
+func probeTarget(probe *corev1.Probe) (intstr.IntOrString, corev1.URIScheme, []corev1.HTTPHeader) {
+	switch {
+	case probe.HTTPGet != nil:
+		return probe.HTTPGet.Port, probe.HTTPGet.Scheme, probe.HTTPGet.HTTPHeaders
+	case probe.TCPSocket != nil:
+		return probe.TCPSocket.Port, "", nil
+	case probe.GRPC != nil:
+		return intstr.FromInt32(probe.GRPC.Port), "", nil
+	}
+	return intstr.IntOrString{}, "", nil
+}
+
+// probeFailureMessages returns the messages of a container's failure events
+// for one probe kind
+func probeFailureMessages(events []domain.EventInfo, container, kind string) []string {
+	var messages []string
+	for _, e := range events {
+		if e.Type != "Warning" || e.Reason != "Unhealthy" {
+			continue
+		}
+		if !strings.HasSuffix(e.FieldPath, "{"+container+"}") {
+			continue
+		}
+		if strings.HasPrefix(e.Message, kind+" probe failed") {
+			messages = append(messages, e.Message)
+		}
+	}
+	return messages
+}
+
+// rejectedHostStatus returns the status code of a probe failure that points
+// at a rejected virtual host, e.g. 404 Not Found, 421 Misdirected Request or
+// 400 Bad Request. The same codes come from a wrong path, so it is only
+// asked when the probe overrides the Host header.
+func rejectedHostStatus(messages []string) string {
+	for _, m := range messages {
+		match := probeStatusCode.FindStringSubmatch(m)
+		if match == nil {
+			continue
+		}
+		switch match[1] {
+		case "400", "403", "404", "421":
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// firstMatching returns the first message containing one of the needles
+func firstMatching(messages, needles []string) string {
+	for _, m := range messages {
+		for _, needle := range needles {
+			if strings.Contains(m, needle) {
+				return m
+			}
+		}
+	}
+	return ""
+}
+
+// hasContainerPort reports whether the container declares a port with the
+// given name
+func hasContainerPort(container corev1.Container, name string) bool {
+	return containsString(containerPortNames(container), name)
+}
+
+// containerPortNames returns the names of the container's declared ports
+func containerPortNames(container corev1.Container) []string {
+	var names []string
+	for _, port := range container.Ports {
+		if port.Name != "" {
+			names = append(names, port.Name)
+		}
+	}
+	return names
+}
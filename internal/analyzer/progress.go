@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Progress is a diagnosis in the making, reported as each of its sections
+// completes
+type Progress struct {
+	// Diagnosis holds the pod's status and the findings of the completed
+	// sections. It shares nothing with later snapshots, so the receiver
+	// may keep it and read it on another goroutine.
+	Diagnosis *domain.Diagnosis
+	// Pending names the sections still running, in diagnosis order
+	Pending []string
+}
+
+// section is one independent part of a diagnosis: an analyzer, or data
+// such as events or node health. run does the slow work and returns how to
+// add its result to a diagnosis. That may be applied to several diagnoses,
+// one per progress snapshot, so it copies what assembling them changes.
+type section struct {
+	name string
+	run  func(ctx context.Context) func(*domain.Diagnosis)
+}
+
+// DiagnoseWithProgress diagnoses a pod, running its sections concurrently,
+// each within the analyzer timeout, and records how long each took.
+// If progress is not nil, it is called once the pod's status is known and
+// again as each section completes, except the last, whose result is the
+// returned diagnosis. Sections are always assembled in the same order, so
+// the result does not depend on which one finished first.
+func (p *PodAnalyzer) DiagnoseWithProgress(ctx context.Context, namespace, name string, progress func(Progress)) (*domain.Diagnosis, error) {
+	pod, err := p.client.GetPod(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Analyzers and sections share one fetch of the pod's events and usage
+	sections := p.sections(pod, p.client.WithPodCache(ctx, namespace, name))
+	log := slog.With("pod", namespace+"/"+name)
+	type result struct {
+		index int
+		apply func(*domain.Diagnosis)
+	}
+	results := make(chan result)
+	for i, s := range sections {
+		go func() {
+			results <- result{index: i, apply: p.runSection(ctx, log, s)}
+		}()
+	}
+
+	applied := make([]func(*domain.Diagnosis), len(sections))
+	for remaining := len(sections); ; remaining-- {
+		if progress != nil && remaining > 0 {
+			var pending []string
+			for i, s := range sections {
+				if applied[i] == nil {
+					pending = append(pending, s.name)
+				}
+			}
+			progress(Progress{Diagnosis: p.assemble(pod, applied), Pending: pending})
+		}
+		if remaining == 0 {
+			break
+		}
+		r := <-results
+		applied[r.index] = r.apply
+	}
+
+	return p.assemble(pod, applied), nil
+}
+
+// runSection runs a section within the analyzer timeout and returns how to
+// add its result and timing to a diagnosis. A section that overruns the
+// timeout is abandoned, and the diagnosis notes that it is incomplete.
+// Each run is logged, its start at debug level.
+func (p *PodAnalyzer) runSection(ctx context.Context, log *slog.Logger, s section) func(*domain.Diagnosis) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	log = log.With("section", s.name)
+	log.Debug("section started")
+	start := time.Now()
+	done := make(chan func(*domain.Diagnosis), 1)
+	go func() {
+		done <- s.run(ctx)
+	}()
+
+	select {
+	case apply := <-done:
+		timing := domain.SectionTiming{Name: s.name, Duration: time.Since(start)}
+		log.Info("section finished", "duration", timing.Duration.Round(time.Millisecond))
+		return func(d *domain.Diagnosis) {
+			apply(d)
+			d.Timings = append(d.Timings, timing)
+		}
+	case <-ctx.Done():
+		// The section may ignore its context; do not wait for it
+		timing := domain.SectionTiming{Name: s.name, Duration: time.Since(start), TimedOut: true}
+		log.Warn("section timed out", "duration", timing.Duration.Round(time.Millisecond))
+		return func(d *domain.Diagnosis) {
+			d.AddWarning(domain.AnalyzerWarning{
+				Analyzer: s.name,
+				Message:  fmt.Sprintf("%s skipped: did not finish within %s", s.name, timing.Duration.Round(time.Millisecond)),
+			})
+			d.Timings = append(d.Timings, timing)
+		}
+	}
+}
+
+// sections returns the parts of a pod's diagnosis: every analyzer, then
+// events, the owning workload, resource usage, chaos experiments, shared
+// acknowledgements and node health. client is the one the sections use.
+func (p *PodAnalyzer) sections(pod *corev1.Pod, client *kubernetes.Client) []section {
+	sections := make([]section, 0, len(p.analyzers)+6)
+	for _, a := range p.analyzers {
+		sections = append(sections, section{name: a.Name(), run: func(ctx context.Context) func(*domain.Diagnosis) {
+			// Analyzers that fail partway return the issues found so far
+			// with the error, so both are kept
+			issues, err := a.Analyze(ctx, pod, client)
+			logSwallowed(pod, a.Name(), err)
+			// Note checks skipped for lack of permissions; the other
+			// analyzers run either way
+			w, degraded := degradedWarning(a.Name(), err)
+			return func(d *domain.Diagnosis) {
+				for _, issue := range issues {
+					d.AddIssue(issue.Clone())
+				}
+				if degraded {
+					d.AddWarning(w)
+				}
+			}
+		}})
+	}
+
+	return append(sections,
+		section{name: "events", run: func(ctx context.Context) func(*domain.Diagnosis) {
+			events, err := client.GetPodEvents(ctx, pod.Namespace, pod.Name)
+			logSwallowed(pod, "events", err)
+			return func(d *domain.Diagnosis) {
+				if err == nil {
+					d.Events = slices.Clone(events)
+				}
+			}
+		}},
+		section{name: "workload", run: func(ctx context.Context) func(*domain.Diagnosis) {
+			workload, err := client.ResolveWorkload(ctx, pod)
+			logSwallowed(pod, "workload", err)
+			return func(d *domain.Diagnosis) {
+				if err == nil {
+					d.Workload = workload
+				}
+			}
+		}},
+		section{name: "usage", run: func(ctx context.Context) func(*domain.Diagnosis) {
+			// Live usage is only available with metrics-server
+			metrics, err := client.GetPodMetrics(ctx, pod.Namespace, pod.Name)
+			if err != nil {
+				slog.Debug("pod metrics unavailable", "pod", pod.Namespace+"/"+pod.Name, "error", err)
+				metrics = nil
+			}
+			resources := podResourceUsage(pod, metrics)
+			return func(d *domain.Diagnosis) {
+				d.Resources = resources
+			}
+		}},
+		section{name: "chaos", run: func(ctx context.Context) func(*domain.Diagnosis) {
+			expected := p.expectedFailure(ctx, pod)
+			return func(d *domain.Diagnosis) {
+				d.ExpectedFailure = expected
+			}
+		}},
+		section{name: "acknowledgements", run: func(ctx context.Context) func(*domain.Diagnosis) {
+			// Read for finalize, which applies them once issue IDs are known
+			err := p.acknowledgements(ctx, pod.Namespace)
+			logSwallowed(pod, "acknowledgements", err)
+			return func(d *domain.Diagnosis) {
+				if w, ok := degradedWarning("acknowledgements", err); ok {
+					d.AddWarning(w)
+				}
+			}
+		}},
+		section{name: "node health", run: func(ctx context.Context) func(*domain.Diagnosis) {
+			if pod.Spec.NodeName == "" {
+				return func(*domain.Diagnosis) {}
+			}
+			nodeHealth, err := client.GetNodeHealth(ctx, pod.Spec.NodeName)
+			logSwallowed(pod, "node health", err)
+			return func(d *domain.Diagnosis) {
+				if err == nil {
+					d.Node = nodeHealth
+				} else if w, ok := degradedWarning("node", err); ok {
+					d.AddWarning(w)
+				}
+			}
+		}},
+	)
+}
+
+// assemble builds a diagnosis from the pod and the results of the sections
+// completed so far, in section order
+func (p *PodAnalyzer) assemble(pod *corev1.Pod, applied []func(*domain.Diagnosis)) *domain.Diagnosis {
+	diagnosis := domain.NewDiagnosis(kubernetes.ExtractPodInfo(pod))
+	diagnosis.Status = DetectPodStatus(pod)
+	for _, apply := range applied {
+		if apply != nil {
+			apply(diagnosis)
+		}
+	}
+
+	// Explain what keeps a running pod from becoming ready in one place
+	diagnosis.NotReady = explainNotReady(pod, diagnosis.Events)
+	diagnosis.StartOrder = startOrder(pod)
+	diagnosis.ReadinessGates = readinessGates(pod)
+	consolidateReadiness(diagnosis, pod)
+	addRuntimes(diagnosis, pod)
+
+	p.finalize(diagnosis)
+	return diagnosis
+}
+
+// logSwallowed logs an error a section does not report, leaving the
+// diagnosis without its result or with at most a warning about missing
+// permissions
+func logSwallowed(pod *corev1.Pod, section string, err error) {
+	if err != nil {
+		slog.Warn("section failed", "pod", pod.Namespace+"/"+pod.Name, "section", section, "error", err)
+	}
+}
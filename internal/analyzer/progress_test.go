@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeCluster serves one running Go pod with a CPU limit and two events
+// listed newest first; other objects are not found
+func fakeCluster(t *testing.T) *httptest.Server {
+	t.Helper()
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	pod := corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", CreationTimestamp: created},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "app",
+				Image: "golang:1.22",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	event := func(reason string, age time.Duration) corev1.Event {
+		seen := metav1.NewTime(time.Now().Add(-age))
+		return corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "api." + reason, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "api"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			FirstTimestamp: seen,
+			LastTimestamp:  seen,
+			Count:          1,
+		}
+	}
+	routes := map[string]interface{}{
+		"/api/v1/namespaces/default/pods/api": pod,
+		"/api/v1/namespaces/default/events": corev1.EventList{
+			TypeMeta: metav1.TypeMeta{Kind: "EventList", APIVersion: "v1"},
+			Items:    []corev1.Event{event("Unhealthy", time.Minute), event("BackOff", time.Hour)},
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if obj, ok := routes[r.URL.Path]; ok {
+			writeJSON(w, http.StatusOK, obj)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		})
+	}))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// newTestClient returns a client connected to the fake cluster
+func newTestClient(t *testing.T) *kubernetes.Client {
+	t.Helper()
+	server := fakeCluster(t)
+	t.Cleanup(server.Close)
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	config := `apiVersion: v1
+kind: Config
+clusters:
+- name: fake
+  cluster:
+    server: ` + server.URL + `
+contexts:
+- name: fake
+  context:
+    cluster: fake
+    user: fake
+current-context: fake
+users:
+- name: fake
+  user: {}
+`
+	if err := os.WriteFile(kubeconfig, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client, err := kubernetes.NewClient(kubeconfig, "", kubernetes.RateLimit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// stubAnalyzer reports a CPU throttling issue of the pod's container after
+// a delay, so that diagnoses report progress several times
+type stubAnalyzer struct {
+	name  string
+	delay time.Duration
+}
+
+func (a stubAnalyzer) Name() string { return a.name }
+
+func (a stubAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	time.Sleep(a.delay)
+	issue := domain.NewIssue(domain.SeverityWarning, "resources", "CPU throttled by "+a.name, "The container is throttled")
+	issue.Details["container"] = "app"
+	return []domain.Issue{issue}, nil
+}
+
+// TestProgressSnapshotsAreIndependent reads every progress snapshot on
+// another goroutine while the diagnosis goes on; run with -race, it fails
+// if later snapshots write to issues or events an earlier one holds
+func TestProgressSnapshotsAreIndependent(t *testing.T) {
+	client := newTestClient(t)
+	p := &PodAnalyzer{
+		client:  client,
+		chaos:   &chaosCache{},
+		acks:    &ackCache{},
+		logs:    newConfiguredLogAnalyzer(DefaultConfig().Logs),
+		timeout: 10 * time.Second,
+	}
+	for i, name := range []string{"first", "second", "third", "fourth"} {
+		p.analyzers = append(p.analyzers, stubAnalyzer{name: name, delay: time.Duration(i+1) * 20 * time.Millisecond})
+	}
+
+	var wg sync.WaitGroup
+	snapshots := 0
+	final, err := p.DiagnoseWithProgress(context.Background(), "default", "api", func(progress Progress) {
+		snapshots++
+		wg.Add(1)
+		go func(d *domain.Diagnosis) {
+			defer wg.Done()
+			deadline := time.Now().Add(200 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				var b strings.Builder
+				for _, issue := range d.Issues {
+					for k, v := range issue.Details {
+						b.WriteString(k + v)
+					}
+				}
+				for _, e := range d.Events {
+					b.WriteString(e.Reason)
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}(progress.Diagnosis)
+	})
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshots < 2 {
+		t.Errorf("got %d progress snapshots, want several", snapshots)
+	}
+	if len(final.Events) != 2 {
+		t.Fatalf("got %d events, want 2", len(final.Events))
+	}
+	for _, issue := range final.Issues {
+		if strings.HasPrefix(issue.Title, "CPU throttled") && issue.Details["runtime"] != runtimeGo {
+			t.Errorf("issue %q has runtime %q, want %q", issue.Title, issue.Details["runtime"], runtimeGo)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	"github.com/pavanInnamuri/pod-doctor/internal/provenance"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// verifyTimeout bounds checking one image's signature against its registry
+const verifyTimeout = 10 * time.Second
+
+// ProvenanceAnalyzer checks that running images come from trusted
+// registries and carry valid cosign signatures
+type ProvenanceAnalyzer struct {
+	policy   *provenance.TrustPolicy
+	verifier *provenance.Verifier
+}
+
+// NewProvenanceAnalyzer creates a ProvenanceAnalyzer for a trust policy
+func NewProvenanceAnalyzer(policy *provenance.TrustPolicy) *ProvenanceAnalyzer {
+	return &ProvenanceAnalyzer{
+		policy:   policy,
+		verifier: provenance.NewVerifier(),
+	}
+}
+
+// Name returns the analyzer name
+func (p *ProvenanceAnalyzer) Name() string {
+	return "provenance"
+}
+
+// Analyze checks each container image against the policies for the pod's namespace
+func (p *ProvenanceAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	policies := p.policy.ForNamespace(pod.Namespace)
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	severity := domain.SeverityWarning
+	var registries []string
+	for _, policy := range policies {
+		if policy.Enforce {
+			severity = domain.SeverityCritical
+		}
+		registries = append(registries, policy.Registries...)
+	}
+
+	var issues []domain.Issue
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		details := map[string]string{
+			"container": cs.Name,
+			"image":     cs.Image,
+		}
+
+		var trusted *provenance.Policy
+		for _, policy := range policies {
+			if policy.Trusts(cs.Image) {
+				trusted = policy
+				break
+			}
+		}
+		if trusted == nil {
+			details["trusted_registries"] = strings.Join(registries, ", ")
+			issues = append(issues, domain.Issue{
+				Severity:    severity,
+				Category:    "security",
+				Title:       fmt.Sprintf("Untrusted registry for %s", cs.Name),
+				Description: "Image does not come from a registry trusted in this namespace",
+				Details:     details,
+			})
+			continue
+		}
+		if !trusted.HasKey() {
+			continue
+		}
+
+		details["policy"] = trusted.Name
+		repository, digest, ok := provenance.ParseImageID(cs.ImageID)
+		if !ok {
+			// The image has not been pulled yet, so there is no digest to verify
+			continue
+		}
+		details["digest"] = digest
+
+		verifyCtx, cancel := context.WithTimeout(ctx, verifyTimeout)
+		err := p.verifier.Verify(verifyCtx, trusted, repository, digest)
+		cancel()
+
+		switch {
+		case err == nil:
+			continue
+		case errors.Is(err, provenance.ErrUnsigned):
+			issues = append(issues, domain.Issue{
+				Severity:    severity,
+				Category:    "security",
+				Title:       fmt.Sprintf("Unsigned image for %s", cs.Name),
+				Description: "No cosign signature was found for the running image digest",
+				Details:     details,
+			})
+		case errors.Is(err, provenance.ErrInvalidSignature):
+			issues = append(issues, domain.Issue{
+				Severity:    severity,
+				Category:    "security",
+				Title:       fmt.Sprintf("Invalid image signature for %s", cs.Name),
+				Description: "The image is signed, but not with the key trusted for this registry",
+				Details:     details,
+			})
+		default:
+			details["error"] = err.Error()
+			issues = append(issues, domain.Issue{
+				Severity:    severity,
+				Category:    "security",
+				Title:       fmt.Sprintf("Image provenance unverifiable for %s", cs.Name),
+				Description: "The image signature could not be checked against the registry",
+				Details:     details,
+			})
+		}
+	}
+
+	return issues, nil
+}
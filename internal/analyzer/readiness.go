@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// explainNotReady lists what keeps a running pod from becoming ready: each
+// container that is not ready, with the probe holding it back and the last
+// probe error from events, and each readiness gate whose condition is not true
+func explainNotReady(pod *corev1.Pod, events []domain.EventInfo) []domain.ReadinessBlocker {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || isPodReady(pod) {
+		return nil
+	}
+
+	specs := make(map[string]corev1.Container, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		specs[c.Name] = c
+	}
+
+	var blockers []domain.ReadinessBlocker
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			continue
+		}
+		blocker := domain.ReadinessBlocker{Kind: "container", Name: cs.Name}
+		spec := specs[cs.Name]
+
+		switch {
+		case cs.State.Waiting != nil:
+			blocker.Reason = cs.State.Waiting.Reason
+			blocker.Message = cs.State.Waiting.Message
+		case cs.State.Terminated != nil:
+			blocker.Reason = fmt.Sprintf("terminated with exit code %d", cs.State.Terminated.ExitCode)
+			blocker.Message = cs.State.Terminated.Reason
+		case cs.Started != nil && !*cs.Started && spec.StartupProbe != nil:
+			blocker.Probe = "startup"
+			blocker.Reason = "startup probe has not succeeded yet"
+			blocker.Message = lastProbeFailure(events, pod, cs.Name, "Startup")
+		case spec.ReadinessProbe != nil:
+			blocker.Probe = "readiness"
+			blocker.Reason = "readiness probe failing"
+			blocker.Message = lastProbeFailure(events, pod, cs.Name, "Readiness")
+		default:
+			blocker.Reason = "container running but not ready"
+		}
+		blockers = append(blockers, blocker)
+	}
+
+	for _, gate := range pod.Spec.ReadinessGates {
+		cond := podCondition(pod, gate.ConditionType)
+		switch {
+		case cond == nil:
+			blockers = append(blockers, domain.ReadinessBlocker{
+				Kind:   "readinessGate",
+				Name:   string(gate.ConditionType),
+				Reason: "condition not set by its controller",
+			})
+		case cond.Status != corev1.ConditionTrue:
+			blockers = append(blockers, domain.ReadinessBlocker{
+				Kind:    "readinessGate",
+				Name:    string(gate.ConditionType),
+				Reason:  strings.TrimSpace("condition is " + string(cond.Status) + " " + cond.Reason),
+				Message: cond.Message,
+			})
+		}
+	}
+
+	return blockers
+}
+
+// lastProbeFailure returns the message of the most recent probe failure event
+// for a container, e.g. "Readiness probe failed: HTTP probe failed with statuscode: 503"
+func lastProbeFailure(events []domain.EventInfo, pod *corev1.Pod, container, probe string) string {
+	fieldPath := "spec.containers{" + container + "}"
+	var latest *domain.EventInfo
+	for i, e := range events {
+		if e.Reason != "Unhealthy" || !strings.HasPrefix(e.Message, probe+" probe") {
+			continue
+		}
+		// Events without a field path can only be attributed in single-container pods
+		if e.FieldPath != fieldPath && (e.FieldPath != "" || len(pod.Spec.Containers) > 1) {
+			continue
+		}
+		if latest == nil || e.LastSeen.After(latest.LastSeen) {
+			latest = &events[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+// podCondition returns the pod condition of a type, or nil
+func podCondition(pod *corev1.Pod, condType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == condType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// consolidateReadiness replaces the readiness issues reported separately by
+// the status and probe analyzers with one "Pod is not ready" issue built
+// from the readiness blockers
+func consolidateReadiness(diagnosis *domain.Diagnosis, pod *corev1.Pod) {
+	if len(diagnosis.NotReady) == 0 {
+		return
+	}
+
+	issues := diagnosis.Issues[:0]
+	for _, issue := range diagnosis.Issues {
+		if isReadinessIssue(issue) {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	var blockedBy, probes, gates []string
+	for _, b := range diagnosis.NotReady {
+		switch {
+		case b.Kind == "readinessGate":
+			blockedBy = append(blockedBy, "readiness gate "+b.Name)
+			gates = append(gates, b.Name)
+		case b.Probe != "":
+			blockedBy = append(blockedBy, fmt.Sprintf("container %s (%s probe)", b.Name, b.Probe))
+			probes = append(probes, b.Name)
+		default:
+			blockedBy = append(blockedBy, fmt.Sprintf("container %s (%s)", b.Name, b.Reason))
+		}
+	}
+
+	details := map[string]string{
+		"blocked_by": strings.Join(blockedBy, ", "),
+	}
+	if len(probes) > 0 {
+		details["probe_containers"] = strings.Join(probes, ", ")
+	}
+	if len(gates) > 0 {
+		details["readiness_gates"] = strings.Join(gates, ", ")
+	}
+	if cond := podCondition(pod, corev1.PodReady); cond != nil && !cond.LastTransitionTime.IsZero() {
+		details["not_ready_for"] = time.Since(cond.LastTransitionTime.Time).Round(time.Second).String()
+	}
+
+	diagnosis.Issues = append(issues, domain.Issue{
+		Severity:    domain.SeverityWarning,
+		Category:    "container",
+		Title:       "Pod is not ready",
+		Description: "Pod is running but not ready, so Services send it no traffic",
+		Details:     details,
+	})
+}
+
+// isReadinessIssue reports whether an issue only restates that the pod or a
+// container is not ready, which the consolidated readiness issue covers
+func isReadinessIssue(issue domain.Issue) bool {
+	switch {
+	case issue.Category == "container" && (issue.Title == "Pod is not ready" || issue.Title == "Containers not ready"):
+		return true
+	case issue.Category == "probes" && strings.HasSuffix(issue.Title, "running but not ready"):
+		return true
+	case issue.Category == "probes" && issue.Title == "Readiness probe failed":
+		return true
+	}
+	return false
+}
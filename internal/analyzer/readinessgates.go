@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// readinessGateGrace is how long after a pod starts its gate controllers
+// get to post their conditions before a missing one is a warning
+const readinessGateGrace = 2 * time.Minute
+
+// gateController is a controller known to inject readiness gates and post
+// their conditions, recognized by the prefix of the condition type
+type gateController struct {
+	prefix string
+	name   string
+	// hint says what usually keeps the controller from posting the condition
+	hint string
+}
+
+var gateControllers = []gateController{
+	{
+		prefix: "target-health.elbv2.k8s.aws/",
+		name:   "AWS Load Balancer Controller",
+		hint:   "posts the condition once the pod is healthy in the target group of the TargetGroupBinding the condition is named after, and never does when that binding was deleted, the controller is down or it cannot reach the AWS API",
+	},
+	{
+		prefix: "target-health.alb.ingress.k8s.aws/",
+		name:   "AWS ALB Ingress Controller",
+		hint:   "posts the condition once the pod is healthy in the target group of its ingress, and never does when the ingress changed or the controller is down",
+	},
+	{
+		prefix: "cloud.google.com/load-balancer-neg-ready",
+		name:   "GKE NEG controller",
+		hint:   "posts the condition once the pod's endpoint is healthy in its network endpoint group, and never does when the service no longer uses container-native load balancing",
+	},
+}
+
+// ReadinessGateAnalyzer checks the custom readiness gates of a pod. A gate
+// whose controller never posts its condition keeps the pod NotReady with
+// healthy containers and no failing probe to point at.
+type ReadinessGateAnalyzer struct{}
+
+// NewReadinessGateAnalyzer creates a new ReadinessGateAnalyzer
+func NewReadinessGateAnalyzer() *ReadinessGateAnalyzer {
+	return &ReadinessGateAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (a *ReadinessGateAnalyzer) Name() string {
+	return "readinessgates"
+}
+
+// Analyze reports readiness gates without a condition, as information
+// while the pod is young and as a warning once their controller had time
+// to post it
+func (a *ReadinessGateAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	started := pod.CreationTimestamp.Time
+	if pod.Status.StartTime != nil {
+		started = pod.Status.StartTime.Time
+	}
+	age := time.Since(started).Round(time.Second)
+
+	var issues []domain.Issue
+	for _, gate := range readinessGates(pod) {
+		if gate.Status != "" {
+			continue
+		}
+
+		issue := domain.NewIssue(domain.SeverityWarning, "container",
+			fmt.Sprintf("Readiness gate %s never got a condition", gate.ConditionType),
+			fmt.Sprintf("No controller posted the %s condition in %s, so the pod stays NotReady however healthy its containers are", gate.ConditionType, age),
+		)
+		if age < readinessGateGrace {
+			issue = domain.NewIssue(domain.SeverityInfo, "container",
+				fmt.Sprintf("Readiness gate %s has no condition yet", gate.ConditionType),
+				"The pod started recently; its gate controller may not have posted the condition yet",
+			)
+		}
+		issue = issue.WithDetail("condition_type", gate.ConditionType).WithDetail("pod_age", age.String())
+		if c, ok := gateControllerFor(gate.ConditionType); ok {
+			issue.Description += "; the " + c.name + " " + c.hint
+			issue = issue.WithDetail("controller", c.name)
+			if target := strings.TrimPrefix(gate.ConditionType, c.prefix); target != "" {
+				issue = issue.WithDetail("target", target)
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// readinessGates lists the custom readiness gates of a pod with their
+// conditions, or nil when it has none
+func readinessGates(pod *corev1.Pod) []domain.ReadinessGate {
+	var gates []domain.ReadinessGate
+	for _, g := range pod.Spec.ReadinessGates {
+		gate := domain.ReadinessGate{ConditionType: string(g.ConditionType)}
+		if c, ok := gateControllerFor(gate.ConditionType); ok {
+			gate.Controller = c.name
+		}
+		if cond := podCondition(pod, g.ConditionType); cond != nil {
+			gate.Status = string(cond.Status)
+			gate.Reason = cond.Reason
+			gate.Message = cond.Message
+			gate.Since = cond.LastTransitionTime.Time
+		}
+		gates = append(gates, gate)
+	}
+	return gates
+}
+
+// gateControllerFor returns the known controller posting a condition type
+func gateControllerFor(conditionType string) (gateController, bool) {
+	for _, c := range gateControllers {
+		if strings.HasPrefix(conditionType, c.prefix) {
+			return c, true
+		}
+	}
+	return gateController{}, false
+}
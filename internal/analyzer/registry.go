@@ -0,0 +1,314 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/rules"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Config selects which analyzers run and holds their settings
+type Config struct {
+	// Enabled lists the analyzers to run; empty means all registered analyzers
+	Enabled []string `yaml:"enabled,omitempty"`
+	// Disabled lists analyzers to skip
+	Disabled []string `yaml:"disabled,omitempty"`
+	// Timeout bounds each analyzer, and each other part of a diagnosis such
+	// as the event or node lookup; one that overruns it is skipped
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	Logs        LogConfig         `yaml:"logs,omitempty"`
+	Status      StatusConfig      `yaml:"status,omitempty"`
+	Workload    WorkloadConfig    `yaml:"workload,omitempty"`
+	Conformance ConformanceConfig `yaml:"conformance,omitempty"`
+	Plugins     PluginConfig      `yaml:"plugins,omitempty"`
+}
+
+// LogConfig configures the logs analyzer
+type LogConfig struct {
+	// TailLines is how many recent lines of each container's log are scanned
+	TailLines int64 `yaml:"tailLines,omitempty"`
+	// PatternFiles are files of custom log patterns, loaded into Patterns
+	PatternFiles []string `yaml:"patternFiles,omitempty"`
+	// Patterns are custom log patterns added to the built-in ones; a pattern
+	// with the title of a built-in one replaces it
+	Patterns []rules.LogPattern `yaml:"patterns,omitempty"`
+	// DisabledPatterns are titles of built-in patterns to drop, e.g. "Process killed"
+	DisabledPatterns []string `yaml:"disabledPatterns,omitempty"`
+}
+
+// StatusConfig configures the status analyzer
+type StatusConfig struct {
+	// RestartThreshold is the restart count above which a container is flagged
+	RestartThreshold int32 `yaml:"restartThreshold,omitempty"`
+	// InitTimeout is how long an init container may run before it is
+	// reported stuck, unless the pod's pod-doctor.io/init-expected-duration
+	// annotation says otherwise
+	InitTimeout time.Duration `yaml:"initTimeout,omitempty"`
+}
+
+// WorkloadConfig configures the workload analyzer
+type WorkloadConfig struct {
+	// ProductionSelector is a label selector matching the namespaces where a
+	// single-replica Deployment with an unhealthy pod is a full outage
+	ProductionSelector string `yaml:"productionSelector,omitempty"`
+}
+
+// ConformanceConfig configures the conformance analyzer
+type ConformanceConfig struct {
+	// RequiredLabels must be set on pods and their workloads; a glob such
+	// as app.kubernetes.io/* requires at least one matching label
+	RequiredLabels []string `yaml:"requiredLabels,omitempty"`
+	// RequiredAnnotations are checked like RequiredLabels
+	RequiredAnnotations []string `yaml:"requiredAnnotations,omitempty"`
+	// Severity of violations: info or warning
+	Severity string `yaml:"severity,omitempty"`
+}
+
+// PluginConfig configures analyzer plugins discovered on PATH
+type PluginConfig struct {
+	// Enabled turns on plugin discovery. Plugins are opt-in, so that an
+	// executable that happens to be on PATH does not run unasked
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Timeout bounds each plugin run
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// DefaultConfig returns the configuration with every analyzer enabled
+func DefaultConfig() Config {
+	return Config{
+		Timeout:     15 * time.Second,
+		Logs:        LogConfig{TailLines: 100},
+		Status:      StatusConfig{RestartThreshold: 5, InitTimeout: 10 * time.Minute},
+		Workload:    WorkloadConfig{ProductionSelector: "environment=production"},
+		Conformance: ConformanceConfig{Severity: string(domain.SeverityInfo)},
+		Plugins:     PluginConfig{Timeout: 10 * time.Second},
+	}
+}
+
+// Factory creates an analyzer from the configuration
+type Factory func(cfg Config) Analyzer
+
+type registeredAnalyzer struct {
+	name    string
+	factory Factory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registeredAnalyzer
+)
+
+// RegisterAnalyzer makes an analyzer available under a name. Analyzers run
+// in registration order. It panics if the name is already registered.
+func RegisterAnalyzer(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range registry {
+		if r.name == name {
+			panic(fmt.Sprintf("analyzer %q registered twice", name))
+		}
+	}
+	registry = append(registry, registeredAnalyzer{name: name, factory: factory})
+}
+
+// AnalyzerNames returns the names of all registered analyzers in run order
+func AnalyzerNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for _, r := range registry {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+// buildAnalyzers creates the analyzers selected by the configuration. It
+// also returns the configuration with defaults applied and pattern files
+// loaded.
+func buildAnalyzers(cfg Config) ([]Analyzer, Config, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	known := make(map[string]bool, len(registry))
+	names := make([]string, 0, len(registry))
+	for _, r := range registry {
+		known[r.name] = true
+		names = append(names, r.name)
+	}
+
+	// Plugins are selected by name like built-in analyzers, which win on
+	// a name clash
+	var plugins []Plugin
+	if cfg.Plugins.Enabled {
+		for _, plugin := range DiscoverPlugins() {
+			if known[plugin.Name] {
+				continue
+			}
+			known[plugin.Name] = true
+			names = append(names, plugin.Name)
+			plugins = append(plugins, plugin)
+		}
+	}
+	for _, name := range append(append([]string{}, cfg.Enabled...), cfg.Disabled...) {
+		if !known[name] {
+			return nil, cfg, fmt.Errorf("unknown analyzer %q (available: %s)", name, strings.Join(names, ", "))
+		}
+	}
+
+	// Settings left unset keep their defaults
+	defaults := DefaultConfig()
+	if cfg.Logs.TailLines <= 0 {
+		cfg.Logs.TailLines = defaults.Logs.TailLines
+	}
+	if cfg.Status.RestartThreshold <= 0 {
+		cfg.Status.RestartThreshold = defaults.Status.RestartThreshold
+	}
+	if cfg.Status.InitTimeout <= 0 {
+		cfg.Status.InitTimeout = defaults.Status.InitTimeout
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.Plugins.Timeout <= 0 {
+		cfg.Plugins.Timeout = defaults.Plugins.Timeout
+	}
+	if cfg.Workload.ProductionSelector == "" {
+		cfg.Workload.ProductionSelector = defaults.Workload.ProductionSelector
+	}
+	if _, err := labels.Parse(cfg.Workload.ProductionSelector); err != nil {
+		return nil, cfg, fmt.Errorf("invalid production namespace selector %q: %w", cfg.Workload.ProductionSelector, err)
+	}
+
+	if cfg.Conformance.Severity == "" {
+		cfg.Conformance.Severity = defaults.Conformance.Severity
+	}
+	if err := validateConformance(cfg.Conformance); err != nil {
+		return nil, cfg, err
+	}
+
+	if err := resolveLogPatterns(&cfg.Logs); err != nil {
+		return nil, cfg, err
+	}
+
+	enabled := toSet(cfg.Enabled)
+	disabled := toSet(cfg.Disabled)
+
+	var analyzers []Analyzer
+	for _, r := range registry {
+		if len(enabled) > 0 && !enabled[r.name] {
+			continue
+		}
+		if disabled[r.name] {
+			continue
+		}
+		analyzers = append(analyzers, r.factory(cfg))
+	}
+	for _, plugin := range plugins {
+		if len(enabled) > 0 && !enabled[plugin.Name] {
+			continue
+		}
+		if disabled[plugin.Name] {
+			continue
+		}
+		analyzers = append(analyzers, NewPluginAnalyzer(plugin, cfg.Plugins.Timeout))
+	}
+	return analyzers, cfg, nil
+}
+
+// resolveLogPatterns loads the pattern files and checks every custom
+// pattern and disabled built-in, so mistakes are reported at startup
+func resolveLogPatterns(cfg *LogConfig) error {
+	patterns := append([]rules.LogPattern{}, cfg.Patterns...)
+	for _, file := range cfg.PatternFiles {
+		loaded, err := rules.LoadLogPatterns(file)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, loaded...)
+	}
+	for i, lp := range patterns {
+		if err := lp.Validate(); err != nil {
+			return fmt.Errorf("log pattern %d: %w", i+1, err)
+		}
+	}
+	cfg.Patterns = patterns
+	cfg.PatternFiles = nil
+
+	builtin := make(map[string]bool)
+	var titles []string
+	for _, lp := range DefaultLogPatterns() {
+		if !builtin[lp.Title] {
+			titles = append(titles, lp.Title)
+		}
+		builtin[lp.Title] = true
+	}
+	for _, title := range cfg.DisabledPatterns {
+		if !builtin[title] {
+			return fmt.Errorf("unknown built-in log pattern %q (available: %s)", title, strings.Join(titles, ", "))
+		}
+	}
+	return nil
+}
+
+// validateConformance rejects unknown severities and malformed key globs
+func validateConformance(cfg ConformanceConfig) error {
+	switch domain.Severity(cfg.Severity) {
+	case domain.SeverityInfo, domain.SeverityWarning:
+	default:
+		return fmt.Errorf("invalid conformance severity %q (available: info, warning)", cfg.Severity)
+	}
+	for _, key := range append(append([]string{}, cfg.RequiredLabels...), cfg.RequiredAnnotations...) {
+		if _, err := path.Match(key, ""); err != nil || key == "" {
+			return fmt.Errorf("invalid required label or annotation %q", key)
+		}
+	}
+	return nil
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+func init() {
+	RegisterAnalyzer("status", func(cfg Config) Analyzer {
+		return &StatusAnalyzer{restartThreshold: cfg.Status.RestartThreshold, initTimeout: cfg.Status.InitTimeout}
+	})
+	RegisterAnalyzer("events", func(Config) Analyzer { return NewEventAnalyzer() })
+	RegisterAnalyzer("logs", func(cfg Config) Analyzer { return newConfiguredLogAnalyzer(cfg.Logs) })
+	RegisterAnalyzer("node", func(Config) Analyzer { return NewNodeAnalyzer() })
+	RegisterAnalyzer("resources", func(Config) Analyzer { return NewResourceAnalyzer() })
+	RegisterAnalyzer("probes", func(Config) Analyzer { return NewProbeAnalyzer() })
+	RegisterAnalyzer("startorder", func(Config) Analyzer { return NewStartOrderAnalyzer() })
+	RegisterAnalyzer("readinessgates", func(Config) Analyzer { return NewReadinessGateAnalyzer() })
+	RegisterAnalyzer("neighbors", func(Config) Analyzer { return NewNeighborAnalyzer() })
+	RegisterAnalyzer("dns", func(Config) Analyzer { return NewDNSAnalyzer() })
+	RegisterAnalyzer("network", func(Config) Analyzer { return NewNetworkAnalyzer() })
+	RegisterAnalyzer("networkpolicy", func(Config) Analyzer { return NewNetworkPolicyAnalyzer() })
+	RegisterAnalyzer("workload", func(cfg Config) Analyzer {
+		w := NewWorkloadAnalyzer()
+		w.productionSelector, _ = labels.Parse(cfg.Workload.ProductionSelector)
+		return w
+	})
+	RegisterAnalyzer("autoscaler", func(Config) Analyzer { return NewAutoscalerAnalyzer() })
+	RegisterAnalyzer("preemption", func(Config) Analyzer { return NewPreemptionAnalyzer() })
+	RegisterAnalyzer("disruption", func(Config) Analyzer { return NewDisruptionAnalyzer() })
+	RegisterAnalyzer("vpa", func(Config) Analyzer { return NewVPAAnalyzer() })
+	RegisterAnalyzer("volumes", func(Config) Analyzer { return NewVolumeAnalyzer() })
+	RegisterAnalyzer("image", func(Config) Analyzer { return NewImageAnalyzer() })
+	RegisterAnalyzer("scheduling", func(Config) Analyzer { return NewSchedulingAnalyzer() })
+	RegisterAnalyzer("conformance", func(cfg Config) Analyzer { return NewConformanceAnalyzer(cfg.Conformance) })
+	RegisterAnalyzer("serviceaccount", func(Config) Analyzer { return NewServiceAccountTokenAnalyzer() })
+	RegisterAnalyzer("specsize", func(Config) Analyzer { return NewSpecSizeAnalyzer() })
+}
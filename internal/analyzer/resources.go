@@ -8,8 +8,12 @@ import (
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
+// usageThreshold is the fraction of a limit above which live usage is flagged
+const usageThreshold = 0.9
+
 // ResourceAnalyzer analyzes pod resource configurations and usage
 type ResourceAnalyzer struct{}
 
@@ -35,9 +39,124 @@ func (r *ResourceAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client
 		issues = append(issues, r.analyzeContainer(container)...)
 	}
 
+	// Compare live usage against limits when metrics-server is available
+	if metrics, err := client.GetPodMetrics(ctx, pod.Namespace, pod.Name); err == nil {
+		issues = append(issues, r.analyzeUsage(pod, metrics)...)
+	}
+
 	return issues, nil
 }
 
+// analyzeUsage flags containers running close to their memory or CPU limit
+func (r *ResourceAnalyzer) analyzeUsage(pod *corev1.Pod, metrics *metricsv1beta1.PodMetrics) []domain.Issue {
+	var issues []domain.Issue
+
+	limits := make(map[string]corev1.ResourceList)
+	for _, container := range pod.Spec.Containers {
+		limits[container.Name] = container.Resources.Limits
+	}
+
+	for _, usage := range metrics.Containers {
+		limit, ok := limits[usage.Name]
+		if !ok {
+			continue
+		}
+
+		memUsage := usage.Usage.Memory()
+		if memLimit := limit.Memory(); !memLimit.IsZero() {
+			ratio := float64(memUsage.Value()) / float64(memLimit.Value())
+			if ratio >= usageThreshold {
+				issues = append(issues, domain.Issue{
+					Severity:    domain.SeverityCritical,
+					Category:    "resources",
+					Title:       fmt.Sprintf("Memory near limit for %s", usage.Name),
+					Description: "Container is using most of its memory limit and is likely to be OOMKilled soon",
+					Details: map[string]string{
+						"container":    usage.Name,
+						"memory_usage": formatMemory(memUsage),
+						"memory_limit": memLimit.String(),
+						"percent":      fmt.Sprintf("%.0f%%", ratio*100),
+					},
+				})
+			}
+		}
+
+		// metrics-server does not report throttling directly; a container
+		// pinned at its CPU limit is being throttled by the CFS quota
+		cpuUsage := usage.Usage.Cpu()
+		if cpuLimit := limit.Cpu(); !cpuLimit.IsZero() {
+			ratio := float64(cpuUsage.MilliValue()) / float64(cpuLimit.MilliValue())
+			if ratio >= usageThreshold {
+				issues = append(issues, domain.Issue{
+					Severity:    domain.SeverityWarning,
+					Category:    "resources",
+					Title:       fmt.Sprintf("CPU throttled for %s", usage.Name),
+					Description: "Container is running at its CPU limit and is being throttled, which slows requests and probes",
+					Details: map[string]string{
+						"container": usage.Name,
+						"cpu_usage": formatCPU(cpuUsage),
+						"cpu_limit": cpuLimit.String(),
+						"percent":   fmt.Sprintf("%.0f%%", ratio*100),
+					},
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// podResourceUsage totals requests, limits and, when metrics are available,
+// live usage across the pod's containers
+func podResourceUsage(pod *corev1.Pod, metrics *metricsv1beta1.PodMetrics) *domain.ResourceUsage {
+	var cpuReq, cpuLim, memReq, memLim resource.Quantity
+	for _, container := range pod.Spec.Containers {
+		cpuReq.Add(*container.Resources.Requests.Cpu())
+		cpuLim.Add(*container.Resources.Limits.Cpu())
+		memReq.Add(*container.Resources.Requests.Memory())
+		memLim.Add(*container.Resources.Limits.Memory())
+	}
+
+	summary := &domain.ResourceUsage{}
+	if !cpuReq.IsZero() {
+		summary.CPURequests = cpuReq.String()
+	}
+	if !cpuLim.IsZero() {
+		summary.CPULimits = cpuLim.String()
+	}
+	if !memReq.IsZero() {
+		summary.MemoryRequests = memReq.String()
+	}
+	if !memLim.IsZero() {
+		summary.MemoryLimits = memLim.String()
+	}
+
+	if metrics != nil {
+		var cpu, mem resource.Quantity
+		for _, usage := range metrics.Containers {
+			cpu.Add(*usage.Usage.Cpu())
+			mem.Add(*usage.Usage.Memory())
+		}
+		summary.CPUUsage = formatCPU(&cpu)
+		summary.MemoryUsage = formatMemory(&mem)
+	}
+
+	if *summary == (domain.ResourceUsage{}) {
+		return nil
+	}
+	return summary
+}
+
+// formatCPU renders a CPU quantity in millicores
+func formatCPU(q *resource.Quantity) string {
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+// formatMemory renders a memory quantity in mebibytes
+func formatMemory(q *resource.Quantity) string {
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
+
 // analyzeContainer checks a container's resource configuration
 func (r *ResourceAnalyzer) analyzeContainer(container corev1.Container) []domain.Issue {
 	var issues []domain.Issue
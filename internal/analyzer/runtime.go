@@ -0,0 +1,413 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Application runtimes pod-doctor tailors memory advice to
+const (
+	runtimeJVM    = "jvm"
+	runtimeNode   = "node"
+	runtimeGo     = "go"
+	runtimePython = "python"
+)
+
+// runtimeSignal is evidence of a runtime in a container: a pattern for its
+// image, an environment variable it reads, a command it runs as or a line
+// it logs
+type runtimeSignal struct {
+	runtime string
+	image   *regexp.Regexp
+	env     []string
+	command *regexp.Regexp
+	log     *regexp.Regexp
+}
+
+var runtimeSignals = []runtimeSignal{
+	{
+		runtime: runtimeJVM,
+		image:   regexp.MustCompile(`(?i)(^|/)(openjdk|eclipse-temurin|amazoncorretto|ibm-semeru-runtimes|sapmachine|java|jre|jdk|maven|gradle|tomcat|jetty|wildfly|keycloak|elasticsearch|kafka|zookeeper|cassandra|spark)([:/@-]|$)`),
+		env:     []string{"JAVA_OPTS", "JAVA_TOOL_OPTIONS", "JDK_JAVA_OPTIONS", "_JAVA_OPTIONS", "JAVA_HOME"},
+		command: regexp.MustCompile(`(^|/)java$|\.jar$`),
+		log:     regexp.MustCompile(`java\.lang\.OutOfMemoryError|Exception in thread "|^\s+at [\w$.]+\([\w$]+\.java:\d+\)`),
+	},
+	{
+		runtime: runtimeNode,
+		image:   regexp.MustCompile(`(?i)(^|/)(node|nodejs|deno|bun)([:/@-]|$)`),
+		env:     []string{"NODE_OPTIONS", "NODE_ENV"},
+		command: regexp.MustCompile(`(^|/)(node|npm|yarn|pnpm|npx)$`),
+		log:     regexp.MustCompile(`JavaScript heap out of memory|Reached heap limit|^\s+at .+ \(node:`),
+	},
+	{
+		runtime: runtimeGo,
+		image:   regexp.MustCompile(`(?i)(^|/)golang([:/@-]|$)`),
+		env:     []string{"GOMEMLIMIT", "GOGC", "GOMAXPROCS", "GODEBUG"},
+		log:     regexp.MustCompile(`^goroutine \d+ \[|fatal error: runtime: out of memory|^runtime: out of memory`),
+	},
+	{
+		runtime: runtimePython,
+		image:   regexp.MustCompile(`(?i)(^|/)(python|pypy)([:/@-]|$)`),
+		env:     []string{"PYTHONPATH", "PYTHONUNBUFFERED", "PYTHONDONTWRITEBYTECODE"},
+		command: regexp.MustCompile(`(^|/)(python[\d.]*|gunicorn|uvicorn|celery)$`),
+		log:     regexp.MustCompile(`^Traceback \(most recent call last\)|^MemoryError`),
+	},
+}
+
+// jvmHeapFlag matches JVM options that size the heap
+var jvmHeapFlag = regexp.MustCompile(`-Xmx\S+|-XX:MaxRAMPercentage=\S+|-XX:MaxRAM=\S+`)
+
+// nodeHeapFlag matches the Node.js option that sizes the old generation heap
+var nodeHeapFlag = regexp.MustCompile(`--max-old-space-size=\S+`)
+
+// jvmCPUFlag matches the JVM option that sets how many CPUs it sizes its
+// thread pools for
+var jvmCPUFlag = regexp.MustCompile(`-XX:ActiveProcessorCount=\S+`)
+
+// jvmNoContainerSupport matches the JVM option that makes it ignore the
+// container's CPU and memory limits
+var jvmNoContainerSupport = regexp.MustCompile(`-XX:-UseContainerSupport`)
+
+// automaxprocsLog matches what go.uber.org/automaxprocs logs when it sets
+// GOMAXPROCS from the CPU quota at startup
+var automaxprocsLog = regexp.MustCompile(`maxprocs: (Updating|Leaving) GOMAXPROCS`)
+
+// jvmOptionEnv lists the environment variables the JVM, or the scripts
+// starting it, read options from
+var jvmOptionEnv = []string{"JAVA_TOOL_OPTIONS", "JAVA_OPTS", "JDK_JAVA_OPTIONS", "_JAVA_OPTIONS"}
+
+// detectRuntime infers the runtime of a container from its image, its
+// environment and command, and, when only one container logs, the log
+// lines of the diagnosis. It returns what gave it away.
+func detectRuntime(c corev1.Container, logLines []string) (runtime, evidence string) {
+	for _, s := range runtimeSignals {
+		if s.image != nil && s.image.MatchString(c.Image) {
+			return s.runtime, "image " + c.Image
+		}
+	}
+	for _, s := range runtimeSignals {
+		for _, env := range c.Env {
+			for _, name := range s.env {
+				if env.Name == name {
+					return s.runtime, "environment variable " + name
+				}
+			}
+		}
+	}
+	for _, s := range runtimeSignals {
+		if s.command == nil {
+			continue
+		}
+		for _, arg := range append(append([]string(nil), c.Command...), c.Args...) {
+			if s.command.MatchString(arg) {
+				return s.runtime, "command " + arg
+			}
+		}
+	}
+	for _, s := range runtimeSignals {
+		for _, line := range logLines {
+			if s.log.MatchString(line) {
+				return s.runtime, "log line " + truncateLine(strings.TrimSpace(line), 80)
+			}
+		}
+	}
+	return "", ""
+}
+
+// addRuntimes names the runtime of the container of each memory issue, with
+// its heap setting and one sized to the container's memory limit, so the
+// recommendations can tune the runtime instead of only raising the limit.
+// For throttled containers it does the same with the number of CPUs the
+// runtime sizes its threads for.
+func addRuntimes(diagnosis *domain.Diagnosis, pod *corev1.Pod) {
+	var logLines []string
+	if diagnosis.Logs != nil && len(pod.Spec.Containers) == 1 {
+		logLines = append(append(logLines, diagnosis.Logs.ErrorLines...), diagnosis.Logs.LastLines...)
+	}
+
+	for i := range diagnosis.Issues {
+		issue := &diagnosis.Issues[i]
+		memory, cpu := isMemoryIssue(*issue), isCPUThrottleIssue(*issue)
+		if !memory && !cpu {
+			continue
+		}
+		spec := containerSpec(pod, issue.Details["container"])
+		if spec == nil {
+			continue
+		}
+		runtime, evidence := detectRuntime(*spec, logLines)
+		if runtime == "" {
+			continue
+		}
+		issue.Details["runtime"] = runtime
+		issue.Details["runtime_evidence"] = evidence
+		if cpu {
+			addCPUQuotaSettings(issue, runtime, *spec, logLines)
+			continue
+		}
+		if setting := runtimeMemorySetting(runtime, *spec); setting != "" {
+			issue.Details["runtime_memory_setting"] = setting
+		}
+
+		// Size the runtime to the limit the container is told to move to
+		limit := spec.Resources.Limits.Memory()
+		if suggested, err := resource.ParseQuantity(issue.Details["suggested_memory_limit"]); err == nil {
+			limit = &suggested
+		}
+		if setting := suggestRuntimeSetting(runtime, limit); setting != "" {
+			issue.Details["suggested_runtime_setting"] = setting
+		}
+	}
+}
+
+// addCPUQuotaSettings records how a throttled Go or JVM container tells its
+// runtime about the CPU limit, and, when it does not, the setting that
+// would. Without it the runtime sizes its threads by the node's cores, and
+// they use up the quota early in each period and wait out the rest. A JVM
+// is only flagged when run with -XX:-UseContainerSupport.
+func addCPUQuotaSettings(issue *domain.Issue, runtime string, c corev1.Container, logLines []string) {
+	if runtime != runtimeGo && runtime != runtimeJVM {
+		return
+	}
+	if setting := cpuQuotaSetting(runtime, c, logLines); setting != "" {
+		issue.Details["cpu_quota_setting"] = setting
+		return
+	}
+	if runtime == runtimeJVM {
+		// JVMs since 10 and 8u191 read the CPU limit from the cgroup on
+		// their own, so only one told not to is flagged
+		if !jvmOptionsMatch(c, jvmNoContainerSupport) {
+			return
+		}
+		issue.Details["container_support"] = "disabled"
+	}
+
+	limit := c.Resources.Limits.Cpu()
+	if l, err := resource.ParseQuantity(issue.Details["cpu_limit"]); err == nil {
+		limit = &l
+	}
+	if setting := suggestCPUSetting(runtime, limit); setting != "" {
+		issue.Details["suggested_cpu_setting"] = setting
+		issue.Description += "; the " + runtimeName(runtime) + " does not appear to know the CPU limit, so it may run more threads than the limit can serve"
+	}
+}
+
+// isCPUThrottleIssue reports whether an issue is about a container being
+// throttled at its CPU limit
+func isCPUThrottleIssue(issue domain.Issue) bool {
+	return issue.Details["container"] != "" && strings.HasPrefix(issue.Title, "CPU throttled")
+}
+
+// cpuQuotaSetting returns how a container tells its runtime how many CPUs
+// it may use, e.g. "GOMAXPROCS=2", or an empty string
+func cpuQuotaSetting(runtime string, c corev1.Container, logLines []string) string {
+	switch runtime {
+	case runtimeGo:
+		for _, e := range c.Env {
+			if e.Name == "GOMAXPROCS" {
+				if e.ValueFrom != nil && e.ValueFrom.ResourceFieldRef != nil {
+					return "GOMAXPROCS from " + e.ValueFrom.ResourceFieldRef.Resource
+				}
+				return "GOMAXPROCS=" + e.Value
+			}
+		}
+		for _, line := range logLines {
+			if automaxprocsLog.MatchString(line) {
+				return "automaxprocs"
+			}
+		}
+	case runtimeJVM:
+		for _, e := range c.Env {
+			for _, name := range jvmOptionEnv {
+				if e.Name == name {
+					if m := jvmCPUFlag.FindString(e.Value); m != "" {
+						return name + "=" + m
+					}
+				}
+			}
+		}
+		return jvmCPUFlag.FindString(strings.Join(append(append([]string(nil), c.Command...), c.Args...), " "))
+	}
+	return ""
+}
+
+// jvmOptionsMatch reports whether a JVM option is set in the container's
+// command or option environment variables
+func jvmOptionsMatch(c corev1.Container, option *regexp.Regexp) bool {
+	for _, e := range c.Env {
+		for _, name := range jvmOptionEnv {
+			if e.Name == name && option.MatchString(e.Value) {
+				return true
+			}
+		}
+	}
+	return option.MatchString(strings.Join(append(append([]string(nil), c.Command...), c.Args...), " "))
+}
+
+// suggestCPUSetting returns the setting that sizes a runtime's threads to a
+// CPU limit, rounded up to whole CPUs
+func suggestCPUSetting(runtime string, limit *resource.Quantity) string {
+	if limit == nil || limit.IsZero() {
+		return ""
+	}
+	cpus := (limit.MilliValue() + 999) / 1000
+	switch runtime {
+	case runtimeGo:
+		return fmt.Sprintf("GOMAXPROCS=%d", cpus)
+	case runtimeJVM:
+		return fmt.Sprintf("JAVA_TOOL_OPTIONS=-XX:ActiveProcessorCount=%d", cpus)
+	}
+	return ""
+}
+
+// runtimeName returns how descriptions refer to a runtime
+func runtimeName(runtime string) string {
+	switch runtime {
+	case runtimeJVM:
+		return "JVM"
+	case runtimeGo:
+		return "Go runtime"
+	case runtimeNode:
+		return "Node.js runtime"
+	case runtimePython:
+		return "Python interpreter"
+	}
+	return runtime
+}
+
+// isMemoryIssue reports whether an issue is about a container running out
+// of memory
+func isMemoryIssue(issue domain.Issue) bool {
+	return issue.Details["container"] != "" &&
+		(containsReason(issue, "OOMKilled") || strings.HasPrefix(issue.Title, "Memory near limit"))
+}
+
+// runtimeMemorySetting returns how a container already sizes its runtime's
+// memory, e.g. "JAVA_OPTS=-Xmx2g", or an empty string
+func runtimeMemorySetting(runtime string, c corev1.Container) string {
+	args := strings.Join(append(append([]string(nil), c.Command...), c.Args...), " ")
+	var flag *regexp.Regexp
+	var env []string
+	switch runtime {
+	case runtimeJVM:
+		flag, env = jvmHeapFlag, jvmOptionEnv
+	case runtimeNode:
+		flag, env = nodeHeapFlag, []string{"NODE_OPTIONS"}
+	case runtimeGo:
+		for _, e := range c.Env {
+			if e.Name == "GOMEMLIMIT" {
+				return "GOMEMLIMIT=" + e.Value
+			}
+		}
+		return ""
+	default:
+		return ""
+	}
+
+	for _, e := range c.Env {
+		for _, name := range env {
+			if e.Name == name {
+				if m := flag.FindString(e.Value); m != "" {
+					return name + "=" + m
+				}
+			}
+		}
+	}
+	return flag.FindString(args)
+}
+
+// suggestRuntimeSetting returns the setting that sizes a runtime's heap to
+// a memory limit, leaving room for what the runtime uses outside of it
+func suggestRuntimeSetting(runtime string, limit *resource.Quantity) string {
+	switch runtime {
+	case runtimeJVM:
+		// A percentage follows the limit when it changes; the rest is left
+		// to metaspace, thread stacks and direct buffers
+		return "JAVA_TOOL_OPTIONS=-XX:MaxRAMPercentage=75.0"
+	case runtimeNode:
+		if limit == nil || limit.IsZero() {
+			return ""
+		}
+		return fmt.Sprintf("NODE_OPTIONS=--max-old-space-size=%d", limit.Value()*3/4/(1024*1024))
+	case runtimeGo:
+		if limit == nil || limit.IsZero() {
+			return ""
+		}
+		return fmt.Sprintf("GOMEMLIMIT=%dMiB", limit.Value()*9/10/(1024*1024))
+	}
+	return ""
+}
+
+// runtimeRecommendation tunes the runtime of a container with a memory
+// issue, when its runtime is known
+func runtimeRecommendation(issue domain.Issue, target, namespace, container string) (domain.Recommendation, bool) {
+	current := issue.Details["runtime_memory_setting"]
+	suggested := issue.Details["suggested_runtime_setting"]
+	rec := domain.Recommendation{Priority: 2}
+	switch issue.Details["runtime"] {
+	case runtimeJVM:
+		rec.Title = "Size the JVM heap to the container"
+		rec.Description = "The JVM has no heap setting, so it sizes the heap by its own defaults, or from the host's memory on old versions; set -XX:MaxRAMPercentage=75.0 so heap, metaspace and thread stacks fit the limit"
+		if current != "" {
+			rec.Description = "The JVM runs with " + current + "; make sure the heap plus about 25% for metaspace, thread stacks and direct buffers fits the memory limit, or replace the fixed size with -XX:MaxRAMPercentage=75.0"
+		}
+	case runtimeNode:
+		rec.Title = "Cap the Node.js heap below the limit"
+		rec.Description = "Node.js may size its heap without regard to the container's limit, so it grows until the kernel kills it; cap it with --max-old-space-size at about 75% of the memory limit and it collects garbage first"
+		if current != "" {
+			rec.Description = "Node.js runs with " + current + "; keep it at about 75% of the memory limit, so the heap leaves room for buffers and native memory"
+		}
+	case runtimeGo:
+		rec.Title = "Set GOMEMLIMIT for the Go runtime"
+		rec.Description = "The Go garbage collector does not know the container's limit; GOMEMLIMIT at about 90% of it makes it collect harder before the kernel kills the process"
+		if current != "" {
+			rec.Description = "The Go runtime runs with " + current + "; keep it at about 90% of the memory limit"
+		}
+	case runtimePython:
+		return domain.Recommendation{
+			Priority:    2,
+			Title:       "Check how many Python processes share the memory limit",
+			Description: "Each gunicorn, uvicorn or celery worker is a process with its own copy of the application; lower the worker count, or recycle workers with --max-requests if memory grows over time",
+		}, true
+	default:
+		return domain.Recommendation{}, false
+	}
+	if suggested != "" {
+		rec.Command = "kubectl set env " + domain.ShellArg(target) + " -n " + domain.ShellArg(namespace) + " -c " + domain.ShellArg(container) + " " + domain.ShellArg(suggested)
+		if strings.HasPrefix(suggested, "JAVA_TOOL_OPTIONS=") || strings.HasPrefix(suggested, "NODE_OPTIONS=") {
+			// Keep the options the container already has
+			name, value, _ := strings.Cut(suggested, "=")
+			rec.Description += "; add " + value + " to " + name + " if it is already set, instead of replacing it"
+		}
+	}
+	return rec, true
+}
+
+// cpuQuotaRecommendation tells the runtime of a throttled container how many
+// CPUs its limit allows, when it does not know
+func cpuQuotaRecommendation(issue domain.Issue, target, namespace, container string) (domain.Recommendation, bool) {
+	suggested := issue.Details["suggested_cpu_setting"]
+	if suggested == "" {
+		return domain.Recommendation{}, false
+	}
+	rec := domain.Recommendation{
+		Priority: 1,
+		Command:  "kubectl set env " + domain.ShellArg(target) + " -n " + domain.ShellArg(namespace) + " -c " + domain.ShellArg(container) + " " + domain.ShellArg(suggested),
+	}
+	switch issue.Details["runtime"] {
+	case runtimeGo:
+		rec.Title = "Match GOMAXPROCS to the CPU limit"
+		rec.Description = "Before Go 1.25 the runtime sets GOMAXPROCS to the node's cores, not the container's CPU limit, so its threads use up the quota early in each period and then stall, adding latency; set GOMAXPROCS to the limit, or import go.uber.org/automaxprocs"
+	case runtimeJVM:
+		rec.Title = "Size the JVM's threads to the CPU limit"
+		rec.Description = "The JVM runs with -XX:-UseContainerSupport, so it sizes its garbage collector, JIT and common pool threads, and its heap, by the node instead of the container's limits; remove the option, or set -XX:ActiveProcessorCount to the CPU limit; add it to JAVA_TOOL_OPTIONS if that is already set, instead of replacing it"
+	default:
+		return domain.Recommendation{}, false
+	}
+	return rec, true
+}
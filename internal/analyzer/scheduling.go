@@ -0,0 +1,520 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Node rejection kinds, stored in the "rejection" issue detail
+const (
+	rejectionCordoned     = "cordoned"
+	rejectionNotReady     = "not_ready"
+	rejectionNodeSelector = "node_selector"
+	rejectionNodeAffinity = "node_affinity"
+	rejectionTaint        = "taint"
+	rejectionInsufficient = "insufficient"
+)
+
+// maxListedNodes bounds the node names listed in one issue
+const maxListedNodes = 10
+
+var (
+	// schedulerMessagePattern matches the scheduler's summary, e.g.
+	// "0/5 nodes are available: 3 Insufficient cpu, 2 node(s) had untolerated
+	// taint {dedicated: gpu}. preemption: ..."
+	schedulerMessagePattern = regexp.MustCompile(`(\d+)/(\d+) nodes are available: (.*?)\.(?:\s+preemption:|$)`)
+	// schedulerReasonPattern matches one "3 Insufficient cpu" entry
+	schedulerReasonPattern = regexp.MustCompile(`^(\d+) (.+)$`)
+)
+
+// schedulerReason is one entry of the scheduler's summary
+type schedulerReason struct {
+	Count  int
+	Reason string
+}
+
+// parseSchedulerMessage extracts the node count and per-reason node counts
+// from a FailedScheduling message
+func parseSchedulerMessage(message string) (total int, reasons []schedulerReason, ok bool) {
+	m := schedulerMessagePattern.FindStringSubmatch(message)
+	if m == nil {
+		return 0, nil, false
+	}
+	total, _ = strconv.Atoi(m[2])
+
+	for _, part := range strings.Split(m[3], ", ") {
+		rm := schedulerReasonPattern.FindStringSubmatch(strings.TrimSpace(part))
+		if rm == nil {
+			continue
+		}
+		count, _ := strconv.Atoi(rm[1])
+		reasons = append(reasons, schedulerReason{Count: count, Reason: rm[2]})
+	}
+	return total, reasons, true
+}
+
+// nodeRejection is one reason a node cannot run the pod
+type nodeRejection struct {
+	kind   string
+	key    string // groups equal rejections across nodes, e.g. one taint
+	reason string // for this node, e.g. "insufficient cpu (requests 2000m, free 500m)"
+}
+
+// SchedulingAnalyzer explains why a pending pod cannot be scheduled by
+// checking every node against the pod's selectors, affinity, tolerations
+// and resource requests
+type SchedulingAnalyzer struct{}
+
+// NewSchedulingAnalyzer creates a new SchedulingAnalyzer
+func NewSchedulingAnalyzer() *SchedulingAnalyzer {
+	return &SchedulingAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (s *SchedulingAnalyzer) Name() string {
+	return "scheduling"
+}
+
+// Analyze reports which nodes reject an unschedulable pod and why
+func (s *SchedulingAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var cond *corev1.PodCondition
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == corev1.PodScheduled && pod.Status.Conditions[i].Status == corev1.ConditionFalse {
+			cond = &pod.Status.Conditions[i]
+		}
+	}
+	if cond == nil {
+		return nil, nil
+	}
+
+	summary := domain.Issue{
+		Severity:    domain.SeverityCritical,
+		Category:    "scheduling",
+		Title:       "Pod cannot be scheduled",
+		Description: cond.Message,
+		Details: map[string]string{
+			"reason": cond.Reason,
+		},
+	}
+	if total, reasons, ok := parseSchedulerMessage(cond.Message); ok {
+		summary.Details["total_nodes"] = strconv.Itoa(total)
+		for _, r := range reasons {
+			summary.Details[r.Reason] = fmt.Sprintf("%d node(s)", r.Count)
+		}
+	}
+
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		// Without node access the scheduler's message is all there is;
+		// the error notes that the per-node explanation is missing
+		return []domain.Issue{summary}, err
+	}
+
+	groups := s.rejectionGroups(ctx, pod, nodes, client)
+
+	var kinds []string
+	issues := []domain.Issue{summary}
+	for _, g := range groups {
+		issues = append(issues, s.groupIssue(pod, g, len(nodes)))
+		if !containsString(kinds, g.kind) {
+			kinds = append(kinds, g.kind)
+		}
+	}
+	issues[0].Details["rejections"] = strings.Join(kinds, ",")
+
+	return issues, nil
+}
+
+// rejectionGroup collects the nodes rejecting the pod for the same reason
+type rejectionGroup struct {
+	nodeRejection
+	nodes   []string
+	reasons []string // per node, e.g. "node-a: missing label disktype=ssd"
+}
+
+// rejectionGroups evaluates every node and groups equal rejections, most
+// widespread first
+func (s *SchedulingAnalyzer) rejectionGroups(ctx context.Context, pod *corev1.Pod, nodes []corev1.Node, client *kubernetes.Client) []*rejectionGroup {
+	var groups []*rejectionGroup
+	byKey := make(map[string]*rejectionGroup)
+
+	// The pods of every node are listed once, and only when a node would
+	// otherwise fit, to sum up what they request
+	var podsByNode map[string][]corev1.Pod
+	var listErr error
+	listed := false
+
+	for i := range nodes {
+		node := &nodes[i]
+		rejections := rejectNode(pod, node)
+
+		if len(rejections) == 0 {
+			if !listed {
+				podsByNode, listErr = client.PodsByNode(ctx)
+				listed = true
+			}
+			if listErr == nil {
+				rejections = rejectForResources(pod, node, podsByNode[node.Name])
+			}
+		}
+
+		for _, r := range rejections {
+			g, ok := byKey[r.key]
+			if !ok {
+				g = &rejectionGroup{nodeRejection: r}
+				byKey[r.key] = g
+				groups = append(groups, g)
+			}
+			g.nodes = append(g.nodes, node.Name)
+			g.reasons = append(g.reasons, node.Name+": "+r.reason)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].nodes) > len(groups[j].nodes)
+	})
+	return groups
+}
+
+// groupIssue describes one rejection shared by a set of nodes
+func (s *SchedulingAnalyzer) groupIssue(pod *corev1.Pod, g *rejectionGroup, totalNodes int) domain.Issue {
+	// A rejection shared by every node blocks scheduling on its own
+	severity := domain.SeverityWarning
+	if len(g.nodes) == totalNodes {
+		severity = domain.SeverityCritical
+	}
+
+	count := fmt.Sprintf("%d of %d nodes", len(g.nodes), totalNodes)
+	details := map[string]string{
+		"rejection": g.kind,
+		"nodes":     listNodes(g.nodes),
+	}
+
+	var title string
+	switch g.kind {
+	case rejectionCordoned:
+		title = count + " are cordoned"
+	case rejectionNotReady:
+		title = count + " are not ready"
+	case rejectionNodeSelector:
+		title = "nodeSelector does not match " + count
+		details["selector"] = formatLabels(pod.Spec.NodeSelector)
+	case rejectionNodeAffinity:
+		title = "Required node affinity does not match " + count
+	case rejectionTaint:
+		taint := strings.TrimPrefix(g.key, rejectionTaint+":")
+		title = fmt.Sprintf("Untolerated taint %s on %s", taint, count)
+		details["taint"] = taint
+	case rejectionInsufficient:
+		name := strings.TrimPrefix(g.key, rejectionInsufficient+":")
+		title = fmt.Sprintf("Insufficient %s on %s", name, count)
+		details["resource"] = name
+		if requested, ok := podRequests(pod)[corev1.ResourceName(name)]; ok {
+			details["requested"] = requested.String()
+		}
+	}
+
+	reasons := g.reasons
+	if len(reasons) > maxListedNodes {
+		reasons = append(reasons[:maxListedNodes:maxListedNodes], fmt.Sprintf("and %d more", len(g.reasons)-maxListedNodes))
+	}
+
+	return domain.Issue{
+		Severity:    severity,
+		Category:    "scheduling",
+		Title:       title,
+		Description: strings.Join(reasons, "; "),
+		Details:     details,
+	}
+}
+
+// rejectNode checks a node's state, labels and taints against the pod
+func rejectNode(pod *corev1.Pod, node *corev1.Node) []nodeRejection {
+	var rejections []nodeRejection
+
+	// Cordoned and not ready nodes also carry node.kubernetes.io taints;
+	// those are reported by state rather than as taints
+	unavailable := false
+	if node.Spec.Unschedulable {
+		rejections = append(rejections, nodeRejection{rejectionCordoned, rejectionCordoned, "cordoned"})
+		unavailable = true
+	}
+	if !nodeReady(node) {
+		rejections = append(rejections, nodeRejection{rejectionNotReady, rejectionNotReady, "not ready"})
+		unavailable = true
+	}
+
+	var missing []string
+	for _, key := range sortedKeys(pod.Spec.NodeSelector) {
+		if value := pod.Spec.NodeSelector[key]; node.Labels[key] != value {
+			missing = append(missing, key+"="+value)
+		}
+	}
+	if len(missing) > 0 {
+		rejections = append(rejections, nodeRejection{rejectionNodeSelector, rejectionNodeSelector, "missing label " + strings.Join(missing, ", ")})
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil && !matchesNodeSelectorTerms(node, required.NodeSelectorTerms) {
+			rejections = append(rejections, nodeRejection{rejectionNodeAffinity, rejectionNodeAffinity, "required node affinity not satisfied"})
+		}
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		if unavailable && strings.HasPrefix(taint.Key, "node.kubernetes.io/") {
+			continue
+		}
+		if !toleratesTaint(pod.Spec.Tolerations, taint) {
+			text := formatTaint(taint)
+			rejections = append(rejections, nodeRejection{rejectionTaint, rejectionTaint + ":" + text, "untolerated taint " + text})
+		}
+	}
+
+	return rejections
+}
+
+// rejectForResources checks the pod's requests against what the node has
+// left after the requests of the pods already running there
+func rejectForResources(pod *corev1.Pod, node *corev1.Node, podsOnNode []corev1.Pod) []nodeRejection {
+	var rejections []nodeRejection
+
+	free := node.Status.Allocatable.DeepCopy()
+	active := 0
+	for i := range podsOnNode {
+		p := &podsOnNode[i]
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		active++
+		for name, q := range podRequests(p) {
+			if f, ok := free[name]; ok {
+				f.Sub(q)
+				free[name] = f
+			}
+		}
+	}
+
+	if allowed, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && int64(active) >= allowed.Value() {
+		rejections = append(rejections, nodeRejection{
+			rejectionInsufficient, rejectionInsufficient + ":pods",
+			fmt.Sprintf("too many pods (%d of %d)", active, allowed.Value()),
+		})
+	}
+
+	requests := podRequests(pod)
+	for _, name := range sortedResourceNames(requests) {
+		requested := requests[name]
+		if requested.IsZero() {
+			continue
+		}
+		available, ok := free[name]
+		if !ok {
+			available = resource.Quantity{}
+		}
+		if requested.Cmp(available) > 0 {
+			rejections = append(rejections, nodeRejection{
+				rejectionInsufficient, rejectionInsufficient + ":" + string(name),
+				fmt.Sprintf("insufficient %s (requests %s, free %s)", name, formatQuantity(name, requested), formatQuantity(name, available)),
+			})
+		}
+	}
+
+	return rejections
+}
+
+// podRequests returns the resources the scheduler reserves for a pod: the
+// sum of its containers and sidecars, or its largest init container if
+// that is more, plus the pod overhead
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	add := func(list corev1.ResourceList) {
+		for name, q := range list {
+			total := requests[name]
+			total.Add(q)
+			requests[name] = total
+		}
+	}
+
+	for _, c := range pod.Spec.Containers {
+		add(c.Resources.Requests)
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			add(c.Resources.Requests)
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			continue
+		}
+		for name, q := range c.Resources.Requests {
+			if total := requests[name]; q.Cmp(total) > 0 {
+				requests[name] = q.DeepCopy()
+			}
+		}
+	}
+	add(pod.Spec.Overhead)
+
+	return requests
+}
+
+// matchesNodeSelectorTerms reports whether a node satisfies any of the terms
+func matchesNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		matches := true
+		for _, req := range term.MatchExpressions {
+			value, exists := node.Labels[req.Key]
+			if !matchesRequirement(req, value, exists) {
+				matches = false
+			}
+		}
+		for _, req := range term.MatchFields {
+			if req.Key != "metadata.name" || !matchesRequirement(req, node.Name, true) {
+				matches = false
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRequirement evaluates one node selector requirement against a
+// label value
+func matchesRequirement(req corev1.NodeSelectorRequirement, value string, exists bool) bool {
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return exists && containsString(req.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !exists || !containsString(req.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !exists
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !exists || len(req.Values) != 1 {
+			return false
+		}
+		actual, err1 := strconv.ParseInt(value, 10, 64)
+		bound, err2 := strconv.ParseInt(req.Values[0], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return actual > bound
+		}
+		return actual < bound
+	}
+	return false
+}
+
+// toleratesTaint reports whether any of the tolerations matches the taint
+func toleratesTaint(tolerations []corev1.Toleration, taint corev1.Taint) bool {
+	for _, t := range tolerations {
+		if t.Effect != "" && t.Effect != taint.Effect {
+			continue
+		}
+		if t.Key != "" && t.Key != taint.Key {
+			continue
+		}
+		switch t.Operator {
+		case corev1.TolerationOpExists:
+			return true
+		case "", corev1.TolerationOpEqual:
+			if t.Key != "" && t.Value == taint.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeReady reports whether the node's Ready condition is true
+func nodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// formatTaint renders a taint as key=value:Effect
+func formatTaint(taint corev1.Taint) string {
+	if taint.Value == "" {
+		return taint.Key + ":" + string(taint.Effect)
+	}
+	return taint.Key + "=" + taint.Value + ":" + string(taint.Effect)
+}
+
+// formatQuantity renders CPU in millicores and memory in mebibytes, and
+// other resources as they are
+func formatQuantity(name corev1.ResourceName, q resource.Quantity) string {
+	switch name {
+	case corev1.ResourceCPU:
+		return formatCPU(&q)
+	case corev1.ResourceMemory:
+		return formatMemory(&q)
+	}
+	return q.String()
+}
+
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for _, key := range sortedKeys(labels) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// listNodes joins node names, naming at most maxListedNodes
+func listNodes(names []string) string {
+	if len(names) > maxListedNodes {
+		return strings.Join(names[:maxListedNodes], ", ") + fmt.Sprintf(" and %d more", len(names)-maxListedNodes)
+	}
+	return strings.Join(names, ", ")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
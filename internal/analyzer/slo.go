@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SLO indicator names, used as issue details and breach keys
+const (
+	sloRestartRate    = "restart_rate"
+	sloReadinessFlaps = "readiness_flaps"
+	sloCrashLoopTime  = "crashloop_time"
+)
+
+// SLOThresholds configures when lifecycle indicators count as breached.
+// A zero threshold disables that indicator.
+type SLOThresholds struct {
+	RestartsPerHour      int
+	ReadinessFlapsPerDay int
+	MaxCrashLoop         time.Duration
+}
+
+// DefaultSLOThresholds returns the thresholds used when none are configured
+func DefaultSLOThresholds() SLOThresholds {
+	return SLOThresholds{
+		RestartsPerHour:      5,
+		ReadinessFlapsPerDay: 10,
+		MaxCrashLoop:         10 * time.Minute,
+	}
+}
+
+// SLOTracker follows pod status updates over time and tracks per-workload
+// lifecycle indicators: container restarts per hour, readiness flaps per
+// day and time spent in CrashLoopBackOff. It is meant for long-running
+// modes that see pods repeatedly, such as scan --watch and serve.
+type SLOTracker struct {
+	thresholds SLOThresholds
+
+	mu        sync.Mutex
+	pods      map[string]*podSLOState
+	workloads map[string]*workloadSLOState
+}
+
+// podSLOState is the last observed state of one pod
+type podSLOState struct {
+	workload       string
+	restarts       int32
+	ready          bool
+	crashLoopSince time.Time
+}
+
+// workloadSLOState holds the indicator history of one workload
+type workloadSLOState struct {
+	restarts []time.Time
+	flaps    []time.Time
+	breached map[string]bool
+}
+
+// NewSLOTracker creates a tracker with the given thresholds
+func NewSLOTracker(thresholds SLOThresholds) *SLOTracker {
+	return &SLOTracker{
+		thresholds: thresholds,
+		pods:       make(map[string]*podSLOState),
+		workloads:  make(map[string]*workloadSLOState),
+	}
+}
+
+// Observe records a pod update and returns issues for indicators of the
+// pod's workload that newly crossed their threshold. An indicator is only
+// reported again after it has recovered.
+func (t *SLOTracker) Observe(pod *corev1.Pod) []domain.Issue {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	key := pod.Namespace + "/" + pod.Name
+	workload := sloWorkloadKey(pod)
+
+	ws, ok := t.workloads[workload]
+	if !ok {
+		ws = &workloadSLOState{breached: make(map[string]bool)}
+		t.workloads[workload] = ws
+	}
+
+	restarts, crashLooping := int32(0), false
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			crashLooping = true
+		}
+	}
+	ready := isPodReady(pod)
+
+	ps, seen := t.pods[key]
+	if !seen {
+		// The first observation sets the baseline; restarts that happened
+		// before the tracker started are not attributed to this window
+		ps = &podSLOState{workload: workload, restarts: restarts, ready: ready}
+		t.pods[key] = ps
+	} else {
+		for i := ps.restarts; i < restarts; i++ {
+			ws.restarts = append(ws.restarts, now)
+		}
+		if ps.ready && !ready {
+			ws.flaps = append(ws.flaps, now)
+		}
+		ps.restarts, ps.ready = restarts, ready
+	}
+	switch {
+	case crashLooping && ps.crashLoopSince.IsZero():
+		ps.crashLoopSince = now
+	case !crashLooping:
+		ps.crashLoopSince = time.Time{}
+	}
+
+	return t.evaluate(pod.Namespace, workload, ws, now)
+}
+
+// Forget drops a deleted pod. Its restarts and flaps stay in the workload's
+// history until they age out of the window.
+func (t *SLOTracker) Forget(namespace, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pods, namespace+"/"+name)
+}
+
+// Evaluate re-checks every workload and returns newly breached indicators.
+// Call it periodically so time-based indicators such as time in
+// CrashLoopBackOff are noticed between pod updates. Workloads whose
+// history has aged out of the window and that breach nothing are dropped,
+// so deleted workloads do not accumulate.
+func (t *SLOTracker) Evaluate() []domain.Issue {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var issues []domain.Issue
+	for workload, ws := range t.workloads {
+		namespace, _, _ := strings.Cut(workload, "/")
+		issues = append(issues, t.evaluate(namespace, workload, ws, now)...)
+		if len(ws.restarts) == 0 && len(ws.flaps) == 0 && len(ws.breached) == 0 {
+			// A pod observed again starts the workload afresh, which is
+			// what this empty state amounts to
+			delete(t.workloads, workload)
+		}
+	}
+	return issues
+}
+
+// Breaches returns the issues for all indicators currently breached
+func (t *SLOTracker) Breaches() []domain.Issue {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var issues []domain.Issue
+	for workload, ws := range t.workloads {
+		namespace, _, _ := strings.Cut(workload, "/")
+		for _, issue := range t.indicators(namespace, workload, ws, now) {
+			if ws.breached[issue.Details["indicator"]] {
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
+
+// evaluate updates breach state for a workload and returns newly breached indicators
+func (t *SLOTracker) evaluate(namespace, workload string, ws *workloadSLOState, now time.Time) []domain.Issue {
+	ws.restarts = pruneBefore(ws.restarts, now.Add(-time.Hour))
+	ws.flaps = pruneBefore(ws.flaps, now.Add(-24*time.Hour))
+
+	breachedNow := make(map[string]bool)
+	var fresh []domain.Issue
+	for _, issue := range t.indicators(namespace, workload, ws, now) {
+		indicator := issue.Details["indicator"]
+		breachedNow[indicator] = true
+		if !ws.breached[indicator] {
+			fresh = append(fresh, issue)
+		}
+	}
+	ws.breached = breachedNow
+	return fresh
+}
+
+// indicators returns an issue for every indicator of a workload over its threshold
+func (t *SLOTracker) indicators(namespace, workload string, ws *workloadSLOState, now time.Time) []domain.Issue {
+	var issues []domain.Issue
+	name := strings.TrimPrefix(workload, namespace+"/")
+
+	if limit := t.thresholds.RestartsPerHour; limit > 0 && len(ws.restarts) > limit {
+		issues = append(issues, sloIssue(domain.SeverityWarning, sloRestartRate, namespace, name,
+			fmt.Sprintf("SLO breached: restart rate of %s", name),
+			"Containers of this workload restart more often than the configured budget",
+			fmt.Sprintf("%d restarts in the last hour", len(ws.restarts)),
+			fmt.Sprintf("%d/hour", limit)))
+	}
+
+	if limit := t.thresholds.ReadinessFlapsPerDay; limit > 0 && len(ws.flaps) > limit {
+		issues = append(issues, sloIssue(domain.SeverityWarning, sloReadinessFlaps, namespace, name,
+			fmt.Sprintf("SLO breached: readiness flaps of %s", name),
+			"Pods of this workload keep dropping out of service endpoints",
+			fmt.Sprintf("%d flaps in the last 24h", len(ws.flaps)),
+			fmt.Sprintf("%d/day", limit)))
+	}
+
+	if limit := t.thresholds.MaxCrashLoop; limit > 0 {
+		var longest time.Duration
+		for _, ps := range t.pods {
+			if ps.workload == workload && !ps.crashLoopSince.IsZero() {
+				if d := now.Sub(ps.crashLoopSince); d > longest {
+					longest = d
+				}
+			}
+		}
+		if longest > limit {
+			issues = append(issues, sloIssue(domain.SeverityCritical, sloCrashLoopTime, namespace, name,
+				fmt.Sprintf("SLO breached: time in CrashLoopBackOff for %s", name),
+				"A pod of this workload has been crash looping longer than the configured budget",
+				longest.Round(time.Second).String(),
+				limit.String()))
+		}
+	}
+
+	return issues
+}
+
+// sloIssue builds the issue reported for a breached indicator
+func sloIssue(severity domain.Severity, indicator, namespace, workload, title, description, value, threshold string) domain.Issue {
+	return domain.Issue{
+		Severity:    severity,
+		Category:    "slo",
+		Title:       title,
+		Description: description,
+		Details: map[string]string{
+			"indicator": indicator,
+			"namespace": namespace,
+			"workload":  workload,
+			"value":     value,
+			"threshold": threshold,
+		},
+	}
+}
+
+// pruneBefore drops timestamps older than cutoff from a sorted slice
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
+
+// sloWorkloadKey identifies the workload a pod belongs to from its owner
+// references alone, so tracking needs no extra API calls per update. Pods of
+// a Deployment are grouped across ReplicaSets by stripping the template hash.
+func sloWorkloadKey(pod *corev1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return pod.Namespace + "/pod/" + pod.Name
+	}
+	if ref.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(ref.Name, "-"+hash) {
+			return pod.Namespace + "/deployment/" + strings.TrimSuffix(ref.Name, "-"+hash)
+		}
+	}
+	return pod.Namespace + "/" + strings.ToLower(ref.Kind) + "/" + ref.Name
+}
@@ -0,0 +1,413 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// maxEnvVarBytes is the kernel's limit on a single environment string
+	// (MAX_ARG_STRLEN); exec fails with "argument list too long" past it
+	maxEnvVarBytes = 128 * 1024
+	// maxEnvBytes is what Linux leaves for a process's arguments and
+	// environment together with the default 8MiB stack (ARG_MAX)
+	maxEnvBytes = 2 * 1024 * 1024
+	// manyEnvVars is the number of environment variables past which
+	// container creation slows down and the environment is hard to audit
+	manyEnvVars = 1000
+	// maxAnnotationBytes is the API server's limit on the total size of an
+	// object's annotations; updates past it are rejected
+	maxAnnotationBytes = 256 * 1024
+	// maxObjectBytes is etcd's default limit on a request, and so on the
+	// size of a stored object
+	maxObjectBytes = 1536 * 1024
+	// nearLimit is the share of a limit at which a size is reported
+	nearLimit = 0.8
+)
+
+// envFootprint sums up the environment a container starts with
+type envFootprint struct {
+	vars         int
+	bytes        int
+	largest      string // name of the largest variable
+	largestBytes int
+	configBytes  int      // bytes from ConfigMaps and Secrets
+	serviceLinks int      // variables injected for the namespace's services
+	unknown      []string // ConfigMaps and Secrets that could not be read
+}
+
+// add counts one variable as NAME=value plus the terminating NUL
+func (f *envFootprint) add(name string, valueBytes int) int {
+	size := len(name) + 1 + valueBytes + 1
+	f.vars++
+	f.bytes += size
+	if size > f.largestBytes {
+		f.largest, f.largestBytes = name, size
+	}
+	return size
+}
+
+// SpecSizeAnalyzer flags pods whose spec is large enough to fail in ways
+// nobody thinks to check: environments that exec rejects or that slow
+// container creation, and annotations or whole objects close to what the
+// API server and etcd accept
+type SpecSizeAnalyzer struct{}
+
+// NewSpecSizeAnalyzer creates a new SpecSizeAnalyzer
+func NewSpecSizeAnalyzer() *SpecSizeAnalyzer {
+	return &SpecSizeAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (s *SpecSizeAnalyzer) Name() string {
+	return "specsize"
+}
+
+// Analyze sizes the environment of each container, the pod's annotations
+// and the pod object
+func (s *SpecSizeAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	sources := newEnvSources(ctx, pod.Namespace, client)
+	links := serviceLinkFootprint(ctx, pod, client)
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		issues = append(issues, envIssues(c.Name, containerEnv(c, sources, links))...)
+	}
+
+	if size, largest := annotationBytes(pod.Annotations); float64(size) >= nearLimit*maxAnnotationBytes {
+		issues = append(issues, domain.NewIssue(
+			domain.SeverityWarning,
+			"spec",
+			"Pod annotations near size limit",
+			fmt.Sprintf("Annotations take %s of the %s the API server allows; once they pass it, every update of the pod, including status and label changes, is rejected", formatSize(size), formatSize(maxAnnotationBytes)),
+		).WithDetail("annotation_bytes", strconv.Itoa(size)).
+			WithDetail("limit_bytes", strconv.Itoa(maxAnnotationBytes)).
+			WithDetail("largest_annotation", largest))
+	}
+
+	if data, err := json.Marshal(pod); err == nil && float64(len(data)) >= nearLimit*maxObjectBytes {
+		issues = append(issues, domain.NewIssue(
+			domain.SeverityWarning,
+			"spec",
+			"Pod object near etcd size limit",
+			fmt.Sprintf("The pod is %s, close to the %s etcd stores by default; pods of the workload may fail to be created, and status updates may fail, as the object grows", formatSize(len(data)), formatSize(maxObjectBytes)),
+		).WithDetail("object_bytes", strconv.Itoa(len(data))).
+			WithDetail("limit_bytes", strconv.Itoa(maxObjectBytes)))
+	}
+
+	return issues, nil
+}
+
+// envIssues reports a container environment that exec rejects, or that is
+// close to it or very large
+func envIssues(container string, env envFootprint) []domain.Issue {
+	var issues []domain.Issue
+	withDetails := func(issue domain.Issue) domain.Issue {
+		issue = issue.WithDetail("container", container).
+			WithDetail("env_vars", strconv.Itoa(env.vars)).
+			WithDetail("env_bytes", strconv.Itoa(env.bytes))
+		if env.configBytes > 0 {
+			issue = issue.WithDetail("configmap_secret_bytes", strconv.Itoa(env.configBytes))
+		}
+		if env.serviceLinks > 0 {
+			issue = issue.WithDetail("service_link_vars", strconv.Itoa(env.serviceLinks))
+		}
+		if len(env.unknown) > 0 {
+			issue = issue.WithDetail("unread_sources", strings.Join(env.unknown, ","))
+		}
+		return issue
+	}
+
+	if env.largestBytes > maxEnvVarBytes {
+		issues = append(issues, withDetails(domain.NewIssue(
+			domain.SeverityCritical,
+			"spec",
+			fmt.Sprintf("Env var %s too large for %s", env.largest, container),
+			fmt.Sprintf("%s is %s, more than the %s the kernel allows for one variable; starting the process fails with \"argument list too long\"", env.largest, formatSize(env.largestBytes), formatSize(maxEnvVarBytes)),
+		).WithDetail("variable", env.largest)))
+	}
+
+	switch {
+	case env.bytes > maxEnvBytes:
+		issues = append(issues, withDetails(domain.NewIssue(
+			domain.SeverityCritical,
+			"spec",
+			fmt.Sprintf("Environment too large for %s", container),
+			fmt.Sprintf("%d variables take %s, more than the %s Linux leaves for arguments and environment; starting the process fails with \"argument list too long\"%s", env.vars, formatSize(env.bytes), formatSize(maxEnvBytes), env.sourceNote()),
+		)))
+	case float64(env.bytes) >= nearLimit*maxEnvBytes:
+		issues = append(issues, withDetails(domain.NewIssue(
+			domain.SeverityWarning,
+			"spec",
+			fmt.Sprintf("Environment near size limit for %s", container),
+			fmt.Sprintf("%d variables take %s of the %s Linux leaves for arguments and environment; a larger ConfigMap or a few more services will stop the process from starting%s", env.vars, formatSize(env.bytes), formatSize(maxEnvBytes), env.sourceNote()),
+		)))
+	case env.vars > manyEnvVars:
+		issues = append(issues, withDetails(domain.NewIssue(
+			domain.SeverityWarning,
+			"spec",
+			fmt.Sprintf("Too many environment variables in %s", container),
+			fmt.Sprintf("%s starts with %d environment variables, which slows container creation and makes its configuration hard to audit%s", container, env.vars, env.sourceNote()),
+		)))
+	}
+	return issues
+}
+
+// sourceNote names the biggest contributor to an environment, for issue
+// descriptions
+func (f envFootprint) sourceNote() string {
+	switch {
+	case f.serviceLinks > f.vars/2:
+		return fmt.Sprintf("; %d of them are service links for the namespace's services", f.serviceLinks)
+	case f.configBytes > f.bytes/2:
+		return fmt.Sprintf("; %s come from ConfigMaps and Secrets", formatSize(f.configBytes))
+	}
+	return ""
+}
+
+// envSources reads the ConfigMaps and Secrets environments refer to, each
+// once
+type envSources struct {
+	ctx        context.Context
+	namespace  string
+	client     *kubernetes.Client
+	configMaps map[string]map[string]int // key sizes by ConfigMap, nil if unreadable
+	secrets    map[string]map[string]int
+}
+
+func newEnvSources(ctx context.Context, namespace string, client *kubernetes.Client) *envSources {
+	return &envSources{
+		ctx:        ctx,
+		namespace:  namespace,
+		client:     client,
+		configMaps: make(map[string]map[string]int),
+		secrets:    make(map[string]map[string]int),
+	}
+}
+
+// configMap returns the size of each key of a ConfigMap, or nil if it
+// cannot be read
+func (s *envSources) configMap(name string) map[string]int {
+	sizes, ok := s.configMaps[name]
+	if !ok {
+		if cm, err := s.client.GetConfigMap(s.ctx, s.namespace, name); err == nil {
+			sizes = make(map[string]int, len(cm.Data))
+			for k, v := range cm.Data {
+				sizes[k] = len(v)
+			}
+		}
+		s.configMaps[name] = sizes
+	}
+	return sizes
+}
+
+// secret returns the size of each key of a Secret, or nil if it cannot be
+// read
+func (s *envSources) secret(name string) map[string]int {
+	sizes, ok := s.secrets[name]
+	if !ok {
+		if secret, err := s.client.GetSecret(s.ctx, s.namespace, name); err == nil {
+			sizes = make(map[string]int, len(secret.Data))
+			for k, v := range secret.Data {
+				sizes[k] = len(v)
+			}
+		}
+		s.secrets[name] = sizes
+	}
+	return sizes
+}
+
+// containerEnv sizes the environment a container starts with: its env and
+// envFrom entries, resolved against ConfigMaps and Secrets, plus the
+// service links injected into every container
+func containerEnv(c corev1.Container, sources *envSources, links envFootprint) envFootprint {
+	env := links
+	unknown := make(map[string]bool)
+	fromSource := func(kind, name string, sizes map[string]int) bool {
+		if sizes == nil {
+			unknown[kind+"/"+name] = true
+			return false
+		}
+		return true
+	}
+
+	for _, from := range c.EnvFrom {
+		var kind, name string
+		var sizes map[string]int
+		switch {
+		case from.ConfigMapRef != nil:
+			kind, name = "configmap", from.ConfigMapRef.Name
+			sizes = sources.configMap(name)
+		case from.SecretRef != nil:
+			kind, name = "secret", from.SecretRef.Name
+			sizes = sources.secret(name)
+		default:
+			continue
+		}
+		if !fromSource(kind, name, sizes) {
+			continue
+		}
+		for key, size := range sizes {
+			env.configBytes += env.add(from.Prefix+key, size)
+		}
+	}
+
+	for _, e := range c.Env {
+		switch {
+		case e.ValueFrom == nil:
+			env.add(e.Name, len(e.Value))
+		case e.ValueFrom.ConfigMapKeyRef != nil:
+			ref := e.ValueFrom.ConfigMapKeyRef
+			if sizes := sources.configMap(ref.Name); fromSource("configmap", ref.Name, sizes) {
+				env.configBytes += env.add(e.Name, sizes[ref.Key])
+			} else {
+				env.add(e.Name, 0)
+			}
+		case e.ValueFrom.SecretKeyRef != nil:
+			ref := e.ValueFrom.SecretKeyRef
+			if sizes := sources.secret(ref.Name); fromSource("secret", ref.Name, sizes) {
+				env.configBytes += env.add(e.Name, sizes[ref.Key])
+			} else {
+				env.add(e.Name, 0)
+			}
+		default:
+			// Field and resource references resolve to short values
+			env.add(e.Name, 32)
+		}
+	}
+
+	for source := range unknown {
+		env.unknown = append(env.unknown, source)
+	}
+	sort.Strings(env.unknown)
+	return env
+}
+
+// serviceLinkFootprint sizes the Docker-style link variables the kubelet
+// injects for every service in the pod's namespace unless
+// enableServiceLinks is false, e.g. WEB_SERVICE_HOST and WEB_PORT_80_TCP.
+// In namespaces with hundreds of services they dwarf the pod's own
+// environment.
+func serviceLinkFootprint(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) envFootprint {
+	var links envFootprint
+	if pod.Spec.EnableServiceLinks != nil && !*pod.Spec.EnableServiceLinks {
+		return links
+	}
+	services, err := client.ListServices(ctx, pod.Namespace)
+	if err != nil {
+		return links
+	}
+	for _, svc := range services.Items {
+		for name, value := range serviceLinkEnv(svc) {
+			links.add(name, len(value))
+		}
+	}
+	links.serviceLinks = links.vars
+	return links
+}
+
+// serviceLinkEnv returns the link variables the kubelet injects for a
+// service
+func serviceLinkEnv(svc corev1.Service) map[string]string {
+	ip := svc.Spec.ClusterIP
+	if ip == "" || ip == corev1.ClusterIPNone || len(svc.Spec.Ports) == 0 {
+		return nil
+	}
+	prefix := strings.ToUpper(strings.ReplaceAll(svc.Name, "-", "_"))
+	first := svc.Spec.Ports[0]
+	env := map[string]string{
+		prefix + "_SERVICE_HOST": ip,
+		prefix + "_SERVICE_PORT": strconv.Itoa(int(first.Port)),
+		prefix + "_PORT":         fmt.Sprintf("%s://%s:%d", strings.ToLower(string(first.Protocol)), ip, first.Port),
+	}
+	for _, port := range svc.Spec.Ports {
+		if port.Name != "" {
+			env[prefix+"_SERVICE_PORT_"+strings.ToUpper(strings.ReplaceAll(port.Name, "-", "_"))] = strconv.Itoa(int(port.Port))
+		}
+		protocol := strings.ToLower(string(port.Protocol))
+		base := fmt.Sprintf("%s_PORT_%d_%s", prefix, port.Port, strings.ToUpper(protocol))
+		env[base] = fmt.Sprintf("%s://%s:%d", protocol, ip, port.Port)
+		env[base+"_PROTO"] = protocol
+		env[base+"_PORT"] = strconv.Itoa(int(port.Port))
+		env[base+"_ADDR"] = ip
+	}
+	return env
+}
+
+// annotationBytes returns the total size of annotations as the API server
+// counts it, and the key of the largest one
+func annotationBytes(annotations map[string]string) (int, string) {
+	total, largest, largestSize := 0, "", 0
+	for k, v := range annotations {
+		size := len(k) + len(v)
+		total += size
+		if size > largestSize {
+			largest, largestSize = k, size
+		}
+	}
+	return total, largest
+}
+
+// formatSize formats a byte count for issue descriptions, e.g. 1.5MiB
+func formatSize(bytes int) string {
+	switch {
+	case bytes >= 1024*1024:
+		return strconv.FormatFloat(float64(bytes)/(1024*1024), 'f', 1, 64) + "MiB"
+	case bytes >= 1024:
+		return strconv.FormatFloat(float64(bytes)/1024, 'f', 1, 64) + "KiB"
+	}
+	return strconv.Itoa(bytes) + "B"
+}
+
+// specSizeRecommendations suggests how to shrink an oversized pod spec
+func specSizeRecommendations(issue domain.Issue, pod domain.PodInfo, workload *domain.WorkloadInfo) []domain.Recommendation {
+	where := "the pod spec"
+	if workload != nil {
+		where = "the pod template of " + workload.Ref()
+	}
+
+	switch {
+	case issue.Details["largest_annotation"] != "":
+		return []domain.Recommendation{{
+			Priority:    2,
+			Title:       "Shrink pod annotations",
+			Description: "Annotation " + issue.Details["largest_annotation"] + " is the largest; move large payloads into a ConfigMap, and apply with kubectl apply --server-side so no last-applied-configuration copy is kept",
+			Command:     "kubectl get pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -o json | jq '.metadata.annotations | map_values(length)'",
+		}}
+
+	case issue.Details["object_bytes"] != "":
+		return []domain.Recommendation{{
+			Priority:    2,
+			Title:       "Shrink the pod spec",
+			Description: "Move inline configuration, such as large env values and annotations, from " + where + " into ConfigMaps mounted as files",
+		}}
+
+	case issue.Details["env_bytes"] != "":
+		var recs []domain.Recommendation
+		links, _ := strconv.Atoi(issue.Details["service_link_vars"])
+		vars, _ := strconv.Atoi(issue.Details["env_vars"])
+		if links > vars/2 {
+			recs = append(recs, domain.Recommendation{
+				Priority:    1,
+				Title:       "Disable service links",
+				Description: "Set enableServiceLinks: false in " + where + "; applications find services through DNS, and the link variables for every service in the namespace are what fills the environment",
+			})
+		}
+		recs = append(recs, domain.Recommendation{
+			Priority:    1,
+			Title:       "Mount large configuration as files",
+			Description: "Replace envFrom and large env values in " + where + " with a ConfigMap or Secret volume, which has no size limit per variable and none on the environment",
+		})
+		return recs
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StartDependenciesAnnotation declares which containers a container needs
+// running before it starts, e.g. "app=istio-proxy,cloud-sql-proxy;worker=app".
+// The app containers depend on the pod's native sidecars without it.
+const StartDependenciesAnnotation = "pod-doctor.io/start-dependencies"
+
+// StartOrderAnalyzer checks that the containers of a multi-container pod
+// started after the containers they depend on, a common cause of crashes
+// right after startup, e.g. an app connecting through a proxy that is not
+// up yet
+type StartOrderAnalyzer struct{}
+
+// NewStartOrderAnalyzer creates a new StartOrderAnalyzer
+func NewStartOrderAnalyzer() *StartOrderAnalyzer {
+	return &StartOrderAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (a *StartOrderAnalyzer) Name() string {
+	return "startorder"
+}
+
+// Analyze reports containers that started before a dependency, or may
+// have because a native sidecar has no startup probe, and dependencies
+// declared on containers the pod does not have
+func (a *StartOrderAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	order := startOrder(pod)
+	byName := make(map[string]domain.ContainerStart, len(order))
+	for _, c := range order {
+		byName[c.Name] = c
+	}
+	specs := containerSpecs(pod)
+
+	var issues []domain.Issue
+	for _, c := range order {
+		for _, dep := range c.DependsOn {
+			d, ok := byName[dep]
+			if !ok {
+				issues = append(issues, domain.NewIssue(domain.SeverityWarning, "container",
+					fmt.Sprintf("Start dependency %s of %s is not a container of the pod", dep, c.Name),
+					fmt.Sprintf("The %s annotation names a container the pod does not have; check it for typos", StartDependenciesAnnotation),
+				).WithDetail("container", c.Name).WithDetail("dependency", dep))
+				continue
+			}
+			if issue, ok := startedEarly(c, d, specs[dep]); ok {
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// startedEarly checks one dependency of a container. A native sidecar
+// without a startup probe counts as started as soon as its process runs, so
+// the app may reach it before it is ready; a declared dependency among the
+// app containers is started in parallel unless its postStart hook holds the
+// next containers back.
+func startedEarly(c, dep domain.ContainerStart, depSpec corev1.Container) (domain.Issue, bool) {
+	if c.StartedAt.IsZero() || dep.StartedAt.IsZero() {
+		return domain.Issue{}, false
+	}
+
+	var title, description string
+	switch {
+	case c.StartedAt.Before(dep.StartedAt) || (dep.Kind == "app" && !c.StartedAt.After(dep.StartedAt) && depSpec.Lifecycle == nil):
+		title = fmt.Sprintf("Container %s started before its dependency %s", c.Name, dep.Name)
+		description = fmt.Sprintf("%s started at %s, %s at %s, so %s may have found it unavailable",
+			c.Name, c.StartedAt.Format("15:04:05"), dep.Name, dep.StartedAt.Format("15:04:05"), c.Name)
+	case dep.Kind == "sidecar" && depSpec.StartupProbe == nil && c.Restarts > 0:
+		title = fmt.Sprintf("Container %s may start before sidecar %s is ready", c.Name, dep.Name)
+		description = fmt.Sprintf("Without a startup probe, %s counts as started once its process runs, so %s starts without waiting for it to be ready", dep.Name, c.Name)
+	default:
+		return domain.Issue{}, false
+	}
+
+	// Starting early only matters if it hurt; a container that never
+	// restarted coped with it
+	severity := domain.SeverityInfo
+	if c.Restarts > 0 {
+		severity = domain.SeverityWarning
+		description += fmt.Sprintf("; it has restarted %d times, which a race at startup would explain", c.Restarts)
+	}
+	return domain.NewIssue(severity, "container", title, description).
+		WithDetail("container", c.Name).
+		WithDetail("dependency", dep.Name).
+		WithDetail("dependency_kind", dep.Kind).
+		WithDetail("started_at", c.StartedAt.Format(time.RFC3339)).
+		WithDetail("dependency_started_at", dep.StartedAt.Format(time.RFC3339)), true
+}
+
+// startOrder returns the containers of a pod in start order, with when each
+// started and what it depends on, or nil for a single-container pod
+func startOrder(pod *corev1.Pod) []domain.ContainerStart {
+	if len(pod.Spec.InitContainers)+len(pod.Spec.Containers) < 2 {
+		return nil
+	}
+	declared := startDependencies(pod)
+
+	var order []domain.ContainerStart
+	var sidecars []string
+	for _, c := range pod.Spec.InitContainers {
+		start := containerStart(c.Name, "init", findStatus(pod.Status.InitContainerStatuses, c.Name))
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			start.Kind = "sidecar"
+			start.FinishedAt = time.Time{}
+			// A sidecar only waits for the sidecars before it
+			start.DependsOn = append([]string(nil), sidecars...)
+			sidecars = append(sidecars, c.Name)
+		}
+		start.DependsOn = appendMissing(start.DependsOn, declared[c.Name]...)
+		order = append(order, start)
+	}
+	for _, c := range pod.Spec.Containers {
+		start := containerStart(c.Name, "app", findStatus(pod.Status.ContainerStatuses, c.Name))
+		start.DependsOn = appendMissing(append([]string(nil), sidecars...), declared[c.Name]...)
+		order = append(order, start)
+	}
+	return order
+}
+
+// containerStart describes a container from its status, if reported
+func containerStart(name, kind string, status *corev1.ContainerStatus) domain.ContainerStart {
+	start := domain.ContainerStart{Name: name, Kind: kind}
+	if status == nil {
+		return start
+	}
+	start.Ready = status.Ready
+	start.Restarts = status.RestartCount
+	switch {
+	case status.State.Running != nil:
+		start.StartedAt = status.State.Running.StartedAt.Time
+	case status.State.Terminated != nil:
+		start.StartedAt = status.State.Terminated.StartedAt.Time
+		if status.State.Terminated.ExitCode == 0 {
+			start.FinishedAt = status.State.Terminated.FinishedAt.Time
+		}
+	}
+	// The previous run, if still known, is closer to the pod's start
+	if last := status.LastTerminationState.Terminated; last != nil && !last.StartedAt.IsZero() &&
+		(start.StartedAt.IsZero() || last.StartedAt.Time.Before(start.StartedAt)) {
+		start.StartedAt = last.StartedAt.Time
+	}
+	return start
+}
+
+// startDependencies parses the pod's start dependencies annotation into
+// the containers each container depends on
+func startDependencies(pod *corev1.Pod) map[string][]string {
+	deps := make(map[string][]string)
+	for _, entry := range strings.Split(pod.Annotations[StartDependenciesAnnotation], ";") {
+		name, list, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		for _, dep := range strings.Split(list, ",") {
+			if dep = strings.TrimSpace(dep); dep != "" && dep != name {
+				deps[name] = appendMissing(deps[name], dep)
+			}
+		}
+	}
+	return deps
+}
+
+// containerSpecs returns the init and app containers of a pod by name
+func containerSpecs(pod *corev1.Pod) map[string]corev1.Container {
+	specs := make(map[string]corev1.Container, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, c := range pod.Spec.InitContainers {
+		specs[c.Name] = c
+	}
+	for _, c := range pod.Spec.Containers {
+		specs[c.Name] = c
+	}
+	return specs
+}
+
+// findStatus returns the status of the named container, or nil
+func findStatus(statuses []corev1.ContainerStatus, name string) *corev1.ContainerStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// appendMissing appends the values not in list yet
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pavanInnamuri/pod-doctor/internal/domain"
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
@@ -10,11 +11,15 @@ import (
 )
 
 // StatusAnalyzer analyzes pod and container statuses
-type StatusAnalyzer struct{}
+type StatusAnalyzer struct {
+	restartThreshold int32
+	initTimeout      time.Duration // how long an init container may run before it is reported stuck
+}
 
 // NewStatusAnalyzer creates a new StatusAnalyzer
 func NewStatusAnalyzer() *StatusAnalyzer {
-	return &StatusAnalyzer{}
+	defaults := DefaultConfig().Status
+	return &StatusAnalyzer{restartThreshold: defaults.RestartThreshold, initTimeout: defaults.InitTimeout}
 }
 
 // Name returns the analyzer name
@@ -31,17 +36,22 @@ func (s *StatusAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *k
 		issues = append(issues, s.analyzeContainerStatus(cs)...)
 	}
 
-	// Check init container statuses
+	// Check init container statuses, and which one the pod is on
+	positions := initPositions(pod)
 	for _, cs := range pod.Status.InitContainerStatuses {
-		issues = append(issues, s.analyzeInitContainerStatus(cs)...)
+		issues = append(issues, s.analyzeInitContainerStatus(cs, positions[cs.Name])...)
 	}
+	issues = append(issues, s.analyzeInitProgress(pod, time.Now())...)
+
+	// Tell one-off OOMKills from recurring ones
+	addOOMTrends(ctx, pod, client, issues)
 
 	// Check pod conditions
 	issues = append(issues, s.analyzePodConditions(pod)...)
 
 	// Check for high restart count
 	for _, cs := range pod.Status.ContainerStatuses {
-		if cs.RestartCount > 5 {
+		if cs.RestartCount > s.restartThreshold {
 			issues = append(issues, domain.Issue{
 				Severity:    domain.SeverityWarning,
 				Category:    "container",
@@ -82,17 +92,7 @@ func (s *StatusAnalyzer) analyzeContainerStatus(cs corev1.ContainerStatus) []dom
 			})
 
 		case "ImagePullBackOff", "ErrImagePull":
-			issues = append(issues, domain.Issue{
-				Severity:    domain.SeverityCritical,
-				Category:    "container",
-				Title:       fmt.Sprintf("Cannot pull image for %s", cs.Name),
-				Description: waiting.Message,
-				Details: map[string]string{
-					"container": cs.Name,
-					"reason":    waiting.Reason,
-					"image":     cs.Image,
-				},
-			})
+			// ImageAnalyzer reports these with the parsed image reference
 
 		case "CreateContainerConfigError":
 			issues = append(issues, domain.Issue{
@@ -184,12 +184,13 @@ func (s *StatusAnalyzer) analyzeContainerStatus(cs corev1.ContainerStatus) []dom
 	return issues
 }
 
-// analyzeInitContainerStatus checks init container status
-func (s *StatusAnalyzer) analyzeInitContainerStatus(cs corev1.ContainerStatus) []domain.Issue {
+// analyzeInitContainerStatus checks init container status. The sequence
+// is the container's position among the init containers, e.g. "init 2/4".
+func (s *StatusAnalyzer) analyzeInitContainerStatus(cs corev1.ContainerStatus, sequence string) []domain.Issue {
 	var issues []domain.Issue
 
-	// Check if init container is stuck
-	if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+	// Check if init container is stuck; ImageAnalyzer reports image pulls
+	if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && !isImagePullReason(cs.State.Waiting.Reason) {
 		issues = append(issues, domain.Issue{
 			Severity:    domain.SeverityWarning,
 			Category:    "container",
@@ -198,6 +199,7 @@ func (s *StatusAnalyzer) analyzeInitContainerStatus(cs corev1.ContainerStatus) [
 			Details: map[string]string{
 				"container": cs.Name,
 				"type":      "init",
+				"sequence":  sequence,
 				"reason":    cs.State.Waiting.Reason,
 			},
 		})
@@ -213,6 +215,7 @@ func (s *StatusAnalyzer) analyzeInitContainerStatus(cs corev1.ContainerStatus) [
 			Details: map[string]string{
 				"container": cs.Name,
 				"type":      "init",
+				"sequence":  sequence,
 				"exit_code": fmt.Sprintf("%d", cs.State.Terminated.ExitCode),
 			},
 		})
@@ -228,17 +231,7 @@ func (s *StatusAnalyzer) analyzePodConditions(pod *corev1.Pod) []domain.Issue {
 	for _, cond := range pod.Status.Conditions {
 		switch cond.Type {
 		case corev1.PodScheduled:
-			if cond.Status == corev1.ConditionFalse {
-				issues = append(issues, domain.Issue{
-					Severity:    domain.SeverityCritical,
-					Category:    "scheduling",
-					Title:       "Pod cannot be scheduled",
-					Description: cond.Message,
-					Details: map[string]string{
-						"reason": cond.Reason,
-					},
-				})
-			}
+			// SchedulingAnalyzer reports these with the nodes rejecting the pod
 
 		case corev1.PodReady:
 			if cond.Status == corev1.ConditionFalse && pod.Status.Phase == corev1.PodRunning {
@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+)
+
+// DiagnoseAt reconstructs a pod's likely state at a past time, after it has
+// recovered or been replaced. The pod's most recent recorded diagnosis at or
+// before then, if any, is the starting point, and the events that remain
+// from between that diagnosis and at are replayed on top of it. Without a
+// recorded diagnosis the state is rebuilt from the remaining events alone,
+// which the API server keeps for about an hour by default.
+func (p *PodAnalyzer) DiagnoseAt(ctx context.Context, namespace, name string, at time.Time, snapshot *domain.Diagnosis) (*domain.Diagnosis, error) {
+	events, err := p.client.ListNamespaceEvents(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var podEvents []domain.EventInfo
+	for _, e := range events {
+		obj := e.InvolvedObject
+		if obj.Kind != "Pod" || obj.Name != name {
+			continue
+		}
+		if event := kubernetes.ExtractEventInfo(e); !eventStart(event).After(at) {
+			podEvents = append(podEvents, event)
+		}
+	}
+	domain.SortEvents(podEvents)
+
+	var diagnosis *domain.Diagnosis
+	source := "events"
+	if snapshot != nil {
+		diagnosis = copyDiagnosis(snapshot)
+		source = "recorded diagnosis"
+
+		var newer []domain.EventInfo
+		for _, event := range podEvents {
+			if event.LastSeen.After(snapshot.DiagnosedAt) {
+				newer = append(newer, event)
+			}
+		}
+		diagnosis.Events = mergeEvents(diagnosis.Events, newer)
+		replayEvents(diagnosis, newer)
+	} else {
+		if len(podEvents) == 0 {
+			return nil, fmt.Errorf("no recorded diagnosis of pod %s/%s at or before %s and no events from then remain",
+				namespace, name, at.Format(time.RFC3339))
+		}
+		diagnosis = domain.NewDiagnosis(domain.PodInfo{
+			Name:       name,
+			Namespace:  namespace,
+			Phase:      "Unknown",
+			Containers: make([]domain.ContainerInfo, 0),
+		})
+		diagnosis.Events = podEvents
+		diagnosis.Status = statusFromEvents(podEvents)
+		for _, event := range podEvents {
+			if event.Reason == "Scheduled" && event.FieldPath == "" {
+				diagnosis.Pod.Node = scheduledNode(event.Message)
+			}
+		}
+		replayEvents(diagnosis, podEvents)
+	}
+	diagnosis.DiagnosedAt = at
+
+	details := map[string]string{
+		"at":     at.Format(time.RFC3339),
+		"source": source,
+		"events": fmt.Sprintf("%d", len(podEvents)),
+	}
+	description := fmt.Sprintf("State at %s was reconstructed from the pod's remaining events", at.Format(time.RFC3339))
+	if snapshot != nil {
+		details["recorded_at"] = snapshot.DiagnosedAt.Format(time.RFC3339)
+		description = fmt.Sprintf("State at %s was reconstructed from the diagnosis recorded %s earlier and the events since",
+			at.Format(time.RFC3339), at.Sub(snapshot.DiagnosedAt).Round(time.Second))
+	}
+	diagnosis.AddIssue(domain.Issue{
+		Severity:    domain.SeverityInfo,
+		Category:    "container",
+		Title:       "Reconstructed past state",
+		Description: description,
+		Details:     details,
+	})
+
+	p.finalize(diagnosis)
+
+	return diagnosis, nil
+}
+
+// eventStart returns when an event was first seen, falling back to when it
+// was last seen for events that do not record it
+func eventStart(event domain.EventInfo) time.Time {
+	if event.FirstSeen.IsZero() {
+		return event.LastSeen
+	}
+	return event.FirstSeen
+}
+
+// copyDiagnosis copies a recorded diagnosis so that reconstructing from it
+// does not modify it
+func copyDiagnosis(d *domain.Diagnosis) *domain.Diagnosis {
+	c := *d
+	c.Issues = append(make([]domain.Issue, 0, len(d.Issues)), d.Issues...)
+	c.Events = append(make([]domain.EventInfo, 0, len(d.Events)), d.Events...)
+	return &c
+}
+
+// mergeEvents adds newer events to recorded ones, replacing a recorded
+// event with its later occurrence
+func mergeEvents(recorded, newer []domain.EventInfo) []domain.EventInfo {
+	key := func(e domain.EventInfo) string {
+		return strings.Join([]string{e.Type, e.Reason, e.FieldPath, eventStart(e).String()}, "|")
+	}
+	index := make(map[string]int, len(recorded))
+	for i, e := range recorded {
+		index[key(e)] = i
+	}
+	for _, e := range newer {
+		if i, ok := index[key(e)]; ok {
+			recorded[i] = e
+			continue
+		}
+		recorded = append(recorded, e)
+	}
+	domain.SortEvents(recorded)
+	return recorded
+}
+
+// statusFromEvents guesses a pod's status from the last of its events that
+// point to one
+func statusFromEvents(events []domain.EventInfo) domain.PodStatus {
+	status := domain.StatusUnknown
+	for _, event := range events {
+		switch event.Reason {
+		case "BackOff":
+			if strings.Contains(event.Message, "pulling image") {
+				status = domain.StatusImagePull
+			} else {
+				status = domain.StatusCrashLoop
+			}
+		case "ErrImagePull", "ImagePullBackOff":
+			status = domain.StatusImagePull
+		case "Failed":
+			if strings.Contains(event.Message, "pull") {
+				status = domain.StatusImagePull
+			}
+		case "FailedScheduling":
+			status = domain.StatusPending
+		case "Evicted", "Preempted":
+			status = domain.StatusEvicted
+		case "OOMKilling":
+			status = domain.StatusOOMKilled
+		case "Unhealthy":
+			status = domain.StatusNotReady
+		case "Killing":
+			status = domain.StatusTerminating
+		}
+	}
+	return status
+}
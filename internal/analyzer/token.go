@@ -0,0 +1,366 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// tokenAudienceAnnotation declares, on a pod, the audiences its
+	// projected service account tokens must include, comma separated
+	tokenAudienceAnnotation = "pod-doctor.io/token-audience"
+	// tokenExpirationAnnotation declares, on a pod, the lifetime its
+	// projected service account tokens are expected to have, e.g. 1h
+	tokenExpirationAnnotation = "pod-doctor.io/token-expiration"
+
+	// inClusterTokenDir is where API clients read the pod's token from
+	inClusterTokenDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// defaultTokenExpiration applies to projected tokens that set none
+	defaultTokenExpiration = time.Hour
+)
+
+// tokenIntegration is a workload identity integration that exchanges a
+// projected service account token for cloud credentials
+type tokenIntegration struct {
+	name string
+	// env names the variable pointing the SDK to the token file
+	env string
+	// audience is the audience the identity provider accepts by default
+	audience string
+	// audienceAnnotation and expirationAnnotation override the defaults
+	// when set on the service account or pod
+	audienceAnnotation   string
+	expirationAnnotation string
+}
+
+var tokenIntegrations = []tokenIntegration{
+	{
+		name:                 "AWS IAM roles for service accounts",
+		env:                  "AWS_WEB_IDENTITY_TOKEN_FILE",
+		audience:             "sts.amazonaws.com",
+		audienceAnnotation:   "eks.amazonaws.com/audience",
+		expirationAnnotation: "eks.amazonaws.com/token-expiration",
+	},
+	{
+		name:                 "Azure workload identity",
+		env:                  "AZURE_FEDERATED_TOKEN_FILE",
+		audience:             "api://AzureADTokenExchange",
+		expirationAnnotation: "azure.workload.identity/service-account-token-expiration",
+	},
+}
+
+// projectedToken is a service account token projected into a container
+type projectedToken struct {
+	volume     string
+	file       string // path of the token file in the container
+	audience   string // empty for the API server's audience
+	expiration time.Duration
+}
+
+// ServiceAccountTokenAnalyzer checks that the projected service account
+// tokens a pod uses have the audience and lifetime their consumers expect.
+// Bound tokens with the wrong audience are rejected with 401 Unauthorized,
+// by the API server or by a cloud identity provider.
+type ServiceAccountTokenAnalyzer struct{}
+
+// NewServiceAccountTokenAnalyzer creates a new ServiceAccountTokenAnalyzer
+func NewServiceAccountTokenAnalyzer() *ServiceAccountTokenAnalyzer {
+	return &ServiceAccountTokenAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (t *ServiceAccountTokenAnalyzer) Name() string {
+	return "serviceaccount"
+}
+
+// Analyze checks the pod's projected service account tokens
+func (t *ServiceAccountTokenAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	// Service account annotations configure the identity integrations; it
+	// is fetched only when a container uses one
+	var saAnnotations map[string]string
+	fetched := false
+	annotation := func(key string) string {
+		if key == "" {
+			return ""
+		}
+		if v := pod.Annotations[key]; v != "" {
+			return v
+		}
+		if !fetched {
+			fetched = true
+			if sa, err := client.GetServiceAccount(ctx, pod.Namespace, serviceAccountName(pod)); err == nil {
+				saAnnotations = sa.Annotations
+			}
+		}
+		return saAnnotations[key]
+	}
+
+	for _, c := range pod.Spec.Containers {
+		tokens := containerTokens(pod, c)
+
+		for _, token := range tokens {
+			if token.audience != "" && path.Dir(token.file) == inClusterTokenDir {
+				issues = append(issues, tokenIssue(domain.SeverityCritical, c.Name, token,
+					fmt.Sprintf("Token for the API server has audience %s", token.audience),
+					fmt.Sprintf("Clients read the token at %s to call the API server, which rejects this token's audience with 401 Unauthorized", token.file)).
+					WithDetail("expected_audience", audienceName("")))
+			}
+		}
+
+		for _, integration := range tokenIntegrations {
+			file := envValue(c, integration.env)
+			if file == "" {
+				continue
+			}
+			token, ok := findToken(tokens, file)
+			if !ok {
+				issues = append(issues, domain.NewIssue(
+					domain.SeverityCritical,
+					"serviceaccount",
+					fmt.Sprintf("%s token file is not mounted in %s", integration.env, c.Name),
+					fmt.Sprintf("%s points to %s, but no projected service account token is mounted there, so %s cannot get credentials", integration.env, file, integration.name),
+				).WithDetail("container", c.Name).WithDetail("path", file).WithDetail("env", integration.env))
+				continue
+			}
+
+			expected := integration.audience
+			if v := annotation(integration.audienceAnnotation); v != "" {
+				expected = v
+			}
+			if token.audience != expected {
+				issues = append(issues, tokenIssue(domain.SeverityCritical, c.Name, token,
+					fmt.Sprintf("Token audience mismatch for %s", integration.name),
+					fmt.Sprintf("The token at %s has audience %s, but %s only accepts %s and fails with 401 or InvalidIdentityToken", token.file, audienceName(token.audience), integration.name, expected)).
+					WithDetail("expected_audience", expected))
+			}
+			if v := annotation(integration.expirationAnnotation); v != "" {
+				if want, err := parseTokenExpiration(v); err == nil && want != token.expiration {
+					issues = append(issues, tokenExpirationIssue(c.Name, token, want, integration.expirationAnnotation))
+				}
+			}
+		}
+
+		// Audiences and lifetime the pod declares it needs
+		for _, want := range splitList(pod.Annotations[tokenAudienceAnnotation]) {
+			if !hasAudience(tokens, want) {
+				issues = append(issues, domain.NewIssue(
+					domain.SeverityWarning,
+					"serviceaccount",
+					fmt.Sprintf("No token with audience %s in %s", want, c.Name),
+					fmt.Sprintf("The pod expects a projected service account token for %s (%s), but %s mounts none; requests with another token fail with 401", want, tokenAudienceAnnotation, c.Name),
+				).WithDetail("container", c.Name).WithDetail("expected_audience", want))
+			}
+		}
+		if v := pod.Annotations[tokenExpirationAnnotation]; v != "" && len(tokens) > 0 {
+			if want, err := parseTokenExpiration(v); err == nil {
+				for _, token := range tokens {
+					if token.expiration != want {
+						issues = append(issues, tokenExpirationIssue(c.Name, token, want, tokenExpirationAnnotation))
+					}
+				}
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// containerTokens returns the projected service account tokens mounted in
+// a container
+func containerTokens(pod *corev1.Pod, c corev1.Container) []projectedToken {
+	volumes := make(map[string]*corev1.ProjectedVolumeSource)
+	for _, v := range pod.Spec.Volumes {
+		if v.Projected != nil {
+			volumes[v.Name] = v.Projected
+		}
+	}
+
+	var tokens []projectedToken
+	for _, mount := range c.VolumeMounts {
+		projected, ok := volumes[mount.Name]
+		if !ok {
+			continue
+		}
+		for _, source := range projected.Sources {
+			sat := source.ServiceAccountToken
+			if sat == nil {
+				continue
+			}
+			file := path.Join(mount.MountPath, sat.Path)
+			if mount.SubPath != "" {
+				if mount.SubPath != sat.Path {
+					continue
+				}
+				file = mount.MountPath
+			}
+			expiration := defaultTokenExpiration
+			if sat.ExpirationSeconds != nil {
+				expiration = time.Duration(*sat.ExpirationSeconds) * time.Second
+			}
+			tokens = append(tokens, projectedToken{
+				volume:     mount.Name,
+				file:       file,
+				audience:   sat.Audience,
+				expiration: expiration,
+			})
+		}
+	}
+	return tokens
+}
+
+// tokenIssue creates an issue about a projected token
+func tokenIssue(severity domain.Severity, container string, token projectedToken, title, description string) domain.Issue {
+	return domain.NewIssue(severity, "serviceaccount", title, description).
+		WithDetail("container", container).
+		WithDetail("volume", token.volume).
+		WithDetail("path", token.file).
+		WithDetail("audience", audienceName(token.audience))
+}
+
+// tokenExpirationIssue reports a token whose lifetime differs from the
+// one configured for its consumer
+func tokenExpirationIssue(container string, token projectedToken, want time.Duration, source string) domain.Issue {
+	return tokenIssue(domain.SeverityWarning, container, token,
+		fmt.Sprintf("Token expiration differs from %s", source),
+		fmt.Sprintf("The token at %s expires after %s, but %s expects %s; clients that cache the token longer than it lives fail with 401 once it expires", token.file, token.expiration, source, want)).
+		WithDetail("expiration", token.expiration.String()).
+		WithDetail("expected_expiration", want.String()).
+		WithDetail("expected_expiration_seconds", strconv.Itoa(int(want.Seconds())))
+}
+
+// findToken returns the token mounted at file
+func findToken(tokens []projectedToken, file string) (projectedToken, bool) {
+	for _, token := range tokens {
+		if path.Clean(token.file) == path.Clean(file) {
+			return token, true
+		}
+	}
+	return projectedToken{}, false
+}
+
+// hasAudience reports whether one of the tokens has the audience
+func hasAudience(tokens []projectedToken, audience string) bool {
+	for _, token := range tokens {
+		if token.audience == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// audienceName names a token audience, which is the API server's when empty
+func audienceName(audience string) string {
+	if audience == "" {
+		return "(API server)"
+	}
+	return audience
+}
+
+// parseTokenExpiration parses a token lifetime given in seconds, as the
+// cloud webhooks annotate it, or as a duration like 1h
+func parseTokenExpiration(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// envValue returns the literal value of a container environment variable
+func envValue(c corev1.Container, name string) string {
+	for _, env := range c.Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+// serviceAccountName returns the service account the pod runs as
+func serviceAccountName(pod *corev1.Pod) string {
+	if pod.Spec.ServiceAccountName != "" {
+		return pod.Spec.ServiceAccountName
+	}
+	return "default"
+}
+
+// splitList splits a comma separated annotation value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// tokenRecommendations suggests how to fix a service account token issue
+func tokenRecommendations(issue domain.Issue, pod domain.PodInfo, workload *domain.WorkloadInfo) []domain.Recommendation {
+	// Projected volumes are part of the pod spec, so fixes go in the
+	// workload's pod template
+	where := "the pod spec"
+	if workload != nil {
+		where = "the pod template of " + workload.Ref()
+	}
+	inspect := "kubectl get pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -o jsonpath='{.spec.volumes[*].projected.sources[*].serviceAccountToken}'"
+
+	switch {
+	case issue.Details["env"] != "":
+		return []domain.Recommendation{{
+			Priority:    1,
+			Title:       "Mount the workload identity token",
+			Description: "The identity webhook adds the token volume when the pod is created; check the service account is annotated for it and recreate the pod, or add a projected serviceAccountToken volume at " + issue.Details["path"] + " in " + where,
+			Command:     "kubectl get pod " + domain.ShellArg(pod.Name) + " -n " + domain.ShellArg(pod.Namespace) + " -o jsonpath='{.spec.serviceAccountName}'",
+		}}
+
+	case issue.Details["expected_expiration"] != "":
+		return []domain.Recommendation{{
+			Priority:    2,
+			Title:       "Align token expiration",
+			Description: "Set expirationSeconds: " + issue.Details["expected_expiration_seconds"] + " on the serviceAccountToken source of volume " + issue.Details["volume"] + " in " + where + ", or update the annotation if the token's lifetime is intended",
+			Command:     inspect,
+		}}
+
+	case issue.Details["expected_audience"] != "":
+		audience := issue.Details["expected_audience"]
+		description := "Set audience: " + audience + " on the serviceAccountToken source of volume " + issue.Details["volume"] + " in " + where
+		if audience == audienceName("") {
+			description = "Remove the audience from the serviceAccountToken source of volume " + issue.Details["volume"] + " in " + where + ", or mount this token elsewhere and leave the in-cluster path to the default token"
+		}
+		if issue.Details["volume"] == "" {
+			description = "Add a projected serviceAccountToken volume with audience: " + audience + " to " + where + " and point the client to its file"
+		}
+		return []domain.Recommendation{{
+			Priority:    1,
+			Title:       "Fix service account token audience",
+			Description: description,
+			Command:     inspect,
+		}}
+
+	case strings.Contains(issue.Title, "audience"):
+		return []domain.Recommendation{{
+			Priority:    1,
+			Title:       "Check service account token audiences",
+			Description: "The token was rejected for its audience; compare the audiences of the pod's projected tokens with what the API server or identity provider accepts",
+			Command:     inspect,
+		}}
+
+	case strings.Contains(issue.Title, "Expired"):
+		return []domain.Recommendation{{
+			Priority:    2,
+			Title:       "Re-read tokens before they expire",
+			Description: "The kubelet refreshes projected tokens at 80% of their lifetime; the client must re-read the token file instead of caching it at startup (update old SDKs)",
+		}}
+	}
+	return nil
+}
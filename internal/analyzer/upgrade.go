@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodeUpgradeFailure returns why a pod is expected to be unhealthy while its
+// node is replaced by a managed upgrade or other routine maintenance, or ""
+// if its node is not being upgraded or the pod fails for its own reasons
+func (p *PodAnalyzer) nodeUpgradeFailure(ctx context.Context, pod *corev1.Pod) string {
+	if pod.Spec.NodeName == "" || !disruptedByNode(pod) {
+		return ""
+	}
+	upgrade, err := p.client.NodeUpgrade(ctx, pod.Spec.NodeName)
+	if err != nil {
+		slog.Warn("failed to check for a node upgrade", "node", pod.Spec.NodeName, "error", err)
+		return ""
+	}
+	if upgrade == "" {
+		return ""
+	}
+	return fmt.Sprintf("node upgrade in progress: %s of %s", upgrade, pod.Spec.NodeName)
+}
+
+// disruptedByNode reports whether a pod shows what draining or restarting
+// its node does to it: it is being deleted, was evicted or shut down with
+// the node, or is not ready without a container of its own failing
+func disruptedByNode(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodUnknown {
+		return true
+	}
+	switch pod.Status.Reason {
+	case "Evicted", "NodeShutdown", "Terminated", "NodeLost":
+		return true
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "ContainerCreating" && w.Reason != "PodInitializing" {
+			return false
+		}
+	}
+	return pod.Status.Phase == corev1.PodRunning && !isPodReady(pod)
+}
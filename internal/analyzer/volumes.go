@@ -0,0 +1,307 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// volumeEventReasons are the warning events VolumeAnalyzer reports with
+// volume context, so EventAnalyzer skips them for live pods
+var volumeEventReasons = map[string]bool{
+	"FailedMount":        true,
+	"FailedAttachVolume": true,
+}
+
+var (
+	// volumeNamePattern matches `volume "data"` in mount and attach events
+	volumeNamePattern = regexp.MustCompile(`volume "([^"]+)"`)
+	// unmountedPattern matches `unmounted volumes=[data config]`
+	unmountedPattern = regexp.MustCompile(`unmounted volumes=\[([^\]]*)\]`)
+)
+
+// VolumeAnalyzer checks the pod's volumes and persistent volume claims
+type VolumeAnalyzer struct{}
+
+// NewVolumeAnalyzer creates a new VolumeAnalyzer
+func NewVolumeAnalyzer() *VolumeAnalyzer {
+	return &VolumeAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (v *VolumeAnalyzer) Name() string {
+	return "volumes"
+}
+
+// Analyze checks PVCs, read-only conflicts and mount failures
+func (v *VolumeAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	var issues []domain.Issue
+
+	// pvNames maps bound persistent volume names back to pod volume names,
+	// since attach events name the PV rather than the pod's volume
+	pvNames := make(map[string]string)
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := volume.PersistentVolumeClaim.ClaimName
+
+		pvc, err := client.GetPersistentVolumeClaim(ctx, pod.Namespace, claimName)
+		if apierrors.IsNotFound(err) {
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "storage",
+				Title:       fmt.Sprintf("PVC %s not found", claimName),
+				Description: "The pod references a persistent volume claim that does not exist, so it cannot start",
+				Details: map[string]string{
+					"volume": volume.Name,
+					"claim":  claimName,
+				},
+			})
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if pvc.Spec.VolumeName != "" {
+			pvNames[pvc.Spec.VolumeName] = volume.Name
+		}
+
+		claimIssues := v.analyzeClaim(ctx, pod, volume, pvc, client)
+		claimIssues = append(claimIssues, v.analyzeReadOnly(pod, volume, pvc)...)
+		issues = append(issues, withObject(claimIssues, objectRef("PersistentVolumeClaim", pvc))...)
+	}
+
+	events, err := client.GetPodEvents(ctx, pod.Namespace, pod.Name)
+	if err != nil {
+		return issues, nil
+	}
+	for _, event := range events {
+		if event.Type != "Warning" || !volumeEventReasons[event.Reason] {
+			continue
+		}
+		issues = append(issues, v.mountFailure(pod, event, pvNames))
+	}
+
+	return issues, nil
+}
+
+// analyzeClaim reports claims that are not bound to a volume
+func (v *VolumeAnalyzer) analyzeClaim(ctx context.Context, pod *corev1.Pod, volume corev1.Volume, pvc *corev1.PersistentVolumeClaim, client *kubernetes.Client) []domain.Issue {
+	details := map[string]string{
+		"volume": volume.Name,
+		"claim":  pvc.Name,
+		"phase":  string(pvc.Status.Phase),
+	}
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimLost:
+		return []domain.Issue{{
+			Severity:    domain.SeverityCritical,
+			Category:    "storage",
+			Title:       fmt.Sprintf("PVC %s lost its volume", pvc.Name),
+			Description: "The persistent volume bound to this claim no longer exists; data may need to be restored",
+			Details:     details,
+		}}
+
+	case corev1.ClaimPending:
+		details["age"] = time.Since(pvc.CreationTimestamp.Time).Round(time.Second).String()
+
+		className := ""
+		if pvc.Spec.StorageClassName != nil {
+			className = *pvc.Spec.StorageClassName
+		}
+		if className == "" {
+			return []domain.Issue{{
+				Severity:    domain.SeverityWarning,
+				Category:    "storage",
+				Title:       fmt.Sprintf("PVC %s stuck Pending", pvc.Name),
+				Description: "Claim has no storage class, so it needs a default StorageClass or a matching pre-provisioned PersistentVolume",
+				Details:     details,
+			}}
+		}
+		details["storage_class"] = className
+
+		class, err := client.GetStorageClass(ctx, className)
+		if apierrors.IsNotFound(err) {
+			return []domain.Issue{{
+				Severity:    domain.SeverityCritical,
+				Category:    "storage",
+				Title:       fmt.Sprintf("Storage class %s not found", className),
+				Description: "Claim requests a storage class that does not exist, so no volume will be provisioned",
+				Details:     details,
+			}}
+		}
+		if err == nil && class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer && pod.Spec.NodeName == "" {
+			// Binding waits for the pod to be scheduled; the scheduling
+			// failure, not the claim, is the root cause
+			return []domain.Issue{{
+				Severity:    domain.SeverityInfo,
+				Category:    "storage",
+				Title:       fmt.Sprintf("PVC %s waiting for pod to be scheduled", pvc.Name),
+				Description: "Storage class uses WaitForFirstConsumer; the volume is provisioned once the pod is scheduled",
+				Details:     details,
+			}}
+		}
+		if err == nil {
+			details["provisioner"] = class.Provisioner
+		}
+		return []domain.Issue{{
+			Severity:    domain.SeverityWarning,
+			Category:    "storage",
+			Title:       fmt.Sprintf("PVC %s stuck Pending", pvc.Name),
+			Description: "Claim is not bound to a volume; the provisioner may be failing or no matching volume is available",
+			Details:     details,
+		}}
+	}
+
+	return nil
+}
+
+// analyzeReadOnly reports mounts whose read/write mode conflicts with the claim
+func (v *VolumeAnalyzer) analyzeReadOnly(pod *corev1.Pod, volume corev1.Volume, pvc *corev1.PersistentVolumeClaim) []domain.Issue {
+	readOnlyMany := len(pvc.Spec.AccessModes) > 0
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode != corev1.ReadOnlyMany {
+			readOnlyMany = false
+		}
+	}
+
+	var issues []domain.Issue
+	for _, container := range pod.Spec.Containers {
+		for _, mount := range container.VolumeMounts {
+			if mount.Name != volume.Name || mount.ReadOnly {
+				continue
+			}
+			details := map[string]string{
+				"container":  container.Name,
+				"volume":     volume.Name,
+				"claim":      pvc.Name,
+				"mount_path": mount.MountPath,
+			}
+			switch {
+			case volume.PersistentVolumeClaim.ReadOnly:
+				issues = append(issues, domain.Issue{
+					Severity:    domain.SeverityWarning,
+					Category:    "storage",
+					Title:       fmt.Sprintf("Volume %s is forced read-only for %s", volume.Name, container.Name),
+					Description: "The volume is mounted read-write but the pod's claim reference sets readOnly, so writes will fail",
+					Details:     details,
+				})
+			case readOnlyMany:
+				issues = append(issues, domain.Issue{
+					Severity:    domain.SeverityWarning,
+					Category:    "storage",
+					Title:       fmt.Sprintf("Volume %s mounted read-write but claim is ReadOnlyMany", volume.Name),
+					Description: "The claim only allows read-only access, so writes will fail",
+					Details:     details,
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// mountFailure turns a FailedMount or FailedAttachVolume event into an issue
+// naming the affected volume and its source
+func (v *VolumeAnalyzer) mountFailure(pod *corev1.Pod, event domain.EventInfo, pvNames map[string]string) domain.Issue {
+	var volumes []string
+	if m := volumeNamePattern.FindStringSubmatch(event.Message); m != nil {
+		name := m[1]
+		if podVolume, ok := pvNames[name]; ok {
+			name = podVolume
+		}
+		volumes = append(volumes, name)
+	} else if m := unmountedPattern.FindStringSubmatch(event.Message); m != nil {
+		volumes = strings.Fields(m[1])
+	}
+
+	action := "mount"
+	if event.Reason == "FailedAttachVolume" {
+		action = "attach"
+	}
+
+	title := fmt.Sprintf("Volume failed to %s", action)
+	details := map[string]string{
+		"event":     event.Reason,
+		"count":     formatCount(event.Count),
+		"last_seen": event.LastSeen.Format("2006-01-02 15:04:05"),
+	}
+	if len(volumes) > 0 {
+		title = fmt.Sprintf("Volume %s failed to %s", strings.Join(volumes, ", "), action)
+		details["volume"] = strings.Join(volumes, ", ")
+		if len(volumes) == 1 {
+			if source := volumeSource(pod, volumes[0]); source != "" {
+				details["source"] = source
+			}
+			// Volume attachments name the PV, not the pod's volume
+			for pv, podVolume := range pvNames {
+				if podVolume == volumes[0] {
+					details["persistent_volume"] = pv
+				}
+			}
+		}
+	}
+	if cause := mountFailureCause(event.Message); cause != "" {
+		details["cause"] = cause
+	}
+
+	return domain.Issue{
+		Severity:    domain.SeverityCritical,
+		Category:    "storage",
+		Title:       title,
+		Description: event.Message,
+		Details:     details,
+	}
+}
+
+// mountFailureCause summarizes common mount and attach failure messages
+func mountFailureCause(message string) string {
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "multi-attach"):
+		return "volume is still attached to another node (ReadWriteOnce)"
+	case strings.Contains(msg, "configmap") && strings.Contains(msg, "not found"):
+		return "referenced ConfigMap does not exist"
+	case strings.Contains(msg, "secret") && strings.Contains(msg, "not found"):
+		return "referenced Secret does not exist"
+	case strings.Contains(msg, "permission denied"):
+		return "permission denied while mounting"
+	case strings.Contains(msg, "timed out"):
+		return "timed out waiting for the volume to attach or mount"
+	}
+	return ""
+}
+
+// volumeSource describes where a pod volume comes from, e.g. pvc/data
+func volumeSource(pod *corev1.Pod, name string) string {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name != name {
+			continue
+		}
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			return "pvc/" + volume.PersistentVolumeClaim.ClaimName
+		case volume.ConfigMap != nil:
+			return "configmap/" + volume.ConfigMap.Name
+		case volume.Secret != nil:
+			return "secret/" + volume.Secret.SecretName
+		case volume.CSI != nil:
+			return "csi/" + volume.CSI.Driver
+		case volume.HostPath != nil:
+			return "hostPath " + volume.HostPath.Path
+		case volume.EmptyDir != nil:
+			return "emptyDir"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// VPAAnalyzer compares pod requests with Vertical Pod Autoscaler
+// recommendations for the pod's workload
+type VPAAnalyzer struct{}
+
+// NewVPAAnalyzer creates a new VPAAnalyzer
+func NewVPAAnalyzer() *VPAAnalyzer {
+	return &VPAAnalyzer{}
+}
+
+// Name returns the analyzer name
+func (v *VPAAnalyzer) Name() string {
+	return "vpa"
+}
+
+// Analyze flags containers whose requests fall outside VPA's recommended bounds
+func (v *VPAAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	workload, err := client.ResolveWorkload(ctx, pod)
+	if err != nil || workload == nil {
+		return nil, err
+	}
+
+	vpas, err := client.ListVerticalPodAutoscalers(ctx, pod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var vpa *kubernetes.VerticalPodAutoscaler
+	for i := range vpas {
+		ref := vpas[i].Spec.TargetRef.Kind + "/" + vpas[i].Spec.TargetRef.Name
+		for _, owner := range workload.Chain {
+			if owner == ref {
+				vpa = &vpas[i]
+			}
+		}
+	}
+	if vpa == nil {
+		return nil, nil
+	}
+
+	recommendations := make(map[string]kubernetes.VPAContainerRecommendation)
+	for _, rec := range vpa.ContainerRecommendations() {
+		recommendations[rec.ContainerName] = rec
+	}
+
+	var issues []domain.Issue
+	for _, container := range pod.Spec.Containers {
+		rec, ok := recommendations[container.Name]
+		if !ok {
+			continue
+		}
+		if issue := v.compare(container, rec, vpa); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
+	return issues, nil
+}
+
+// compare checks a container's CPU and memory requests against VPA's bounds
+func (v *VPAAnalyzer) compare(container corev1.Container, rec kubernetes.VPAContainerRecommendation, vpa *kubernetes.VerticalPodAutoscaler) *domain.Issue {
+	details := map[string]string{
+		"container":   container.Name,
+		"vpa":         vpa.Name,
+		"update_mode": vpa.UpdateMode(),
+	}
+
+	var findings []string
+	under := false
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		target, ok := rec.Target[name]
+		if !ok {
+			continue
+		}
+		format := formatMemory
+		if name == corev1.ResourceCPU {
+			format = formatCPU
+		}
+
+		request := container.Resources.Requests[name]
+		lower, upper := rec.LowerBound[name], rec.UpperBound[name]
+
+		switch {
+		case request.IsZero():
+			findings = append(findings, fmt.Sprintf("no %s request", name))
+			under = true
+		case !lower.IsZero() && request.Cmp(lower) < 0:
+			findings = append(findings, fmt.Sprintf("%s request below VPA lower bound", name))
+			under = true
+		case !upper.IsZero() && request.Cmp(upper) > 0:
+			findings = append(findings, fmt.Sprintf("%s request above VPA upper bound", name))
+		default:
+			continue
+		}
+
+		details[string(name)+"_request"] = quantityOrNone(request, format)
+		details["vpa_"+string(name)+"_target"] = format(&target)
+		if !lower.IsZero() && !upper.IsZero() {
+			details["vpa_"+string(name)+"_range"] = format(&lower) + " - " + format(&upper)
+		}
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+
+	// Offer the full recommendation so both resources can be set at once
+	var requests []string
+	if cpu, ok := rec.Target[corev1.ResourceCPU]; ok {
+		requests = append(requests, "cpu="+formatCPU(&cpu))
+	}
+	if mem, ok := rec.Target[corev1.ResourceMemory]; ok {
+		requests = append(requests, "memory="+formatMemory(&mem))
+	}
+	details["vpa_requests"] = strings.Join(requests, ",")
+
+	severity := domain.SeverityInfo
+	description := "Requests are higher than VPA recommends; the container reserves capacity it does not use"
+	if under {
+		severity = domain.SeverityWarning
+		description = "Requests are lower than VPA recommends; the container risks throttling, OOMKills or being scheduled onto a node that is too small"
+	}
+	if vpa.UpdateMode() != "Off" && vpa.UpdateMode() != "Initial" {
+		description += "; VPA will apply its recommendation the next time it evicts the pod"
+	}
+
+	return &domain.Issue{
+		Severity:    severity,
+		Category:    "resources",
+		Title:       fmt.Sprintf("Requests diverge from VPA recommendation for %s", container.Name),
+		Description: fmt.Sprintf("%s (%s)", description, strings.Join(findings, ", ")),
+		Details:     details,
+	}
+}
+
+// quantityOrNone formats a quantity, or "none" when it is unset
+func quantityOrNone(q resource.Quantity, format func(*resource.Quantity) string) string {
+	if q.IsZero() {
+		return "none"
+	}
+	return format(&q)
+}
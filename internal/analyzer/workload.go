@@ -0,0 +1,299 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// WorkloadAnalyzer analyzes the Deployment, StatefulSet, DaemonSet or Job
+// that owns the pod for rollout and replica problems
+type WorkloadAnalyzer struct {
+	// productionSelector matches namespaces where a single-replica
+	// Deployment with an unhealthy pod is flagged as a full outage
+	productionSelector labels.Selector
+}
+
+// NewWorkloadAnalyzer creates a new WorkloadAnalyzer
+func NewWorkloadAnalyzer() *WorkloadAnalyzer {
+	selector, _ := labels.Parse(DefaultConfig().Workload.ProductionSelector)
+	return &WorkloadAnalyzer{productionSelector: selector}
+}
+
+// Name returns the analyzer name
+func (w *WorkloadAnalyzer) Name() string {
+	return "workload"
+}
+
+// Analyze checks the owning workload's rollout status and replica counts
+func (w *WorkloadAnalyzer) Analyze(ctx context.Context, pod *corev1.Pod, client *kubernetes.Client) ([]domain.Issue, error) {
+	workload, err := client.ResolveWorkload(ctx, pod)
+	if err != nil || workload == nil {
+		return nil, err
+	}
+
+	switch workload.Kind {
+	case "Deployment":
+		deploy, err := client.GetDeployment(ctx, pod.Namespace, workload.Name)
+		if err != nil {
+			return nil, err
+		}
+		issues := w.analyzeDeployment(deploy)
+		if w.isProductionNamespace(ctx, client, pod.Namespace) {
+			issues = append(issues, singleReplicaIssue(deploy, pod)...)
+		}
+		return withObject(issues, objectRef("Deployment", deploy)), nil
+	case "StatefulSet":
+		sts, err := client.GetStatefulSet(ctx, pod.Namespace, workload.Name)
+		if err != nil {
+			return nil, err
+		}
+		return withObject(w.analyzeStatefulSet(sts), objectRef("StatefulSet", sts)), nil
+	case "DaemonSet":
+		ds, err := client.GetDaemonSet(ctx, pod.Namespace, workload.Name)
+		if err != nil {
+			return nil, err
+		}
+		return withObject(w.analyzeDaemonSet(ds), objectRef("DaemonSet", ds)), nil
+	case "Job":
+		job, err := client.GetJob(ctx, pod.Namespace, workload.Name)
+		if err != nil {
+			return nil, err
+		}
+		return withObject(w.analyzeJob(job), objectRef("Job", job)), nil
+	}
+
+	return nil, nil
+}
+
+// analyzeDeployment checks a deployment's rollout and replicas
+func (w *WorkloadAnalyzer) analyzeDeployment(deploy *appsv1.Deployment) []domain.Issue {
+	var issues []domain.Issue
+	ref := "deployment/" + deploy.Name
+
+	if deploy.Spec.Paused {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "workload",
+			Title:       fmt.Sprintf("Rollout of %s is paused", ref),
+			Description: "Spec changes will not be rolled out until the deployment is resumed",
+			Details: map[string]string{
+				"workload": ref,
+			},
+		})
+	}
+
+	for _, cond := range deploy.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse {
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "workload",
+				Title:       fmt.Sprintf("Rollout of %s failed", ref),
+				Description: cond.Message,
+				Details: map[string]string{
+					"workload": ref,
+					"reason":   cond.Reason,
+				},
+			})
+		}
+	}
+
+	issues = append(issues, generationIssue(ref, deploy.Generation, deploy.Status.ObservedGeneration)...)
+	issues = append(issues, replicaIssues(ref, desiredReplicas(deploy.Spec.Replicas), deploy.Status.Replicas, deploy.Status.AvailableReplicas)...)
+
+	return issues
+}
+
+// isProductionNamespace reports whether the namespace matches the
+// production selector
+func (w *WorkloadAnalyzer) isProductionNamespace(ctx context.Context, client *kubernetes.Client, namespace string) bool {
+	if w.productionSelector == nil || w.productionSelector.Empty() {
+		return false
+	}
+	ns, err := client.GetNamespace(ctx, namespace)
+	if err != nil {
+		return false
+	}
+	return w.productionSelector.Matches(labels.Set(ns.Labels))
+}
+
+// singleReplicaIssue reports an unhealthy pod of a production Deployment
+// running a single replica: with nothing to fail over to, one bad pod is a
+// full outage rather than reduced capacity
+func singleReplicaIssue(deploy *appsv1.Deployment, pod *corev1.Pod) []domain.Issue {
+	if desiredReplicas(deploy.Spec.Replicas) != 1 {
+		return nil
+	}
+	status := DetectPodStatus(pod)
+	if status == domain.StatusHealthy {
+		return nil
+	}
+
+	ref := "deployment/" + deploy.Name
+	return []domain.Issue{{
+		Severity:    domain.SeverityCritical,
+		Category:    "workload",
+		Title:       fmt.Sprintf("Single-replica %s is down", ref),
+		Description: fmt.Sprintf("%s runs one replica in a production namespace and its only pod is %s, so the workload is fully unavailable", ref, status),
+		Details: map[string]string{
+			"workload":   ref,
+			"replicas":   "1",
+			"pod_status": string(status),
+		},
+	}}
+}
+
+// analyzeStatefulSet checks a statefulset's rollout and replicas
+func (w *WorkloadAnalyzer) analyzeStatefulSet(sts *appsv1.StatefulSet) []domain.Issue {
+	var issues []domain.Issue
+	ref := "statefulset/" + sts.Name
+
+	issues = append(issues, generationIssue(ref, sts.Generation, sts.Status.ObservedGeneration)...)
+	issues = append(issues, replicaIssues(ref, desiredReplicas(sts.Spec.Replicas), sts.Status.Replicas, sts.Status.ReadyReplicas)...)
+
+	if sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision &&
+		sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityInfo,
+			Category:    "workload",
+			Title:       fmt.Sprintf("%s has pending OnDelete update", ref),
+			Description: "The update strategy is OnDelete, so pods only pick up the new revision when deleted manually",
+			Details: map[string]string{
+				"workload":         ref,
+				"current_revision": sts.Status.CurrentRevision,
+				"update_revision":  sts.Status.UpdateRevision,
+			},
+		})
+	}
+
+	return issues
+}
+
+// analyzeDaemonSet checks a daemonset's scheduling and availability
+func (w *WorkloadAnalyzer) analyzeDaemonSet(ds *appsv1.DaemonSet) []domain.Issue {
+	var issues []domain.Issue
+	ref := "daemonset/" + ds.Name
+
+	issues = append(issues, generationIssue(ref, ds.Generation, ds.Status.ObservedGeneration)...)
+
+	if ds.Status.NumberAvailable < ds.Status.DesiredNumberScheduled {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "workload",
+			Title:       fmt.Sprintf("%s has missing replicas", ref),
+			Description: fmt.Sprintf("%d of %d desired pods are available", ds.Status.NumberAvailable, ds.Status.DesiredNumberScheduled),
+			Details: map[string]string{
+				"workload":  ref,
+				"desired":   fmt.Sprintf("%d", ds.Status.DesiredNumberScheduled),
+				"available": fmt.Sprintf("%d", ds.Status.NumberAvailable),
+			},
+		})
+	}
+
+	if ds.Status.NumberMisscheduled > 0 {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "workload",
+			Title:       fmt.Sprintf("%s has misscheduled pods", ref),
+			Description: fmt.Sprintf("%d pods are running on nodes where they should not be", ds.Status.NumberMisscheduled),
+			Details: map[string]string{
+				"workload":     ref,
+				"misscheduled": fmt.Sprintf("%d", ds.Status.NumberMisscheduled),
+			},
+		})
+	}
+
+	return issues
+}
+
+// analyzeJob checks a job's completion status
+func (w *WorkloadAnalyzer) analyzeJob(job *batchv1.Job) []domain.Issue {
+	var issues []domain.Issue
+	ref := "job/" + job.Name
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			issues = append(issues, domain.Issue{
+				Severity:    domain.SeverityCritical,
+				Category:    "workload",
+				Title:       fmt.Sprintf("Job %s failed: %s", job.Name, cond.Reason),
+				Description: cond.Message,
+				Details: map[string]string{
+					"workload": ref,
+					"job":      job.Name,
+					"reason":   cond.Reason,
+					"failed":   fmt.Sprintf("%d", job.Status.Failed),
+				},
+			})
+		}
+	}
+
+	return issues
+}
+
+// generationIssue reports a controller that has not yet observed the latest spec
+func generationIssue(ref string, generation, observed int64) []domain.Issue {
+	if observed >= generation {
+		return nil
+	}
+	return []domain.Issue{{
+		Severity:    domain.SeverityInfo,
+		Category:    "workload",
+		Title:       fmt.Sprintf("%s spec change not yet observed", ref),
+		Description: "The controller has not processed the latest spec; a rollout may be about to start",
+		Details: map[string]string{
+			"workload":            ref,
+			"generation":          fmt.Sprintf("%d", generation),
+			"observed_generation": fmt.Sprintf("%d", observed),
+		},
+	}}
+}
+
+// replicaIssues reports missing or surplus replicas
+func replicaIssues(ref string, desired, current, available int32) []domain.Issue {
+	var issues []domain.Issue
+
+	if available < desired {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityWarning,
+			Category:    "workload",
+			Title:       fmt.Sprintf("%s has missing replicas", ref),
+			Description: fmt.Sprintf("%d of %d desired replicas are available", available, desired),
+			Details: map[string]string{
+				"workload":  ref,
+				"desired":   fmt.Sprintf("%d", desired),
+				"available": fmt.Sprintf("%d", available),
+			},
+		})
+	}
+
+	if current > desired {
+		issues = append(issues, domain.Issue{
+			Severity:    domain.SeverityInfo,
+			Category:    "workload",
+			Title:       fmt.Sprintf("%s has surplus replicas", ref),
+			Description: fmt.Sprintf("%d replicas exist but %d are desired; a rollout or scale-down is in progress", current, desired),
+			Details: map[string]string{
+				"workload": ref,
+				"desired":  fmt.Sprintf("%d", desired),
+				"current":  fmt.Sprintf("%d", current),
+			},
+		})
+	}
+
+	return issues
+}
+
+// desiredReplicas returns the replica count, defaulting to 1 like the API server
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
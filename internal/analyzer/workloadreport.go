@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// AggregateWorkload builds a workload report from the diagnoses of its
+// pods. Issues found on every pod are common; the rest are pod-specific.
+// Recommendations are merged across pods, keeping each title once at its
+// highest priority.
+func AggregateWorkload(report *domain.WorkloadReport, diagnoses []*domain.Diagnosis) {
+	report.APIVersion = domain.APIVersion
+	report.Pods = len(diagnoses)
+	report.Statuses = make(map[domain.PodStatus]int)
+	report.Diagnoses = diagnoses
+	report.CommonIssues = make([]domain.WorkloadIssue, 0)
+	report.PodIssues = make([]domain.WorkloadIssue, 0)
+	report.Recommendations = make([]domain.Recommendation, 0)
+
+	var order []string
+	issues := make(map[string]*domain.WorkloadIssue)
+	recs := make(map[string]int)
+	for _, d := range diagnoses {
+		report.Statuses[d.Status]++
+		if d.IsHealthy() {
+			report.Healthy++
+		}
+
+		seen := make(map[string]bool)
+		for _, issue := range d.Issues {
+			key := workloadIssueKey(issue)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if existing, ok := issues[key]; ok {
+				existing.Pods = append(existing.Pods, d.Pod.Name)
+				continue
+			}
+			issues[key] = &domain.WorkloadIssue{Issue: issue, Pods: []string{d.Pod.Name}}
+			order = append(order, key)
+		}
+
+		for _, rec := range d.Recommendations {
+			if i, ok := recs[rec.Title]; ok {
+				if rec.Priority < report.Recommendations[i].Priority {
+					report.Recommendations[i] = rec
+				}
+				continue
+			}
+			recs[rec.Title] = len(report.Recommendations)
+			report.Recommendations = append(report.Recommendations, rec)
+		}
+	}
+
+	for _, key := range order {
+		issue := issues[key]
+		sort.Strings(issue.Pods)
+		if len(issue.Pods) == len(diagnoses) {
+			report.CommonIssues = append(report.CommonIssues, *issue)
+		} else {
+			report.PodIssues = append(report.PodIssues, *issue)
+		}
+	}
+	sortWorkloadIssues(report.CommonIssues)
+	sortWorkloadIssues(report.PodIssues)
+	sort.SliceStable(report.Recommendations, func(i, j int) bool {
+		return report.Recommendations[i].Priority < report.Recommendations[j].Priority
+	})
+}
+
+// sortWorkloadIssues orders issues by severity, then by how many pods have
+// them
+func sortWorkloadIssues(issues []domain.WorkloadIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if severityOrder(a.Issue.Severity) != severityOrder(b.Issue.Severity) {
+			return severityOrder(a.Issue.Severity) < severityOrder(b.Issue.Severity)
+		}
+		return len(a.Pods) > len(b.Pods)
+	})
+}
+
+// workloadIssueKey identifies the same issue on different replicas, whose
+// descriptions may mention the pod
+func workloadIssueKey(issue domain.Issue) string {
+	return issue.Category + "\x00" + issue.Container() + "\x00" + issue.Title
+}
+
+// severityOrder orders severities worst first
+func severityOrder(s domain.Severity) int {
+	switch s {
+	case domain.SeverityCritical:
+		return 0
+	case domain.SeverityWarning:
+		return 1
+	}
+	return 2
+}
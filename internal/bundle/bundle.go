@@ -0,0 +1,100 @@
+// Package bundle collects what support needs to investigate one pod into a
+// single archive: its manifest, events, container logs, node and diagnosis
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Bundle is an archive in the making. Parts that could not be collected
+// are listed in errors.txt instead of failing the whole bundle.
+type Bundle struct {
+	root    string // directory the files are archived under
+	created time.Time
+	files   []file
+	errors  []string
+}
+
+type file struct {
+	name string
+	data []byte
+}
+
+// New creates an empty bundle whose files are archived under root
+func New(root string, created time.Time) *Bundle {
+	return &Bundle{root: root, created: created}
+}
+
+// Add adds a file to the bundle
+func (b *Bundle) Add(name string, data []byte) {
+	b.files = append(b.files, file{name: name, data: data})
+}
+
+// AddYAML adds an object as a YAML file, written as kubectl get -o yaml
+// would, or records why it could not be
+func (b *Bundle) AddYAML(name string, obj interface{}) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		b.Fail(name, err)
+		return
+	}
+	b.Add(name, data)
+}
+
+// Fail records a part of the bundle that could not be collected
+func (b *Bundle) Fail(part string, err error) {
+	b.errors = append(b.errors, fmt.Sprintf("%s: %v", part, err))
+}
+
+// Errors returns the parts that could not be collected
+func (b *Bundle) Errors() []string {
+	return b.errors
+}
+
+// Files returns the names of the files in the bundle, in the order added
+func (b *Bundle) Files() []string {
+	names := make([]string, 0, len(b.files))
+	for _, f := range b.files {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// Write writes the bundle as a gzipped tar archive, with errors.txt when
+// parts are missing
+func (b *Bundle) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	files := b.files
+	if len(b.errors) > 0 {
+		files = append(files, file{name: "errors.txt", data: []byte(strings.Join(b.errors, "\n") + "\n")})
+	}
+	for _, f := range files {
+		header := &tar.Header{
+			Name:    path.Join(b.root, f.name),
+			Mode:    0o644,
+			Size:    int64(len(f.data)),
+			ModTime: b.created,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return gz.Close()
+}
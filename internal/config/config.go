@@ -0,0 +1,275 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/rules"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variables that override flag defaults,
+// e.g. POD_DOCTOR_NAMESPACE or POD_DOCTOR_LOG_TAIL_LINES
+const EnvPrefix = "POD_DOCTOR_"
+
+// DefaultFileName is the config file looked up in the home directory
+const DefaultFileName = ".pod-doctor.yaml"
+
+// File holds flag defaults read from a config file. Top-level keys are flag
+// names and apply to every command; a key naming a command holds defaults
+// for that command's own flags:
+//
+//	namespace: production
+//	output: json
+//	log-tail-lines: 200
+//	skip-analyzers: [vpa, autoscaler]
+//	scan:
+//	  concurrency: 10
+//	suppressions:
+//	  - category: resources
+//	    title: No resource limits
+//	contexts:
+//	  prod-cluster:
+//	    namespace: payments
+//	    output: console
+//
+// The contexts section holds the same keys per kube context; they apply when
+// that context is current (see UseContext) and take precedence over the
+// keys outside it. Suppressions drop matching issues, like the suppressions
+// of a rule pack.
+type File struct {
+	Path     string
+	values   map[string]string
+	commands map[string]map[string]string
+
+	suppressions []rules.Match
+	contexts     map[string]*File
+	context      *File // set by UseContext
+}
+
+// DefaultPath returns ~/.pod-doctor.yaml, or an empty string if the home
+// directory is unknown
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// Load reads a config file. An empty path loads the default file, and a
+// missing default file yields an empty config rather than an error.
+func Load(path string) (*File, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	file := newFile(path)
+	if path == "" {
+		return file, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			file.Path = ""
+			return file, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := file.parse(data); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// newFile creates an empty config read from path
+func newFile(path string) *File {
+	return &File{Path: path, values: map[string]string{}, commands: map[string]map[string]string{}}
+}
+
+// parse decodes the YAML document into flag values
+func (f *File) parse(data []byte) error {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return f.parseKeys(raw, "")
+}
+
+// parseKeys decodes the keys of the document, or of one context's section
+// when context is set
+func (f *File) parseKeys(raw map[string]any, context string) error {
+	prefix := ""
+	if context != "" {
+		prefix = "contexts." + context + "."
+	}
+
+	for key, value := range raw {
+		switch key {
+		case "suppressions":
+			if err := decode(value, &f.suppressions); err != nil {
+				return fmt.Errorf("%ssuppressions: %w", prefix, err)
+			}
+			continue
+		case "contexts":
+			if context != "" {
+				return fmt.Errorf("%scontexts: contexts cannot be nested", prefix)
+			}
+			if err := f.parseContexts(value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		section, ok := value.(map[string]any)
+		if !ok {
+			s, err := flagValue(value)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", prefix, key, err)
+			}
+			f.values[key] = s
+			continue
+		}
+
+		values := make(map[string]string, len(section))
+		for flag, v := range section {
+			s, err := flagValue(v)
+			if err != nil {
+				return fmt.Errorf("%s%s.%s: %w", prefix, key, flag, err)
+			}
+			values[flag] = s
+		}
+		f.commands[key] = values
+	}
+	return nil
+}
+
+// parseContexts decodes the contexts section, keyed by kube context name
+func (f *File) parseContexts(value any) error {
+	contexts, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("contexts: expected a section per kube context")
+	}
+
+	f.contexts = make(map[string]*File, len(contexts))
+	for name, v := range contexts {
+		section, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("contexts.%s: expected a section of flag values", name)
+		}
+		ctxFile := newFile(f.Path)
+		if err := ctxFile.parseKeys(section, name); err != nil {
+			return err
+		}
+		f.contexts[name] = ctxFile
+	}
+	return nil
+}
+
+// UseContext selects the section of a kube context, if the config has one,
+// so that Lookup and Suppressions include its values
+func (f *File) UseContext(name string) {
+	f.context = f.contexts[name]
+}
+
+// Lookup returns the configured value of a flag for a command. Values of
+// the selected kube context take precedence over the rest of the file, and
+// within each, a value in the command's section over a top-level one.
+func (f *File) Lookup(command, flag string) (string, bool) {
+	if f.context != nil {
+		if v, ok := f.context.Lookup(command, flag); ok {
+			return v, true
+		}
+	}
+	if v, ok := f.commands[command][flag]; ok {
+		return v, true
+	}
+	v, ok := f.values[flag]
+	return v, ok
+}
+
+// Suppressions returns the configured suppressions, including those of the
+// selected kube context
+func (f *File) Suppressions() []rules.Match {
+	if f.context == nil {
+		return f.suppressions
+	}
+	return append(append([]rules.Match(nil), f.suppressions...), f.context.suppressions...)
+}
+
+// UnknownKeys returns the keys that known reports as no flag of their
+// command, e.g. "scan.concurency" or "contexts.prod.namspace", sorted.
+// Top-level keys are checked with an empty command, as they apply to every
+// command. Such keys are otherwise ignored without notice.
+func (f *File) UnknownKeys(known func(command, flag string) bool) []string {
+	var unknown []string
+	for flag := range f.values {
+		if !known("", flag) {
+			unknown = append(unknown, flag)
+		}
+	}
+	for command, values := range f.commands {
+		for flag := range values {
+			if !known(command, flag) {
+				unknown = append(unknown, command+"."+flag)
+			}
+		}
+	}
+	for name, ctxFile := range f.contexts {
+		for _, key := range ctxFile.UnknownKeys(known) {
+			unknown = append(unknown, "contexts."+name+"."+key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// EnvName returns the environment variable that overrides a flag,
+// e.g. log-tail-lines -> POD_DOCTOR_LOG_TAIL_LINES
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// decode converts a generic YAML value into out, rejecting fields out does
+// not have, so a misspelled one is not silently ignored
+func decode(value any, out any) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(out)
+}
+
+// flagValue converts a YAML value to its command-line form; lists become
+// comma-separated values
+func flagValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := flagValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		return "", fmt.Errorf("nested sections are only supported one level deep")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Acknowledgement silences an issue of one pod until it expires, e.g. while
+// a fix is rolled out. Acknowledgements are shared through the cluster, so
+// every pod-doctor run against it silences the same issues.
+type Acknowledgement struct {
+	Pod     string    `json:"pod"`
+	Issue   string    `json:"issue"` // issue ID such as PD-CRASHLOOP-001, or part of the issue's title
+	By      string    `json:"by,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// Matches reports whether the acknowledgement covers an issue of the named
+// pod
+func (a Acknowledgement) Matches(issue Issue, pod string) bool {
+	if a.Pod != pod {
+		return false
+	}
+	return a.Issue == issue.ID || strings.Contains(issue.Title, a.Issue)
+}
+
+// Expired reports whether the acknowledgement no longer applies at a time
+func (a Acknowledgement) Expired(now time.Time) bool {
+	return !now.Before(a.Expires)
+}
+
+// Acknowledge drops the issues covered by an acknowledgement that has not
+// expired, and records the acknowledgements that dropped any
+func (d *Diagnosis) Acknowledge(acks []Acknowledgement, now time.Time) {
+	used := make([]bool, len(acks))
+	issues := d.Issues[:0]
+	for _, issue := range d.Issues {
+		acknowledged := false
+		for i, a := range acks {
+			if !a.Expired(now) && a.Matches(issue, d.Pod.Name) {
+				used[i] = true
+				acknowledged = true
+			}
+		}
+		if !acknowledged {
+			issues = append(issues, issue)
+		}
+	}
+	d.Issues = issues
+	for i, a := range acks {
+		if used[i] {
+			d.Acknowledged = append(d.Acknowledged, a)
+		}
+	}
+}
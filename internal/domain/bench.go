@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// BenchReport is the result of running the analyzers over a set of pods
+type BenchReport struct {
+	APIVersion string          `json:"apiVersion"`
+	Source     string          `json:"source"` // synthetic, or the recording the pods came from
+	Pods       int             `json:"pods"`
+	Duration   time.Duration   `json:"duration"` // of all analyzers over all pods
+	Analyzers  []AnalyzerBench `json:"analyzers"`
+}
+
+// AnalyzerBench measures one analyzer over every pod of a benchmark
+type AnalyzerBench struct {
+	Name          string        `json:"name"`
+	Duration      time.Duration `json:"duration"`
+	PerPod        time.Duration `json:"perPod"`
+	PodsPerSecond float64       `json:"podsPerSecond"`
+	AllocsPerPod  uint64        `json:"allocsPerPod"`
+	BytesPerPod   uint64        `json:"bytesPerPod"`
+	Issues        int           `json:"issues"`           // found over all pods
+	Errors        int           `json:"errors,omitempty"` // pods the analyzer failed on
+}
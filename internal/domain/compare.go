@@ -0,0 +1,39 @@
+package domain
+
+// PodComparison is how two pods, usually replicas of one workload, differ:
+// in their spec, placement and state, and in the issues found
+type PodComparison struct {
+	APIVersion   string          `json:"apiVersion"`
+	Namespace    string          `json:"namespace"`
+	A            ComparedPod     `json:"a"`
+	B            ComparedPod     `json:"b"`
+	Failing      string          `json:"failing,omitempty"` // name of the pod in worse health, empty if they are equally healthy
+	SameWorkload bool            `json:"sameWorkload"`
+	Differences  []PodDifference `json:"differences"`
+	OnlyA        []Issue         `json:"onlyA"`  // issues only pod A has
+	OnlyB        []Issue         `json:"onlyB"`  // issues only pod B has
+	Shared       []Issue         `json:"shared"` // issues both pods have, as found on pod A
+}
+
+// ComparedPod summarizes one pod of a comparison
+type ComparedPod struct {
+	Name        string    `json:"name"`
+	Status      PodStatus `json:"status"`
+	HealthScore int       `json:"healthScore"`
+	Node        string    `json:"node,omitempty"`
+	Workload    string    `json:"workload,omitempty"` // e.g. deployment/web
+	Restarts    int32     `json:"restarts"`
+}
+
+// PodDifference is one setting or state that differs between the pods
+type PodDifference struct {
+	Field     string `json:"field"`               // e.g. image, env FOO, memory limit, node
+	Container string `json:"container,omitempty"` // empty for pod-level differences
+	A         string `json:"a"`
+	B         string `json:"b"`
+}
+
+// IsFailing reports whether the named pod is the one in worse health
+func (c *PodComparison) IsFailing(name string) bool {
+	return c.Failing != "" && c.Failing == name
+}
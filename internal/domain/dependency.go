@@ -0,0 +1,9 @@
+package domain
+
+// DependencyFailure represents a dependency (host:port) that several pods in
+// the same namespace are failing to connect to
+type DependencyFailure struct {
+	Namespace string   `json:"namespace"`
+	Target    string   `json:"target"`
+	Pods      []string `json:"pods"`
+}
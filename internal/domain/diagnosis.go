@@ -19,26 +19,40 @@ const (
 	StatusInitializing   PodStatus = "Initializing"
 	StatusCreateError    PodStatus = "CreateContainerError"
 	StatusConfigError    PodStatus = "CreateContainerConfigError"
+	StatusDeleted        PodStatus = "Deleted"
 )
 
 // ContainerInfo holds information about a container
 type ContainerInfo struct {
-	Name         string        `json:"name"`
-	Image        string        `json:"image"`
-	Ready        bool          `json:"ready"`
-	RestartCount int32         `json:"restartCount"`
-	State        string        `json:"state"` // running, waiting, terminated
-	Reason       string        `json:"reason,omitempty"`
-	Message      string        `json:"message,omitempty"`
-	ExitCode     int32         `json:"exitCode,omitempty"`
-	StartedAt    time.Time     `json:"startedAt,omitempty"`
-	FinishedAt   time.Time     `json:"finishedAt,omitempty"`
+	Name         string         `json:"name"`
+	Image        string         `json:"image"`
+	Ready        bool           `json:"ready"`
+	RestartCount int32          `json:"restartCount"`
+	State        string         `json:"state"` // running, waiting, terminated
+	Reason       string         `json:"reason,omitempty"`
+	Message      string         `json:"message,omitempty"`
+	ExitCode     int32          `json:"exitCode,omitempty"`
+	StartedAt    time.Time      `json:"startedAt,omitempty"`
+	FinishedAt   time.Time      `json:"finishedAt,omitempty"`
+	Resources    *ResourceUsage `json:"resources,omitempty"` // requests and limits from the spec
+	Probes       []ProbeInfo    `json:"probes,omitempty"`
+}
+
+// ProbeInfo summarizes a container probe's configuration
+type ProbeInfo struct {
+	Type             string `json:"type"`    // liveness, readiness, startup
+	Handler          string `json:"handler"` // e.g. GET :8080/healthz, tcp :5432, exec
+	InitialDelay     int32  `json:"initialDelaySeconds,omitempty"`
+	Period           int32  `json:"periodSeconds,omitempty"`
+	Timeout          int32  `json:"timeoutSeconds,omitempty"`
+	FailureThreshold int32  `json:"failureThreshold,omitempty"`
 }
 
 // PodInfo holds basic information about the pod
 type PodInfo struct {
 	Name       string          `json:"name"`
 	Namespace  string          `json:"namespace"`
+	UID        string          `json:"uid,omitempty"`
 	Node       string          `json:"node"`
 	Age        time.Duration   `json:"age"`
 	Phase      string          `json:"phase"`
@@ -57,6 +71,17 @@ type EventInfo struct {
 	FirstSeen time.Time `json:"firstSeen"`
 	LastSeen  time.Time `json:"lastSeen"`
 	Source    string    `json:"source"`
+	FieldPath string    `json:"fieldPath,omitempty"` // involved object field, e.g. spec.containers{api}
+}
+
+// ReadinessBlocker is one reason a running pod is not ready: a container
+// that is not ready, or a readiness gate whose condition is not true
+type ReadinessBlocker struct {
+	Kind    string `json:"kind"` // container, readinessGate
+	Name    string `json:"name"`
+	Probe   string `json:"probe,omitempty"` // readiness or startup, when a probe holds the container back
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"` // last probe error or condition message
 }
 
 // ResourceUsage holds resource usage information
@@ -72,6 +97,7 @@ type ResourceUsage struct {
 // NodeHealth holds node health information
 type NodeHealth struct {
 	Name            string `json:"name"`
+	UID             string `json:"uid,omitempty"`
 	Ready           bool   `json:"ready"`
 	MemoryPressure  bool   `json:"memoryPressure"`
 	DiskPressure    bool   `json:"diskPressure"`
@@ -87,27 +113,60 @@ type LogAnalysis struct {
 	TotalLines  int      `json:"totalLines"`
 }
 
+// APIVersion identifies the format of a serialized Diagnosis. It only
+// changes on breaking changes (removed or renamed fields, changed types),
+// so consumers can reject output they do not understand; new fields may be
+// added within a version.
+const APIVersion = "pod-doctor/v1"
+
 // Diagnosis represents the complete diagnosis result for a pod
 type Diagnosis struct {
-	Pod             PodInfo          `json:"pod"`
-	Status          PodStatus        `json:"status"`
-	Issues          []Issue          `json:"issues"`
-	Events          []EventInfo      `json:"events,omitempty"`
-	Logs            *LogAnalysis     `json:"logs,omitempty"`
-	Resources       *ResourceUsage   `json:"resources,omitempty"`
-	Node            *NodeHealth      `json:"node,omitempty"`
-	Recommendations []Recommendation `json:"recommendations"`
-	DiagnosedAt     time.Time        `json:"diagnosedAt"`
+	APIVersion      string             `json:"apiVersion"`
+	Pod             PodInfo            `json:"pod"`
+	Status          PodStatus          `json:"status"`
+	Issues          []Issue            `json:"issues"`
+	Events          []EventInfo        `json:"events,omitempty"`
+	Logs            *LogAnalysis       `json:"logs,omitempty"`
+	Resources       *ResourceUsage     `json:"resources,omitempty"`
+	Node            *NodeHealth        `json:"node,omitempty"`
+	Workload        *WorkloadInfo      `json:"workload,omitempty"`
+	ExpectedFailure string             `json:"expectedFailure,omitempty"` // chaos experiment or node upgrade disrupting the pod on purpose
+	NotReady        []ReadinessBlocker `json:"notReady,omitempty"`        // why a running pod is not ready
+	StartOrder      []ContainerStart   `json:"startOrder,omitempty"`      // how the containers of a multi-container pod started
+	ReadinessGates  []ReadinessGate    `json:"readinessGates,omitempty"`  // custom readiness gates and their conditions
+	Warnings        []AnalyzerWarning  `json:"warnings,omitempty"`        // checks that could not run
+	Acknowledged    []Acknowledgement  `json:"acknowledged,omitempty"`    // acknowledgements that silenced issues
+	Timings         []SectionTiming    `json:"timings,omitempty"`         // how long each analyzer and other section took
+	Recommendations []Recommendation   `json:"recommendations"`
+	HealthScore     int                `json:"healthScore"`
+	DiagnosedAt     time.Time          `json:"diagnosedAt"`
+}
+
+// AnalyzerWarning notes a check that could not run, e.g. because the user
+// may not read nodes, so the diagnosis may be missing issues
+type AnalyzerWarning struct {
+	Analyzer string `json:"analyzer"`
+	Message  string `json:"message"`
+}
+
+// SectionTiming records how long one part of a diagnosis, an analyzer or
+// a lookup such as events or node health, took to run
+type SectionTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	TimedOut bool          `json:"timedOut,omitempty"` // abandoned after the analyzer timeout
 }
 
 // NewDiagnosis creates a new diagnosis for a pod
 func NewDiagnosis(pod PodInfo) *Diagnosis {
 	return &Diagnosis{
+		APIVersion:      APIVersion,
 		Pod:             pod,
 		Status:          StatusUnknown,
 		Issues:          make([]Issue, 0),
 		Events:          make([]EventInfo, 0),
 		Recommendations: make([]Recommendation, 0),
+		HealthScore:     100,
 		DiagnosedAt:     time.Now(),
 	}
 }
@@ -117,6 +176,17 @@ func (d *Diagnosis) AddIssue(issue Issue) {
 	d.Issues = append(d.Issues, issue)
 }
 
+// AddWarning records a check that could not run; repeated warnings are
+// recorded once
+func (d *Diagnosis) AddWarning(w AnalyzerWarning) {
+	for _, existing := range d.Warnings {
+		if existing == w {
+			return
+		}
+	}
+	d.Warnings = append(d.Warnings, w)
+}
+
 // AddRecommendation adds a recommendation to the diagnosis
 func (d *Diagnosis) AddRecommendation(rec Recommendation) {
 	d.Recommendations = append(d.Recommendations, rec)
@@ -137,6 +207,40 @@ func (d *Diagnosis) IsHealthy() bool {
 	return len(d.Issues) == 0 && d.Status == StatusHealthy
 }
 
+// IsExpectedFailure returns true if the pod is unhealthy because a chaos
+// experiment is disrupting it on purpose, or its node is being upgraded
+func (d *Diagnosis) IsExpectedFailure() bool {
+	return d.ExpectedFailure != "" && !d.IsHealthy()
+}
+
+// ContainerIssues returns the issues attributed to the named container
+func (d *Diagnosis) ContainerIssues(container string) []Issue {
+	var issues []Issue
+	for _, issue := range d.Issues {
+		if issue.Container() == container {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// PodIssues returns the issues that are not attributed to a single
+// container, such as scheduling, node and workload problems
+func (d *Diagnosis) PodIssues() []Issue {
+	return d.ContainerIssues("")
+}
+
+// IssuesByContainer groups issues by container name. Issues not attributed
+// to a container are grouped under the empty name.
+func (d *Diagnosis) IssuesByContainer() map[string][]Issue {
+	groups := make(map[string][]Issue)
+	for _, issue := range d.Issues {
+		name := issue.Container()
+		groups[name] = append(groups[name], issue)
+	}
+	return groups
+}
+
 // IssueCount returns the count of issues by severity
 func (d *Diagnosis) IssueCount() (critical, warning, info int) {
 	for _, issue := range d.Issues {
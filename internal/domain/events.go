@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// EventGroup is a set of warning events with the same reason on the same
+// object, deduplicated into one entry
+type EventGroup struct {
+	Namespace string    `json:"namespace"`
+	Object    string    `json:"object"` // involved object as Kind/name, e.g. Pod/web-7d8f
+	Reason    string    `json:"reason"`
+	Severity  Severity  `json:"severity"`
+	Category  string    `json:"category"`
+	Message   string    `json:"message"` // most recent message
+	Count     int32     `json:"count"`   // occurrences across all grouped events
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// GroupEventsByReason deduplicates a pod's warning events by reason, most
+// recently seen first. Long-broken pods accumulate hundreds of identical
+// events; grouped, each reason shows once with its total count.
+func GroupEventsByReason(events []EventInfo) []EventGroup {
+	var groups []EventGroup
+	index := make(map[string]int)
+
+	for _, e := range events {
+		if e.Type != "Warning" {
+			continue
+		}
+		count := e.Count
+		if count < 1 {
+			count = 1
+		}
+		firstSeen := e.FirstSeen
+		if firstSeen.IsZero() {
+			firstSeen = e.LastSeen
+		}
+
+		i, ok := index[e.Reason]
+		if !ok {
+			index[e.Reason] = len(groups)
+			groups = append(groups, EventGroup{
+				Reason:    e.Reason,
+				Message:   e.Message,
+				Count:     count,
+				FirstSeen: firstSeen,
+				LastSeen:  e.LastSeen,
+			})
+			continue
+		}
+
+		g := &groups[i]
+		g.Count += count
+		if !firstSeen.IsZero() && (g.FirstSeen.IsZero() || firstSeen.Before(g.FirstSeen)) {
+			g.FirstSeen = firstSeen
+		}
+		if e.LastSeen.After(g.LastSeen) {
+			g.LastSeen = e.LastSeen
+			g.Message = e.Message
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].LastSeen.After(groups[j].LastSeen)
+	})
+	return groups
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueFilter selects the issues a diagnosis reports, to leave out noise
+// such as info-level findings
+type IssueFilter struct {
+	MinSeverity Severity // report issues at least this severe; empty for all
+	Categories  []string // report issues of these categories only; empty for all
+}
+
+// ParseSeverity parses a severity name, case-insensitively
+func ParseSeverity(s string) (Severity, error) {
+	switch severity := Severity(strings.ToLower(strings.TrimSpace(s))); severity {
+	case SeverityCritical, SeverityWarning, SeverityInfo:
+		return severity, nil
+	}
+	return "", fmt.Errorf("unknown severity %q: use critical, warning or info", s)
+}
+
+// IsZero reports whether the filter keeps every issue
+func (f IssueFilter) IsZero() bool {
+	return f.MinSeverity == "" && len(f.Categories) == 0
+}
+
+// Matches reports whether the filter keeps an issue
+func (f IssueFilter) Matches(issue Issue) bool {
+	if f.MinSeverity != "" && issue.Severity.Rank() > f.MinSeverity.Rank() {
+		return false
+	}
+	if len(f.Categories) == 0 {
+		return true
+	}
+	for _, c := range f.Categories {
+		if strings.EqualFold(c, issue.Category) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterIssues drops the issues the filter does not keep
+func (d *Diagnosis) FilterIssues(f IssueFilter) {
+	if f.IsZero() {
+		return
+	}
+	issues := d.Issues[:0]
+	for _, issue := range d.Issues {
+		if f.Matches(issue) {
+			issues = append(issues, issue)
+		}
+	}
+	d.Issues = issues
+}
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"maps"
+	"slices"
+)
+
 // Severity represents the severity level of an issue
 type Severity string
 
@@ -11,11 +16,25 @@ const (
 
 // Issue represents a detected problem with a pod
 type Issue struct {
+	ID          string            `json:"id,omitempty"` // stable issue type, e.g. PD-CRASHLOOP-001; see pod-doctor explain
 	Severity    Severity          `json:"severity"`
 	Category    string            `json:"category"` // container, node, network, resources, scheduling, logs
 	Title       string            `json:"title"`
 	Description string            `json:"description"`
 	Details     map[string]string `json:"details,omitempty"`
+	Objects     []ObjectRef       `json:"objects,omitempty"` // Kubernetes objects the issue involves, the pod first
+}
+
+// ObjectRef identifies a Kubernetes object an issue involves, so tooling
+// can link the issue to it without parsing names out of Details. A
+// container is referenced as its pod with a field path, like the involved
+// object of an event.
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"` // empty for cluster-scoped objects
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`       // empty if the object was not fetched, e.g. because it does not exist
+	FieldPath string `json:"fieldPath,omitempty"` // e.g. spec.containers{api}
 }
 
 // NewIssue creates a new issue with the given parameters
@@ -29,6 +48,14 @@ func NewIssue(severity Severity, category, title, description string) Issue {
 	}
 }
 
+// Clone returns a copy of the issue that shares no details or objects with
+// it, so either can be changed without affecting the other
+func (i Issue) Clone() Issue {
+	i.Details = maps.Clone(i.Details)
+	i.Objects = slices.Clone(i.Objects)
+	return i
+}
+
 // WithDetail adds a detail to the issue and returns the issue for chaining
 func (i Issue) WithDetail(key, value string) Issue {
 	if i.Details == nil {
@@ -38,6 +65,31 @@ func (i Issue) WithDetail(key, value string) Issue {
 	return i
 }
 
+// WithObject adds an involved object to the issue, unless it already
+// names it, and returns the issue for chaining. A UID fills in a reference
+// added without one.
+func (i Issue) WithObject(ref ObjectRef) Issue {
+	for j, o := range i.Objects {
+		if o.Kind == ref.Kind && o.Namespace == ref.Namespace && o.Name == ref.Name && o.FieldPath == ref.FieldPath {
+			if o.UID == "" && ref.UID != "" {
+				objects := append([]ObjectRef(nil), i.Objects...)
+				objects[j].UID = ref.UID
+				i.Objects = objects
+			}
+			return i
+		}
+	}
+	// Copy on append, so copies of the issue keep their own objects
+	i.Objects = append(i.Objects[:len(i.Objects):len(i.Objects)], ref)
+	return i
+}
+
+// Container returns the name of the container the issue is about, or an
+// empty string for pod-level issues
+func (i Issue) Container() string {
+	return i.Details["container"]
+}
+
 // IsCritical returns true if the issue is critical
 func (i Issue) IsCritical() bool {
 	return i.Severity == SeverityCritical
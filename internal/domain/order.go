@@ -0,0 +1,70 @@
+package domain
+
+import "sort"
+
+// Output of the same cluster state must not change between runs, so saved
+// reports can be diffed. Analyzers run concurrently and keep some of their
+// state in maps; the functions below put what they report in a fixed order.
+
+// Rank orders severities worst first: critical, warning, then info and
+// anything else
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 0
+	case SeverityWarning:
+		return 1
+	}
+	return 2
+}
+
+// SortIssues orders issues by severity, worst first, then by category and
+// title. Issues that tie keep the order they were reported in.
+func SortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() < b.Severity.Rank()
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Title < b.Title
+	})
+}
+
+// SortEvents orders events by when they were last seen, oldest first, then
+// by reason and message, so events of the same second keep their order
+func SortEvents(events []EventInfo) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.LastSeen.Equal(b.LastSeen) {
+			return a.LastSeen.Before(b.LastSeen)
+		}
+		if a.Reason != b.Reason {
+			return a.Reason < b.Reason
+		}
+		return a.Message < b.Message
+	})
+}
+
+// SortDiagnoses orders diagnoses by namespace, then pod name
+func SortDiagnoses(diagnoses []*Diagnosis) {
+	sort.SliceStable(diagnoses, func(i, j int) bool {
+		a, b := diagnoses[i].Pod, diagnoses[j].Pod
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// DetailKeys returns the keys of the issue's details, sorted
+func (i Issue) DetailKeys() []string {
+	keys := make([]string, 0, len(i.Details))
+	for key := range i.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
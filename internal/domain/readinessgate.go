@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ReadinessGate is one custom readiness gate of a pod with the condition
+// its controller posted, if any
+type ReadinessGate struct {
+	ConditionType string    `json:"conditionType"`
+	Status        string    `json:"status"` // True, False, Unknown, or empty when no condition was posted
+	Reason        string    `json:"reason,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Since         time.Time `json:"since,omitempty"`      // last transition of the condition
+	Controller    string    `json:"controller,omitempty"` // the controller known to post the condition
+}
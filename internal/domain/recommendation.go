@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"regexp"
+	"strings"
+)
+
 // Recommendation represents a suggested fix for an issue
 type Recommendation struct {
 	Priority    int    `json:"priority"`
@@ -22,3 +27,29 @@ func (r Recommendation) WithCommand(cmd string) Recommendation {
 	r.Command = cmd
 	return r
 }
+
+// ShellArg returns s as one shell word: as-is when it is a plain token,
+// such as a name, image reference or placeholder, and single-quoted
+// otherwise. Values from the cluster go through it when building commands,
+// so that a crafted image or name cannot run its own commands when an
+// operator runs the command or a fix script.
+func ShellArg(s string) string {
+	if plainShellWord.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ShellArgs returns values as shell words separated by spaces
+func ShellArgs(values []string) string {
+	words := make([]string, len(values))
+	for i, v := range values {
+		words[i] = ShellArg(v)
+	}
+	return strings.Join(words, " ")
+}
+
+// plainShellWord matches words the shell takes literally: no quotes,
+// spaces, metacharacters or expansions, except for placeholders such as
+// <image>, which commands are not run with
+var plainShellWord = regexp.MustCompile(`^([A-Za-z0-9._/:@=+,%-]|<[A-Za-z][\w.-]*>)+$`)
@@ -0,0 +1,48 @@
+package domain
+
+// severityWeights is the score penalty for one issue of each severity
+var severityWeights = map[Severity]float64{
+	SeverityCritical: 30,
+	SeverityWarning:  10,
+	SeverityInfo:     2,
+}
+
+// categoryWeights scales the severity penalty by how directly an issue
+// category affects the pod. Categories not listed use a weight of 1.
+var categoryWeights = map[string]float64{
+	"container":  1.0,
+	"resources":  1.0,
+	"scheduling": 1.0,
+	"node":       1.0,
+	"storage":    1.0,
+	"network":    0.8,
+	"probes":     0.8,
+	"health":     0.8,
+	"events":     0.6,
+	"logs":       0.5,
+}
+
+// CalculateHealthScore computes a 0-100 health score for the diagnosis,
+// where 100 means no issues were found. Each issue subtracts a penalty
+// weighted by its severity and category.
+func (d *Diagnosis) CalculateHealthScore() int {
+	penalty := 0.0
+	for _, issue := range d.Issues {
+		weight, ok := categoryWeights[issue.Category]
+		if !ok {
+			weight = 1
+		}
+		penalty += severityWeights[issue.Severity] * weight
+	}
+
+	// A pod that is not healthy should never score a perfect 100
+	if penalty == 0 && d.Status != StatusHealthy {
+		penalty = severityWeights[SeverityWarning]
+	}
+
+	score := 100 - int(penalty+0.5)
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// ContainerStart is one container of a pod in start order: init containers
+// in spec order, native sidecars among them, then the app containers
+type ContainerStart struct {
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`                 // init, sidecar or app
+	StartedAt  time.Time `json:"startedAt,omitempty"`  // earliest start still known, before any restart
+	FinishedAt time.Time `json:"finishedAt,omitempty"` // when an init container completed
+	Ready      bool      `json:"ready"`
+	Restarts   int32     `json:"restarts,omitempty"`
+	DependsOn  []string  `json:"dependsOn,omitempty"` // containers it needs running first
+}
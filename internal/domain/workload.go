@@ -0,0 +1,48 @@
+package domain
+
+import "strings"
+
+// WorkloadInfo identifies the top-level controller that manages a pod
+type WorkloadInfo struct {
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Chain     []string `json:"chain,omitempty"` // owner chain from the pod up, e.g. ReplicaSet/web-7d8f, Deployment/web
+}
+
+// Ref returns the workload as a kubectl resource reference, e.g. deployment/web
+func (w *WorkloadInfo) Ref() string {
+	return strings.ToLower(w.Kind) + "/" + w.Name
+}
+
+// WorkloadReport aggregates the diagnoses of the pods of one workload, or
+// of the pods matching a label selector, separating the issues every pod
+// has from those only some pods have
+type WorkloadReport struct {
+	APIVersion      string            `json:"apiVersion"`
+	Kind            string            `json:"kind,omitempty"` // e.g. Deployment; empty for a label selector
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace"`
+	Selector        string            `json:"selector"`
+	Pods            int               `json:"pods"`
+	Healthy         int               `json:"healthy"`
+	Statuses        map[PodStatus]int `json:"statuses"`
+	CommonIssues    []WorkloadIssue   `json:"commonIssues"` // found on every pod
+	PodIssues       []WorkloadIssue   `json:"podIssues"`    // found on some pods only
+	Recommendations []Recommendation  `json:"recommendations"`
+	Diagnoses       []*Diagnosis      `json:"diagnoses"`
+}
+
+// WorkloadIssue is an issue found on one or more pods of a workload
+type WorkloadIssue struct {
+	Issue Issue    `json:"issue"` // as reported for the first affected pod
+	Pods  []string `json:"pods"`
+}
+
+// Ref returns the report's target, e.g. deployment/web or the selector
+func (r *WorkloadReport) Ref() string {
+	if r.Kind == "" {
+		return r.Selector
+	}
+	return strings.ToLower(r.Kind) + "/" + r.Name
+}
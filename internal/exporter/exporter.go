@@ -0,0 +1,140 @@
+package exporter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	podIssuesDesc = prometheus.NewDesc(
+		"poddoctor_pod_issues",
+		"Number of issues detected on a pod, by severity and category.",
+		[]string{"namespace", "pod", "severity", "category"}, nil)
+	podStatusDesc = prometheus.NewDesc(
+		"poddoctor_pod_status",
+		"Detected pod status; the series with value 1 is the current status.",
+		[]string{"namespace", "pod", "status"}, nil)
+	podHealthScoreDesc = prometheus.NewDesc(
+		"poddoctor_pod_health_score",
+		"Pod health score from 0 (critical) to 100 (healthy).",
+		[]string{"namespace", "pod"}, nil)
+	podExpectedFailureDesc = prometheus.NewDesc(
+		"poddoctor_pod_expected_failure",
+		"Set to 1 for unhealthy pods disrupted on purpose by a chaos experiment or node upgrade.",
+		[]string{"namespace", "pod", "experiment"}, nil)
+	sloBreachDesc = prometheus.NewDesc(
+		"poddoctor_slo_breach",
+		"Set to 1 for each workload lifecycle SLO indicator currently breached.",
+		[]string{"namespace", "workload", "indicator"}, nil)
+	podsScannedDesc = prometheus.NewDesc(
+		"poddoctor_pods_scanned",
+		"Number of pods diagnosed by the last scan.",
+		nil, nil)
+	scanDurationDesc = prometheus.NewDesc(
+		"poddoctor_scan_duration_seconds",
+		"Duration of the last scan.",
+		nil, nil)
+	scanTimestampDesc = prometheus.NewDesc(
+		"poddoctor_last_scan_timestamp_seconds",
+		"Unix time the last successful scan finished.",
+		nil, nil)
+	scanErrorsDesc = prometheus.NewDesc(
+		"poddoctor_scan_errors_total",
+		"Number of scans that failed to list pods.",
+		nil, nil)
+)
+
+// Exporter exposes the results of the latest scan as Prometheus metrics.
+// Metrics are built from a snapshot on every collection, so pods that
+// disappear between scans stop being reported.
+type Exporter struct {
+	mu           sync.Mutex
+	diagnoses    []*domain.Diagnosis
+	breaches     []domain.Issue
+	scanDuration time.Duration
+	lastScan     time.Time
+	scanErrors   int
+}
+
+// New creates an exporter with no scan results
+func New() *Exporter {
+	return &Exporter{}
+}
+
+// Update replaces the exported results with those of a finished scan
+func (e *Exporter) Update(diagnoses []*domain.Diagnosis, breaches []domain.Issue, duration time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.diagnoses = diagnoses
+	e.breaches = breaches
+	e.scanDuration = duration
+	e.lastScan = time.Now()
+}
+
+// ScanFailed records a scan that could not list pods. The previous results
+// stay exported.
+func (e *Exporter) ScanFailed() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.scanErrors++
+}
+
+// LastScan returns when the last successful scan finished, or the zero time
+func (e *Exporter) LastScan() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastScan
+}
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- podIssuesDesc
+	ch <- podStatusDesc
+	ch <- podHealthScoreDesc
+	ch <- podExpectedFailureDesc
+	ch <- sloBreachDesc
+	ch <- podsScannedDesc
+	ch <- scanDurationDesc
+	ch <- scanTimestampDesc
+	ch <- scanErrorsDesc
+}
+
+// Collect implements prometheus.Collector
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, d := range e.diagnoses {
+		ns, pod := d.Pod.Namespace, d.Pod.Name
+
+		type issueKey struct{ severity, category string }
+		counts := make(map[issueKey]int)
+		for _, issue := range d.Issues {
+			counts[issueKey{string(issue.Severity), issue.Category}]++
+		}
+		for k, count := range counts {
+			ch <- prometheus.MustNewConstMetric(podIssuesDesc, prometheus.GaugeValue, float64(count), ns, pod, k.severity, k.category)
+		}
+
+		ch <- prometheus.MustNewConstMetric(podStatusDesc, prometheus.GaugeValue, 1, ns, pod, string(d.Status))
+		ch <- prometheus.MustNewConstMetric(podHealthScoreDesc, prometheus.GaugeValue, float64(d.HealthScore), ns, pod)
+		if d.IsExpectedFailure() {
+			ch <- prometheus.MustNewConstMetric(podExpectedFailureDesc, prometheus.GaugeValue, 1, ns, pod, d.ExpectedFailure)
+		}
+	}
+
+	for _, issue := range e.breaches {
+		ch <- prometheus.MustNewConstMetric(sloBreachDesc, prometheus.GaugeValue, 1,
+			issue.Details["namespace"], issue.Details["workload"], issue.Details["indicator"])
+	}
+
+	ch <- prometheus.MustNewConstMetric(podsScannedDesc, prometheus.GaugeValue, float64(len(e.diagnoses)))
+	ch <- prometheus.MustNewConstMetric(scanDurationDesc, prometheus.GaugeValue, e.scanDuration.Seconds())
+	if !e.lastScan.IsZero() {
+		ch <- prometheus.MustNewConstMetric(scanTimestampDesc, prometheus.GaugeValue, float64(e.lastScan.Unix()))
+	}
+	ch <- prometheus.MustNewConstMetric(scanErrorsDesc, prometheus.CounterValue, float64(e.scanErrors))
+}
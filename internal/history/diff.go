@@ -0,0 +1,97 @@
+package history
+
+import (
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// Diff is what changed between two diagnoses of the same pod
+type Diff struct {
+	Namespace      string           `json:"namespace"`
+	Pod            string           `json:"pod"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	StatusFrom     domain.PodStatus `json:"statusFrom"`
+	StatusTo       domain.PodStatus `json:"statusTo"`
+	ScoreFrom      int              `json:"scoreFrom"`
+	ScoreTo        int              `json:"scoreTo"`
+	NewIssues      []domain.Issue   `json:"newIssues"`
+	ResolvedIssues []domain.Issue   `json:"resolvedIssues"`
+	Restarts       []RestartDelta   `json:"restarts,omitempty"`
+	NodeFrom       string           `json:"nodeFrom,omitempty"`
+	NodeTo         string           `json:"nodeTo,omitempty"`
+}
+
+// RestartDelta is the change in a container's restart count
+type RestartDelta struct {
+	Container string `json:"container"`
+	From      int32  `json:"from"`
+	To        int32  `json:"to"`
+}
+
+// Delta returns how many times the container restarted in between. It is
+// negative when the count was reset, e.g. the pod was recreated.
+func (r RestartDelta) Delta() int32 {
+	return r.To - r.From
+}
+
+// Compare returns what changed from prev to curr. Issues are matched by
+// category and title, which name the affected container.
+func Compare(prev, curr *domain.Diagnosis) Diff {
+	diff := Diff{
+		Namespace:      curr.Pod.Namespace,
+		Pod:            curr.Pod.Name,
+		From:           prev.DiagnosedAt,
+		To:             curr.DiagnosedAt,
+		StatusFrom:     prev.Status,
+		StatusTo:       curr.Status,
+		ScoreFrom:      prev.HealthScore,
+		ScoreTo:        curr.HealthScore,
+		NewIssues:      subtractIssues(curr.Issues, prev.Issues),
+		ResolvedIssues: subtractIssues(prev.Issues, curr.Issues),
+		NodeFrom:       prev.Pod.Node,
+		NodeTo:         curr.Pod.Node,
+	}
+
+	prevRestarts := make(map[string]int32)
+	for _, c := range prev.Pod.Containers {
+		prevRestarts[c.Name] = c.RestartCount
+	}
+	for _, c := range curr.Pod.Containers {
+		from, ok := prevRestarts[c.Name]
+		if ok && from != c.RestartCount {
+			diff.Restarts = append(diff.Restarts, RestartDelta{Container: c.Name, From: from, To: c.RestartCount})
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty reports whether nothing changed
+func (d Diff) IsEmpty() bool {
+	return d.StatusFrom == d.StatusTo && d.ScoreFrom == d.ScoreTo &&
+		len(d.NewIssues) == 0 && len(d.ResolvedIssues) == 0 &&
+		len(d.Restarts) == 0 && d.NodeFrom == d.NodeTo
+}
+
+// subtractIssues returns the issues in a that are not in b
+func subtractIssues(a, b []domain.Issue) []domain.Issue {
+	seen := make(map[string]bool, len(b))
+	for _, issue := range b {
+		seen[issueKey(issue)] = true
+	}
+
+	result := make([]domain.Issue, 0)
+	for _, issue := range a {
+		if !seen[issueKey(issue)] {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// issueKey identifies an issue across diagnoses
+func issueKey(issue domain.Issue) string {
+	return issue.Category + "\x00" + issue.Title
+}
@@ -0,0 +1,152 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// MaxEntries bounds the diagnoses kept per pod; older ones are pruned when
+// a new diagnosis is recorded
+const MaxEntries = 100
+
+// timeLayout names history files so they sort chronologically
+const timeLayout = "20060102T150405.000000000Z"
+
+// DefaultDir returns ~/.pod-doctor/history, or an empty string if the home
+// directory is unknown
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".pod-doctor", "history")
+}
+
+// Store keeps past diagnoses as JSON files, one directory per pod:
+// <dir>/<namespace>/<pod>/<diagnosed at>.json
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store rooted at dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Entry is a recorded diagnosis
+type Entry struct {
+	Path      string
+	Diagnosis *domain.Diagnosis
+}
+
+// Record saves a diagnosis and prunes the pod's oldest entries beyond
+// MaxEntries
+func (s *Store) Record(d *domain.Diagnosis) error {
+	podDir := s.podDir(d.Pod.Namespace, d.Pod.Name)
+	if err := os.MkdirAll(podDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnosis: %w", err)
+	}
+	name := d.DiagnosedAt.UTC().Format(timeLayout) + ".json"
+	if err := os.WriteFile(filepath.Join(podDir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to record diagnosis: %w", err)
+	}
+
+	files, err := s.files(podDir)
+	if err != nil {
+		return err
+	}
+	for len(files) > MaxEntries {
+		if err := os.Remove(files[0]); err != nil {
+			return fmt.Errorf("failed to prune history: %w", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// List returns the recorded diagnoses of a pod, oldest first. A pod that
+// was never recorded has no entries.
+func (s *Store) List(namespace, name string) ([]Entry, error) {
+	files, err := s.files(s.podDir(namespace, name))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(files))
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		var d domain.Diagnosis
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("invalid history entry %s: %w", path, err)
+		}
+		entries = append(entries, Entry{Path: path, Diagnosis: &d})
+	}
+	return entries, nil
+}
+
+// Latest returns the most recent recorded diagnosis of a pod, or nil if
+// there is none
+func (s *Store) Latest(namespace, name string) (*domain.Diagnosis, error) {
+	entries, err := s.List(namespace, name)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries[len(entries)-1].Diagnosis, nil
+}
+
+// At returns the most recent diagnosis of a pod recorded at or before t,
+// or nil if there is none
+func (s *Store) At(namespace, name string, t time.Time) (*domain.Diagnosis, error) {
+	entries, err := s.List(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if d := entries[i].Diagnosis; !d.DiagnosedAt.After(t) {
+			return d, nil
+		}
+	}
+	return nil, nil
+}
+
+// podDir returns the directory holding a pod's history
+func (s *Store) podDir(namespace, name string) string {
+	return filepath.Join(s.dir, namespace, name)
+}
+
+// files returns the history files in a pod directory, oldest first
+func (s *Store) files(podDir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(podDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var files []string
+	for _, e := range dirEntries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, filepath.Join(podDir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
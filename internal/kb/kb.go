@@ -0,0 +1,155 @@
+// Package kb is the knowledge base behind pod-doctor explain: an article for
+// each type of issue, bundled in the binary, and the stable issue IDs that
+// point to them.
+package kb
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed articles.yaml
+var articlesYAML []byte
+
+// Article explains one type of issue
+type Article struct {
+	ID         string   `json:"id" yaml:"id"`
+	Title      string   `json:"title" yaml:"title"`
+	Categories []string `json:"categories,omitempty" yaml:"categories,omitempty"`
+	Match      string   `json:"-" yaml:"match,omitempty"` // regular expression on issue titles
+	Summary    string   `json:"summary" yaml:"summary"`
+	Causes     []string `json:"causes" yaml:"causes"`
+	Steps      []string `json:"steps" yaml:"steps"`
+	Links      []string `json:"links,omitempty" yaml:"links,omitempty"`
+
+	match *regexp.Regexp
+}
+
+// articles holds the bundled articles in matching order
+var articles = mustLoad(articlesYAML)
+
+// mustLoad parses the bundled articles. They ship with the binary, so a
+// malformed one is a build mistake rather than a runtime error.
+func mustLoad(data []byte) []Article {
+	loaded, err := load(data)
+	if err != nil {
+		panic(fmt.Sprintf("kb: %v", err))
+	}
+	return loaded
+}
+
+// load parses articles and compiles their match expressions
+func load(data []byte) ([]Article, error) {
+	var loaded []Article
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("invalid articles: %w", err)
+	}
+	seen := make(map[string]bool, len(loaded))
+	for i := range loaded {
+		a := &loaded[i]
+		if a.ID == "" {
+			return nil, fmt.Errorf("article %d has no id", i+1)
+		}
+		if seen[a.ID] {
+			return nil, fmt.Errorf("duplicate article id %s", a.ID)
+		}
+		seen[a.ID] = true
+		if a.Match != "" {
+			re, err := regexp.Compile(a.Match)
+			if err != nil {
+				return nil, fmt.Errorf("article %s: invalid match: %w", a.ID, err)
+			}
+			a.match = re
+		}
+	}
+	return loaded, nil
+}
+
+// Articles returns every article, sorted by ID
+func Articles() []Article {
+	sorted := append([]Article(nil), articles...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// Lookup returns the article with an issue ID, ignoring case
+func Lookup(id string) (Article, bool) {
+	for _, a := range articles {
+		if strings.EqualFold(a.ID, id) {
+			return a, true
+		}
+	}
+	return Article{}, false
+}
+
+// Classify returns the ID of the article for an issue: the first article of
+// the issue's category whose match expression matches its title, else the
+// category's fallback article, else the general one
+func Classify(issue domain.Issue) string {
+	fallback, general := "", ""
+	for _, a := range articles {
+		if len(a.Categories) > 0 && !containsString(a.Categories, issue.Category) {
+			continue
+		}
+		switch {
+		case a.match != nil:
+			if a.match.MatchString(issue.Title) {
+				return a.ID
+			}
+		case len(a.Categories) == 0:
+			if general == "" {
+				general = a.ID
+			}
+		case fallback == "":
+			fallback = a.ID
+		}
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return general
+}
+
+// Assign gives every issue without an ID the ID of its article. Issues that
+// already carry one, such as ones reported by analyzer plugins, keep it.
+func Assign(issues []domain.Issue) {
+	for i := range issues {
+		if issues[i].ID == "" {
+			issues[i].ID = Classify(issues[i])
+		}
+	}
+}
+
+// ForIssues returns the articles of the given issues, once each, in the
+// order the issues come in. Issue IDs without an article are skipped.
+func ForIssues(issues []domain.Issue) []Article {
+	var found []Article
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.ID == "" || seen[issue.ID] {
+			continue
+		}
+		seen[issue.ID] = true
+		if a, ok := Lookup(issue.ID); ok {
+			found = append(found, a)
+		}
+	}
+	return found
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// AccessCheck is a permission pod-doctor uses, with what stops working
+// without it
+type AccessCheck struct {
+	Group    string `json:"group,omitempty"`
+	Resource string `json:"resource"` // e.g. pods or pods/log
+	Verb     string `json:"verb"`
+	Cluster  bool   `json:"cluster,omitempty"` // cluster-scoped resource
+	UsedFor  string `json:"usedFor"`
+	Required bool   `json:"required,omitempty"` // pod-doctor cannot diagnose pods without it
+}
+
+// AccessResult is the outcome of an AccessCheck
+type AccessResult struct {
+	AccessCheck
+	Namespace string `json:"namespace,omitempty"`
+	Allowed   bool   `json:"allowed"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// AccessChecks lists the permissions pod-doctor uses, required ones first
+var AccessChecks = []AccessCheck{
+	{Resource: "pods", Verb: "get", UsedFor: "diagnosing a pod", Required: true},
+	{Resource: "pods", Verb: "list", UsedFor: "scan, the TUI pod list and similar-name suggestions", Required: true},
+	{Resource: "pods", Verb: "watch", UsedFor: "scan --watch"},
+	{Resource: "pods/log", Verb: "get", UsedFor: "log analysis and the log viewer"},
+	{Resource: "pods", Verb: "delete", UsedFor: "deleting a pod from the TUI"},
+	{Resource: "pods/ephemeralcontainers", Verb: "update", UsedFor: "the debug command and the TUI debug shell"},
+	{Resource: "events", Verb: "list", UsedFor: "event timeline and event-based analyzers"},
+	{Resource: "nodes", Verb: "get", Cluster: true, UsedFor: "node health"},
+	{Resource: "nodes", Verb: "list", Cluster: true, UsedFor: "scheduling explanations"},
+	{Resource: "namespaces", Verb: "list", Cluster: true, UsedFor: "the TUI namespace list"},
+	{Resource: "namespaces", Verb: "get", Cluster: true, UsedFor: "production namespace and provenance enforcement checks"},
+	{Resource: "services", Verb: "list", UsedFor: "network analysis and service link env sizes"},
+	{Group: "discovery.k8s.io", Resource: "endpointslices", Verb: "list", UsedFor: "service endpoint checks"},
+	{Resource: "persistentvolumeclaims", Verb: "get", UsedFor: "volume analysis"},
+	{Group: "storage.k8s.io", Resource: "storageclasses", Verb: "get", Cluster: true, UsedFor: "storage class checks"},
+	{Resource: "configmaps", Verb: "get", UsedFor: "missing configmap checks, env size checks and shared acknowledgements"},
+	{Resource: "configmaps", Verb: "create", UsedFor: "the ack command, in a namespace without acknowledgements yet"},
+	{Resource: "configmaps", Verb: "update", UsedFor: "the ack command"},
+	{Resource: "secrets", Verb: "get", UsedFor: "missing secret and image pull secret checks"},
+	{Resource: "serviceaccounts", Verb: "get", UsedFor: "workload identity token audience checks"},
+	{Group: "apps", Resource: "replicasets", Verb: "get", UsedFor: "resolving owning deployments"},
+	{Group: "apps", Resource: "deployments", Verb: "get", UsedFor: "workload rollout analysis"},
+	{Group: "apps", Resource: "deployments", Verb: "patch", UsedFor: "restarting a deployment from the TUI"},
+	{Group: "apps", Resource: "statefulsets", Verb: "get", UsedFor: "workload and headless service analysis"},
+	{Group: "apps", Resource: "daemonsets", Verb: "get", UsedFor: "workload analysis"},
+	{Group: "batch", Resource: "jobs", Verb: "get", UsedFor: "job analysis"},
+	{Group: "policy", Resource: "poddisruptionbudgets", Verb: "list", UsedFor: "node drain safety and disruption budget checks"},
+	{Group: "networking.k8s.io", Resource: "networkpolicies", Verb: "list", UsedFor: "network policy analysis"},
+	{Group: "metrics.k8s.io", Resource: "pods", Verb: "get", UsedFor: "live resource usage (metrics-server)"},
+	{Group: "metrics.k8s.io", Resource: "pods", Verb: "list", UsedFor: "eviction ranking and noisy neighbors on a pod's node"},
+	{Group: "autoscaling.k8s.io", Resource: "verticalpodautoscalers", Verb: "list", UsedFor: "VPA comparison"},
+}
+
+// CheckAccess asks the API server, through SelfSubjectAccessReviews,
+// whether the current user has each permission in AccessChecks. Namespaced
+// permissions are checked in namespace, or across all namespaces if it is
+// empty.
+func (c *Client) CheckAccess(ctx context.Context, namespace string) ([]AccessResult, error) {
+	results := make([]AccessResult, 0, len(AccessChecks))
+	for _, check := range AccessChecks {
+		resource, subresource, _ := strings.Cut(check.Resource, "/")
+		attrs := &authorizationv1.ResourceAttributes{
+			Verb:        check.Verb,
+			Group:       check.Group,
+			Resource:    resource,
+			Subresource: subresource,
+		}
+		if !check.Cluster {
+			attrs.Namespace = namespace
+		}
+
+		status, err := c.reviewAccess(ctx, attrs)
+		if err != nil {
+			return nil, err
+		}
+
+		reason := status.Reason
+		if status.EvaluationError != "" && reason == "" {
+			reason = status.EvaluationError
+		}
+		results = append(results, AccessResult{
+			AccessCheck: check,
+			Namespace:   attrs.Namespace,
+			Allowed:     status.Allowed,
+			Reason:      reason,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// AcknowledgementsConfigMap is the ConfigMap in each namespace that
+	// holds the acknowledgements of its pods' issues
+	AcknowledgementsConfigMap = "pod-doctor-acks"
+	// acknowledgementsKey is the ConfigMap key of the acknowledgements, a
+	// YAML list that can also be edited with kubectl edit
+	acknowledgementsKey = "acks.yaml"
+)
+
+// GetAcknowledgements returns the acknowledgements stored in a namespace,
+// expired ones included, or none if it has no acknowledgements ConfigMap
+func (c *Client) GetAcknowledgements(ctx context.Context, namespace string) ([]domain.Acknowledgement, error) {
+	cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, AcknowledgementsConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapAPIError(err, "get", "configmaps", namespace, AcknowledgementsConfigMap)
+	}
+	return parseAcknowledgements(cm)
+}
+
+// UpdateAcknowledgements changes the acknowledgements stored in a
+// namespace, creating their ConfigMap if needed. update gets the current
+// acknowledgements and returns the new ones; it is called again if another
+// writer changed them in the meantime.
+func (c *Client) UpdateAcknowledgements(ctx context.Context, namespace string, update func([]domain.Acknowledgement) []domain.Acknowledgement) error {
+	configMaps := c.clientset.CoreV1().ConfigMaps(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, AcknowledgementsConfigMap, metav1.GetOptions{})
+		create := apierrors.IsNotFound(err)
+		if create {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:      AcknowledgementsConfigMap,
+				Namespace: namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "pod-doctor"},
+			}}
+		} else if err != nil {
+			return wrapAPIError(err, "get", "configmaps", namespace, AcknowledgementsConfigMap)
+		}
+
+		acks, err := parseAcknowledgements(cm)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(update(acks))
+		if err != nil {
+			return fmt.Errorf("failed to marshal acknowledgements: %w", err)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[acknowledgementsKey] = string(data)
+
+		if create {
+			_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Created by another writer since; retry as an update
+				return apierrors.NewConflict(corev1.Resource("configmaps"), AcknowledgementsConfigMap, err)
+			}
+			return wrapAPIError(err, "create", "configmaps", namespace, AcknowledgementsConfigMap)
+		}
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			return err
+		}
+		return wrapAPIError(err, "update", "configmaps", namespace, AcknowledgementsConfigMap)
+	})
+}
+
+// parseAcknowledgements reads the acknowledgements of a ConfigMap
+func parseAcknowledgements(cm *corev1.ConfigMap) ([]domain.Acknowledgement, error) {
+	var acks []domain.Acknowledgement
+	if err := yaml.Unmarshal([]byte(cm.Data[acknowledgementsKey]), &acks); err != nil {
+		return nil, fmt.Errorf("invalid acknowledgements in configmap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return acks, nil
+}
@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RestartedAtAnnotation is the pod template annotation kubectl rollout
+// restart sets; changing it rolls out new pods
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// DeletePod deletes a pod; its controller, if it has one, creates a
+// replacement
+func (c *Client) DeletePod(ctx context.Context, namespace, name string) error {
+	err := c.clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	return wrapAPIError(err, "delete", "pods", namespace, name)
+}
+
+// Restartable reports whether a workload can be rolled out again, like
+// kubectl rollout restart
+func Restartable(workload *domain.WorkloadInfo) bool {
+	if workload == nil {
+		return false
+	}
+	switch workload.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return true
+	}
+	return false
+}
+
+// RestartWorkload replaces the pods of a deployment, statefulset or
+// daemonset through a rolling update, by stamping its pod template with
+// the restart time like kubectl rollout restart does
+func (c *Client) RestartWorkload(ctx context.Context, workload *domain.WorkloadInfo) error {
+	if workload == nil {
+		return fmt.Errorf("the pod has no workload to restart")
+	}
+	if !Restartable(workload) {
+		return fmt.Errorf("cannot restart %s: only deployments, statefulsets and daemonsets can be restarted", workload.Ref())
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		RestartedAtAnnotation, time.Now().Format(time.RFC3339))
+	apps := c.clientset.AppsV1()
+	namespace, name := workload.Namespace, workload.Name
+	var err error
+	switch workload.Kind {
+	case "Deployment":
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = apps.DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	}
+	return wrapAPIError(err, "patch", strings.ToLower(workload.Kind)+"s", namespace, name)
+}
@@ -0,0 +1,43 @@
+package kubernetes
+
+import (
+	"context"
+	"sync"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// capabilities caches what the current user may read, so analyzers on
+// restricted clusters skip requests bound to be forbidden instead of
+// failing on each one. Each capability is detected once per client.
+type capabilities struct {
+	nodesOnce sync.Once
+	nodes     bool
+}
+
+// CanReadNodes reports whether the current user may get nodes. If the
+// access review itself fails, nodes are assumed readable and the node
+// request reports the actual error.
+func (c *Client) CanReadNodes(ctx context.Context) bool {
+	if c.caps == nil {
+		return true
+	}
+	c.caps.nodesOnce.Do(func() {
+		status, err := c.reviewAccess(ctx, &authorizationv1.ResourceAttributes{Verb: "get", Resource: "nodes"})
+		c.caps.nodes = err != nil || status.Allowed
+	})
+	return c.caps.nodes
+}
+
+// reviewAccess asks the API server whether the current user may perform
+// the request described by attrs
+func (c *Client) reviewAccess(ctx context.Context, attrs *authorizationv1.ResourceAttributes) (*authorizationv1.SubjectAccessReviewStatus, error) {
+	review, err := c.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attrs},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err, "create", "selfsubjectaccessreviews", "", "")
+	}
+	return &review.Status, nil
+}
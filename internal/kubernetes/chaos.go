@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// chaosMeshResources are the Chaos Mesh experiment kinds that disrupt pods
+var chaosMeshResources = []string{
+	"podchaos", "networkchaos", "stresschaos", "iochaos", "timechaos", "dnschaos", "httpchaos",
+}
+
+// litmusEngineResource is the Litmus ChaosEngine custom resource
+var litmusEngineResource = schema.GroupVersionResource{
+	Group:    "litmuschaos.io",
+	Version:  "v1alpha1",
+	Resource: "chaosengines",
+}
+
+// ChaosExperiment is a running chaos experiment and the pods it targets
+type ChaosExperiment struct {
+	Tool      string // chaos-mesh or litmus
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Pods lists targeted pods as namespace/name when the tool records them
+	Pods []string
+	// TargetNamespace and TargetSelector select targets when only a label
+	// selector is known
+	TargetNamespace string
+	TargetSelector  labels.Selector
+}
+
+// Ref returns a short name for the experiment, e.g. chaos-mesh podchaos/kill-api
+func (e ChaosExperiment) Ref() string {
+	return e.Tool + " " + e.Kind + " " + e.Namespace + "/" + e.Name
+}
+
+// ListActiveChaosExperiments lists running Chaos Mesh and Litmus experiments
+// across all namespaces. Tools whose CRDs are not installed are skipped.
+func (c *Client) ListActiveChaosExperiments(ctx context.Context) ([]ChaosExperiment, error) {
+	var experiments []ChaosExperiment
+
+	for _, resource := range chaosMeshResources {
+		gvr := schema.GroupVersionResource{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: resource}
+		list, err := c.dynamic.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			// Chaos Mesh is not installed
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			if exp, ok := chaosMeshExperiment(item); ok {
+				experiments = append(experiments, exp)
+			}
+		}
+	}
+
+	list, err := c.dynamic.Resource(litmusEngineResource).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, item := range list.Items {
+			if exp, ok := litmusExperiment(item); ok {
+				experiments = append(experiments, exp)
+			}
+		}
+	}
+
+	return experiments, nil
+}
+
+// chaosMeshExperiment reads the injected pods of a running Chaos Mesh experiment
+func chaosMeshExperiment(item unstructured.Unstructured) (ChaosExperiment, bool) {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "experiment", "desiredPhase")
+	if phase != "Run" {
+		return ChaosExperiment{}, false
+	}
+
+	exp := ChaosExperiment{
+		Tool:      "chaos-mesh",
+		Kind:      item.GetKind(),
+		Namespace: item.GetNamespace(),
+		Name:      item.GetName(),
+	}
+	records, _, _ := unstructured.NestedSlice(item.Object, "status", "experiment", "containerRecords")
+	for _, r := range records {
+		record, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Record IDs are namespace/pod, with a container suffix for
+		// container-level experiments
+		if id, ok := record["id"].(string); ok {
+			exp.Pods = append(exp.Pods, id)
+		}
+	}
+	return exp, len(exp.Pods) > 0
+}
+
+// litmusExperiment reads the target application of an active ChaosEngine
+func litmusExperiment(item unstructured.Unstructured) (ChaosExperiment, bool) {
+	state, _, _ := unstructured.NestedString(item.Object, "spec", "engineState")
+	status, _, _ := unstructured.NestedString(item.Object, "status", "engineStatus")
+	if state != "active" || status == "completed" || status == "stopped" {
+		return ChaosExperiment{}, false
+	}
+
+	appNamespace, _, _ := unstructured.NestedString(item.Object, "spec", "appinfo", "appns")
+	appLabel, _, _ := unstructured.NestedString(item.Object, "spec", "appinfo", "applabel")
+	if appLabel == "" {
+		return ChaosExperiment{}, false
+	}
+	selector, err := labels.Parse(appLabel)
+	if err != nil {
+		return ChaosExperiment{}, false
+	}
+	if appNamespace == "" {
+		appNamespace = item.GetNamespace()
+	}
+
+	return ChaosExperiment{
+		Tool:            "litmus",
+		Kind:            "chaosengine",
+		Namespace:       item.GetNamespace(),
+		Name:            item.GetName(),
+		TargetNamespace: appNamespace,
+		TargetSelector:  selector,
+	}, true
+}
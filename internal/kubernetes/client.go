@@ -3,81 +3,385 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 // Client wraps the Kubernetes clientset
 type Client struct {
 	clientset *kubernetes.Clientset
+	metrics   *metricsclientset.Clientset
+	dynamic   dynamic.Interface
 	config    *rest.Config
+	nodes     *nodeCache // set by WithNodeCache
+	snapshot  *snapshot  // set by WithSnapshot
+	pod       *podCache  // set by WithPodCache
+	caps      *capabilities
+
+	kubeconfig string // as passed to NewClient, for kubectl commands
+	context    string // kubeconfig context in use, empty in-cluster
+}
+
+// RateLimit controls the load a client puts on the API server: the
+// requests it sends, and how it retries those that were throttled or failed
+// transiently. Zero QPS and Burst keep client-go's defaults of 5 queries per
+// second with bursts of 10.
+type RateLimit struct {
+	QPS          float32
+	Burst        int
+	MaxRetries   int           // retries of a read, 0 for none
+	RetryBackoff time.Duration // wait before the first retry, doubled after
+}
+
+// apply sets the limit on a REST config
+func (l RateLimit) apply(config *rest.Config) {
+	if l.QPS > 0 {
+		config.QPS = l.QPS
+	}
+	if l.Burst > 0 {
+		config.Burst = l.Burst
+	}
+	if l.MaxRetries > 0 {
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return retryTransport{next: rt, maxRetries: l.MaxRetries, backoff: max(l.RetryBackoff, 0)}
+		})
+	}
 }
 
-// NewClient creates a new Kubernetes client
-func NewClient(kubeconfigPath string) (*Client, error) {
-	config, err := buildConfig(kubeconfigPath)
+// NewClient creates a new Kubernetes client for a context of the
+// kubeconfig, or for its current context if contextName is empty
+func NewClient(kubeconfigPath, contextName string, limit RateLimit) (*Client, error) {
+	config, err := buildConfig(kubeconfigPath, contextName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build config: %w", err)
 	}
+	limit.apply(config)
+	client, err := newClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client.useKubeconfig(kubeconfigPath, contextName)
+	return client, nil
+}
+
+// useKubeconfig records the kubeconfig and context a client was built from
+func (c *Client) useKubeconfig(kubeconfigPath, contextName string) {
+	c.kubeconfig = kubeconfigPath
+	c.context = contextName
+	if contextName == "" {
+		c.context = CurrentContext(kubeconfigPath)
+	}
+}
+
+// Kubeconfig returns the kubeconfig path the client was built from, empty
+// for the default one
+func (c *Client) Kubeconfig() string {
+	return c.kubeconfig
+}
+
+// Context returns the kubeconfig context the client talks to, or an empty
+// string when running in-cluster or replaying a recording
+func (c *Client) Context() string {
+	return c.context
+}
+
+// newClientForConfig creates the clientsets for a REST config
+func newClientForConfig(config *rest.Config) (*Client, error) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return loggingTransport{next: rt}
+	})
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
 	}
 
+	metrics, err := metricsclientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics clientset: %w", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
 	return &Client{
 		clientset: clientset,
+		metrics:   metrics,
+		dynamic:   dynamicClient,
 		config:    config,
+		caps:      &capabilities{},
 	}, nil
 }
 
-// buildConfig builds a Kubernetes config from kubeconfig file or in-cluster config
-func buildConfig(kubeconfigPath string) (*rest.Config, error) {
-	if kubeconfigPath == "" {
-		// Try in-cluster config first
+// buildConfig builds a Kubernetes config from a context of the kubeconfig
+// file, its current context if contextName is empty, or in-cluster config
+func buildConfig(kubeconfigPath, contextName string) (*rest.Config, error) {
+	// Try in-cluster config first, unless a kubeconfig or context was asked for
+	if kubeconfigPath == "" && contextName == "" {
 		if config, err := rest.InClusterConfig(); err == nil {
 			return config, nil
 		}
-		// Fall back to default kubeconfig location
-		kubeconfigPath = defaultKubeconfigPath()
 	}
 
-	return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	rules := kubeconfigLoadingRules(kubeconfigPath)
+	if err := checkKubeconfigFound(rules); err != nil {
+		return nil, err
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules,
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
 }
 
-// defaultKubeconfigPath returns the default kubeconfig path
-func defaultKubeconfigPath() string {
-	if home := os.Getenv("HOME"); home != "" {
-		return filepath.Join(home, ".kube", "config")
+// CurrentContext returns the name of the current context of the kubeconfig
+// that NewClient would use, or an empty string when running in-cluster or
+// when the kubeconfig cannot be read
+func CurrentContext(kubeconfigPath string) string {
+	if kubeconfigPath == "" {
+		if _, err := rest.InClusterConfig(); err == nil {
+			return ""
+		}
 	}
-	return ""
+
+	config, err := kubeconfigLoadingRules(kubeconfigPath).Load()
+	if err != nil {
+		return ""
+	}
+	return config.CurrentContext
 }
 
-// GetPod retrieves a pod by name and namespace
+// Contexts returns the names of the contexts in the kubeconfig that
+// NewClient would use, sorted, and its current context
+func Contexts(kubeconfigPath string) ([]string, string, error) {
+	rules := kubeconfigLoadingRules(kubeconfigPath)
+	if err := checkKubeconfigFound(rules); err != nil {
+		return nil, "", err
+	}
+	config, err := rules.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, config.CurrentContext, nil
+}
+
+// GetPod retrieves a pod by name and namespace. A missing pod yields an
+// APIError of kind ErrorNotFound; FindSimilarPods looks up suggestions.
 func (c *Client) GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
-	return c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if c.snapshot != nil {
+		if pod, ok := c.snapshot.pod(namespace, name); ok {
+			return pod, nil
+		}
+	}
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err, "get", "pods", namespace, name)
+	}
+	return pod, nil
 }
 
-// ListPods lists pods in a namespace with optional label selector
+// podListPageSize is how many pods are requested per list call; larger
+// results are fetched in chunks using the continue token
+const podListPageSize = 500
+
+// ListPods lists pods in a namespace with optional label selector. An
+// empty namespace lists pods across all namespaces.
 func (c *Client) ListPods(ctx context.Context, namespace string, labelSelector string) (*corev1.PodList, error) {
-	opts := metav1.ListOptions{}
-	if labelSelector != "" {
-		opts.LabelSelector = labelSelector
+	podList := &corev1.PodList{}
+	err := c.ListPodPages(ctx, namespace, labelSelector, func(pods []corev1.Pod) error {
+		podList.Items = append(podList.Items, pods...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	return podList, nil
+}
+
+// ListPodPages lists pods in chunks using limit and continue, calling fn
+// with each page as it arrives so callers can start work before the whole
+// list is fetched. An empty namespace lists pods across all namespaces.
+// Listing stops at the first error returned by fn.
+func (c *Client) ListPodPages(ctx context.Context, namespace, labelSelector string, fn func(pods []corev1.Pod) error) error {
+	opts := metav1.ListOptions{
+		LabelSelector: labelSelector,
+		Limit:         podListPageSize,
+	}
+	for {
+		page, err := c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return wrapAPIError(err, "list", "pods", namespace, "")
+		}
+		if c.snapshot != nil {
+			c.snapshot.addPods(page.Items)
+		}
+		if err := fn(page.Items); err != nil {
+			return err
+		}
+		if page.Continue == "" {
+			if c.snapshot != nil && namespace == metav1.NamespaceAll && labelSelector == "" {
+				c.snapshot.listedAllPods()
+			}
+			return nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// SkippedNamespace is a namespace left out of a cluster-wide pod listing
+// because the user may not list its pods
+type SkippedNamespace struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Reason    string `json:"reason" yaml:"reason"`
+}
+
+// ListPermittedPodPages lists the pods of all namespaces page by page, like
+// ListPodPages. If the user may not list pods cluster-wide, it lists them
+// namespace by namespace instead and returns the namespaces it was denied
+// rather than failing. It fails only if namespaces cannot be listed either.
+func (c *Client) ListPermittedPodPages(ctx context.Context, labelSelector string, fn func(pods []corev1.Pod) error) ([]SkippedNamespace, error) {
+	err := c.ListPodPages(ctx, metav1.NamespaceAll, labelSelector, fn)
+	if !IsForbidden(err) {
+		return nil, err
+	}
+
+	namespaces, nsErr := c.GetNamespaces(ctx)
+	if nsErr != nil {
+		// Without the namespace list there is nothing to fall back to
+		return nil, err
+	}
+
+	var skipped []SkippedNamespace
+	for _, namespace := range namespaces {
+		err := c.ListPodPages(ctx, namespace, labelSelector, fn)
+		switch {
+		case IsForbidden(err):
+			skipped = append(skipped, SkippedNamespace{Namespace: namespace, Reason: err.Error()})
+		case err != nil:
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+// ListNodes lists all nodes in the cluster
+func (c *Client) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	if c.nodes != nil && c.nodes.listed != nil {
+		return c.nodes.listed, nil
+	}
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err, "list", "nodes", "", "")
+	}
+	return nodes.Items, nil
+}
+
+// ListPodsOnNode lists pods in all namespaces scheduled to a node. In a
+// scan, it is answered from the snapshot once every pod was listed, and
+// otherwise each node's pods are listed at most once; the pods returned are
+// then shared and must not be modified.
+func (c *Client) ListPodsOnNode(ctx context.Context, nodeName string) (*corev1.PodList, error) {
+	if pods, ok := c.snapshot.podsOnNode(nodeName); ok {
+		return &corev1.PodList{Items: pods}, nil
+	}
+	if c.nodes != nil {
+		pods, err := c.nodes.podsOn(c, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		return &corev1.PodList{Items: pods}, nil
+	}
+	return c.listPodsOnNode(ctx, nodeName)
+}
+
+// activePodSelector leaves out pods that completed or failed, which no
+// longer hold resources on their node
+const activePodSelector = "status.phase!=Succeeded,status.phase!=Failed"
+
+// PodsByNode returns the pods in all namespaces that have not completed or
+// failed, keyed by the node they are scheduled to, in one listing instead
+// of one per node. In a scan it is answered from the snapshot once every pod
+// was listed, or listed once; the pods returned are then shared and must
+// not be modified.
+func (c *Client) PodsByNode(ctx context.Context) (map[string][]corev1.Pod, error) {
+	if c.snapshot != nil && c.snapshot.listedAll() {
+		byNode := make(map[string][]corev1.Pod)
+		for _, node := range c.snapshot.nodeNames() {
+			pods, _ := c.snapshot.podsOnNode(node)
+			for _, pod := range pods {
+				if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+					byNode[node] = append(byNode[node], pod)
+				}
+			}
+		}
+		return byNode, nil
+	}
+	if c.nodes != nil {
+		return c.nodes.activeByNode(c)
+	}
+	return c.listActivePodsByNode(ctx)
+}
+
+// listActivePodsByNode lists the running and pending pods of the cluster
+// page by page and groups them by node
+func (c *Client) listActivePodsByNode(ctx context.Context) (map[string][]corev1.Pod, error) {
+	byNode := make(map[string][]corev1.Pod)
+	opts := metav1.ListOptions{FieldSelector: activePodSelector, Limit: podListPageSize}
+	for {
+		page, err := c.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, wrapAPIError(err, "list", "pods", "", "")
+		}
+		for _, pod := range page.Items {
+			if pod.Spec.NodeName != "" {
+				byNode[pod.Spec.NodeName] = append(byNode[pod.Spec.NodeName], pod)
+			}
+		}
+		if page.Continue == "" {
+			return byNode, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// listPodsOnNode lists the pods of a node from the API server
+func (c *Client) listPodsOnNode(ctx context.Context, nodeName string) (*corev1.PodList, error) {
+	list, err := c.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, wrapAPIError(err, "list", "pods", "", "")
+	}
+	return list, nil
 }
 
 // ListAllPods lists pods across all namespaces
 func (c *Client) ListAllPods(ctx context.Context) (*corev1.PodList, error) {
-	return c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	return c.ListPods(ctx, metav1.NamespaceAll, "")
 }
 
 // GetPodLogs retrieves logs from a pod's container
@@ -91,7 +395,7 @@ func (c *Client) GetPodLogs(ctx context.Context, namespace, name, container stri
 	req := c.clientset.CoreV1().Pods(namespace).GetLogs(name, opts)
 	result, err := req.Do(ctx).Raw()
 	if err != nil {
-		return "", err
+		return "", wrapAPIError(err, "get", "pods/log", namespace, name)
 	}
 
 	return string(result), nil
@@ -99,6 +403,20 @@ func (c *Client) GetPodLogs(ctx context.Context, namespace, name, container stri
 
 // GetPodEvents retrieves events related to a pod
 func (c *Client) GetPodEvents(ctx context.Context, namespace, name string) ([]domain.EventInfo, error) {
+	if c.pod.holds(namespace, name) {
+		return c.pod.podEvents(c)
+	}
+	return c.fetchPodEvents(ctx, namespace, name)
+}
+
+// fetchPodEvents retrieves events related to a pod from the snapshot or
+// the API server
+func (c *Client) fetchPodEvents(ctx context.Context, namespace, name string) ([]domain.EventInfo, error) {
+	if c.snapshot != nil {
+		if events, ok := c.snapshot.podEvents(c, namespace, name); ok {
+			return events, nil
+		}
+	}
 	fieldSelector := fmt.Sprintf("involvedObject.name=%s,involvedObject.namespace=%s,involvedObject.kind=Pod", name, namespace)
 
 	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
@@ -110,23 +428,66 @@ func (c *Client) GetPodEvents(ctx context.Context, namespace, name string) ([]do
 
 	result := make([]domain.EventInfo, 0, len(events.Items))
 	for _, e := range events.Items {
-		result = append(result, domain.EventInfo{
-			Type:      e.Type,
-			Reason:    e.Reason,
-			Message:   e.Message,
-			Count:     e.Count,
-			FirstSeen: e.FirstTimestamp.Time,
-			LastSeen:  e.LastTimestamp.Time,
-			Source:    e.Source.Component,
-		})
+		result = append(result, ExtractEventInfo(e))
 	}
 
 	return result, nil
 }
 
-// GetNode retrieves a node by name
+// ExtractEventInfo extracts domain.EventInfo from a Kubernetes Event
+func ExtractEventInfo(e corev1.Event) domain.EventInfo {
+	return domain.EventInfo{
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Count:     e.Count,
+		FirstSeen: e.FirstTimestamp.Time,
+		LastSeen:  e.LastTimestamp.Time,
+		Source:    e.Source.Component,
+		FieldPath: e.InvolvedObject.FieldPath,
+	}
+}
+
+// ListWarningEvents retrieves warning events in a namespace, or across all
+// namespaces if namespace is empty
+func (c *Client) ListWarningEvents(ctx context.Context, namespace string) ([]corev1.Event, error) {
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + corev1.EventTypeWarning,
+	})
+	if err != nil {
+		return nil, wrapAPIError(err, "list", "events", namespace, "")
+	}
+	return events.Items, nil
+}
+
+// ListNamespaceEvents retrieves all events in a namespace
+func (c *Client) ListNamespaceEvents(ctx context.Context, namespace string) ([]corev1.Event, error) {
+	if c.snapshot != nil {
+		if events, ok := c.snapshot.namespaceEvents(c, namespace); ok {
+			return events, nil
+		}
+	}
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return events.Items, nil
+}
+
+// GetNode retrieves a node by name. If the user may not read nodes, it
+// fails with a forbidden APIError without contacting the API server.
 func (c *Client) GetNode(ctx context.Context, name string) (*corev1.Node, error) {
-	return c.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if !c.CanReadNodes(ctx) {
+		return nil, &APIError{Kind: ErrorForbidden, Verb: "get", Resource: "nodes", Name: name}
+	}
+	if c.nodes != nil {
+		return c.nodes.get(ctx, c, name)
+	}
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err, "get", "nodes", "", name)
+	}
+	return node, nil
 }
 
 // GetNodeHealth returns health information for a node
@@ -138,6 +499,7 @@ func (c *Client) GetNodeHealth(ctx context.Context, nodeName string) (*domain.No
 
 	health := &domain.NodeHealth{
 		Name: nodeName,
+		UID:  string(node.UID),
 	}
 
 	for _, condition := range node.Status.Conditions {
@@ -162,7 +524,7 @@ func (c *Client) GetNodeHealth(ctx context.Context, nodeName string) (*domain.No
 func (c *Client) GetNamespaces(ctx context.Context) ([]string, error) {
 	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, err
+		return nil, wrapAPIError(err, "list", "namespaces", "", "")
 	}
 
 	result := make([]string, 0, len(namespaces.Items))
@@ -173,11 +535,115 @@ func (c *Client) GetNamespaces(ctx context.Context) ([]string, error) {
 	return result, nil
 }
 
+// GetNamespace retrieves a namespace by name
+func (c *Client) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetService retrieves a service by name and namespace
+func (c *Client) GetService(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	return c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListServices lists services in a namespace
+func (c *Client) ListServices(ctx context.Context, namespace string) (*corev1.ServiceList, error) {
+	return c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// ListEndpointSlices lists the endpoint slices backing a service
+func (c *Client) ListEndpointSlices(ctx context.Context, namespace, serviceName string) (*discoveryv1.EndpointSliceList, error) {
+	return c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + serviceName,
+	})
+}
+
+// GetPodMetrics retrieves live container usage from metrics-server. It
+// fails when metrics-server is not installed or has no sample for the pod yet.
+func (c *Client) GetPodMetrics(ctx context.Context, namespace, name string) (*metricsv1beta1.PodMetrics, error) {
+	if c.pod.holds(namespace, name) {
+		return c.pod.podMetrics(c)
+	}
+	return c.fetchPodMetrics(ctx, namespace, name)
+}
+
+// fetchPodMetrics retrieves a pod's live usage from metrics-server
+func (c *Client) fetchPodMetrics(ctx context.Context, namespace, name string) (*metricsv1beta1.PodMetrics, error) {
+	return c.metrics.MetricsV1beta1().PodMetricses(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListPodMetrics retrieves live usage of every pod in a namespace from
+// metrics-server
+func (c *Client) ListPodMetrics(ctx context.Context, namespace string) ([]metricsv1beta1.PodMetrics, error) {
+	list, err := c.metrics.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetPersistentVolumeClaim retrieves a PVC by name and namespace
+func (c *Client) GetPersistentVolumeClaim(ctx context.Context, namespace, name string) (*corev1.PersistentVolumeClaim, error) {
+	return c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetStorageClass retrieves a storage class by name
+func (c *Client) GetStorageClass(ctx context.Context, name string) (*storagev1.StorageClass, error) {
+	return c.clientset.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetConfigMap retrieves a configmap by name and namespace
+func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetSecret retrieves a secret by name and namespace
+func (c *Client) GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	return c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetServiceAccount retrieves a service account by name and namespace
+func (c *Client) GetServiceAccount(ctx context.Context, namespace, name string) (*corev1.ServiceAccount, error) {
+	return c.clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetStatefulSet retrieves a statefulset by name and namespace
+func (c *Client) GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error) {
+	return c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// NewPodInformer creates an informer that watches pods in a namespace
+// ("" for all namespaces) matching an optional label selector
+func (c *Client) NewPodInformer(namespace, labelSelector string, resync time.Duration) cache.SharedIndexInformer {
+	factory := informers.NewSharedInformerFactoryWithOptions(c.clientset, resync,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = labelSelector
+		}),
+	)
+	return factory.Core().V1().Pods().Informer()
+}
+
+// GetDeployment retrieves a deployment by name and namespace
+func (c *Client) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	return c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetDaemonSet retrieves a daemonset by name and namespace
+func (c *Client) GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error) {
+	return c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetJob retrieves a job by name and namespace
+func (c *Client) GetJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	return c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
 // ExtractPodInfo extracts domain.PodInfo from a Kubernetes Pod
 func ExtractPodInfo(pod *corev1.Pod) domain.PodInfo {
 	info := domain.PodInfo{
 		Name:       pod.Name,
 		Namespace:  pod.Namespace,
+		UID:        string(pod.UID),
 		Node:       pod.Spec.NodeName,
 		Phase:      string(pod.Status.Phase),
 		IP:         pod.Status.PodIP,
@@ -199,8 +665,10 @@ func ExtractPodInfo(pod *corev1.Pod) domain.PodInfo {
 
 	for _, container := range pod.Spec.Containers {
 		ci := domain.ContainerInfo{
-			Name:  container.Name,
-			Image: container.Image,
+			Name:      container.Name,
+			Image:     container.Image,
+			Resources: containerResources(container),
+			Probes:    containerProbes(container),
 		}
 
 		if status, ok := containerStatuses[container.Name]; ok {
@@ -229,6 +697,74 @@ func ExtractPodInfo(pod *corev1.Pod) domain.PodInfo {
 	return info
 }
 
+// containerResources returns the container's requests and limits, or nil
+// if it sets none
+func containerResources(container corev1.Container) *domain.ResourceUsage {
+	quantity := func(list corev1.ResourceList, name corev1.ResourceName) string {
+		if q, ok := list[name]; ok && !q.IsZero() {
+			return q.String()
+		}
+		return ""
+	}
+
+	usage := &domain.ResourceUsage{
+		CPURequests:    quantity(container.Resources.Requests, corev1.ResourceCPU),
+		CPULimits:      quantity(container.Resources.Limits, corev1.ResourceCPU),
+		MemoryRequests: quantity(container.Resources.Requests, corev1.ResourceMemory),
+		MemoryLimits:   quantity(container.Resources.Limits, corev1.ResourceMemory),
+	}
+	if *usage == (domain.ResourceUsage{}) {
+		return nil
+	}
+	return usage
+}
+
+// containerProbes summarizes the container's liveness, readiness and
+// startup probes
+func containerProbes(container corev1.Container) []domain.ProbeInfo {
+	var probes []domain.ProbeInfo
+	for _, p := range []struct {
+		kind  string
+		probe *corev1.Probe
+	}{
+		{"liveness", container.LivenessProbe},
+		{"readiness", container.ReadinessProbe},
+		{"startup", container.StartupProbe},
+	} {
+		if p.probe == nil {
+			continue
+		}
+		probes = append(probes, domain.ProbeInfo{
+			Type:             p.kind,
+			Handler:          probeHandler(p.probe),
+			InitialDelay:     p.probe.InitialDelaySeconds,
+			Period:           p.probe.PeriodSeconds,
+			Timeout:          p.probe.TimeoutSeconds,
+			FailureThreshold: p.probe.FailureThreshold,
+		})
+	}
+	return probes
+}
+
+// probeHandler describes what a probe checks, e.g. GET :8080/healthz
+func probeHandler(probe *corev1.Probe) string {
+	switch {
+	case probe.HTTPGet != nil:
+		scheme := ""
+		if probe.HTTPGet.Scheme == corev1.URISchemeHTTPS {
+			scheme = "https "
+		}
+		return fmt.Sprintf("%sGET :%s%s", scheme, probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		return "tcp :" + probe.TCPSocket.Port.String()
+	case probe.GRPC != nil:
+		return fmt.Sprintf("grpc :%d", probe.GRPC.Port)
+	case probe.Exec != nil:
+		return "exec " + strings.Join(probe.Exec.Command, " ")
+	}
+	return "unknown"
+}
+
 // Clientset returns the underlying Kubernetes clientset
 func (c *Client) Clientset() *kubernetes.Clientset {
 	return c.clientset
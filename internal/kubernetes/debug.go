@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// DefaultDebugImage is the image of debug containers when none is chosen
+const DefaultDebugImage = "busybox:1.36"
+
+// debugPollInterval is how often a new debug container's status is checked
+const debugPollInterval = time.Second
+
+// DebugContainer describes an ephemeral debug container to add to a pod
+type DebugContainer struct {
+	Image string
+	// Target is the container whose process namespace the debug container
+	// joins, so its processes and filesystem (under /proc/1/root) are visible
+	Target string
+	// Command overrides the image's entrypoint
+	Command []string
+}
+
+// CreateDebugContainer adds an ephemeral container with stdin and a TTY to
+// a running pod, like kubectl debug, and returns its name
+func (c *Client) CreateDebugContainer(ctx context.Context, namespace, podName string, debug DebugContainer) (string, error) {
+	pod, err := c.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return "", fmt.Errorf("pod %s/%s is %s; debug containers can only be added to running pods", namespace, podName, pod.Status.Phase)
+	}
+
+	image := debug.Image
+	if image == "" {
+		image = DefaultDebugImage
+	}
+	name := "debugger-" + utilrand.String(5)
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			Command:                  debug.Command,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: debug.Target,
+	})
+
+	_, err = c.clientset.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, podName, pod, metav1.UpdateOptions{})
+	if err != nil {
+		return "", wrapAPIError(err, "update", "pods/ephemeralcontainers", namespace, podName)
+	}
+	return name, nil
+}
+
+// WaitForDebugContainer waits until an ephemeral container is running. It
+// fails early if the container exits or its image cannot be pulled.
+func (c *Client) WaitForDebugContainer(ctx context.Context, namespace, podName, container string) error {
+	ticker := time.NewTicker(debugPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pod, err := c.GetPod(ctx, namespace, podName)
+		if err != nil {
+			return err
+		}
+		for _, cs := range pod.Status.EphemeralContainerStatuses {
+			if cs.Name != container {
+				continue
+			}
+			switch {
+			case cs.State.Running != nil:
+				return nil
+			case cs.State.Terminated != nil:
+				return fmt.Errorf("debug container %s exited: %s", container, cs.State.Terminated.Reason)
+			case cs.State.Waiting != nil && isImagePullFailure(cs.State.Waiting.Reason):
+				return fmt.Errorf("debug container %s cannot start: %s: %s", container, cs.State.Waiting.Reason, cs.State.Waiting.Message)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("debug container %s did not start: %w", container, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// AttachCommand returns the kubectl command that attaches a terminal to a
+// debug container, using the same kubeconfig and context as the client
+func (c *Client) AttachCommand(namespace, podName, container string) []string {
+	return c.KubectlCommand("attach", podName, "-n", namespace, "-c", container, "-i", "-t")
+}
+
+// KubectlCommand returns a kubectl command line with the same kubeconfig
+// and context as the client
+func (c *Client) KubectlCommand(arg ...string) []string {
+	args := append([]string{"kubectl"}, arg...)
+	if c.kubeconfig != "" {
+		args = append(args, "--kubeconfig", c.kubeconfig)
+	}
+	if c.context != "" {
+		args = append(args, "--context", c.context)
+	}
+	return args
+}
+
+// isImagePullFailure reports whether a waiting reason means the image will
+// not be pulled without a change
+func isImagePullFailure(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+		return true
+	}
+	return false
+}
+
+// DebugTarget returns the container a debug container should target by
+// default: the first one that is not ready, else the first one
+func DebugTarget(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if !cs.Ready {
+			return cs.Name
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListPodDisruptionBudgets lists the PodDisruptionBudgets in a namespace
+func (c *Client) ListPodDisruptionBudgets(ctx context.Context, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+	pdbs, err := c.clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err, "list", "poddisruptionbudgets", namespace, "")
+	}
+	return pdbs.Items, nil
+}
+
+// ControllerReplicas returns the desired and ready replicas of a pod's
+// controller. ok is false for kinds without a replica count, such as Jobs.
+func (c *Client) ControllerReplicas(ctx context.Context, namespace, kind, name string) (desired, ready int32, ok bool, err error) {
+	var replicas *int32
+	switch kind {
+	case "ReplicaSet":
+		rs, getErr := c.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			return 0, 0, false, wrapAPIError(getErr, "get", "replicasets", namespace, name)
+		}
+		replicas, ready = rs.Spec.Replicas, rs.Status.ReadyReplicas
+	case "StatefulSet":
+		sts, getErr := c.GetStatefulSet(ctx, namespace, name)
+		if getErr != nil {
+			return 0, 0, false, wrapAPIError(getErr, "get", "statefulsets", namespace, name)
+		}
+		replicas, ready = sts.Spec.Replicas, sts.Status.ReadyReplicas
+	default:
+		return 0, 0, false, nil
+	}
+
+	desired = 1
+	if replicas != nil {
+		desired = *replicas
+	}
+	return desired, ready, true, nil
+}
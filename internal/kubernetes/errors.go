@@ -0,0 +1,236 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrorKind classifies a failed API request
+type ErrorKind string
+
+const (
+	ErrorForbidden ErrorKind = "forbidden"
+	ErrorNotFound  ErrorKind = "notFound"
+	ErrorTimeout   ErrorKind = "timeout"
+	ErrorOther     ErrorKind = "other"
+)
+
+// APIError is returned when a request to the API server fails. It names the
+// request that failed so commands can show what permission is missing or
+// what was not found instead of the raw client-go error. The underlying
+// error stays reachable, so apierrors.IsNotFound and friends still work.
+type APIError struct {
+	Kind      ErrorKind
+	Verb      string // get, list
+	Resource  string // pods, pods/log, namespaces
+	Namespace string
+	Name      string
+	Err       error
+
+	// Suggestions are similar pods, as namespace/name, when a pod was not found
+	Suggestions []string
+}
+
+func (e *APIError) Error() string {
+	switch e.Kind {
+	case ErrorForbidden:
+		return fmt.Sprintf("permission denied: cannot %s %s%s", e.Verb, e.Resource, e.scope())
+	case ErrorNotFound:
+		return fmt.Sprintf("%s %s not found", strings.TrimSuffix(e.Resource, "s"), e.object())
+	case ErrorTimeout:
+		return fmt.Sprintf("timed out trying to %s %s%s", e.Verb, e.Resource, e.scope())
+	}
+	return fmt.Sprintf("failed to %s %s%s: %v", e.Verb, e.Resource, e.scope(), e.Err)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Hints returns likely fixes for the failure
+func (e *APIError) Hints() []string {
+	nsFlag := " --all-namespaces"
+	if e.Namespace != "" {
+		nsFlag = " -n " + e.Namespace
+	}
+
+	switch e.Kind {
+	case ErrorForbidden:
+		where := "cluster-wide (ClusterRole)"
+		if e.Namespace != "" {
+			where = "in namespace " + e.Namespace + " (Role or ClusterRole)"
+		}
+		return []string{
+			fmt.Sprintf("Missing RBAC rule: verbs [%s] on resource %q %s", e.Verb, e.Resource, where),
+			"Check your access: kubectl auth can-i " + e.Verb + " " + e.Resource + nsFlag,
+		}
+	case ErrorNotFound:
+		if len(e.Suggestions) > 0 {
+			return []string{"Did you mean: " + strings.Join(e.Suggestions, ", ")}
+		}
+		return []string{
+			"Check the name and namespace (-n); list pods with: kubectl get pods" + nsFlag,
+		}
+	case ErrorTimeout:
+		return []string{
+			"The API server is slow or overloaded; retry, or narrow the scope with -n or -l",
+			"Check connectivity: kubectl cluster-info",
+		}
+	}
+	return nil
+}
+
+// ErrorHints returns the hints of a ConnectionError or APIError wrapped in
+// err, or nil for other errors
+func ErrorHints(err error) []string {
+	var connErr *ConnectionError
+	if errors.As(err, &connErr) {
+		return connErr.Hints()
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Hints()
+	}
+	return nil
+}
+
+// IsForbidden reports whether err is an APIError for a request the user is
+// not allowed to make
+func IsForbidden(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Kind == ErrorForbidden
+}
+
+// object returns namespace/name of the requested object
+func (e *APIError) object() string {
+	if e.Namespace == "" {
+		return e.Name
+	}
+	return e.Namespace + "/" + e.Name
+}
+
+// scope describes where the request was made, e.g. " in namespace prod"
+func (e *APIError) scope() string {
+	switch {
+	case e.Name != "":
+		return " " + e.object()
+	case e.Namespace != "":
+		return " in namespace " + e.Namespace
+	}
+	return " across all namespaces"
+}
+
+// wrapAPIError classifies err as an APIError for the given request.
+// Context cancellation is returned unchanged.
+func wrapAPIError(err error, verb, resource, namespace, name string) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	apiErr := &APIError{
+		Kind:      ErrorOther,
+		Verb:      verb,
+		Resource:  resource,
+		Namespace: namespace,
+		Name:      name,
+		Err:       err,
+	}
+
+	var netErr net.Error
+	switch {
+	case apierrors.IsForbidden(err):
+		apiErr.Kind = ErrorForbidden
+	case apierrors.IsNotFound(err) && name != "":
+		apiErr.Kind = ErrorNotFound
+	case apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		apiErr.Kind = ErrorTimeout
+	}
+	return apiErr
+}
+
+// FindSimilarPods returns pods, as namespace/name, whose name is close to
+// name: the same name in another namespace first, then names starting
+// with it (generated replica names), names containing it, and names within
+// a small edit distance. Close matches are looked up in the given namespace,
+// or everywhere if allNamespaces is set. At most limit pods are returned.
+func (c *Client) FindSimilarPods(ctx context.Context, namespace, name string, allNamespaces bool, limit int) ([]string, error) {
+	pods, err := c.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// Fall back to the pod's own namespace when listing everything is not allowed
+		if pods, err = c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+			return nil, wrapAPIError(err, "list", "pods", namespace, "")
+		}
+	}
+
+	type candidate struct {
+		ref   string
+		score int
+	}
+	var candidates []candidate
+	for _, pod := range pods.Items {
+		ref := pod.Namespace + "/" + pod.Name
+		if pod.Namespace == namespace && pod.Name == name {
+			continue
+		}
+		if pod.Name == name {
+			candidates = append(candidates, candidate{ref, 0})
+			continue
+		}
+		if pod.Namespace != namespace && !allNamespaces {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(pod.Name, name):
+			// Deployment pods carry a hash suffix, e.g. web -> web-7d8f9-x2x4p
+			candidates = append(candidates, candidate{ref, 1})
+		case strings.Contains(pod.Name, name):
+			candidates = append(candidates, candidate{ref, 2})
+		case editDistance(pod.Name, name) <= 2:
+			candidates = append(candidates, candidate{ref, 3})
+		}
+	}
+
+	// Prefer the requested namespace among equally good matches
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score < candidates[j].score
+		}
+		return strings.HasPrefix(candidates[i].ref, namespace+"/") && !strings.HasPrefix(candidates[j].ref, namespace+"/")
+	})
+
+	var similar []string
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		similar = append(similar, candidates[i].ref)
+	}
+	return similar, nil
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
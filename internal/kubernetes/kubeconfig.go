@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/homedir"
+)
+
+// kubeconfigLoadingRules returns how the kubeconfig is loaded: from the
+// given path, otherwise from the files listed in KUBECONFIG, merged like
+// kubectl merges them, or from .kube/config in the home directory
+func kubeconfigLoadingRules(kubeconfigPath string) *clientcmd.ClientConfigLoadingRules {
+	if kubeconfigPath != "" {
+		return &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if os.Getenv(clientcmd.RecommendedConfigPathEnvVar) == "" {
+		// clientcmd looks in a relative .kube/config when there is no
+		// home directory; find one the way defaultKubeconfigPath does
+		rules.Precedence = nil
+		rules.MigrationRules = nil
+		if path := defaultKubeconfigPath(); path != "" {
+			rules.Precedence = []string{path}
+		}
+	}
+	return rules
+}
+
+// checkKubeconfigFound fails with the paths tried when none of the
+// kubeconfig files of the rules exists
+func checkKubeconfigFound(rules *clientcmd.ClientConfigLoadingRules) error {
+	if rules.ExplicitPath != "" {
+		// clientcmd reports a missing explicit file itself
+		return nil
+	}
+	for _, path := range rules.Precedence {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+	}
+	return errNoKubeconfig(rules.Precedence)
+}
+
+// errNoKubeconfig explains that no kubeconfig was found at the paths tried
+func errNoKubeconfig(tried []string) error {
+	hint := "set KUBECONFIG or pass --kubeconfig"
+	switch {
+	case os.Getenv(clientcmd.RecommendedConfigPathEnvVar) != "":
+		return fmt.Errorf("no kubeconfig found: none of the files in KUBECONFIG exists (tried %s); %s",
+			strings.Join(tried, ", "), hint)
+	case len(tried) == 0:
+		return fmt.Errorf("no kubeconfig found: KUBECONFIG is not set and there is no home directory to look for %s in (%s unset); %s",
+			filepath.Join(clientcmd.RecommendedHomeDir, clientcmd.RecommendedFileName), homeEnvNames(), hint)
+	}
+	return fmt.Errorf("no kubeconfig found at %s and KUBECONFIG is not set; %s", strings.Join(tried, ", "), hint)
+}
+
+// defaultKubeconfigPath returns .kube/config in the home directory, or an
+// empty string when there is none
+func defaultKubeconfigPath() string {
+	if home := homeDir(); home != "" {
+		return filepath.Join(home, clientcmd.RecommendedHomeDir, clientcmd.RecommendedFileName)
+	}
+	return ""
+}
+
+// homeDir returns the home directory: HOME, or on Windows USERPROFILE or
+// HOMEDRIVE and HOMEPATH, falling back to the home of the user account for
+// services and containers that run without those variables
+func homeDir() string {
+	if home := homedir.HomeDir(); home != "" {
+		return home
+	}
+	if u, err := user.Current(); err == nil {
+		return u.HomeDir
+	}
+	return ""
+}
+
+// homeEnvNames names the environment variables homeDir reads
+func homeEnvNames() string {
+	if runtime.GOOS == "windows" {
+		return "HOME, USERPROFILE, HOMEDRIVE and HOMEPATH are"
+	}
+	return "HOME is"
+}
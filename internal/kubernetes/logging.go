@@ -0,0 +1,32 @@
+package kubernetes
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// loggingTransport logs every request to the API server at debug level,
+// with its response status and how long it took
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	attrs := []any{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"duration", time.Since(start).Round(time.Millisecond),
+	}
+	if req.URL.RawQuery != "" {
+		attrs = append(attrs, "query", req.URL.RawQuery)
+	}
+	if err != nil {
+		slog.Debug("API request failed", append(attrs, "error", err)...)
+		return resp, err
+	}
+	slog.Debug("API request", append(attrs, "status", resp.StatusCode)...)
+	return resp, nil
+}
@@ -0,0 +1,17 @@
+package kubernetes
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListNetworkPolicies lists the NetworkPolicies in a namespace
+func (c *Client) ListNetworkPolicies(ctx context.Context, namespace string) ([]networkingv1.NetworkPolicy, error) {
+	policies, err := c.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err, "list", "networkpolicies", namespace, "")
+	}
+	return policies.Items, nil
+}
@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeCache holds the nodes fetched for one scan run, and the pods listed
+// on each. Many pods share a node, so each node and its pods are fetched at
+// most once.
+type nodeCache struct {
+	ctx    context.Context // of the scan run, so no single diagnosis' deadline cuts a listing short
+	mu     sync.Mutex
+	nodes  map[string]*corev1.Node
+	listed []corev1.Node        // nil if the initial list failed
+	pods   map[string]*nodePods // keyed by node name
+	active activePods           // pods of every node, for PodsByNode
+}
+
+// activePods is the running and pending pods of every node, listed once on
+// first use
+type activePods struct {
+	once   sync.Once
+	byNode map[string][]corev1.Pod
+	err    error
+}
+
+// nodePods is the pods of one node, listed once on first use
+type nodePods struct {
+	once sync.Once
+	pods []corev1.Pod
+	err  error
+}
+
+// WithNodeCache returns a client that lists all nodes in a single call and
+// then answers GetNode, GetNodeHealth and ListNodes from memory. Nodes
+// missing from the list, e.g. added since, are fetched once and cached.
+// Use a new cache for each scan run so node health does not go stale.
+// Cached nodes are shared and must not be modified. A client that already
+// caches nodes, e.g. one from WithSnapshot, is returned as is.
+func (c *Client) WithNodeCache(ctx context.Context) *Client {
+	if c.nodes != nil {
+		return c
+	}
+	cache := &nodeCache{
+		ctx:   ctx,
+		nodes: make(map[string]*corev1.Node),
+		pods:  make(map[string]*nodePods),
+	}
+	if list, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+		cache.listed = list.Items
+		for i := range list.Items {
+			cache.nodes[list.Items[i].Name] = &list.Items[i]
+		}
+	}
+
+	cached := *c
+	cached.nodes = cache
+	return &cached
+}
+
+// get returns a cached node, fetching it on a miss
+func (n *nodeCache) get(ctx context.Context, c *Client, name string) (*corev1.Node, error) {
+	n.mu.Lock()
+	node, ok := n.nodes[name]
+	n.mu.Unlock()
+	if ok {
+		return node, nil
+	}
+
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err, "get", "nodes", "", name)
+	}
+
+	n.mu.Lock()
+	n.nodes[name] = node
+	n.mu.Unlock()
+	return node, nil
+}
+
+// podsOn returns the pods of a node, listing them on first use. Concurrent
+// diagnoses wait for the one listing instead of each making it.
+func (n *nodeCache) podsOn(c *Client, nodeName string) ([]corev1.Pod, error) {
+	n.mu.Lock()
+	np, ok := n.pods[nodeName]
+	if !ok {
+		np = &nodePods{}
+		n.pods[nodeName] = np
+	}
+	n.mu.Unlock()
+
+	np.once.Do(func() {
+		var list *corev1.PodList
+		list, np.err = c.listPodsOnNode(n.ctx, nodeName)
+		if np.err == nil {
+			np.pods = list.Items
+		}
+	})
+	return np.pods, np.err
+}
+
+// activeByNode returns the running and pending pods of every node, listing
+// them on first use
+func (n *nodeCache) activeByNode(c *Client) (map[string][]corev1.Pod, error) {
+	n.active.once.Do(func() {
+		n.active.byNode, n.active.err = c.listActivePodsByNode(n.ctx)
+	})
+	return n.active.byNode, n.active.err
+}
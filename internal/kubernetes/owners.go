@@ -0,0 +1,181 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxOwnerDepth bounds owner traversal in case of reference cycles
+const maxOwnerDepth = 5
+
+// ResolveWorkload walks the pod's controller owner references up to the
+// top-level workload (e.g. Pod -> ReplicaSet -> Deployment). It returns nil
+// if the pod has no controller.
+func (c *Client) ResolveWorkload(ctx context.Context, pod *corev1.Pod) (*domain.WorkloadInfo, error) {
+	if c.pod.holds(pod.Namespace, pod.Name) {
+		return c.pod.podWorkload(c, pod)
+	}
+	return c.resolveWorkload(ctx, pod)
+}
+
+// resolveWorkload walks the pod's owner references on the API server
+func (c *Client) resolveWorkload(ctx context.Context, pod *corev1.Pod) (*domain.WorkloadInfo, error) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return nil, nil
+	}
+
+	workload := &domain.WorkloadInfo{
+		Kind:      ref.Kind,
+		Name:      ref.Name,
+		Namespace: pod.Namespace,
+		Chain:     []string{ref.Kind + "/" + ref.Name},
+	}
+
+	for i := 0; i < maxOwnerDepth; i++ {
+		owner, err := c.getControllerOf(ctx, pod.Namespace, workload.Kind, workload.Name)
+		if err != nil {
+			return workload, err
+		}
+		if owner == nil {
+			break
+		}
+		workload.Kind = owner.Kind
+		workload.Name = owner.Name
+		workload.Chain = append(workload.Chain, owner.Kind+"/"+owner.Name)
+	}
+
+	return workload, nil
+}
+
+// getControllerOf returns the controller reference of an owner object, for
+// the kinds that are themselves commonly owned by another controller
+func (c *Client) getControllerOf(ctx context.Context, namespace, kind, name string) (*metav1.OwnerReference, error) {
+	var obj metav1.Object
+
+	switch kind {
+	case "ReplicaSet":
+		rs, err := c.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		obj = rs
+	case "Job":
+		job, err := c.GetJob(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		obj = job
+	default:
+		return nil, nil
+	}
+
+	return metav1.GetControllerOf(obj), nil
+}
+
+// workloadKinds maps the resource names kubectl accepts for a workload,
+// e.g. deploy or statefulsets, to its kind
+var workloadKinds = map[string]string{
+	"deployment":   "Deployment",
+	"deployments":  "Deployment",
+	"deploy":       "Deployment",
+	"statefulset":  "StatefulSet",
+	"statefulsets": "StatefulSet",
+	"sts":          "StatefulSet",
+	"daemonset":    "DaemonSet",
+	"daemonsets":   "DaemonSet",
+	"ds":           "DaemonSet",
+	"job":          "Job",
+	"jobs":         "Job",
+}
+
+// ParseWorkloadRef parses a kubectl workload reference such as
+// deployment/web. It returns false if ref does not name a supported
+// workload kind.
+func ParseWorkloadRef(ref string) (kind, name string, ok bool) {
+	resource, name, found := strings.Cut(ref, "/")
+	if !found || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	kind, ok = workloadKinds[strings.ToLower(resource)]
+	return kind, name, ok
+}
+
+// WorkloadSelector returns the label selector a deployment, statefulset,
+// daemonset or job selects its pods with
+func (c *Client) WorkloadSelector(ctx context.Context, namespace, kind, name string) (string, error) {
+	var selector *metav1.LabelSelector
+	var err error
+	switch kind {
+	case "Deployment":
+		deploy, getErr := c.GetDeployment(ctx, namespace, name)
+		err = wrapAPIError(getErr, "get", "deployments", namespace, name)
+		if err == nil {
+			selector = deploy.Spec.Selector
+		}
+	case "StatefulSet":
+		sts, getErr := c.GetStatefulSet(ctx, namespace, name)
+		err = wrapAPIError(getErr, "get", "statefulsets", namespace, name)
+		if err == nil {
+			selector = sts.Spec.Selector
+		}
+	case "DaemonSet":
+		ds, getErr := c.GetDaemonSet(ctx, namespace, name)
+		err = wrapAPIError(getErr, "get", "daemonsets", namespace, name)
+		if err == nil {
+			selector = ds.Spec.Selector
+		}
+	case "Job":
+		job, getErr := c.GetJob(ctx, namespace, name)
+		err = wrapAPIError(getErr, "get", "jobs", namespace, name)
+		if err == nil {
+			selector = job.Spec.Selector
+		}
+	default:
+		return "", fmt.Errorf("unsupported workload kind %q", kind)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", fmt.Errorf("%s/%s has an invalid selector: %w", strings.ToLower(kind), name, err)
+	}
+	if parsed.Empty() {
+		return "", fmt.Errorf("%s/%s has no pod selector", strings.ToLower(kind), name)
+	}
+	return parsed.String(), nil
+}
+
+// GetWorkloadMeta returns the metadata of a pod owner such as a
+// Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob
+func (c *Client) GetWorkloadMeta(ctx context.Context, namespace, kind, name string) (metav1.Object, error) {
+	var obj metav1.Object
+	var err error
+	switch kind {
+	case "Deployment":
+		obj, err = c.GetDeployment(ctx, namespace, name)
+	case "StatefulSet":
+		obj, err = c.GetStatefulSet(ctx, namespace, name)
+	case "DaemonSet":
+		obj, err = c.GetDaemonSet(ctx, namespace, name)
+	case "ReplicaSet":
+		obj, err = c.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "Job":
+		obj, err = c.GetJob(ctx, namespace, name)
+	case "CronJob":
+		obj, err = c.clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+	if err != nil {
+		return nil, wrapAPIError(err, "get", strings.ToLower(kind)+"s", namespace, name)
+	}
+	return obj, nil
+}
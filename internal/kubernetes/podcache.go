@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// podCache holds the events, live usage and workload of the pod being
+// diagnosed. Several analyzers read each, so each is fetched at most once
+// per diagnosis.
+type podCache struct {
+	ctx       context.Context // of the diagnosis, so no single analyzer's deadline cuts a fetch short
+	namespace string
+	name      string
+
+	eventsOnce sync.Once
+	events     []domain.EventInfo
+	eventsErr  error
+
+	metricsOnce sync.Once
+	metrics     *metricsv1beta1.PodMetrics
+	metricsErr  error
+
+	workloadOnce sync.Once
+	workload     *domain.WorkloadInfo
+	workloadErr  error
+}
+
+// WithPodCache returns a client that answers GetPodEvents, GetPodMetrics
+// and ResolveWorkload for one pod from a single fetch each, failures
+// included. Use a new cache for each diagnosis. The cached results are
+// shared and must not be modified.
+func (c *Client) WithPodCache(ctx context.Context, namespace, name string) *Client {
+	cached := *c
+	cached.pod = &podCache{ctx: ctx, namespace: namespace, name: name}
+	return &cached
+}
+
+// holds reports whether the cache is for the given pod
+func (p *podCache) holds(namespace, name string) bool {
+	return p != nil && p.namespace == namespace && p.name == name
+}
+
+// podEvents returns the pod's events, fetching them on first use
+func (p *podCache) podEvents(c *Client) ([]domain.EventInfo, error) {
+	p.eventsOnce.Do(func() {
+		p.events, p.eventsErr = c.fetchPodEvents(p.ctx, p.namespace, p.name)
+	})
+	return p.events, p.eventsErr
+}
+
+// podMetrics returns the pod's live usage, fetching it on first use
+func (p *podCache) podMetrics(c *Client) (*metricsv1beta1.PodMetrics, error) {
+	p.metricsOnce.Do(func() {
+		p.metrics, p.metricsErr = c.fetchPodMetrics(p.ctx, p.namespace, p.name)
+	})
+	return p.metrics, p.metricsErr
+}
+
+// podWorkload returns the pod's top-level workload, resolving it on first
+// use
+func (p *podCache) podWorkload(c *Client, pod *corev1.Pod) (*domain.WorkloadInfo, error) {
+	p.workloadOnce.Do(func() {
+		p.workload, p.workloadErr = c.resolveWorkload(p.ctx, pod)
+	})
+	return p.workload, p.workloadErr
+}
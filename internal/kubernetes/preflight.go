@@ -0,0 +1,85 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ConnectionError is returned when the API server cannot be reached
+type ConnectionError struct {
+	Server string
+	Err    error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("cannot reach cluster %s: %v", e.Server, e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// Hints returns likely fixes for the connection failure
+func (e *ConnectionError) Hints() []string {
+	msg := strings.ToLower(e.Err.Error())
+	var netErr net.Error
+
+	switch {
+	case apierrors.IsUnauthorized(e.Err):
+		return []string{
+			"Your credentials were rejected; the token or client certificate may have expired",
+			"Refresh credentials (e.g. re-run your cloud provider's get-credentials command or re-login)",
+		}
+	case apierrors.IsForbidden(e.Err):
+		return []string{
+			"Your identity is not allowed to query the API server",
+			"Check you are using the intended user/context: kubectl config current-context",
+		}
+	case strings.Contains(msg, "x509") || strings.Contains(msg, "certificate"):
+		return []string{
+			"TLS verification failed; the kubeconfig may point at the wrong cluster or contain a stale CA",
+			"Check the current context: kubectl config current-context",
+		}
+	case strings.Contains(msg, "no such host"):
+		return []string{
+			"The API server hostname does not resolve; you may need to connect to a VPN",
+			"Check the server address in your kubeconfig and the current context",
+		}
+	case strings.Contains(msg, "connection refused"):
+		return []string{
+			"Nothing is listening at the API server address; the cluster may be down or the context wrong",
+			"Check the current context: kubectl config current-context",
+		}
+	case errors.Is(e.Err, context.DeadlineExceeded) || (errors.As(e.Err, &netErr) && netErr.Timeout()) ||
+		strings.Contains(msg, "timeout") || strings.Contains(msg, "no route to host"):
+		return []string{
+			"The API server did not respond; check your VPN or network connection",
+			"Private clusters may only be reachable from an allow-listed network",
+		}
+	}
+
+	return []string{
+		"Verify the cluster is reachable: kubectl cluster-info",
+		"Check the current context: kubectl config current-context",
+	}
+}
+
+// Server returns the API server URL the client is configured for
+func (c *Client) Server() string {
+	return c.config.Host
+}
+
+// Ping does a cheap request against the API server to confirm it is
+// reachable and the credentials are accepted
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return &ConnectionError{Server: c.config.Host, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// replayHost is the API server address used by replay clients. Requests
+// never leave the process.
+const replayHost = "http://replay.pod-doctor.invalid"
+
+// recordedResponse is one API response saved to a recording directory
+type recordedResponse struct {
+	Request     string `json:"request"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body"`
+}
+
+// NewRecordingClient creates a client that saves every API response it
+// receives to dir, so the session can later be replayed offline with
+// NewReplayClient
+func NewRecordingClient(kubeconfigPath, contextName string, limit RateLimit, dir string) (*Client, error) {
+	config, err := buildConfig(kubeconfigPath, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
+	}
+	limit.apply(config)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &recorder{next: rt, dir: dir}
+	})
+	client, err := newClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client.useKubeconfig(kubeconfigPath, contextName)
+	return client, nil
+}
+
+// NewReplayClient creates a client that answers requests from responses
+// recorded in dir instead of contacting a cluster. Requests that were not
+// recorded fail with NotFound.
+func NewReplayClient(dir string) (*Client, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("recording %s is not a directory", dir)
+	}
+
+	return newClientForConfig(&rest.Config{
+		Host:      replayHost,
+		Transport: &replayer{dir: dir},
+	})
+}
+
+// NewEmptyClient creates a client for a cluster without any objects: every
+// request fails with NotFound, without leaving the process. Benchmarks use
+// it to time the analyzers apart from the API server.
+func NewEmptyClient() (*Client, error) {
+	return newClientForConfig(&rest.Config{
+		Host:      replayHost,
+		Transport: emptyCluster{},
+	})
+}
+
+// RecordedPods returns the pods whose responses are in a recording
+// directory, from single pod reads and pod lists, each pod once
+func RecordedPods(dir string) ([]corev1.Pod, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+
+	var pods []corev1.Pod
+	seen := make(map[string]bool)
+	add := func(pod corev1.Pod) {
+		key := pod.Namespace + "/" + pod.Name
+		if pod.Name != "" && !seen[key] {
+			seen[key] = true
+			pods = append(pods, pod)
+		}
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		var recorded recordedResponse
+		if err := json.Unmarshal(data, &recorded); err != nil || recorded.Status != http.StatusOK {
+			continue
+		}
+		var object struct {
+			Kind string `json:"kind"`
+		}
+		if err := json.Unmarshal([]byte(recorded.Body), &object); err != nil {
+			continue
+		}
+		switch object.Kind {
+		case "Pod":
+			var pod corev1.Pod
+			if err := json.Unmarshal([]byte(recorded.Body), &pod); err == nil {
+				add(pod)
+			}
+		case "PodList":
+			var list corev1.PodList
+			if err := json.Unmarshal([]byte(recorded.Body), &list); err == nil {
+				for _, pod := range list.Items {
+					add(pod)
+				}
+			}
+		}
+	}
+	// Glob returns hashed file names; order the pods by name instead
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// emptyCluster answers every request as if the object did not exist
+type emptyCluster struct{}
+
+func (emptyCluster) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := requestKey(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+	return notRecorded(req, key), nil
+}
+
+// recorder saves responses passing through it
+type recorder struct {
+	next http.RoundTripper
+	dir  string
+	mu   sync.Mutex
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The key reads the request body, so take it before the body is sent
+	key, err := requestKey(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record request: %w", err)
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil || isStreamingRequest(req) {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recordedBody := body
+	if strings.Contains(req.URL.Path, "/secrets") {
+		recordedBody = redactSecretData(body)
+	}
+
+	data, err := json.MarshalIndent(recordedResponse{
+		Request:     key,
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(recordedBody),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+
+	// Repeated requests keep the latest response
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.WriteFile(filepath.Join(r.dir, recordingFile(key)), data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+	return resp, nil
+}
+
+// replayer serves recorded responses
+type replayer struct {
+	dir string
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := requestKey(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(r.dir, recordingFile(key)))
+	if os.IsNotExist(err) {
+		return notRecorded(req, key), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var recorded recordedResponse
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("invalid recording for %s: %w", key, err)
+	}
+
+	header := make(http.Header)
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+	return &http.Response{
+		StatusCode: recorded.Status,
+		Status:     fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(recorded.Body)),
+		Request:    req,
+	}, nil
+}
+
+// notRecorded answers a request missing from the recording with a NotFound
+// status, as if the object did not exist
+func notRecorded(req *http.Request, key string) *http.Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"status":     "Failure",
+		"message":    "not in recording: " + key,
+		"reason":     "NotFound",
+		"code":       http.StatusNotFound,
+	})
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// redactSecretData blanks the values of a secret, or of every secret in a
+// list, so recordings can be shared; the keys and type are kept for analysis
+func redactSecretData(body []byte) []byte {
+	var secret map[string]interface{}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return body
+	}
+	blankData(secret)
+	if items, ok := secret["items"].([]interface{}); ok {
+		for _, item := range items {
+			if s, ok := item.(map[string]interface{}); ok {
+				blankData(s)
+			}
+		}
+	}
+	redacted, err := json.Marshal(secret)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// blankData blanks the values under data and stringData of a secret
+func blankData(secret map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		if data, ok := secret[field].(map[string]interface{}); ok {
+			for key := range data {
+				data[key] = ""
+			}
+		}
+	}
+}
+
+// isStreamingRequest reports whether a request streams its response
+// (watches and followed logs), which cannot be recorded
+func isStreamingRequest(req *http.Request) bool {
+	query := req.URL.Query()
+	return query.Get("watch") == "true" || query.Get("follow") == "true"
+}
+
+// requestKey identifies a request independently of the API server address,
+// e.g. GET /api/v1/namespaces/default/pods?limit=500. Requests with a body,
+// such as the POSTs of access reviews, add a hash of it, so two reviews of
+// different permissions are recorded apart.
+func requestKey(req *http.Request) (string, error) {
+	key := req.Method + " " + req.URL.Path
+	if query := req.URL.Query(); len(query) > 0 {
+		// Encode sorts parameters, so equal queries give equal keys
+		key += "?" + query.Encode()
+	}
+	body, err := requestBody(req)
+	if err != nil {
+		return "", err
+	}
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		key += fmt.Sprintf(" body=%x", sum[:8])
+	}
+	return key, nil
+}
+
+// requestBody returns a request's body, leaving it readable for sending
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// recordingFile returns the file name the response to a request, by its
+// key, is stored under
+func recordingFile(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%x.json", sum[:8])
+}
@@ -0,0 +1,85 @@
+package kubernetes
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is how many times a throttled or failed read is
+	// retried before its error is returned
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff is the wait before the first retry, doubled
+	// before each of the next
+	DefaultRetryBackoff = 250 * time.Millisecond
+
+	// maxRetryWait bounds a single backoff wait
+	maxRetryWait = 10 * time.Second
+)
+
+// retryTransport retries reads the API server throttled (429) or failed
+// with a transient error, waiting with exponential backoff and jitter.
+// Responses carrying Retry-After are left to client-go, which already
+// retries them as long as the server asks. Writes are never retried, as
+// they may have taken effect.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	backoff := t.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.maxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		// Full jitter between half the backoff and all of it, so the
+		// workers of a scan do not retry in lockstep
+		wait := min(backoff/2+rand.N(backoff/2+1), maxRetryWait)
+		backoff = min(backoff*2, maxRetryWait)
+		attrs := []any{"method", req.Method, "path", req.URL.Path, "attempt", attempt + 1, "wait", wait}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		} else {
+			attrs = append(attrs, "status", resp.StatusCode)
+			resp.Body.Close()
+		}
+		slog.Debug("retrying API request", attrs...)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a read failed in a way a retry may fix: the
+// server throttled it or was briefly unavailable, or the connection failed
+// without the request's context being done. A response with Retry-After is
+// not, as client-go retries it itself and retrying here too would multiply
+// the attempts.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	if resp.Header.Get("Retry-After") != "" {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package kubernetes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eventListPageSize is how many events are requested per list call when
+// filling a snapshot
+const eventListPageSize = 500
+
+// snapshot holds the pods and events of one scan run. A scan lists every
+// pod up front and its analyzers look up each pod's events several times,
+// so without it a scan of n pods makes several API calls per pod on top of
+// the list.
+type snapshot struct {
+	ctx       context.Context // of the scan run, so no single diagnosis' deadline cuts a listing short
+	namespace string          // scanned namespace, empty for all
+
+	mu     sync.Mutex
+	pods   map[string]*corev1.Pod  // keyed by namespace/name
+	events map[string]*eventList   // keyed by listed namespace, empty for all
+	byNode map[string][]corev1.Pod // set once every pod of the cluster was listed
+}
+
+// eventList is the events of a namespace, or of every namespace, listed
+// once on first use
+type eventList struct {
+	once        sync.Once
+	byNamespace map[string][]corev1.Event
+	byPod       map[string][]domain.EventInfo // keyed by namespace/name of the pod
+	err         error
+}
+
+// WithSnapshot returns a client for one scan run of a namespace, or of every
+// namespace if namespace is empty, that fetches each kind of object once
+// and answers from memory: pods it lists are recorded and returned by
+// GetPod, the first GetPodEvents or ListNamespaceEvents lists the events of
+// the scanned namespaces for every pod, once every pod of the cluster was
+// listed ListPodsOnNode answers from them, and nodes are cached as by
+// WithNodeCache. Lookups the snapshot cannot answer, like pods created
+// since or a failed event listing, go to the API server as usual.
+func (c *Client) WithSnapshot(ctx context.Context, namespace string) *Client {
+	cached := c.WithNodeCache(ctx)
+	cached.snapshot = &snapshot{
+		ctx:       ctx,
+		namespace: namespace,
+		pods:      make(map[string]*corev1.Pod),
+		events:    make(map[string]*eventList),
+	}
+	return cached
+}
+
+// addPods records listed pods
+func (s *snapshot) addPods(pods []corev1.Pod) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range pods {
+		s.pods[pods[i].Namespace+"/"+pods[i].Name] = &pods[i]
+	}
+}
+
+// listedAllPods indexes the recorded pods by node once a listing of every
+// pod in the cluster completed, so the pods of any node are known
+func (s *snapshot) listedAllPods() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byNode = make(map[string][]corev1.Pod)
+	for _, pod := range s.pods {
+		if pod.Spec.NodeName != "" {
+			s.byNode[pod.Spec.NodeName] = append(s.byNode[pod.Spec.NodeName], *pod)
+		}
+	}
+}
+
+// podsOnNode returns the pods of a node, or false if not every pod of the
+// cluster was listed. It is safe to call on a nil snapshot.
+func (s *snapshot) podsOnNode(nodeName string) ([]corev1.Pod, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byNode == nil {
+		return nil, false
+	}
+	return s.byNode[nodeName], true
+}
+
+// listedAll reports whether every pod of the cluster was listed
+func (s *snapshot) listedAll() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byNode != nil
+}
+
+// nodeNames returns the nodes the listed pods are scheduled to
+func (s *snapshot) nodeNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.byNode))
+	for name := range s.byNode {
+		names = append(names, name)
+	}
+	return names
+}
+
+// pod returns a copy of a recorded pod, or false if it was not listed
+func (s *snapshot) pod(namespace, name string) (*corev1.Pod, bool) {
+	s.mu.Lock()
+	pod, ok := s.pods[namespace+"/"+name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return pod.DeepCopy(), true
+}
+
+// eventsOf returns the listed events covering a namespace: the cluster-wide
+// listing for a scan of every namespace, unless the user may not list
+// events cluster-wide, else the namespace's own
+func (s *snapshot) eventsOf(c *Client, namespace string) (*eventList, error) {
+	if s.namespace == metav1.NamespaceAll {
+		all := s.list(c, metav1.NamespaceAll)
+		if !IsForbidden(all.err) {
+			return all, all.err
+		}
+	}
+	l := s.list(c, namespace)
+	return l, l.err
+}
+
+// list returns the events of a namespace, listing them on first use.
+// Concurrent diagnoses wait for the one listing instead of each making it.
+func (s *snapshot) list(c *Client, namespace string) *eventList {
+	s.mu.Lock()
+	l, ok := s.events[namespace]
+	if !ok {
+		l = &eventList{}
+		s.events[namespace] = l
+	}
+	s.mu.Unlock()
+
+	l.once.Do(func() {
+		l.err = l.load(s.ctx, c, namespace)
+	})
+	return l
+}
+
+// load lists events page by page and indexes them by namespace and pod
+func (l *eventList) load(ctx context.Context, c *Client, namespace string) error {
+	l.byNamespace = make(map[string][]corev1.Event)
+	l.byPod = make(map[string][]domain.EventInfo)
+	opts := metav1.ListOptions{Limit: eventListPageSize}
+	for {
+		page, err := c.clientset.CoreV1().Events(namespace).List(ctx, opts)
+		if err != nil {
+			return wrapAPIError(err, "list", "events", namespace, "")
+		}
+		for _, e := range page.Items {
+			l.byNamespace[e.Namespace] = append(l.byNamespace[e.Namespace], e)
+			if obj := e.InvolvedObject; obj.Kind == "Pod" {
+				key := obj.Namespace + "/" + obj.Name
+				l.byPod[key] = append(l.byPod[key], ExtractEventInfo(e))
+			}
+		}
+		if page.Continue == "" {
+			return nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// podEvents returns the events of a pod, or false if they could not be
+// listed
+func (s *snapshot) podEvents(c *Client, namespace, name string) ([]domain.EventInfo, bool) {
+	l, err := s.eventsOf(c, namespace)
+	if err != nil {
+		return nil, false
+	}
+	events := l.byPod[namespace+"/"+name]
+	return append(make([]domain.EventInfo, 0, len(events)), events...), true
+}
+
+// namespaceEvents returns the events of a namespace, or false if they could
+// not be listed
+func (s *snapshot) namespaceEvents(c *Client, namespace string) ([]corev1.Event, bool) {
+	l, err := s.eventsOf(c, namespace)
+	if err != nil {
+		return nil, false
+	}
+	return append([]corev1.Event(nil), l.byNamespace[namespace]...), true
+}
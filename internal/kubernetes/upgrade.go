@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeUpgradeEventWindow is how recent a node's upgrade or drain event must
+// be to count as an upgrade in progress
+const nodeUpgradeEventWindow = 2 * time.Hour
+
+// nodeUpgradeEventReasons are the reasons of events managed node pools post
+// on a node they replace, e.g. AKS posts Surge, Drain and Upgrade
+var nodeUpgradeEventReasons = []string{"Upgrade", "NodeUpgrade", "Drain", "Surge"}
+
+// NodeUpgrade returns a description of the upgrade or other routine
+// maintenance replacing a node, such as "GKE node pool upgrade", or an
+// empty string if there is none. It looks for the taints and annotations
+// Karpenter, kured and OpenShift set on a node they disrupt, and, for a
+// cordoned node, for recent upgrade or drain events from its node pool.
+func (c *Client) NodeUpgrade(ctx context.Context, nodeName string) (string, error) {
+	node, err := c.GetNode(ctx, nodeName)
+	if err != nil {
+		return "", err
+	}
+
+	for _, taint := range node.Spec.Taints {
+		switch {
+		case taint.Key == "karpenter.sh/disrupted",
+			taint.Key == "karpenter.sh/disruption" && taint.Value == "disrupting":
+			return "Karpenter disruption", nil
+		}
+	}
+	if _, ok := node.Annotations["weave.works/kured-reboot-in-progress"]; ok {
+		return "kured reboot", nil
+	}
+	if node.Annotations["machineconfiguration.openshift.io/state"] == "Working" {
+		return "OpenShift machine config update", nil
+	}
+
+	if !isCordoned(node) {
+		return "", nil
+	}
+	for key := range node.Labels {
+		if strings.HasPrefix(key, "plan.upgrade.cattle.io/") {
+			return "Rancher system upgrade (" + strings.TrimPrefix(key, "plan.upgrade.cattle.io/") + ")", nil
+		}
+	}
+
+	events, err := c.clientset.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=Node,involvedObject.name=%s", nodeName),
+	})
+	if err != nil {
+		// Without events, a cordoned node could be down for any reason
+		return "", nil
+	}
+	for _, e := range events.Items {
+		if time.Since(eventTime(e)) > nodeUpgradeEventWindow {
+			continue
+		}
+		if isUpgradeEvent(e) {
+			return nodePoolKind(node) + " upgrade", nil
+		}
+	}
+	return "", nil
+}
+
+// isUpgradeEvent reports whether a node event is about an upgrade or a
+// drain
+func isUpgradeEvent(e corev1.Event) bool {
+	for _, reason := range nodeUpgradeEventReasons {
+		if e.Reason == reason {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(e.Message), "upgrad")
+}
+
+// isCordoned reports whether a node was marked unschedulable, e.g. by
+// kubectl cordon or a drain
+func isCordoned(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+// nodePoolKind names the managed node pool a node belongs to, from the
+// labels each cloud provider sets
+func nodePoolKind(node *corev1.Node) string {
+	switch {
+	case node.Labels["cloud.google.com/gke-nodepool"] != "":
+		return "GKE node pool"
+	case node.Labels["kubernetes.azure.com/agentpool"] != "":
+		return "AKS node pool"
+	case node.Labels["eks.amazonaws.com/nodegroup"] != "":
+		return "EKS managed node group"
+	}
+	return "Node"
+}
+
+// eventTime returns when an event was last seen, falling back to its event
+// time for events that only set that
+func eventTime(e corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	return e.EventTime.Time
+}
@@ -0,0 +1,85 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// vpaResource is the VerticalPodAutoscaler custom resource
+var vpaResource = schema.GroupVersionResource{
+	Group:    "autoscaling.k8s.io",
+	Version:  "v1",
+	Resource: "verticalpodautoscalers",
+}
+
+// VerticalPodAutoscaler holds the parts of a VPA object pod-doctor uses.
+// The VPA API is a CRD, so it is read through the dynamic client instead of
+// pulling in the autoscaler's generated clientset.
+type VerticalPodAutoscaler struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		TargetRef struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"targetRef"`
+		UpdatePolicy *struct {
+			UpdateMode string `json:"updateMode"`
+		} `json:"updatePolicy,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Recommendation *struct {
+			ContainerRecommendations []VPAContainerRecommendation `json:"containerRecommendations"`
+		} `json:"recommendation,omitempty"`
+	} `json:"status"`
+}
+
+// VPAContainerRecommendation is VPA's recommended requests for one container
+type VPAContainerRecommendation struct {
+	ContainerName string              `json:"containerName"`
+	Target        corev1.ResourceList `json:"target"`
+	LowerBound    corev1.ResourceList `json:"lowerBound"`
+	UpperBound    corev1.ResourceList `json:"upperBound"`
+}
+
+// UpdateMode returns the VPA update mode, defaulting to Auto as VPA does
+func (v *VerticalPodAutoscaler) UpdateMode() string {
+	if v.Spec.UpdatePolicy != nil && v.Spec.UpdatePolicy.UpdateMode != "" {
+		return v.Spec.UpdatePolicy.UpdateMode
+	}
+	return "Auto"
+}
+
+// ContainerRecommendations returns VPA's per-container recommendations, if any
+func (v *VerticalPodAutoscaler) ContainerRecommendations() []VPAContainerRecommendation {
+	if v.Status.Recommendation == nil {
+		return nil
+	}
+	return v.Status.Recommendation.ContainerRecommendations
+}
+
+// ListVerticalPodAutoscalers lists VPA objects in a namespace. It returns no
+// VPAs and no error when the VPA CRD is not installed.
+func (c *Client) ListVerticalPodAutoscalers(ctx context.Context, namespace string) ([]VerticalPodAutoscaler, error) {
+	list, err := c.dynamic.Resource(vpaResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	vpas := make([]VerticalPodAutoscaler, 0, len(list.Items))
+	for _, item := range list.Items {
+		var vpa VerticalPodAutoscaler
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &vpa); err != nil {
+			return nil, err
+		}
+		vpas = append(vpas, vpa)
+	}
+	return vpas, nil
+}
@@ -0,0 +1,42 @@
+// Package logging sets up pod-doctor's debug log: API calls, analyzer runs
+// and the errors that leave a diagnosis incomplete without failing the
+// command. Records go through the default slog logger to stderr or a log
+// file, never to the command's output.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Setup points the default slog logger at the file at logFile, or at stderr
+// if it is empty. --debug logs every record, including each API call;
+// --verbose, or a log file alone, logs analyzer runs and swallowed errors.
+// Without any of them nothing is logged.
+func Setup(stderr io.Writer, verbose, debug bool, logFile string) error {
+	var level slog.Level
+	switch {
+	case debug:
+		level = slog.LevelDebug
+	case verbose || logFile != "":
+		level = slog.LevelInfo
+	default:
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return nil
+	}
+
+	w := stderr
+	if logFile != "" {
+		// Left open until the process exits, as records may come from
+		// goroutines outliving the command
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		w = f
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})))
+	return nil
+}
@@ -0,0 +1,187 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// EventKind is why a notification was sent
+type EventKind string
+
+const (
+	EventUnhealthy   EventKind = "unhealthy"    // a healthy pod became unhealthy
+	EventNewCritical EventKind = "new-critical" // an unhealthy pod has a new critical issue
+)
+
+// Event is a change in a pod's health worth telling someone about
+type Event struct {
+	Kind           EventKind              `json:"kind"`
+	Namespace      string                 `json:"namespace"`
+	Pod            string                 `json:"pod"`
+	Status         domain.PodStatus       `json:"status"`
+	Issue          *domain.Issue          `json:"issue,omitempty"`          // the new critical issue, or the worst issue
+	Recommendation *domain.Recommendation `json:"recommendation,omitempty"` // the top recommendation
+	Time           time.Time              `json:"time"`
+}
+
+// Summary returns a one-line description of the event
+func (e Event) Summary() string {
+	var b strings.Builder
+	switch e.Kind {
+	case EventUnhealthy:
+		fmt.Fprintf(&b, "Pod %s/%s is unhealthy (%s)", e.Namespace, e.Pod, e.Status)
+	default:
+		fmt.Fprintf(&b, "New critical issue on pod %s/%s (%s)", e.Namespace, e.Pod, e.Status)
+	}
+	if e.Issue != nil {
+		fmt.Fprintf(&b, ": %s", e.Issue.Title)
+	}
+	return b.String()
+}
+
+// Notifier delivers events to a backend
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Notifiers delivers events to several backends
+type Notifiers []Notifier
+
+// Notify sends event to every backend, even if some fail
+func (n Notifiers) Notify(ctx context.Context, event Event) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// podState is what the tracker remembers about a pod
+type podState struct {
+	unhealthy bool
+	critical  map[string]bool // critical issues, by issueKey
+}
+
+// Tracker turns successive diagnoses of the same pods into events. Pods
+// that were already unhealthy when the tracker started are remembered
+// without an event, so restarting pod-doctor does not repeat every alert.
+type Tracker struct {
+	started time.Time
+
+	mu   sync.Mutex
+	pods map[string]*podState
+}
+
+// NewTracker creates a tracker that treats pods created from now on as
+// new
+func NewTracker() *Tracker {
+	return &Tracker{started: time.Now(), pods: make(map[string]*podState)}
+}
+
+// Observe records a diagnosis and returns the event it causes, if any: the
+// pod became unhealthy, or it has a critical issue it did not have before
+func (t *Tracker) Observe(d *domain.Diagnosis) (Event, bool) {
+	key := d.Pod.Namespace + "/" + d.Pod.Name
+	state := &podState{unhealthy: isUnhealthy(d), critical: make(map[string]bool)}
+	for _, issue := range d.Issues {
+		if issue.IsCritical() {
+			state.critical[issueKey(issue)] = true
+		}
+	}
+
+	t.mu.Lock()
+	prev, seen := t.pods[key]
+	t.pods[key] = state
+	t.mu.Unlock()
+
+	if !state.unhealthy {
+		return Event{}, false
+	}
+	if !seen {
+		// A pod created before the tracker started may have been failing
+		// for a while; only alert on it when it changes
+		created := d.DiagnosedAt.Add(-d.Pod.Age)
+		if created.Before(t.started) {
+			return Event{}, false
+		}
+		prev = &podState{}
+	}
+
+	var newCritical *domain.Issue
+	for i, issue := range d.Issues {
+		if issue.IsCritical() && !prev.critical[issueKey(issue)] {
+			newCritical = &d.Issues[i]
+			break
+		}
+	}
+
+	event := Event{
+		Namespace: d.Pod.Namespace,
+		Pod:       d.Pod.Name,
+		Status:    d.Status,
+		Time:      d.DiagnosedAt,
+	}
+	switch {
+	case !prev.unhealthy:
+		event.Kind = EventUnhealthy
+		event.Issue = newCritical
+		if event.Issue == nil {
+			event.Issue = worstIssue(d)
+		}
+	case newCritical != nil:
+		event.Kind = EventNewCritical
+		event.Issue = newCritical
+	default:
+		return Event{}, false
+	}
+	if len(d.Recommendations) > 0 {
+		rec := d.Recommendations[0]
+		event.Recommendation = &rec
+	}
+	return event, true
+}
+
+// Forget drops a deleted pod
+func (t *Tracker) Forget(namespace, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pods, namespace+"/"+name)
+}
+
+// isUnhealthy reports whether a diagnosis is worth alerting on. Expected
+// failures from chaos experiments or node upgrades and info issues do not
+// count, as for the exit code.
+func isUnhealthy(d *domain.Diagnosis) bool {
+	if d.IsExpectedFailure() {
+		return false
+	}
+	_, warning, _ := d.IssueCount()
+	return d.HasCriticalIssues() || warning > 0 || d.Status != domain.StatusHealthy
+}
+
+// worstIssue returns the first critical issue, else the first warning
+func worstIssue(d *domain.Diagnosis) *domain.Issue {
+	var worst *domain.Issue
+	for i, issue := range d.Issues {
+		switch {
+		case issue.IsCritical():
+			return &d.Issues[i]
+		case issue.Severity == domain.SeverityWarning && worst == nil:
+			worst = &d.Issues[i]
+		}
+	}
+	return worst
+}
+
+// issueKey identifies an issue across diagnoses of the same pod
+func issueKey(issue domain.Issue) string {
+	return issue.Category + "|" + issue.Container() + "|" + issue.Title
+}
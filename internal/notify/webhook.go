@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds a single delivery, so a slow endpoint cannot stall
+// the scan that triggered it
+const requestTimeout = 10 * time.Second
+
+// Webhook posts events as JSON to an HTTP endpoint
+type Webhook struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhook creates a notifier posting events to url
+func NewWebhook(url string) *Webhook {
+	return &Webhook{url: url, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+// Notify posts the event as JSON
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	return post(ctx, w.httpClient, w.url, event)
+}
+
+// Slack posts events to a Slack incoming webhook
+type Slack struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlack creates a notifier posting events to a Slack incoming webhook url
+func NewSlack(url string) *Slack {
+	return &Slack{url: url, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+// Notify posts the event as a Slack message
+func (s *Slack) Notify(ctx context.Context, event Event) error {
+	return post(ctx, s.httpClient, s.url, struct {
+		Text string `json:"text"`
+	}{slackText(event)})
+}
+
+// slackText formats an event in Slack's mrkdwn
+func slackText(event Event) string {
+	var b strings.Builder
+	icon := ":warning:"
+	if event.Kind == EventNewCritical || (event.Issue != nil && event.Issue.IsCritical()) {
+		icon = ":rotating_light:"
+	}
+	fmt.Fprintf(&b, "%s *%s*", icon, slackEscape(event.Summary()))
+	if event.Issue != nil && event.Issue.Description != "" {
+		fmt.Fprintf(&b, "\n%s", slackEscape(event.Issue.Description))
+	}
+	if rec := event.Recommendation; rec != nil {
+		fmt.Fprintf(&b, "\n*Recommendation:* %s", slackEscape(rec.Title))
+		if rec.Description != "" {
+			fmt.Fprintf(&b, " - %s", slackEscape(rec.Description))
+		}
+		if rec.Command != "" {
+			fmt.Fprintf(&b, "\n`%s`", strings.ReplaceAll(rec.Command, "`", "'"))
+		}
+	}
+	return b.String()
+}
+
+// slackEscape escapes the characters Slack treats as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// post sends payload as JSON and fails on any non-2xx response
+func post(ctx context.Context, httpClient *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid notification URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification to %s failed: %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// PrintAcknowledgements prints the acknowledgements of a namespace as a
+// table, marking the expired ones
+func PrintAcknowledgements(w io.Writer, namespace string, acks []domain.Acknowledgement) {
+	if len(acks) == 0 {
+		PrintInfo(w, fmt.Sprintf("No acknowledgements in namespace %s", namespace))
+		return
+	}
+
+	now := time.Now()
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(mutedStyle).
+		Headers("POD", "ISSUE", "BY", "EXPIRES", "REASON")
+	for _, a := range acks {
+		expires := a.Expires.Local().Format("2006-01-02 15:04")
+		if a.Expired(now) {
+			expires = mutedStyle.Render(expires + " (expired)")
+		}
+		tbl.Row(a.Pod, a.Issue, valueOrNA(a.By), expires, truncate(a.Reason, topIssueWidth))
+	}
+	tbl.StyleFunc(func(row, col int) lipgloss.Style {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if row == table.HeaderRow {
+			return style.Bold(true)
+		}
+		return style
+	})
+	fmt.Fprintln(w, tbl.Render())
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// PrintBench prints a benchmark of the analyzers as a table, slowest first
+func PrintBench(w io.Writer, report *domain.BenchReport) {
+	if report.Pods == 0 {
+		PrintInfo(w, "No pods to benchmark")
+		return
+	}
+
+	fmt.Fprintf(w, "%d analyzers over %d %s pods in %s\n\n",
+		len(report.Analyzers), report.Pods, report.Source, report.Duration.Round(time.Millisecond))
+
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(mutedStyle).
+		Headers("ANALYZER", "TIME/POD", "PODS/S", "ALLOCS/POD", "BYTES/POD", "ISSUES", "ERRORS")
+	for _, a := range report.Analyzers {
+		tbl.Row(
+			a.Name,
+			a.PerPod.Round(time.Microsecond).String(),
+			fmt.Sprintf("%.0f", a.PodsPerSecond),
+			fmt.Sprintf("%d", a.AllocsPerPod),
+			formatBytes(a.BytesPerPod),
+			fmt.Sprintf("%d", a.Issues),
+			fmt.Sprintf("%d", a.Errors),
+		)
+	}
+	tbl.StyleFunc(func(row, col int) lipgloss.Style {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if row == table.HeaderRow {
+			return style.Bold(true)
+		}
+		if col > 0 {
+			return style.Align(lipgloss.Right)
+		}
+		return style
+	})
+	fmt.Fprintln(w, tbl.Render())
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. 12.3 KiB
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,108 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+)
+
+// ClusterScan is the scan of one kube context in a multi-context scan, with
+// the settings it ran with
+type ClusterScan struct {
+	Context           string                        `json:"context" yaml:"context"`
+	Namespace         string                        `json:"namespace,omitempty" yaml:"namespace,omitempty"` // empty for all namespaces
+	LabelSelector     string                        `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty"`
+	Concurrency       int                           `json:"concurrency" yaml:"concurrency"`
+	QPS               float32                       `json:"qps,omitempty" yaml:"qps,omitempty"`
+	Burst             int                           `json:"burst,omitempty" yaml:"burst,omitempty"`
+	Duration          time.Duration                 `json:"duration" yaml:"duration"`
+	Listed            int                           `json:"listed" yaml:"listed"` // pods listed, before sampling
+	Diagnoses         []*domain.Diagnosis           `json:"diagnoses" yaml:"diagnoses"`
+	FailedPods        int                           `json:"failedPods,omitempty" yaml:"failedPods,omitempty"`
+	SkippedNamespaces []kubernetes.SkippedNamespace `json:"skippedNamespaces,omitempty" yaml:"skippedNamespaces,omitempty"`
+	Error             string                        `json:"error,omitempty" yaml:"error,omitempty"` // why the cluster could not be scanned
+
+	Failures []ScanFailure `json:"-" yaml:"-"`
+}
+
+// PrintClusterScans prints to w the scan of each kube context in turn, with
+// the pods it failed to diagnose and namespaces it skipped, then a table
+// comparing the clusters, with how long each took
+func PrintClusterScans(w io.Writer, scans []ClusterScan, t ScanTable) {
+	for _, s := range scans {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, headerStyle.Render("Context "+s.Context))
+		if s.Error != "" {
+			PrintError(w, s.Error)
+			continue
+		}
+		PrintScanSummary(w, s.Diagnoses, t)
+		PrintScanFailures(w, s.Failures, len(s.Diagnoses)+len(s.Failures))
+		PrintSkippedNamespaces(w, s.SkippedNamespaces)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("Clusters"))
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(mutedStyle).
+		Headers("CONTEXT", "NAMESPACE", "PODS", "UNHEALTHY", "CRITICAL", "FAILED", "CONCURRENCY", "QPS", "DURATION")
+	for _, s := range scans {
+		if s.Error != "" {
+			tbl.Row(s.Context, namespaceOrAll(s.Namespace), "-", "-", "-", "-",
+				fmt.Sprintf("%d", s.Concurrency), formatQPS(s.QPS), criticalStyle.Render("error after "+s.Duration.Round(time.Millisecond).String()))
+			continue
+		}
+		var unhealthy, critical int
+		for _, d := range s.Diagnoses {
+			if d.IsHealthy() || d.IsExpectedFailure() {
+				continue
+			}
+			unhealthy++
+			if d.HasCriticalIssues() {
+				critical++
+			}
+		}
+		tbl.Row(
+			s.Context,
+			namespaceOrAll(s.Namespace),
+			fmt.Sprintf("%d", len(s.Diagnoses)),
+			fmt.Sprintf("%d", unhealthy),
+			fmt.Sprintf("%d", critical),
+			fmt.Sprintf("%d", s.FailedPods),
+			fmt.Sprintf("%d", s.Concurrency),
+			formatQPS(s.QPS),
+			s.Duration.Round(time.Millisecond).String(),
+		)
+	}
+	tbl.StyleFunc(func(row, col int) lipgloss.Style {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if row == table.HeaderRow {
+			return style.Bold(true)
+		}
+		return style
+	})
+	fmt.Fprintln(w, tbl.Render())
+	fmt.Fprintln(w)
+}
+
+// namespaceOrAll renders the namespace a cluster was scanned in
+func namespaceOrAll(namespace string) string {
+	if namespace == "" {
+		return "*"
+	}
+	return namespace
+}
+
+// formatQPS renders a client-side rate limit, or default when unset
+func formatQPS(qps float32) string {
+	if qps <= 0 {
+		return "default"
+	}
+	return fmt.Sprintf("%g", qps)
+}
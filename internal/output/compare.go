@@ -0,0 +1,95 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// PrintComparison prints how two pods differ, the failing one's values and
+// issues highlighted
+func PrintComparison(w io.Writer, c *domain.PodComparison) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Comparison: %s/%s vs %s", c.Namespace, c.A.Name, c.B.Name)))
+	fmt.Fprintln(w)
+	for _, p := range []domain.ComparedPod{c.A, c.B} {
+		label := p.Name
+		if c.IsFailing(p.Name) {
+			label = criticalStyle.Render(p.Name + " (failing)")
+		}
+		fmt.Fprintf(w, "  %s  %s  score %s  node %s  restarts %d\n",
+			label, p.Status, scoreStyle(p.HealthScore).Render(fmt.Sprintf("%d", p.HealthScore)), valueOrNA(p.Node), p.Restarts)
+	}
+	if !c.SameWorkload {
+		fmt.Fprintln(w)
+		PrintWarning(w, "The pods do not belong to the same workload, so many differences are expected")
+	}
+	fmt.Fprintln(w)
+
+	if len(c.Differences) == 0 {
+		fmt.Fprintln(w, successStyle.Render(indicator(indicatorOK)+" No differences in spec, placement or state"))
+	} else {
+		failingCol := -1
+		switch {
+		case c.IsFailing(c.A.Name):
+			failingCol = 2
+		case c.IsFailing(c.B.Name):
+			failingCol = 3
+		}
+		tbl := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(mutedStyle).
+			Headers("FIELD", "CONTAINER", truncate(c.A.Name, 40), truncate(c.B.Name, 40))
+		for _, d := range c.Differences {
+			tbl.Row(d.Field, valueOrDash(d.Container), truncate(valueOrDash(d.A), 50), truncate(valueOrDash(d.B), 50))
+		}
+		tbl.StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if row == table.HeaderRow {
+				return style.Bold(true)
+			}
+			if col == failingCol {
+				return style.Inherit(warningStyle)
+			}
+			return style
+		})
+		fmt.Fprintln(w, tbl.Render())
+	}
+
+	only := []struct {
+		pod    string
+		issues []domain.Issue
+	}{{c.A.Name, c.OnlyA}, {c.B.Name, c.OnlyB}}
+	if c.IsFailing(c.B.Name) {
+		only[0], only[1] = only[1], only[0]
+	}
+	for _, o := range only {
+		if len(o.issues) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Only on %s: %d", o.pod, len(o.issues))))
+		fmt.Fprintln(w)
+		for _, issue := range o.issues {
+			printIssue(w, issue)
+		}
+	}
+	if len(c.Shared) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("On both: %d", len(c.Shared))))
+		for _, issue := range c.Shared {
+			fmt.Fprintf(w, "  %s %s\n", mutedStyle.Render(indicator(indicatorInfo)), issue.Title)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
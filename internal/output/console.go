@@ -2,11 +2,18 @@ package output
 
 import (
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/history"
+	"github.com/pavanInnamuri/pod-doctor/internal/kb"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
 )
 
 var (
@@ -33,64 +40,87 @@ var (
 			Padding(0, 1)
 )
 
-// PrintDiagnosis prints a diagnosis result to the console
-func PrintDiagnosis(d *domain.Diagnosis) {
+// expandEvents lists every warning event instead of grouping them by reason
+var expandEvents bool
+
+// SetExpandEvents switches diagnosis output between warning events grouped
+// by reason (the default) and every event listed on its own
+func SetExpandEvents(enabled bool) {
+	expandEvents = enabled
+}
+
+// PrintDiagnosis prints a diagnosis result for the console to w
+func PrintDiagnosis(w io.Writer, d *domain.Diagnosis) {
 	// Header
-	fmt.Println()
-	printHeader(d)
-	fmt.Println()
+	fmt.Fprintln(w)
+	printHeader(w, d)
+	fmt.Fprintln(w)
 
 	// Pod Info
-	printPodInfo(d)
-	fmt.Println()
+	printPodInfo(w, d)
+	fmt.Fprintln(w)
+
+	// How the containers started
+	printStartOrder(w, d.StartOrder)
+
+	// Custom readiness gates and their conditions
+	printReadinessGates(w, d.ReadinessGates)
+
+	// Why a running pod is not ready
+	printNotReady(w, d.NotReady)
 
 	// Issues
-	printIssues(d.Issues)
-	fmt.Println()
+	printIssues(w, d.Issues)
+	printAcknowledged(w, d.Acknowledged)
+	fmt.Fprintln(w)
 
 	// Events (if any warnings)
-	printEvents(d.Events)
+	printEvents(w, d.Events)
 
 	// Node Health
 	if d.Node != nil {
-		printNodeHealth(d.Node)
+		printNodeHealth(w, d.Node)
 	}
 
 	// Recommendations
-	printRecommendations(d.Recommendations)
+	printRecommendations(w, d.Recommendations)
+
+	// Checks that could not run
+	printWarnings(w, d.Warnings)
 
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 // printHeader prints the diagnosis header
-func printHeader(d *domain.Diagnosis) {
+func printHeader(w io.Writer, d *domain.Diagnosis) {
 	title := fmt.Sprintf("Diagnosis: %s/%s", d.Pod.Namespace, d.Pod.Name)
-	fmt.Println(headerStyle.Render(title))
-	fmt.Println(mutedStyle.Render(fmt.Sprintf("Diagnosed at: %s", d.DiagnosedAt.Format("2006-01-02 15:04:05"))))
+	fmt.Fprintln(w, headerStyle.Render(title))
+	fmt.Fprintln(w, mutedStyle.Render(fmt.Sprintf("Diagnosed at: %s", d.DiagnosedAt.Format("2006-01-02 15:04:05"))))
 }
 
 // printPodInfo prints pod information
-func printPodInfo(d *domain.Diagnosis) {
+func printPodInfo(w io.Writer, d *domain.Diagnosis) {
 	// Status with color
 	statusStyle := successStyle
-	statusIcon := "✓"
+	statusIcon := indicator(indicatorOK)
 	switch d.Status {
 	case domain.StatusHealthy:
 		statusStyle = successStyle
-		statusIcon = "✓"
+		statusIcon = indicator(indicatorOK)
 	case domain.StatusCrashLoop, domain.StatusOOMKilled, domain.StatusError, domain.StatusImagePull:
 		statusStyle = criticalStyle
-		statusIcon = "✗"
+		statusIcon = indicator(indicatorCritical)
 	case domain.StatusPending, domain.StatusNotReady, domain.StatusTerminating:
 		statusStyle = warningStyle
-		statusIcon = "!"
+		statusIcon = indicator(indicatorWarning)
 	default:
 		statusStyle = warningStyle
-		statusIcon = "?"
+		statusIcon = indicator(indicatorUnknown)
 	}
 
-	fmt.Printf("Status: %s %s\n", statusIcon, statusStyle.Render(string(d.Status)))
-	fmt.Printf("Node: %s | Phase: %s | Age: %s | Restarts: %d\n",
+	fmt.Fprintf(w, "Status: %s %s\n", statusIcon, statusStyle.Render(string(d.Status)))
+	fmt.Fprintf(w, "Health Score: %s\n", scoreStyle(d.HealthScore).Render(fmt.Sprintf("%d/100", d.HealthScore)))
+	fmt.Fprintf(w, "Node: %s | Phase: %s | Age: %s | Restarts: %d\n",
 		valueOrNA(d.Pod.Node),
 		d.Pod.Phase,
 		formatDuration(d.Pod.Age),
@@ -98,13 +128,25 @@ func printPodInfo(d *domain.Diagnosis) {
 	)
 
 	if d.Pod.IP != "" {
-		fmt.Printf("Pod IP: %s\n", d.Pod.IP)
+		fmt.Fprintf(w, "Pod IP: %s\n", d.Pod.IP)
+	}
+	if d.Workload != nil {
+		fmt.Fprintf(w, "Workload: %s\n", d.Workload.Ref())
+	}
+	if d.ExpectedFailure != "" {
+		fmt.Fprintf(w, "Expected failure: %s\n", infoStyle.Render(d.ExpectedFailure))
+	}
+	if r := d.Resources; r != nil {
+		fmt.Fprintf(w, "CPU: %s used / %s req / %s limit | Memory: %s used / %s req / %s limit\n",
+			valueOrNA(r.CPUUsage), valueOrNA(r.CPURequests), valueOrNA(r.CPULimits),
+			valueOrNA(r.MemoryUsage), valueOrNA(r.MemoryRequests), valueOrNA(r.MemoryLimits),
+		)
 	}
 
 	// Container summary
 	if len(d.Pod.Containers) > 0 {
-		fmt.Println()
-		fmt.Println(boldStyle.Render("Containers:"))
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, boldStyle.Render("Containers:"))
 		for _, c := range d.Pod.Containers {
 			stateStyle := successStyle
 			if c.State != "running" || !c.Ready {
@@ -114,23 +156,135 @@ func printPodInfo(d *domain.Diagnosis) {
 			if c.Ready {
 				readyStr = "ready"
 			}
-			fmt.Printf("  • %s: %s (%s, restarts: %d)\n",
+			fmt.Fprintf(w, "  • %s: %s (%s, restarts: %d)\n",
 				c.Name,
 				stateStyle.Render(c.State),
 				readyStr,
 				c.RestartCount,
 			)
 			if c.Reason != "" {
-				fmt.Printf("    Reason: %s\n", mutedStyle.Render(c.Reason))
+				fmt.Fprintf(w, "    Reason: %s\n", mutedStyle.Render(c.Reason))
 			}
 		}
 	}
 }
 
+// printAcknowledged lists the acknowledgements that silenced issues, so
+// silenced problems stay visible until they expire
+func printAcknowledged(w io.Writer, acks []domain.Acknowledgement) {
+	if len(acks) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, boldStyle.Render("Acknowledged:"))
+	for _, a := range acks {
+		line := a.Issue
+		if a.By != "" {
+			line += " by " + a.By
+		}
+		line += ", until " + a.Expires.Local().Format("2006-01-02 15:04")
+		if a.Reason != "" {
+			line += ": " + a.Reason
+		}
+		fmt.Fprintf(w, "  %s %s\n", infoStyle.Render(indicator(indicatorInfo)), mutedStyle.Render(wrapHanging(line, 4, 4)))
+	}
+}
+
+// printWarnings prints the checks that could not run, so a clean diagnosis
+// on a restricted cluster is not mistaken for a complete one
+func printWarnings(w io.Writer, warnings []domain.AnalyzerWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("Incomplete Diagnosis:"))
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "  %s %s\n", infoStyle.Render(indicator(indicatorInfo)), mutedStyle.Render(wrapHanging(warning.Message, 4, 4)))
+	}
+}
+
+// printNotReady prints what blocks a running pod from becoming ready
+func printNotReady(w io.Writer, blockers []domain.ReadinessBlocker) {
+	if len(blockers) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, headerStyle.Render("Why Not Ready:"))
+	for _, b := range blockers {
+		name := "container " + b.Name
+		if b.Kind == "readinessGate" {
+			name = "readiness gate " + b.Name
+		}
+		fmt.Fprintf(w, "  %s %s: %s\n", warningStyle.Render(indicator(indicatorWarning)), boldStyle.Render(name), b.Reason)
+		if b.Message != "" {
+			fmt.Fprintf(w, "    %s\n", mutedStyle.Render(wrapHanging(b.Message, 4, 4)))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// printReadinessGates prints the custom readiness gates of a pod with the
+// status of their conditions
+func printReadinessGates(w io.Writer, gates []domain.ReadinessGate) {
+	if len(gates) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, boldStyle.Render("Readiness Gates:"))
+	for _, g := range gates {
+		switch g.Status {
+		case "True":
+			fmt.Fprintf(w, "  %s %s\n", successStyle.Render(indicator(indicatorOK)), g.ConditionType)
+		case "":
+			fmt.Fprintf(w, "  %s %s: no condition posted\n", warningStyle.Render(indicator(indicatorWarning)), g.ConditionType)
+		default:
+			fmt.Fprintf(w, "  %s %s: %s\n", warningStyle.Render(indicator(indicatorWarning)), g.ConditionType, strings.TrimSpace(g.Status+" "+g.Reason))
+		}
+		if g.Controller != "" {
+			fmt.Fprintf(w, "    %s\n", mutedStyle.Render("set by the "+g.Controller))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// printStartOrder prints the containers of a multi-container pod in start
+// order, with when each started and what it waited for
+func printStartOrder(w io.Writer, order []domain.ContainerStart) {
+	if len(order) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, boldStyle.Render("Start Order:"))
+	for i, c := range order {
+		line := fmt.Sprintf("%d. %s (%s)", i+1, c.Name, c.Kind)
+		if c.StartedAt.IsZero() {
+			line += " not started"
+		} else {
+			line += " started " + c.StartedAt.Local().Format("15:04:05")
+		}
+		if !c.FinishedAt.IsZero() {
+			line += ", completed " + c.FinishedAt.Local().Format("15:04:05")
+		} else if c.Ready {
+			line += ", ready"
+		}
+		if c.Restarts > 0 {
+			line += fmt.Sprintf(", %d restarts", c.Restarts)
+		}
+		fmt.Fprintf(w, "  %s", line)
+		if len(c.DependsOn) > 0 {
+			fmt.Fprint(w, mutedStyle.Render(" — after "+strings.Join(c.DependsOn, ", ")))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
 // printIssues prints detected issues
-func printIssues(issues []domain.Issue) {
+func printIssues(w io.Writer, issues []domain.Issue) {
 	if len(issues) == 0 {
-		fmt.Println(successStyle.Render("✓ No issues detected"))
+		fmt.Fprintln(w, successStyle.Render(indicator(indicatorOK)+" No issues detected"))
 		return
 	}
 
@@ -149,51 +303,57 @@ func printIssues(issues []domain.Issue) {
 
 	summary := fmt.Sprintf("Issues Found: %d critical, %d warnings, %d info",
 		critical, warning, info)
-	fmt.Println(headerStyle.Render(summary))
-	fmt.Println()
+	fmt.Fprintln(w, headerStyle.Render(summary))
+	fmt.Fprintln(w)
 
 	for _, issue := range issues {
-		printIssue(issue)
+		printIssue(w, issue)
 	}
 }
 
-// printIssue prints a single issue
-func printIssue(issue domain.Issue) {
+// printIssue prints a single issue followed by a blank line
+func printIssue(w io.Writer, issue domain.Issue) {
+	printIssueLines(w, issue)
+	fmt.Fprintln(w)
+}
+
+// printIssueLines prints a single issue
+func printIssueLines(w io.Writer, issue domain.Issue) {
 	var icon string
 	var style lipgloss.Style
 
 	switch issue.Severity {
 	case domain.SeverityCritical:
-		icon = "✗"
+		icon = indicator(indicatorCritical)
 		style = criticalStyle
 	case domain.SeverityWarning:
-		icon = "!"
+		icon = indicator(indicatorWarning)
 		style = warningStyle
 	default:
-		icon = "•"
+		icon = indicator(indicatorInfo)
 		style = infoStyle
 	}
 
-	fmt.Printf("  %s %s\n", style.Render(icon), style.Render(issue.Title))
-	fmt.Printf("    %s\n", issue.Description)
+	title := style.Render(issue.Title)
+	if issue.ID != "" {
+		title += " " + mutedStyle.Render("["+issue.ID+"]")
+	}
+	fmt.Fprintf(w, "  %s %s\n", style.Render(icon), title)
+	fmt.Fprintf(w, "    %s\n", wrapHanging(issue.Description, 4, 4))
 
 	// Print relevant details
 	if len(issue.Details) > 0 {
-		for key, value := range issue.Details {
-			if key != "container" && key != "reason" && value != "" {
-				// Truncate long values
-				if len(value) > 100 {
-					value = value[:97] + "..."
-				}
-				fmt.Printf("    %s: %s\n", mutedStyle.Render(key), value)
+		for _, key := range issue.DetailKeys() {
+			if value := issue.Details[key]; key != "container" && key != "reason" && value != "" {
+				// Wrap long values with a hanging indent under the value
+				fmt.Fprintf(w, "    %s: %s\n", mutedStyle.Render(key), wrapHanging(value, 6+len(key), 6+len(key)))
 			}
 		}
 	}
-	fmt.Println()
 }
 
 // printEvents prints warning events
-func printEvents(events []domain.EventInfo) {
+func printEvents(w io.Writer, events []domain.EventInfo) {
 	var warnings []domain.EventInfo
 	for _, e := range events {
 		if e.Type == "Warning" {
@@ -205,56 +365,84 @@ func printEvents(events []domain.EventInfo) {
 		return
 	}
 
-	fmt.Println(headerStyle.Render("Recent Warning Events:"))
-	for _, event := range warnings {
-		fmt.Printf("  • [%s] %s: %s\n",
-			warningStyle.Render(event.Reason),
-			mutedStyle.Render(event.LastSeen.Format("15:04:05")),
-			truncate(event.Message, 80),
+	fmt.Fprintln(w, headerStyle.Render("Recent Warning Events:"))
+	// Messages start after "  • [<reason>] <when>: ", 9 columns besides
+	// the reason and when
+	if expandEvents {
+		for _, event := range warnings {
+			fmt.Fprintf(w, "  • [%s] %s: %s\n",
+				warningStyle.Render(event.Reason),
+				mutedStyle.Render(event.LastSeen.Format("15:04:05")),
+				wrapHanging(event.Message, 9+len(event.Reason)+len("15:04:05"), 4),
+			)
+		}
+		fmt.Fprintln(w)
+		return
+	}
+
+	for _, g := range domain.GroupEventsByReason(warnings) {
+		occurrences := eventOccurrences(g)
+		fmt.Fprintf(w, "  • [%s] %s: %s\n",
+			warningStyle.Render(g.Reason),
+			mutedStyle.Render(occurrences),
+			wrapHanging(g.Message, 9+len(g.Reason)+utf8.RuneCountInString(occurrences), 4),
 		)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
+}
+
+// eventOccurrences summarizes a group of events, e.g. "×47 over 2h0m, last
+// 15:04:05" or just "15:04:05" for a single event
+func eventOccurrences(g domain.EventGroup) string {
+	last := g.LastSeen.Format("15:04:05")
+	if g.Count <= 1 {
+		return last
+	}
+	if span := g.LastSeen.Sub(g.FirstSeen); span >= time.Minute {
+		return fmt.Sprintf("×%d over %s, last %s", g.Count, formatDuration(span), last)
+	}
+	return fmt.Sprintf("×%d, last %s", g.Count, last)
 }
 
 // printNodeHealth prints node health information
-func printNodeHealth(node *domain.NodeHealth) {
+func printNodeHealth(w io.Writer, node *domain.NodeHealth) {
 	if node.Ready && !node.MemoryPressure && !node.DiskPressure && !node.PIDPressure && !node.NetworkUnavail {
 		return // Node is healthy, skip
 	}
 
-	fmt.Println(headerStyle.Render("Node Health:"))
-	fmt.Printf("  Node: %s\n", node.Name)
+	fmt.Fprintln(w, headerStyle.Render("Node Health:"))
+	fmt.Fprintf(w, "  Node: %s\n", node.Name)
 
 	if !node.Ready {
-		fmt.Printf("  %s Node is not ready\n", criticalStyle.Render("✗"))
+		fmt.Fprintf(w, "  %s Node is not ready\n", criticalStyle.Render(indicator(indicatorCritical)))
 	}
 	if node.MemoryPressure {
-		fmt.Printf("  %s Memory pressure\n", warningStyle.Render("!"))
+		fmt.Fprintf(w, "  %s Memory pressure\n", warningStyle.Render(indicator(indicatorWarning)))
 	}
 	if node.DiskPressure {
-		fmt.Printf("  %s Disk pressure\n", warningStyle.Render("!"))
+		fmt.Fprintf(w, "  %s Disk pressure\n", warningStyle.Render(indicator(indicatorWarning)))
 	}
 	if node.PIDPressure {
-		fmt.Printf("  %s PID pressure\n", warningStyle.Render("!"))
+		fmt.Fprintf(w, "  %s PID pressure\n", warningStyle.Render(indicator(indicatorWarning)))
 	}
 	if node.NetworkUnavail {
-		fmt.Printf("  %s Network unavailable\n", criticalStyle.Render("✗"))
+		fmt.Fprintf(w, "  %s Network unavailable\n", criticalStyle.Render(indicator(indicatorCritical)))
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 // printRecommendations prints fix recommendations
-func printRecommendations(recs []domain.Recommendation) {
+func printRecommendations(w io.Writer, recs []domain.Recommendation) {
 	if len(recs) == 0 {
 		return
 	}
 
-	fmt.Println(headerStyle.Render("Recommendations:"))
+	fmt.Fprintln(w, headerStyle.Render("Recommendations:"))
 	for i, rec := range recs {
-		fmt.Printf("  %d. %s\n", i+1, boldStyle.Render(rec.Title))
-		fmt.Printf("     %s\n", rec.Description)
+		fmt.Fprintf(w, "  %d. %s\n", i+1, boldStyle.Render(rec.Title))
+		fmt.Fprintf(w, "     %s\n", rec.Description)
 		if rec.Command != "" {
-			fmt.Printf("     %s %s\n", mutedStyle.Render("$"), infoStyle.Render(rec.Command))
+			fmt.Fprintf(w, "     %s %s\n", mutedStyle.Render("$"), infoStyle.Render(rec.Command))
 		}
 	}
 }
@@ -283,68 +471,515 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dd%dh", days, hours)
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// scoreStyle returns the style used to render a health score
+func scoreStyle(score int) lipgloss.Style {
+	switch {
+	case score >= 80:
+		return successStyle
+	case score >= 50:
+		return warningStyle
+	default:
+		return criticalStyle
 	}
-	return s[:maxLen-3] + "..."
 }
 
-// PrintScanSummary prints a summary of scanned pods
-func PrintScanSummary(diagnoses []*domain.Diagnosis) {
-	fmt.Println()
-	fmt.Println(headerStyle.Render("Scan Summary"))
-	fmt.Println()
+// truncate shortens s to at most maxLen columns, marking the cut with
+// "...". It never splits a multi-byte character or an escape sequence.
+func truncate(s string, maxLen int) string {
+	return ansi.Truncate(s, maxLen, "...")
+}
+
+// PrintScanSummary prints a summary of scanned pods, with the unhealthy ones
+// in a table
+func PrintScanSummary(w io.Writer, diagnoses []*domain.Diagnosis, t ScanTable) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("Scan Summary"))
+	fmt.Fprintln(w)
 
-	var healthy, unhealthy int
+	var healthy, unhealthy, expected int
 	for _, d := range diagnoses {
-		if d.IsHealthy() {
+		switch {
+		case d.IsHealthy():
 			healthy++
-		} else {
+		case d.IsExpectedFailure():
+			expected++
+		default:
 			unhealthy++
 		}
 	}
 
-	fmt.Printf("Total pods scanned: %d\n", len(diagnoses))
-	fmt.Printf("  %s Healthy: %d\n", successStyle.Render("✓"), healthy)
-	fmt.Printf("  %s Unhealthy: %d\n", criticalStyle.Render("✗"), unhealthy)
-	fmt.Println()
+	fmt.Fprintf(w, "Total pods scanned: %d\n", len(diagnoses))
+	fmt.Fprintf(w, "  %s Healthy: %d\n", successStyle.Render(indicator(indicatorOK)), healthy)
+	fmt.Fprintf(w, "  %s Unhealthy: %d\n", criticalStyle.Render(indicator(indicatorCritical)), unhealthy)
+	if expected > 0 {
+		fmt.Fprintf(w, "  %s Expected failures (chaos experiments, node upgrades): %d\n", infoStyle.Render(indicator(indicatorInfo)), expected)
+	}
+	if len(diagnoses) > 0 {
+		total := 0
+		for _, d := range diagnoses {
+			total += d.HealthScore
+		}
+		avg := total / len(diagnoses)
+		fmt.Fprintf(w, "  Average health score: %s\n", scoreStyle(avg).Render(fmt.Sprintf("%d/100", avg)))
+	}
+	fmt.Fprintln(w)
 
 	// List unhealthy pods
 	if unhealthy > 0 {
-		fmt.Println(headerStyle.Render("Unhealthy Pods:"))
+		var rows []*domain.Diagnosis
 		for _, d := range diagnoses {
-			if !d.IsHealthy() {
-				critical, warning, _ := d.IssueCount()
-				statusStyle := warningStyle
-				if critical > 0 {
-					statusStyle = criticalStyle
-				}
-				fmt.Printf("  • %s/%s: %s (%d critical, %d warnings)\n",
-					d.Pod.Namespace,
-					d.Pod.Name,
-					statusStyle.Render(string(d.Status)),
-					critical,
-					warning,
-				)
+			if !d.IsHealthy() && !d.IsExpectedFailure() {
+				rows = append(rows, d)
 			}
 		}
+		fmt.Fprintln(w, headerStyle.Render("Unhealthy Pods:"))
+		printScanTable(w, rows, t)
+	}
+
+	if expected > 0 {
+		printExpectedFailures(w, diagnoses)
+	}
+}
+
+// printExpectedFailures lists pods disrupted on purpose by chaos experiments
+// or node upgrades
+func printExpectedFailures(w io.Writer, diagnoses []*domain.Diagnosis) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("Expected Failures:"))
+	for _, d := range diagnoses {
+		if d.IsExpectedFailure() {
+			fmt.Fprintf(w, "  • %s/%s: %s %s\n",
+				d.Pod.Namespace,
+				d.Pod.Name,
+				string(d.Status),
+				mutedStyle.Render("— expected failure ("+d.ExpectedFailure+")"),
+			)
+		}
+	}
+}
+
+// PrintDependencyFailures prints dependencies that several pods fail to reach
+func PrintDependencyFailures(w io.Writer, failures []domain.DependencyFailure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("Failing Dependencies:"))
+	for _, f := range failures {
+		fmt.Fprintf(w, "  %s Dependency %s appears down in %s, affecting %d pods\n",
+			criticalStyle.Render(indicator(indicatorCritical)),
+			boldStyle.Render(f.Target),
+			f.Namespace,
+			len(f.Pods),
+		)
+		fmt.Fprintf(w, "    Pods: %s\n", wrapHanging(strings.Join(f.Pods, ", "), 10, 10))
 	}
 }
 
+// PrintWorkloadReport prints the aggregated diagnosis of a workload's pods:
+// issues every replica has, issues only some have, merged recommendations
+// and a line per pod
+func PrintWorkloadReport(w io.Writer, r *domain.WorkloadReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Workload Diagnosis: %s (%s)", r.Ref(), r.Namespace)))
+	if r.Kind != "" {
+		fmt.Fprintln(w, mutedStyle.Render("Selector: "+r.Selector))
+	}
+	fmt.Fprintln(w)
+
+	var statuses []string
+	for status, count := range r.Statuses {
+		statuses = append(statuses, fmt.Sprintf("%s: %d", status, count))
+	}
+	sort.Strings(statuses)
+	fmt.Fprintf(w, "Pods: %d (%d healthy) | %s\n", r.Pods, r.Healthy, strings.Join(statuses, ", "))
+	fmt.Fprintln(w)
+
+	if len(r.CommonIssues) == 0 && len(r.PodIssues) == 0 {
+		fmt.Fprintln(w, successStyle.Render(indicator(indicatorOK)+" No issues detected"))
+		fmt.Fprintln(w)
+	}
+	if len(r.CommonIssues) > 0 {
+		fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Common Issues (all %d pods):", r.Pods)))
+		fmt.Fprintln(w)
+		for _, issue := range r.CommonIssues {
+			printIssue(w, issue.Issue)
+		}
+	}
+	if len(r.PodIssues) > 0 {
+		fmt.Fprintln(w, headerStyle.Render("Pod-Specific Issues:"))
+		fmt.Fprintln(w)
+		for _, issue := range r.PodIssues {
+			printIssueLines(w, issue.Issue)
+			pods := fmt.Sprintf("%d of %d pods: %s", len(issue.Pods), r.Pods, strings.Join(issue.Pods, ", "))
+			fmt.Fprintf(w, "    %s\n", mutedStyle.Render(wrapHanging(pods, 4, 4)))
+			fmt.Fprintln(w)
+		}
+	}
+
+	printRecommendations(w, r.Recommendations)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("Pods:"))
+	for _, d := range r.Diagnoses {
+		critical, warning, _ := d.IssueCount()
+		style := successStyle
+		switch {
+		case critical > 0:
+			style = criticalStyle
+		case !d.IsHealthy():
+			style = warningStyle
+		}
+		fmt.Fprintf(w, "  • %s: %s [score %s] (%d critical, %d warnings)\n",
+			d.Pod.Name,
+			style.Render(string(d.Status)),
+			scoreStyle(d.HealthScore).Render(fmt.Sprintf("%d", d.HealthScore)),
+			critical,
+			warning,
+		)
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintSLOBreaches prints workloads whose lifecycle SLO indicators are breached
+func PrintSLOBreaches(w io.Writer, breaches []domain.Issue) {
+	if len(breaches) == 0 {
+		return
+	}
+
+	sort.Slice(breaches, func(i, j int) bool {
+		return breaches[i].Title < breaches[j].Title
+	})
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("SLO Breaches:"))
+	for _, b := range breaches {
+		style := warningStyle
+		if b.Severity == domain.SeverityCritical {
+			style = criticalStyle
+		}
+		fmt.Fprintf(w, "  %s %s/%s: %s (threshold %s)\n",
+			style.Render(indicator(string(b.Severity))),
+			b.Details["namespace"],
+			boldStyle.Render(b.Details["workload"]),
+			b.Details["value"],
+			b.Details["threshold"],
+		)
+	}
+}
+
+// PrintEventGroups prints grouped warning events, most recent first
+func PrintEventGroups(w io.Writer, groups []domain.EventGroup) {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, successStyle.Render(indicator(indicatorOK)+" No warning events"))
+		return
+	}
+
+	var total int32
+	for _, g := range groups {
+		total += g.Count
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Warning Events: %d groups, %d occurrences", len(groups), total)))
+	fmt.Fprintln(w)
+
+	now := time.Now()
+	for _, g := range groups {
+		style := warningStyle
+		icon := indicator(indicatorWarning)
+		if g.Severity == domain.SeverityCritical {
+			style = criticalStyle
+			icon = indicator(indicatorCritical)
+		}
+
+		kind, name, _ := strings.Cut(g.Object, "/")
+		fmt.Fprintf(w, "  %s %s %s %s\n",
+			style.Render(icon),
+			style.Render(g.Reason),
+			boldStyle.Render(kind+" "+g.Namespace+"/"+name),
+			mutedStyle.Render(fmt.Sprintf("×%d, last %s ago [%s]", g.Count, formatDuration(now.Sub(g.LastSeen)), g.Category)),
+		)
+		fmt.Fprintf(w, "    %s\n", wrapHanging(g.Message, 4, 4))
+	}
+	fmt.Fprintln(w)
+}
+
+// ScanFailure is a pod that a scan could not diagnose
+type ScanFailure struct {
+	Pod    string   // namespace/name
+	Reason string   // short cause, e.g. "RBAC denied" or "timeout"
+	Error  string   // full error message
+	Hints  []string // likely fixes for the cause
+}
+
+// PrintScanFailures prints the pods a scan could not diagnose, grouped by
+// reason, so an incomplete scan is not mistaken for a clean one
+func PrintScanFailures(w io.Writer, failures []ScanFailure, scanned int) {
+	if len(failures) == 0 {
+		return
+	}
+
+	var reasons []string
+	groups := make(map[string][]ScanFailure)
+	for _, f := range failures {
+		if _, ok := groups[f.Reason]; !ok {
+			reasons = append(reasons, f.Reason)
+		}
+		groups[f.Reason] = append(groups[f.Reason], f)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Failed to Diagnose: %d of %d pods (scan incomplete)", len(failures), scanned)))
+	for _, reason := range reasons {
+		group := groups[reason]
+		fmt.Fprintf(w, "  %s %s %s\n", criticalStyle.Render(indicator(indicatorCritical)),
+			criticalStyle.Render(reason), mutedStyle.Render(fmt.Sprintf("(%d pods)", len(group))))
+		for _, f := range group {
+			fmt.Fprintf(w, "    %s: %s\n", boldStyle.Render(f.Pod), wrapHanging(f.Error, 6+len(f.Pod), 6))
+		}
+		for _, hint := range group[0].Hints {
+			fmt.Fprintf(w, "    %s\n", infoStyle.Render("• "+hint))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintSkippedNamespaces prints the namespaces an all-namespaces scan left
+// out because the user may not list their pods
+func PrintSkippedNamespaces(w io.Writer, skipped []kubernetes.SkippedNamespace) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	names := make([]string, len(skipped))
+	for i, s := range skipped {
+		names[i] = s.Namespace
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Skipped %d namespaces due to permissions:", len(skipped))))
+	fmt.Fprintf(w, "  %s %s\n", warningStyle.Render(indicator(indicatorWarning)), wrapHanging("["+strings.Join(names, ", ")+"]", 4, 4))
+	fmt.Fprintf(w, "    %s\n", infoStyle.Render("• Check your access: pod-doctor check-access -n "+skipped[0].Namespace))
+	fmt.Fprintln(w)
+}
+
+// PrintAccessMatrix prints which permissions the current user has, with
+// what stops working for each one that is denied
+func PrintAccessMatrix(w io.Writer, results []kubernetes.AccessResult, scope string) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render("Access Check: "+scope))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, mutedStyle.Render(fmt.Sprintf("  %-8s %-6s %-42s %s", "ACCESS", "VERB", "RESOURCE", "USED FOR")))
+
+	var denied, requiredDenied int
+	for _, r := range results {
+		resource := r.Resource
+		if r.Group != "" {
+			resource += "." + r.Group
+		}
+		if r.Cluster {
+			resource += " (cluster)"
+		}
+
+		access := successStyle.Render(fmt.Sprintf("%-8s", indicator(indicatorOK)+" yes"))
+		usedFor := mutedStyle.Render(r.UsedFor)
+		if !r.Allowed {
+			denied++
+			style := warningStyle
+			if r.Required {
+				requiredDenied++
+				style = criticalStyle
+			}
+			access = style.Render(fmt.Sprintf("%-8s", indicator(indicatorCritical)+" no"))
+			usedFor = style.Render(r.UsedFor + " unavailable")
+		}
+		fmt.Fprintf(w, "  %s %-6s %-42s %s\n", access, r.Verb, resource, usedFor)
+	}
+
+	fmt.Fprintln(w)
+	switch {
+	case requiredDenied > 0:
+		fmt.Fprintln(w, criticalStyle.Render(fmt.Sprintf("%s %d permissions denied, including ones needed to diagnose pods at all", indicator(indicatorCritical), denied)))
+	case denied > 0:
+		fmt.Fprintln(w, warningStyle.Render(fmt.Sprintf("%s %d permissions denied; diagnoses will skip the checks listed above", indicator(indicatorWarning), denied)))
+	default:
+		fmt.Fprintln(w, successStyle.Render(indicator(indicatorOK)+" All permissions granted"))
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintHistory prints a pod's recorded diagnoses, oldest first
+func PrintHistory(w io.Writer, diagnoses []*domain.Diagnosis) {
+	if len(diagnoses) == 0 {
+		fmt.Fprintln(w, mutedStyle.Render("No recorded diagnoses"))
+		return
+	}
+
+	last := diagnoses[len(diagnoses)-1]
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("History: %s/%s (%d diagnoses)", last.Pod.Namespace, last.Pod.Name, len(diagnoses))))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, mutedStyle.Render(fmt.Sprintf("  %-19s  %-26s  %-5s  %-8s  %-8s  %s", "DIAGNOSED AT", "STATUS", "SCORE", "CRITICAL", "WARNINGS", "RESTARTS")))
+	for _, d := range diagnoses {
+		critical, warning, _ := d.IssueCount()
+		statusStyle := successStyle
+		switch {
+		case critical > 0:
+			statusStyle = criticalStyle
+		case !d.IsHealthy():
+			statusStyle = warningStyle
+		}
+		fmt.Fprintf(w, "  %-19s  %s  %s  %-8d  %-8d  %d\n",
+			d.DiagnosedAt.Local().Format("2006-01-02 15:04:05"),
+			statusStyle.Render(fmt.Sprintf("%-26s", d.Status)),
+			scoreStyle(d.HealthScore).Render(fmt.Sprintf("%-5d", d.HealthScore)),
+			critical,
+			warning,
+			d.Pod.Restarts,
+		)
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintDiff prints what changed between two diagnoses of a pod
+func PrintDiff(w io.Writer, diff history.Diff) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Changes: %s/%s", diff.Namespace, diff.Pod)))
+	fmt.Fprintln(w, mutedStyle.Render(fmt.Sprintf("%s → %s (%s apart)",
+		diff.From.Local().Format("2006-01-02 15:04:05"),
+		diff.To.Local().Format("2006-01-02 15:04:05"),
+		formatDuration(diff.To.Sub(diff.From)))))
+	fmt.Fprintln(w)
+
+	if diff.IsEmpty() {
+		fmt.Fprintln(w, successStyle.Render(indicator(indicatorOK)+" No changes"))
+		fmt.Fprintln(w)
+		return
+	}
+
+	if diff.StatusFrom != diff.StatusTo {
+		fmt.Fprintf(w, "Status: %s → %s\n", diff.StatusFrom, boldStyle.Render(string(diff.StatusTo)))
+	}
+	if diff.ScoreFrom != diff.ScoreTo {
+		fmt.Fprintf(w, "Health Score: %d → %s\n", diff.ScoreFrom, scoreStyle(diff.ScoreTo).Render(fmt.Sprintf("%d", diff.ScoreTo)))
+	}
+	if diff.NodeFrom != diff.NodeTo {
+		fmt.Fprintf(w, "Node: %s → %s\n", valueOrNA(diff.NodeFrom), valueOrNA(diff.NodeTo))
+	}
+	for _, r := range diff.Restarts {
+		change := fmt.Sprintf("+%d", r.Delta())
+		if r.Delta() < 0 {
+			change = "reset"
+		}
+		fmt.Fprintf(w, "Restarts %s: %d → %d %s\n", r.Container, r.From, r.To, warningStyle.Render("("+change+")"))
+	}
+
+	if len(diff.NewIssues) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("New Issues: %d", len(diff.NewIssues))))
+		fmt.Fprintln(w)
+		for _, issue := range diff.NewIssues {
+			printIssue(w, issue)
+		}
+	}
+	if len(diff.ResolvedIssues) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Resolved Issues: %d", len(diff.ResolvedIssues))))
+		for _, issue := range diff.ResolvedIssues {
+			fmt.Fprintf(w, "  %s %s\n", successStyle.Render(indicator(indicatorOK)), issue.Title)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintTimings prints how long each section of a diagnosis took, slowest
+// first
+func PrintTimings(w io.Writer, timings []domain.SectionTiming) {
+	if len(timings) == 0 {
+		return
+	}
+	sorted := append([]domain.SectionTiming(nil), timings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+
+	fmt.Fprintln(w, boldStyle.Render("Timings (slowest first):"))
+	for _, t := range sorted {
+		line := fmt.Sprintf("  %-16s %10s", t.Name, t.Duration.Round(time.Millisecond))
+		if t.TimedOut {
+			fmt.Fprintln(w, warningStyle.Render(line+"  timed out, skipped"))
+			continue
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintArticle prints a knowledge base article explaining an issue type
+func PrintArticle(w io.Writer, a kb.Article) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(a.ID+": "+a.Title))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, wrapHanging(a.Summary, 0, 0))
+
+	sections := []struct {
+		title    string
+		items    []string
+		numbered bool
+	}{
+		{"Common causes", a.Causes, false},
+		{"Debugging steps", a.Steps, true},
+		{"Links", a.Links, false},
+	}
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, boldStyle.Render(section.title+":"))
+		for i, item := range section.items {
+			if section.numbered {
+				fmt.Fprintf(w, "  %d. %s\n", i+1, wrapHanging(item, 5, 5))
+				continue
+			}
+			fmt.Fprintf(w, "  • %s\n", wrapHanging(item, 4, 4))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintArticleList prints the ID and title of every issue type
+func PrintArticleList(w io.Writer, articles []kb.Article) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerStyle.Render(fmt.Sprintf("Issue types (%d)", len(articles))))
+	fmt.Fprintln(w)
+	for _, a := range articles {
+		fmt.Fprintf(w, "  %-20s %s\n", a.ID, a.Title)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, mutedStyle.Render("Run pod-doctor explain <issue-id> for causes, debugging steps and links"))
+	fmt.Fprintln(w)
+}
+
 // PrintError prints an error message
-func PrintError(msg string) {
-	fmt.Println(criticalStyle.Render("Error: " + msg))
+func PrintError(w io.Writer, msg string) {
+	fmt.Fprintln(w, criticalStyle.Render("Error: "+msg))
+}
+
+// PrintWarning prints a warning message
+func PrintWarning(w io.Writer, msg string) {
+	fmt.Fprintln(w, warningStyle.Render(indicator(indicatorWarning)+" "+msg))
 }
 
 // PrintSuccess prints a success message
-func PrintSuccess(msg string) {
-	fmt.Println(successStyle.Render("✓ " + msg))
+func PrintSuccess(w io.Writer, msg string) {
+	fmt.Fprintln(w, successStyle.Render(indicator(indicatorOK)+" "+msg))
 }
 
 // PrintInfo prints an info message
-func PrintInfo(msg string) {
-	fmt.Println(infoStyle.Render(msg))
+func PrintInfo(w io.Writer, msg string) {
+	fmt.Fprintln(w, infoStyle.Render(msg))
 }
 
 // Spinner characters for loading animation
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// placeholderPattern matches the parts of a recommended command the user
+// has to fill in, e.g. <registry> or <image>:<tag>
+var placeholderPattern = regexp.MustCompile(`<[A-Za-z][\w.-]*>`)
+
+// fixScriptHeader starts a fix script: it asks before each command, and
+// runs them all without asking if YES=1 is set
+const fixScriptHeader = `#!/bin/sh
+# Remediation plan generated by pod-doctor %s on %s.
+#
+# Review every command before running this script. It asks before running
+# each one; set YES=1 to run them all without asking. Commands that still
+# contain placeholders such as <image> are commented out: edit them first.
+set -u
+
+confirm() {
+	[ "${YES:-}" = "1" ] && return 0
+	printf '\nRun: %%s\n[y/N] ' "$1" >&2
+	read -r answer </dev/tty || return 1
+	case "$answer" in
+	[yY]*) return 0 ;;
+	esac
+	return 1
+}
+`
+
+// WriteFixScript writes the recommended commands of diagnoses as a shell
+// script an operator can review and run outside pod-doctor. Pods come
+// worst health score first, each with its commands in recommendation
+// order; a command recommended for several pods, e.g. one changing their
+// shared workload, is included once. Recommendations without a command
+// are listed as comments. It returns how many commands the script runs.
+func WriteFixScript(w io.Writer, diagnoses []*domain.Diagnosis, toolVersion string) (int, error) {
+	ordered := make([]*domain.Diagnosis, 0, len(diagnoses))
+	for _, d := range diagnoses {
+		if !d.IsExpectedFailure() && len(d.Recommendations) > 0 {
+			ordered = append(ordered, d)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].HealthScore < ordered[j].HealthScore
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, fixScriptHeader, toolVersion, time.Now().Format(time.RFC3339))
+
+	seen := make(map[string]bool)
+	commands := 0
+	for _, d := range ordered {
+		fmt.Fprintf(&b, "\n# ==== %s/%s: %s (health score %d)\n", d.Pod.Namespace, d.Pod.Name, d.Status, d.HealthScore)
+		for _, issue := range d.Issues {
+			fmt.Fprintf(&b, "#   [%s] %s\n", issue.Severity, oneLine(issue.Title))
+		}
+
+		for _, rec := range d.Recommendations {
+			fmt.Fprintf(&b, "\n# %s\n", oneLine(rec.Title))
+			if rec.Description != "" {
+				fmt.Fprintf(&b, "# %s\n", oneLine(rec.Description))
+			}
+			command := strings.TrimSpace(rec.Command)
+			switch {
+			case command == "":
+				b.WriteString("# (manual step, no command)\n")
+			case seen[command]:
+				b.WriteString("# (same command as above, not repeated)\n")
+			case placeholderPattern.MatchString(command):
+				b.WriteString("# Edit the placeholders, then uncomment:\n")
+				fmt.Fprintf(&b, "# %s\n", command)
+			default:
+				seen[command] = true
+				commands++
+				fmt.Fprintf(&b, "if confirm %s; then\n\t%s\nfi\n", shellQuote(command), command)
+			}
+		}
+	}
+
+	if commands == 0 {
+		b.WriteString("\n# Nothing to run: no recommendation has a ready-to-run command.\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return commands, err
+}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// oneLine joins the lines of s, so it fits in a script comment
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,282 @@
+package output
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+)
+
+// htmlReport is the data rendered into the HTML report template
+type htmlReport struct {
+	Title            string
+	GeneratedAt      string
+	Total            int
+	Healthy          int
+	Unhealthy        int
+	ExpectedFailures int
+	AverageScore     int
+	Severities       []htmlBar
+	Statuses         []htmlBar
+	Dependencies     []domain.DependencyFailure
+	Namespaces       []htmlNamespace
+	Skipped          []kubernetes.SkippedNamespace
+}
+
+// htmlBar is one bar of a chart in the HTML report
+type htmlBar struct {
+	Label   string
+	Count   int
+	Percent int
+	Class   string
+}
+
+// htmlNamespace groups the diagnosed pods of one namespace
+type htmlNamespace struct {
+	Name      string
+	Unhealthy int
+	Pods      []*domain.Diagnosis
+}
+
+// WriteHTMLReport writes a standalone HTML report of scan results. The page
+// has no external assets so it can be attached to tickets as a single file.
+// Namespaces skipped for lack of permissions are listed at the end.
+func WriteHTMLReport(w io.Writer, title string, diagnoses []*domain.Diagnosis, dependencies []domain.DependencyFailure, skipped []kubernetes.SkippedNamespace) error {
+	report := htmlReport{
+		Title:        title,
+		GeneratedAt:  time.Now().Format("2006-01-02 15:04:05 MST"),
+		Total:        len(diagnoses),
+		Dependencies: dependencies,
+		Skipped:      skipped,
+	}
+
+	var critical, warning, info, scoreTotal int
+	statusCounts := make(map[domain.PodStatus]int)
+	byNamespace := make(map[string]*htmlNamespace)
+
+	for _, d := range diagnoses {
+		switch {
+		case d.IsHealthy():
+			report.Healthy++
+		case d.IsExpectedFailure():
+			report.ExpectedFailures++
+		default:
+			report.Unhealthy++
+		}
+		c, wn, i := d.IssueCount()
+		critical += c
+		warning += wn
+		info += i
+		scoreTotal += d.HealthScore
+		statusCounts[d.Status]++
+
+		ns, ok := byNamespace[d.Pod.Namespace]
+		if !ok {
+			ns = &htmlNamespace{Name: d.Pod.Namespace}
+			byNamespace[d.Pod.Namespace] = ns
+		}
+		ns.Pods = append(ns.Pods, d)
+		if !d.IsHealthy() && !d.IsExpectedFailure() {
+			ns.Unhealthy++
+		}
+	}
+	if len(diagnoses) > 0 {
+		report.AverageScore = scoreTotal / len(diagnoses)
+	}
+
+	report.Severities = bars([]htmlBar{
+		{Label: "Critical", Count: critical, Class: "critical"},
+		{Label: "Warning", Count: warning, Class: "warning"},
+		{Label: "Info", Count: info, Class: "info"},
+	})
+
+	var statuses []htmlBar
+	for status, count := range statusCounts {
+		class := "warning"
+		if status == domain.StatusHealthy {
+			class = "healthy"
+		}
+		statuses = append(statuses, htmlBar{Label: string(status), Count: count, Class: class})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Count != statuses[j].Count {
+			return statuses[i].Count > statuses[j].Count
+		}
+		return statuses[i].Label < statuses[j].Label
+	})
+	report.Statuses = bars(statuses)
+
+	// Namespaces and pods are listed worst first
+	for _, ns := range byNamespace {
+		sort.SliceStable(ns.Pods, func(i, j int) bool {
+			return ns.Pods[i].HealthScore < ns.Pods[j].HealthScore
+		})
+		report.Namespaces = append(report.Namespaces, *ns)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		if report.Namespaces[i].Unhealthy != report.Namespaces[j].Unhealthy {
+			return report.Namespaces[i].Unhealthy > report.Namespaces[j].Unhealthy
+		}
+		return report.Namespaces[i].Name < report.Namespaces[j].Name
+	})
+
+	return htmlTemplate.Execute(w, report)
+}
+
+// bars sets each bar's width relative to the largest count
+func bars(items []htmlBar) []htmlBar {
+	maxCount := 0
+	for _, b := range items {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+	for i := range items {
+		if maxCount > 0 {
+			items[i].Percent = items[i].Count * 100 / maxCount
+		}
+	}
+	return items
+}
+
+// scoreClass returns the CSS class used to color a health score
+func scoreClass(score int) string {
+	switch {
+	case score >= 80:
+		return "healthy"
+	case score >= 50:
+		return "warning"
+	default:
+		return "critical"
+	}
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"scoreClass":     scoreClass,
+	"formatDuration": formatDuration,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+  h1 { margin-bottom: 0.2rem; }
+  h2 { border-bottom: 1px solid #d0d7de; padding-bottom: 0.3rem; margin-top: 2rem; }
+  .muted { color: #656d76; }
+  .summary { display: flex; gap: 1rem; flex-wrap: wrap; margin: 1rem 0; }
+  .card { border: 1px solid #d0d7de; border-radius: 6px; padding: 0.8rem 1.2rem; min-width: 8rem; }
+  .card .value { font-size: 1.8rem; font-weight: 600; }
+  .charts { display: flex; gap: 3rem; flex-wrap: wrap; }
+  .chart { min-width: 22rem; }
+  .bar-row { display: flex; align-items: center; margin: 0.3rem 0; }
+  .bar-label { width: 9rem; }
+  .bar { height: 1rem; border-radius: 3px; margin-right: 0.5rem; min-width: 2px; }
+  .critical { color: #cf222e; } .bar.critical { background: #cf222e; }
+  .warning { color: #bc4c00; } .bar.warning { background: #fb8f44; }
+  .info { color: #0969da; } .bar.info { background: #54aeff; }
+  .healthy { color: #1a7f37; } .bar.healthy { background: #4ac26b; }
+  table { border-collapse: collapse; width: 100%; margin: 0.5rem 0; }
+  th, td { text-align: left; padding: 0.35rem 0.6rem; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+  details { border: 1px solid #d0d7de; border-radius: 6px; margin: 0.5rem 0; padding: 0.5rem 1rem; }
+  summary { cursor: pointer; font-weight: 600; }
+  code { background: #f6f8fa; padding: 0.1rem 0.3rem; border-radius: 4px; }
+  .details-kv { color: #656d76; font-size: 0.9rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="muted">Generated {{.GeneratedAt}} by pod-doctor</div>
+
+<div class="summary">
+  <div class="card"><div class="muted">Pods scanned</div><div class="value">{{.Total}}</div></div>
+  <div class="card"><div class="muted">Healthy</div><div class="value healthy">{{.Healthy}}</div></div>
+  <div class="card"><div class="muted">Unhealthy</div><div class="value critical">{{.Unhealthy}}</div></div>
+  {{if .ExpectedFailures}}<div class="card"><div class="muted">Expected failures</div><div class="value info">{{.ExpectedFailures}}</div></div>{{end}}
+  <div class="card"><div class="muted">Average health score</div><div class="value {{scoreClass .AverageScore}}">{{.AverageScore}}/100</div></div>
+</div>
+
+<div class="charts">
+  <div class="chart">
+    <h3>Issues by severity</h3>
+    {{range .Severities}}<div class="bar-row"><span class="bar-label">{{.Label}}</span><span class="bar {{.Class}}" style="width: {{.Percent}}%"></span>{{.Count}}</div>
+    {{end}}
+  </div>
+  <div class="chart">
+    <h3>Pods by status</h3>
+    {{range .Statuses}}<div class="bar-row"><span class="bar-label">{{.Label}}</span><span class="bar {{.Class}}" style="width: {{.Percent}}%"></span>{{.Count}}</div>
+    {{end}}
+  </div>
+</div>
+
+{{if .Dependencies}}
+<h2>Failing Dependencies</h2>
+<table>
+  <tr><th>Namespace</th><th>Target</th><th>Affected pods</th></tr>
+  {{range .Dependencies}}<tr><td>{{.Namespace}}</td><td><code>{{.Target}}</code></td><td>{{range $i, $p := .Pods}}{{if $i}}, {{end}}{{$p}}{{end}}</td></tr>
+  {{end}}
+</table>
+{{end}}
+
+{{range .Namespaces}}
+<h2>Namespace {{.Name}} <span class="muted">({{len .Pods}} pods, {{.Unhealthy}} unhealthy)</span></h2>
+{{range .Pods}}
+<details{{if not .IsHealthy}} open{{end}}>
+  <summary>{{.Pod.Name}} &mdash; <span class="{{scoreClass .HealthScore}}">{{.Status}}, score {{.HealthScore}}/100</span></summary>
+  <p class="muted">Node: {{or .Pod.Node "N/A"}} | Phase: {{.Pod.Phase}} | Age: {{formatDuration .Pod.Age}} | Restarts: {{.Pod.Restarts}}{{if .Workload}} | Workload: {{.Workload.Ref}}{{end}}</p>
+  {{if .ExpectedFailure}}<p class="info">Expected failure ({{.ExpectedFailure}})</p>{{end}}
+  {{range .Warnings}}<p class="muted">Incomplete: {{.Message}}</p>
+  {{end}}
+  {{if .NotReady}}
+  <h4>Why not ready</h4>
+  <ul>
+    {{range .NotReady}}<li><strong>{{if eq .Kind "readinessGate"}}readiness gate{{else}}container{{end}} {{.Name}}</strong>: {{.Reason}}{{if .Message}}<br><span class="muted">{{.Message}}</span>{{end}}</li>
+    {{end}}
+  </ul>
+  {{end}}
+  {{if .Issues}}
+  <table>
+    <tr><th>Severity</th><th>Issue</th><th>Details</th></tr>
+    {{range .Issues}}<tr>
+      <td class="{{.Severity}}">{{.Severity}}</td>
+      <td><strong>{{.Title}}</strong><br>{{.Description}}</td>
+      <td class="details-kv">{{range $k, $v := .Details}}{{$k}}: {{$v}}<br>{{end}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{else}}
+  <p class="healthy">No issues detected</p>
+  {{end}}
+  {{if .Recommendations}}
+  <h4>Recommendations</h4>
+  <ol>
+    {{range .Recommendations}}<li><strong>{{.Title}}</strong> &mdash; {{.Description}}{{if .Command}}<br><code>{{.Command}}</code>{{end}}</li>
+    {{end}}
+  </ol>
+  {{end}}
+  {{if .Events}}
+  <h4>Events</h4>
+  <table>
+    <tr><th>Type</th><th>Reason</th><th>Message</th><th>Count</th></tr>
+    {{range .Events}}<tr><td>{{.Type}}</td><td>{{.Reason}}</td><td>{{.Message}}</td><td>{{.Count}}</td></tr>
+    {{end}}
+  </table>
+  {{end}}
+</details>
+{{end}}
+{{end}}
+
+{{if .Skipped}}
+<h2>Skipped namespaces <span class="muted">({{len .Skipped}}, due to permissions)</span></h2>
+<table>
+  <tr><th>Namespace</th><th>Reason</th></tr>
+  {{range .Skipped}}<tr><td>{{.Namespace}}</td><td>{{.Reason}}</td></tr>
+  {{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,45 @@
+package output
+
+// Indicator kinds used to prefix statuses and issues
+const (
+	indicatorOK       = "ok"
+	indicatorCritical = "critical"
+	indicatorWarning  = "warning"
+	indicatorInfo     = "info"
+	indicatorUnknown  = "unknown"
+)
+
+var (
+	// symbolIndicators are distinct shapes, so states differ by more than color
+	symbolIndicators = map[string]string{
+		indicatorOK:       "✓",
+		indicatorCritical: "✗",
+		indicatorWarning:  "!",
+		indicatorInfo:     "•",
+		indicatorUnknown:  "?",
+	}
+
+	// textIndicators spell out the state for screen readers and colorblind users
+	textIndicators = map[string]string{
+		indicatorOK:       "[OK]",
+		indicatorCritical: "[CRIT]",
+		indicatorWarning:  "[WARN]",
+		indicatorInfo:     "[INFO]",
+		indicatorUnknown:  "[UNKNOWN]",
+	}
+
+	useTextIndicators bool
+)
+
+// SetTextIndicators switches status icons to text labels like [OK] and [CRIT]
+func SetTextIndicators(enabled bool) {
+	useTextIndicators = enabled
+}
+
+// indicator returns the icon or text label for the given kind
+func indicator(kind string) string {
+	if useTextIndicators {
+		return textIndicators[kind]
+	}
+	return symbolIndicators[kind]
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// progressWidth is the number of cells in the progress bar
+const progressWidth = 30
+
+// Progress draws a single-line progress bar of completed out of queued
+// items, redrawn in place. It is safe for concurrent use. The total may grow
+// while items complete, e.g. while a pod list is still being paged.
+type Progress struct {
+	w     io.Writer
+	label string
+
+	mu    sync.Mutex
+	total int
+	done  int
+}
+
+// NewProgress creates a progress bar writing to w, usually a terminal's
+// stderr, counting items named by label, e.g. "pods"
+func NewProgress(w io.Writer, label string) *Progress {
+	return &Progress{w: w, label: label}
+}
+
+// Add queues n more items
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total += n
+	p.draw()
+}
+
+// Done marks one item complete
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.draw()
+}
+
+// Finish clears the progress bar so regular output can follow
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "\r%s\r", strings.Repeat(" ", progressWidth+40))
+}
+
+// draw redraws the bar; the caller holds mu
+func (p *Progress) draw() {
+	filled := 0
+	if p.total > 0 {
+		filled = p.done * progressWidth / p.total
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressWidth-filled)
+	fmt.Fprintf(p.w, "\r%s %s", infoStyle.Render(bar), mutedStyle.Render(fmt.Sprintf("%d/%d %s", p.done, p.total, p.label)))
+}
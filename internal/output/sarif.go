@@ -0,0 +1,299 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifToolURI = "https://github.com/pavanInnamuri/pod-doctor"
+)
+
+// sarifLog is the top-level SARIF 2.1.0 document
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifProperties    `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	Properties          map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+// WriteSARIF writes diagnoses as a SARIF 2.1.0 log for code scanning and
+// policy tools. Each distinct issue kind becomes a rule and each issue a
+// result located at its pod. Pods have no source file, so the physical
+// location is the pod's API path, which code scanning requires to accept
+// a result. Warnings, e.g. namespaces the scan could not read, become tool
+// execution notifications.
+func WriteSARIF(w io.Writer, diagnoses []*domain.Diagnosis, toolVersion string, warnings ...string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "pod-doctor",
+			Version:        toolVersion,
+			InformationURI: sarifToolURI,
+			Rules:          []sarifRule{},
+		}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+		Results:     []sarifResult{},
+	}
+	for _, warning := range warnings {
+		run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications,
+			sarifNotification{Level: "warning", Message: sarifMessage{Text: warning}})
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, d := range diagnoses {
+		for _, issue := range d.Issues {
+			ruleTitle := sarifRuleTitle(d, issue)
+			ruleID := sarifRuleID(issue.Category, ruleTitle)
+			index, ok := ruleIndex[ruleID]
+			if !ok {
+				index = len(run.Tool.Driver.Rules)
+				ruleIndex[ruleID] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+					ID:                   ruleID,
+					Name:                 ruleID[strings.LastIndex(ruleID, "/")+1:],
+					ShortDescription:     sarifMessage{Text: ruleTitle},
+					DefaultConfiguration: sarifConfiguration{Level: sarifLevel(issue.Severity)},
+					Properties:           sarifProperties{Tags: []string{issue.Category}},
+				})
+			}
+			run.Results = append(run.Results, sarifIssueResult(d, issue, ruleID, index))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifIssueResult builds the result reported for one issue of a pod
+func sarifIssueResult(d *domain.Diagnosis, issue domain.Issue, ruleID string, ruleIndex int) sarifResult {
+	podPath := "namespaces/" + d.Pod.Namespace + "/pods/" + d.Pod.Name
+	logical := []sarifLogicalLocation{{
+		Name:               d.Pod.Name,
+		FullyQualifiedName: d.Pod.Namespace + "/" + d.Pod.Name,
+		Kind:               "pod",
+	}}
+	if d.Workload != nil {
+		logical = append(logical, sarifLogicalLocation{
+			Name:               d.Workload.Name,
+			FullyQualifiedName: d.Pod.Namespace + "/" + d.Workload.Ref(),
+			Kind:               strings.ToLower(d.Workload.Kind),
+		})
+	}
+	for _, obj := range issue.Objects {
+		if obj.Kind == "Pod" {
+			continue
+		}
+		location := sarifLogicalLocation{
+			Name:               obj.Name,
+			FullyQualifiedName: obj.Name,
+			Kind:               strings.ToLower(obj.Kind),
+		}
+		if obj.Namespace != "" {
+			location.FullyQualifiedName = obj.Namespace + "/" + location.Kind + "/" + obj.Name
+		}
+		if !containsLogicalLocation(logical, location) {
+			logical = append(logical, location)
+		}
+	}
+
+	// Fingerprint on the workload when known, so a result keeps its
+	// identity across runs even as pods are replaced
+	owner := d.Pod.Namespace + "/" + d.Pod.Name
+	if d.Workload != nil {
+		owner = d.Pod.Namespace + "/" + d.Workload.Ref()
+	}
+	sum := sha256.Sum256([]byte(ruleID + "|" + owner + "|" + issue.Title))
+
+	result := sarifResult{
+		RuleID:    ruleID,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(issue.Severity),
+		Message:   sarifMessage{Text: issue.Title + ": " + issue.Description},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: podPath}},
+			LogicalLocations: logical,
+		}},
+		PartialFingerprints: map[string]string{"podDoctorIssue/v1": hex.EncodeToString(sum[:16])},
+		Properties:          issue.Details,
+	}
+	if d.IsExpectedFailure() {
+		result.Suppressions = []sarifSuppression{{
+			Kind:          "external",
+			Justification: "expected failure: " + d.ExpectedFailure,
+		}}
+	}
+	return result
+}
+
+// sarifLevel maps an issue severity to a SARIF result level
+func sarifLevel(severity domain.Severity) string {
+	switch severity {
+	case domain.SeverityCritical:
+		return "error"
+	case domain.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+var (
+	nonSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+	// trailingWordPattern matches a preposition left dangling once a name
+	// is removed, as in "Memory near limit for"
+	trailingWordPattern = regexp.MustCompile(`\s+(for|of|on|in|to|by|with)[:]?$`)
+)
+
+// sarifRuleTitle returns an issue's title without the names it embeds, such
+// as the container or volume, so the same problem in different pods maps to
+// one rule, e.g. "Container in CrashLoopBackOff"
+func sarifRuleTitle(d *domain.Diagnosis, issue domain.Issue) string {
+	names := make([]string, 0, len(issue.Details)+len(d.Pod.Containers)+2)
+	for _, v := range issue.Details {
+		names = append(names, v)
+	}
+	for _, c := range d.Pod.Containers {
+		names = append(names, c.Name)
+	}
+	if d.Workload != nil {
+		names = append(names, d.Workload.Ref(), d.Workload.Name)
+	}
+	// Remove longer names first so a name containing another is removed whole
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	title := issue.Title
+	for _, name := range names {
+		if len(name) < 2 {
+			continue
+		}
+		title = regexp.MustCompile(`(^|[^\w-])`+regexp.QuoteMeta(name)+`($|[^\w-])`).ReplaceAllString(title, "$1$2")
+	}
+	title = strings.Join(strings.Fields(strings.ReplaceAll(title, "[]", "")), " ")
+	title = trailingWordPattern.ReplaceAllString(title, "")
+	if title == "" {
+		return issue.Title
+	}
+	return title
+}
+
+// sarifRuleID turns a rule title into an id such as container/container-in-crashloopbackoff
+func sarifRuleID(category, title string) string {
+	slug := strings.Trim(nonSlugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if slug == "" {
+		slug = "issue"
+	}
+	if category == "" {
+		category = "general"
+	}
+	return category + "/" + slug
+}
+
+// containsLogicalLocation reports whether a location is already listed
+func containsLogicalLocation(locations []sarifLogicalLocation, location sarifLogicalLocation) bool {
+	for _, l := range locations {
+		if l.Kind == location.Kind && l.FullyQualifiedName == location.FullyQualifiedName {
+			return true
+		}
+	}
+	return false
+}
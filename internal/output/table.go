@@ -0,0 +1,157 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// topIssueWidth is how much of the top issue's title the scan table shows,
+// unless wide output is on
+const topIssueWidth = 50
+
+// ScanSortColumns are the columns the scan table can be sorted by. Text
+// columns sort ascending; score puts the worst pods first, and the counts
+// and age the highest first.
+var ScanSortColumns = []string{"score", "name", "namespace", "status", "restarts", "critical", "warnings", "age"}
+
+// ScanTable configures the table of unhealthy pods in a scan summary
+type ScanTable struct {
+	SortBy string // one of ScanSortColumns; score if empty
+	Limit  int    // rows to show, 0 for all
+	Wide   bool   // add node and age columns
+}
+
+// IsScanSortColumn reports whether the scan table can be sorted by a column
+func IsScanSortColumn(name string) bool {
+	for _, column := range ScanSortColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// printScanTable prints unhealthy pods as a table with their issue counts
+// and top issue, sorted and limited as configured
+func printScanTable(w io.Writer, diagnoses []*domain.Diagnosis, t ScanTable) {
+	rows := append([]*domain.Diagnosis(nil), diagnoses...)
+	sortScanRows(rows, t.SortBy)
+	hidden := 0
+	if t.Limit > 0 && len(rows) > t.Limit {
+		hidden = len(rows) - t.Limit
+		rows = rows[:t.Limit]
+	}
+
+	headers := []string{"POD", "NAMESPACE", "STATUS", "RESTARTS", "CRITICAL", "WARNINGS", "SCORE", "TOP ISSUE"}
+	if t.Wide {
+		headers = append(headers, "NODE", "AGE")
+	}
+
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(mutedStyle).
+		Headers(headers...)
+	for _, d := range rows {
+		critical, warning, _ := d.IssueCount()
+		statusStyle := warningStyle
+		if critical > 0 {
+			statusStyle = criticalStyle
+		}
+		row := []string{
+			d.Pod.Name,
+			d.Pod.Namespace,
+			statusStyle.Render(string(d.Status)),
+			fmt.Sprintf("%d", d.Pod.Restarts),
+			fmt.Sprintf("%d", critical),
+			fmt.Sprintf("%d", warning),
+			scoreStyle(d.HealthScore).Render(fmt.Sprintf("%d", d.HealthScore)),
+			topIssue(d, t.Wide),
+		}
+		if t.Wide {
+			row = append(row, valueOrNA(d.Pod.Node), formatDuration(d.Pod.Age))
+		}
+		tbl.Row(row...)
+	}
+	tbl.StyleFunc(func(row, col int) lipgloss.Style {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if row == table.HeaderRow {
+			return style.Bold(true)
+		}
+		return style
+	})
+
+	fmt.Fprintln(w, tbl.Render())
+	if hidden > 0 {
+		fmt.Fprintln(w, mutedStyle.Render(fmt.Sprintf("... and %d more unhealthy pods (raise --limit to show them)", hidden)))
+	}
+}
+
+// sortScanRows sorts diagnoses by a scan table column, then by namespace
+// and name
+func sortScanRows(diagnoses []*domain.Diagnosis, by string) {
+	key := func(d *domain.Diagnosis) string {
+		return d.Pod.Namespace + "/" + d.Pod.Name
+	}
+	sort.SliceStable(diagnoses, func(i, j int) bool {
+		a, b := diagnoses[i], diagnoses[j]
+		aCritical, aWarning, _ := a.IssueCount()
+		bCritical, bWarning, _ := b.IssueCount()
+		switch by {
+		case "name":
+			if a.Pod.Name != b.Pod.Name {
+				return a.Pod.Name < b.Pod.Name
+			}
+		case "status":
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+		case "restarts":
+			if a.Pod.Restarts != b.Pod.Restarts {
+				return a.Pod.Restarts > b.Pod.Restarts
+			}
+		case "critical":
+			if aCritical != bCritical {
+				return aCritical > bCritical
+			}
+		case "warnings":
+			if aWarning != bWarning {
+				return aWarning > bWarning
+			}
+		case "age":
+			if a.Pod.Age != b.Pod.Age {
+				return a.Pod.Age > b.Pod.Age
+			}
+		case "namespace":
+		default:
+			if a.HealthScore != b.HealthScore {
+				return a.HealthScore < b.HealthScore
+			}
+		}
+		return key(a) < key(b)
+	})
+}
+
+// topIssue returns the title of a diagnosis' most severe issue, the first
+// one of that severity, cut short unless wide output is on
+func topIssue(d *domain.Diagnosis, wide bool) string {
+	var top *domain.Issue
+	for i := range d.Issues {
+		if top == nil || d.Issues[i].Severity.Rank() < top.Severity.Rank() {
+			top = &d.Issues[i]
+		}
+	}
+	if top == nil {
+		return "-"
+	}
+	title := strings.Join(strings.Fields(top.Title), " ")
+	if wide {
+		return title
+	}
+	return truncate(title, topIssueWidth)
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// defaultWidth is used when the terminal width cannot be detected
+const defaultWidth = 100
+
+// wide disables wrapping so long lines are printed as-is
+var wide bool
+
+// SetWide enables or disables wide output. In wide mode long descriptions
+// and details are printed on a single line instead of being wrapped.
+func SetWide(enabled bool) {
+	wide = enabled
+}
+
+// terminalWidth returns the width of stdout, or defaultWidth if stdout is
+// not a terminal
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	return defaultWidth
+}
+
+// wrapHanging wraps text whose first line is printed starting at column
+// start. Continuation lines are indented by indent columns.
+func wrapHanging(text string, start, indent int) string {
+	if wide {
+		return text
+	}
+	width := terminalWidth()
+	return wrapText(text, width-start, width-indent, strings.Repeat(" ", indent))
+}
+
+// wrapText word-wraps text so the first line fits in firstWidth columns and
+// the following lines fit in width columns, prefixing continuation lines
+// with indent. Words longer than a line are split.
+func wrapText(text string, firstWidth, width int, indent string) string {
+	const minWidth = 20
+	if width < minWidth {
+		width = minWidth
+	}
+	if firstWidth < minWidth {
+		firstWidth = minWidth
+	}
+
+	var b strings.Builder
+	lineWidth := firstWidth
+	lineLen := 0
+
+	newline := func() {
+		b.WriteString("\n" + indent)
+		lineWidth = width
+		lineLen = 0
+	}
+
+	for i, paragraph := range strings.Split(text, "\n") {
+		if i > 0 {
+			newline()
+		}
+
+		for _, word := range strings.Fields(paragraph) {
+			r := []rune(word)
+			if lineLen > 0 && lineLen+1+len(r) > lineWidth {
+				newline()
+			}
+			if lineLen > 0 {
+				b.WriteString(" ")
+				lineLen++
+			}
+
+			// Split words that cannot fit on a line of their own
+			for len(r) > lineWidth-lineLen {
+				n := lineWidth - lineLen
+				b.WriteString(string(r[:n]))
+				r = r[n:]
+				newline()
+			}
+			b.WriteString(string(r))
+			lineLen += len(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,273 @@
+package provenance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// cosignSignatureAnnotation holds the base64 signature on a cosign signature layer
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// ErrUnsigned is returned when no cosign signature exists for an image
+var ErrUnsigned = errors.New("no cosign signature found")
+
+// ErrInvalidSignature is returned when signatures exist but none verifies
+var ErrInvalidSignature = errors.New("signature does not verify against the policy key")
+
+// digestPattern extracts the digest from a container status imageID such as
+// docker-pullable://ghcr.io/acme/api@sha256:abc...
+var digestPattern = regexp.MustCompile(`^(?:[a-z-]+://)?(.+)@(sha256:[a-f0-9]{64})$`)
+
+// Verifier checks cosign signatures by reading them from the image's
+// registry. Results are cached per digest and key.
+type Verifier struct {
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]error
+}
+
+// NewVerifier creates a signature verifier
+func NewVerifier() *Verifier {
+	return &Verifier{
+		httpClient: &http.Client{},
+		cache:      make(map[string]error),
+	}
+}
+
+// ParseImageID splits a container status imageID into the repository and digest
+func ParseImageID(imageID string) (repository, digest string, ok bool) {
+	m := digestPattern.FindStringSubmatch(imageID)
+	if m == nil {
+		return "", "", false
+	}
+	return normalizeImage(m[1]), m[2], true
+}
+
+// Verify checks that repository@digest carries a cosign signature made with
+// the policy's key. It returns ErrUnsigned, ErrInvalidSignature, or another
+// error when the registry cannot be queried.
+func (v *Verifier) Verify(ctx context.Context, policy *Policy, repository, digest string) error {
+	cacheKey := policy.Name + "|" + repository + "@" + digest
+
+	v.mu.Lock()
+	if err, ok := v.cache[cacheKey]; ok {
+		v.mu.Unlock()
+		return err
+	}
+	v.mu.Unlock()
+
+	err := v.verify(ctx, policy.publicKey.(*ecdsa.PublicKey), repository, digest)
+
+	v.mu.Lock()
+	v.cache[cacheKey] = err
+	v.mu.Unlock()
+	return err
+}
+
+// verify fetches the signature manifest stored at the sha256-<hex>.sig tag
+// and checks each signature layer's payload and signature
+func (v *Verifier) verify(ctx context.Context, key *ecdsa.PublicKey, repository, digest string) error {
+	host, repo, _ := strings.Cut(repository, "/")
+	reg := &registryClient{http: v.httpClient, host: registryHost(host), repo: repo}
+
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	var manifest struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}
+	status, err := reg.get(ctx, "manifests/"+sigTag, "application/vnd.oci.image.manifest.v1+json, application/vnd.docker.distribution.manifest.v2+json", &manifest)
+	if status == http.StatusNotFound {
+		return ErrUnsigned
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range manifest.Layers {
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+
+		payload, err := reg.blob(ctx, layer.Digest)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(payload)
+		if "sha256:"+hex.EncodeToString(sum[:]) != layer.Digest {
+			continue
+		}
+
+		// The signed payload must name the image being verified, otherwise
+		// a signature could be replayed from another image
+		var simpleSigning struct {
+			Critical struct {
+				Image struct {
+					DockerManifestDigest string `json:"docker-manifest-digest"`
+				} `json:"image"`
+			} `json:"critical"`
+		}
+		if err := json.Unmarshal(payload, &simpleSigning); err != nil || simpleSigning.Critical.Image.DockerManifestDigest != digest {
+			continue
+		}
+
+		if ecdsa.VerifyASN1(key, sum[:], sig) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+// registryClient does anonymous reads against an OCI distribution registry
+type registryClient struct {
+	http  *http.Client
+	host  string
+	repo  string
+	token string
+}
+
+// get fetches a registry path and decodes the JSON response into out
+func (r *registryClient) get(ctx context.Context, path, accept string, out interface{}) (int, error) {
+	body, status, err := r.do(ctx, path, accept)
+	if err != nil {
+		return status, err
+	}
+	defer body.Close()
+	return status, json.NewDecoder(body).Decode(out)
+}
+
+// blob fetches a blob by digest
+func (r *registryClient) blob(ctx context.Context, digest string) ([]byte, error) {
+	body, _, err := r.do(ctx, "blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(io.LimitReader(body, 1<<20))
+}
+
+// do performs a GET, fetching an anonymous bearer token if the registry asks for one
+func (r *registryClient) do(ctx context.Context, path, accept string) (io.ReadCloser, int, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/v2/%s/%s", r.host, r.repo, path), nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if r.token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.token)
+		}
+
+		resp, err := r.http.Do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && r.token == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			if err := r.authenticate(ctx, challenge); err != nil {
+				return nil, http.StatusUnauthorized, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, resp.StatusCode, fmt.Errorf("registry %s returned %s", r.host, resp.Status)
+		}
+		return resp.Body, resp.StatusCode, nil
+	}
+	return nil, http.StatusUnauthorized, fmt.Errorf("registry %s requires credentials", r.host)
+}
+
+// authenticate requests an anonymous pull token for the repository
+func (r *registryClient) authenticate(ctx context.Context, challenge string) error {
+	params := parseChallenge(challenge)
+	realm := params["realm"]
+	if realm == "" {
+		return fmt.Errorf("registry %s requires credentials", r.host)
+	}
+
+	query := url.Values{}
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", "repository:"+r.repo+":pull")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry %s denied anonymous access: %s", r.host, resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return err
+	}
+	r.token = token.Token
+	if r.token == "" {
+		r.token = token.AccessToken
+	}
+	return nil
+}
+
+// parseChallenge parses a `Bearer realm="...",service="..."` header
+func parseChallenge(header string) map[string]string {
+	params := make(map[string]string)
+	_, rest, _ := strings.Cut(header, " ")
+	for _, part := range strings.Split(rest, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[k] = strings.Trim(v, `"`)
+		}
+	}
+	return params
+}
+
+// registryHost maps Docker Hub's image host to its registry API host
+func registryHost(host string) string {
+	if host == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return host
+}
+
+// normalizeImage expands short Docker Hub references, e.g. nginx becomes
+// docker.io/library/nginx
+func normalizeImage(image string) string {
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	if !found {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + first + "/" + rest
+}
@@ -0,0 +1,139 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TrustPolicy lists which registries are trusted and which keys sign their
+// images, per namespace
+type TrustPolicy struct {
+	Policies []Policy `yaml:"policies"`
+}
+
+// Policy trusts images from a set of registries for a set of namespaces
+type Policy struct {
+	Name string `yaml:"name"`
+	// Namespaces the policy applies to; empty means all namespaces
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// Enforce reports violations as critical instead of warnings
+	Enforce bool `yaml:"enforce,omitempty"`
+	// Registries are image prefixes such as ghcr.io/acme or registry.acme.io
+	Registries []string `yaml:"registries"`
+	// Key is a cosign public key, either a PEM file path or inline PEM.
+	// Without a key only the registry is checked.
+	Key string `yaml:"key,omitempty"`
+
+	publicKey crypto.PublicKey
+}
+
+// LoadPolicy reads and validates a trust policy file
+func LoadPolicy(path string) (*TrustPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust policy %s: %w", path, err)
+	}
+
+	var policy TrustPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %w", path, err)
+	}
+	for i := range policy.Policies {
+		if err := policy.Policies[i].init(); err != nil {
+			return nil, fmt.Errorf("invalid trust policy %s: policies[%d]: %w", path, i, err)
+		}
+	}
+	return &policy, nil
+}
+
+// init validates the policy and parses its public key
+func (p *Policy) init() error {
+	if len(p.Registries) == 0 {
+		return fmt.Errorf("at least one registry is required")
+	}
+	if p.Key == "" {
+		return nil
+	}
+
+	data := []byte(p.Key)
+	if !strings.Contains(p.Key, "-----BEGIN") {
+		var err error
+		if data, err = os.ReadFile(p.Key); err != nil {
+			return fmt.Errorf("failed to read key: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse key: %w", err)
+	}
+	if _, ok := key.(*ecdsa.PublicKey); !ok {
+		return fmt.Errorf("unsupported key type %T, only ECDSA cosign keys are supported", key)
+	}
+	p.publicKey = key
+	return nil
+}
+
+// Applies reports whether the policy covers a namespace
+func (p *Policy) Applies(namespace string) bool {
+	if len(p.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range p.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Trusts reports whether an image reference comes from one of the policy's registries
+func (p *Policy) Trusts(image string) bool {
+	image = normalizeImage(image)
+	for _, registry := range p.Registries {
+		prefix := strings.TrimSuffix(normalizeRegistry(registry), "/")
+		if image == prefix || strings.HasPrefix(image, prefix+"/") || strings.HasPrefix(image, prefix+":") || strings.HasPrefix(image, prefix+"@") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRegistry expands a registry entry of a policy like an image
+// reference, except that an entry starting with a host, such as ghcr.io,
+// registry.local:5000 or localhost, is a host prefix rather than an image
+// on Docker Hub
+func normalizeRegistry(registry string) string {
+	first, _, _ := strings.Cut(registry, "/")
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return registry
+	}
+	return normalizeImage(registry)
+}
+
+// HasKey reports whether images trusted by the policy must be signed
+func (p *Policy) HasKey() bool {
+	return p.publicKey != nil
+}
+
+// ForNamespace returns the policies that apply to a namespace
+func (t *TrustPolicy) ForNamespace(namespace string) []*Policy {
+	var policies []*Policy
+	for i := range t.Policies {
+		if t.Policies[i].Applies(namespace) {
+			policies = append(policies, &t.Policies[i])
+		}
+	}
+	return policies
+}
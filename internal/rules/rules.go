@@ -0,0 +1,254 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// fetchTimeout bounds downloading a rule pack from a URL
+const fetchTimeout = 10 * time.Second
+
+// Pack is a distributable bundle of analyzer rules
+type Pack struct {
+	Name            string           `yaml:"name" json:"name"`
+	Version         string           `yaml:"version,omitempty" json:"version,omitempty"`
+	Description     string           `yaml:"description,omitempty" json:"description,omitempty"`
+	LogPatterns     []LogPattern     `yaml:"logPatterns,omitempty" json:"logPatterns,omitempty"`
+	SeverityRemaps  []SeverityRemap  `yaml:"severityRemaps,omitempty" json:"severityRemaps,omitempty"`
+	Suppressions    []Match          `yaml:"suppressions,omitempty" json:"suppressions,omitempty"`
+	Recommendations []Recommendation `yaml:"recommendations,omitempty" json:"recommendations,omitempty"`
+}
+
+// LogPattern is a custom error pattern matched against container logs
+type LogPattern struct {
+	Pattern     string          `yaml:"pattern" json:"pattern"`
+	Title       string          `yaml:"title" json:"title"`
+	Description string          `yaml:"description,omitempty" json:"description,omitempty"`
+	Severity    domain.Severity `yaml:"severity" json:"severity"`
+	Category    string          `yaml:"category,omitempty" json:"category,omitempty"`
+}
+
+// Match selects issues. Empty fields match anything; Title matches as a
+// substring and Namespace as an exact name.
+type Match struct {
+	Title     string `yaml:"title,omitempty" json:"title,omitempty"`
+	Category  string `yaml:"category,omitempty" json:"category,omitempty"`
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+}
+
+// SeverityRemap changes the severity of matching issues
+type SeverityRemap struct {
+	Match    `yaml:",inline"`
+	Severity domain.Severity `yaml:"severity" json:"severity"`
+}
+
+// Recommendation is added to diagnoses that contain a matching issue
+type Recommendation struct {
+	Match       Match  `yaml:"match" json:"match"`
+	Priority    int    `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Command     string `yaml:"command,omitempty" json:"command,omitempty"`
+}
+
+// Load reads a rule pack from a file path or an http(s) URL
+func Load(source string) (*Pack, error) {
+	data, err := read(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule pack %s: %w", source, err)
+	}
+
+	pack, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule pack %s: %w", source, err)
+	}
+	return pack, nil
+}
+
+// Parse decodes and validates a YAML rule pack
+func Parse(data []byte) (*Pack, error) {
+	var pack Pack
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return nil, err
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// Validate checks that patterns compile and severities are known
+func (p *Pack) Validate() error {
+	for i, lp := range p.LogPatterns {
+		if err := lp.Validate(); err != nil {
+			return fmt.Errorf("logPatterns[%d]: %w", i, err)
+		}
+	}
+	for i, r := range p.SeverityRemaps {
+		if !validSeverity(r.Severity) {
+			return fmt.Errorf("severityRemaps[%d]: unknown severity %q", i, r.Severity)
+		}
+	}
+	for i, r := range p.Recommendations {
+		if r.Title == "" {
+			return fmt.Errorf("recommendations[%d]: title is required", i)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the pattern has a title, compiles and has a known
+// severity
+func (lp LogPattern) Validate() error {
+	if lp.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if lp.Pattern == "" {
+		return fmt.Errorf("%s: pattern is required", lp.Title)
+	}
+	if _, err := regexp.Compile(lp.Pattern); err != nil {
+		return fmt.Errorf("%s: invalid pattern: %w", lp.Title, err)
+	}
+	if !validSeverity(lp.Severity) {
+		return fmt.Errorf("%s: unknown severity %q (use critical, warning or info)", lp.Title, lp.Severity)
+	}
+	return nil
+}
+
+// LoadLogPatterns reads a file of custom log patterns:
+//
+//	patterns:
+//	  - pattern: '(?i)database is locked'
+//	    title: SQLite locked
+//	    severity: warning
+func LoadLogPatterns(path string) ([]LogPattern, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log patterns %s: %w", path, err)
+	}
+
+	var file struct {
+		Patterns []LogPattern `yaml:"patterns"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid log patterns %s: %w", path, err)
+	}
+	for i, lp := range file.Patterns {
+		if err := lp.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid log patterns %s: patterns[%d]: %w", path, i, err)
+		}
+	}
+	return file.Patterns, nil
+}
+
+// Filter drops suppressed issues from the diagnosis and remaps the
+// severity of the remaining ones
+func (p *Pack) Filter(d *domain.Diagnosis) {
+	namespace := d.Pod.Namespace
+
+	issues := d.Issues[:0]
+	for _, issue := range d.Issues {
+		suppressed := false
+		for _, s := range p.Suppressions {
+			if s.Matches(issue, namespace) {
+				suppressed = true
+				break
+			}
+		}
+		if suppressed {
+			continue
+		}
+		for _, r := range p.SeverityRemaps {
+			if r.Matches(issue, namespace) {
+				issue.Severity = r.Severity
+			}
+		}
+		issues = append(issues, issue)
+	}
+	d.Issues = issues
+}
+
+// Recommend returns the pack's recommendations that match an issue in the diagnosis
+func (p *Pack) Recommend(d *domain.Diagnosis) []domain.Recommendation {
+	var recs []domain.Recommendation
+	for _, rec := range p.Recommendations {
+		for _, issue := range d.Issues {
+			if rec.Match.Matches(issue, d.Pod.Namespace) {
+				recs = append(recs, domain.Recommendation{
+					Priority:    rec.Priority,
+					Title:       rec.Title,
+					Description: rec.Description,
+					Command:     expand(rec.Command, d.Pod),
+				})
+				break
+			}
+		}
+	}
+	return recs
+}
+
+// Matches returns true if the issue, found in the given namespace, matches
+func (m Match) Matches(issue domain.Issue, namespace string) bool {
+	if m.Title != "" && !strings.Contains(issue.Title, m.Title) {
+		return false
+	}
+	if m.Category != "" && m.Category != issue.Category {
+		return false
+	}
+	if m.Namespace != "" && m.Namespace != namespace {
+		return false
+	}
+	return true
+}
+
+// expand substitutes {{pod}}, {{namespace}} and {{node}} in a command,
+// quoted as shell words where needed
+func expand(command string, pod domain.PodInfo) string {
+	return strings.NewReplacer(
+		"{{pod}}", domain.ShellArg(pod.Name),
+		"{{namespace}}", domain.ShellArg(pod.Namespace),
+		"{{node}}", domain.ShellArg(pod.Node),
+	).Replace(command)
+}
+
+func validSeverity(s domain.Severity) bool {
+	switch s {
+	case domain.SeverityCritical, domain.SeverityWarning, domain.SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// read returns the contents of a local file or URL
+func read(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
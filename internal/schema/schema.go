@@ -0,0 +1,140 @@
+// Package schema generates JSON Schemas for pod-doctor's serialized output
+// from the domain types, so they cannot drift from what -o json emits.
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// draft is the JSON Schema dialect of generated schemas
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// enums lists the values of string types that only take known constants
+	enums = map[reflect.Type][]string{
+		reflect.TypeOf(domain.Severity("")): {
+			string(domain.SeverityCritical), string(domain.SeverityWarning), string(domain.SeverityInfo),
+		},
+		reflect.TypeOf(domain.PodStatus("")): {
+			string(domain.StatusHealthy), string(domain.StatusCrashLoop), string(domain.StatusImagePull),
+			string(domain.StatusPending), string(domain.StatusOOMKilled), string(domain.StatusEvicted),
+			string(domain.StatusError), string(domain.StatusTerminating), string(domain.StatusUnknown),
+			string(domain.StatusNotReady), string(domain.StatusInitializing), string(domain.StatusCreateError),
+			string(domain.StatusConfigError), string(domain.StatusDeleted),
+		},
+	}
+)
+
+// Diagnosis returns the JSON Schema of a serialized domain.Diagnosis. The
+// apiVersion property is pinned to domain.APIVersion, so validation fails
+// on output from an incompatible version.
+func Diagnosis() ([]byte, error) {
+	g := &generator{defs: make(map[string]map[string]interface{})}
+	root := g.schemaFor(reflect.TypeOf(domain.Diagnosis{}))
+
+	if props, ok := g.defs["Diagnosis"]["properties"].(map[string]interface{}); ok {
+		props["apiVersion"] = map[string]interface{}{"const": domain.APIVersion}
+	}
+
+	root["$schema"] = draft
+	root["title"] = "pod-doctor diagnosis"
+	root["description"] = "One pod diagnosis as printed by diagnose -o json; diagnose -f prints an array of these, and scan prints them under diagnoses"
+	root["$defs"] = g.defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// generator builds schemas for Go types as encoding/json serializes them.
+// Struct types are collected under $defs and referenced by name.
+type generator struct {
+	defs map[string]map[string]interface{}
+}
+
+func (g *generator) schemaFor(t reflect.Type) map[string]interface{} {
+	if values, ok := enums[t]; ok {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "integer", "description": "duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaFor(t.Elem())
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // placeholder, for self-referencing types
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	case reflect.Slice, reflect.Array:
+		// nil slices serialize as null
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema describes a struct's exported fields by their JSON names.
+// Fields without omitempty are always present and so required.
+func (g *generator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = g.schemaFor(field.Type)
+		if !hasOption(opts, "omitempty") || field.Type.Kind() == reflect.Struct {
+			// omitempty never drops a struct value
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
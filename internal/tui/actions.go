@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+)
+
+// actionTimeout bounds the API call of a pod action
+const actionTimeout = 30 * time.Second
+
+// podAction is a change to the cluster started from the diagnosis view,
+// run only once confirmed
+type podAction struct {
+	prompt  string // question the confirmation asks
+	warning string // consequence to point out before confirming, if any
+	running string // notice shown while it runs
+	run     func(ctx context.Context) (string, error)
+}
+
+// actionDoneMsg reports the outcome of a pod action
+type actionDoneMsg struct {
+	message string
+	err     error
+}
+
+// handleDeletePod asks to delete the diagnosed pod, so its controller
+// replaces it. It waits for the workload to be known, so the confirmation
+// can say whether the pod will be recreated.
+func (m Model) handleDeletePod() (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	if v.result == nil {
+		return m, nil
+	}
+	namespace, name := v.namespace, v.pod
+	workload := v.result.Workload
+	if workload == nil && len(v.pending) > 0 {
+		v.notice = "Wait for the diagnosis to finish before deleting the pod"
+		return m, nil
+	}
+
+	action := &podAction{
+		prompt:  fmt.Sprintf("Delete pod %s/%s?", namespace, name),
+		running: fmt.Sprintf("Deleting pod %s...", name),
+	}
+	recreatedBy := "its controller"
+	if workload != nil {
+		recreatedBy = workload.Ref()
+		action.warning = fmt.Sprintf("%s will create a replacement pod", recreatedBy)
+	} else {
+		action.warning = "The pod has no controller: it will not be recreated"
+	}
+	action.run = func(ctx context.Context) (string, error) {
+		if err := m.client.DeletePod(ctx, namespace, name); err != nil {
+			return "", err
+		}
+		if workload == nil {
+			return fmt.Sprintf("Deleted pod %s; it will not be recreated", name), nil
+		}
+		return fmt.Sprintf("Deleted pod %s; %s will recreate it", name, recreatedBy), nil
+	}
+
+	v.notice = ""
+	v.confirming = action
+	return m, nil
+}
+
+// handleRestartWorkload asks to roll out the diagnosed pod's deployment,
+// statefulset or daemonset again, like kubectl rollout restart
+func (m Model) handleRestartWorkload() (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	if v.result == nil {
+		return m, nil
+	}
+	workload := v.result.Workload
+	switch {
+	case workload == nil && len(v.pending) > 0:
+		v.notice = "Wait for the diagnosis to finish before restarting the workload"
+		return m, nil
+	case workload == nil:
+		v.notice = "The pod has no workload to restart"
+		return m, nil
+	case !kubernetes.Restartable(workload):
+		v.notice = fmt.Sprintf("%s cannot be restarted; only deployments, statefulsets and daemonsets can", workload.Ref())
+		return m, nil
+	}
+
+	v.notice = ""
+	v.confirming = &podAction{
+		prompt:  fmt.Sprintf("Restart %s in %s?", workload.Ref(), workload.Namespace),
+		warning: "All its pods are replaced through a rolling update",
+		running: fmt.Sprintf("Restarting %s...", workload.Ref()),
+		run: func(ctx context.Context) (string, error) {
+			if err := m.client.RestartWorkload(ctx, workload); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Restarted %s; its pods are being replaced", workload.Ref()), nil
+		},
+	}
+	return m, nil
+}
+
+// handleConfirmKeys runs the action awaiting confirmation on y, and
+// cancels it on any other key
+func (m Model) handleConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	action := v.confirming
+	v.confirming = nil
+	if msg.String() != "y" && msg.String() != "Y" {
+		v.notice = "Cancelled"
+		return m, nil
+	}
+
+	v.notice = action.running
+	return m, func() tea.Msg {
+		ctx, cancel := m.requestContext(actionTimeout)
+		defer cancel()
+		message, err := action.run(ctx)
+		return actionDoneMsg{message: message, err: err}
+	}
+}
+
+// handleActionDone shows the outcome of a pod action
+func (m Model) handleActionDone(msg actionDoneMsg) Model {
+	if msg.err != nil {
+		m.diagnosis.notice = fmt.Sprintf("%s Failed: %v", statusLabel(false, true), msg.err)
+		return m
+	}
+	m.diagnosis.notice = statusLabel(true, false) + " " + msg.message
+	return m
+}
+
+// renderConfirm renders the confirmation of the action awaiting it
+func (m Model) renderConfirm() string {
+	action := m.diagnosis.confirming
+	var body strings.Builder
+	body.WriteString(lipgloss.NewStyle().Bold(true).Render(action.prompt))
+	if action.warning != "" {
+		body.WriteString("\n" + warningStyle.Render(action.warning))
+	}
+	body.WriteString("\n\n" + helpStyle.Render("y: confirm • any other key: cancel"))
+	return panelStyle.BorderForeground(warningColor).Render(body.String())
+}
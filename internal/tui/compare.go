@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// compareTimeout bounds finding a healthy replica and diagnosing both pods
+const compareTimeout = 60 * time.Second
+
+// comparisonMsg carries the comparison of a pod with a healthy replica
+type comparisonMsg struct {
+	namespace  string
+	pod        string
+	comparison *domain.PodComparison
+	err        error
+}
+
+// handleCompare compares the diagnosed pod with the healthiest other
+// replica of its workload
+func (m Model) handleCompare() (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	if v.result == nil {
+		return m, nil
+	}
+	namespace, name := v.namespace, v.pod
+	v.notice = "Comparing with a healthy replica..."
+	return m, func() tea.Msg {
+		ctx, cancel := m.requestContext(compareTimeout)
+		defer cancel()
+		replica, err := m.analyzer.HealthyReplica(ctx, namespace, name)
+		if err != nil {
+			return comparisonMsg{namespace: namespace, pod: name, err: err}
+		}
+		comparison, err := m.analyzer.Compare(ctx, namespace, name, replica)
+		return comparisonMsg{namespace: namespace, pod: name, comparison: comparison, err: err}
+	}
+}
+
+// handleComparison shows a comparison over the diagnosis, unless the view
+// moved on to another pod meanwhile
+func (m Model) handleComparison(msg comparisonMsg) Model {
+	v := &m.diagnosis
+	if msg.namespace != v.namespace || msg.pod != v.pod {
+		return m
+	}
+	if msg.err != nil {
+		v.notice = fmt.Sprintf("%s Cannot compare: %v", statusLabel(false, true), msg.err)
+		return m
+	}
+	v.notice = ""
+	v.comparison = msg.comparison
+	v.compareOffset = 0
+	return m
+}
+
+// handleCompareKeys handles keys while a comparison covers the diagnosis
+func (m Model) handleCompareKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Compare), key.Matches(msg, m.keys.Back):
+		v.comparison = nil
+	case key.Matches(msg, m.keys.Down):
+		v.compareOffset++
+	case key.Matches(msg, m.keys.Up):
+		if v.compareOffset > 0 {
+			v.compareOffset--
+		}
+	}
+	return m, nil
+}
+
+// renderComparison renders how the diagnosed pod differs from the replica
+// it was compared with, its own values and issues highlighted
+func (m Model) renderComparison() string {
+	c := m.diagnosis.comparison
+	failing := c.IsFailing(c.A.Name)
+
+	var lines []string
+	for _, p := range []domain.ComparedPod{c.A, c.B} {
+		name := p.Name
+		if c.IsFailing(p.Name) {
+			name = criticalStyle.Render(p.Name + " (failing)")
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s  score %s  node %s  restarts %d",
+			name, p.Status, ScoreStyle(p.HealthScore).Render(fmt.Sprintf("%d", p.HealthScore)), p.Node, p.Restarts))
+	}
+	if !c.SameWorkload {
+		lines = append(lines, warningStyle.Render("The pods do not belong to the same workload"))
+	}
+	lines = append(lines, "")
+
+	if len(c.Differences) == 0 {
+		lines = append(lines, healthyStyle.Render("No differences in spec, placement or state"))
+	} else {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Differences (this pod → replica)"))
+		for _, d := range c.Differences {
+			field := d.Field
+			if d.Container != "" {
+				field = d.Container + ": " + field
+			}
+			ours := truncate(d.A, 40)
+			if failing {
+				ours = warningStyle.Render(ours)
+			}
+			lines = append(lines, fmt.Sprintf("  %-32s %s → %s", truncate(field, 32), ours, truncate(d.B, 40)))
+		}
+	}
+
+	issueSections := []struct {
+		title  string
+		issues []domain.Issue
+	}{
+		{"Only on this pod", c.OnlyA},
+		{"Only on the replica", c.OnlyB},
+		{"On both", c.Shared},
+	}
+	for _, section := range issueSections {
+		if len(section.issues) == 0 {
+			continue
+		}
+		lines = append(lines, "", lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s: %d", section.title, len(section.issues))))
+		for _, issue := range section.issues {
+			lines = append(lines, "  "+SeverityIcon(string(issue.Severity))+" "+issue.Title)
+		}
+	}
+
+	// Scroll the body to fit the screen
+	height := m.height - 8
+	if height < 10 {
+		height = 10
+	}
+	offset := m.diagnosis.compareOffset
+	if last := len(lines) - height; offset > last {
+		offset = last
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	end := offset + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("🔍 pod-doctor - Compare"))
+	b.WriteString("\n")
+	b.WriteString(subtitleStyle.Render(fmt.Sprintf("%s/%s vs %s", c.Namespace, c.A.Name, c.B.Name)))
+	b.WriteString("\n\n")
+	b.WriteString(panelStyle.Render(strings.Join(lines[offset:end], "\n")))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s/%s: scroll • %s or %s: close • %s: quit",
+		m.keys.Up.Help().Key, m.keys.Down.Help().Key, m.keys.Compare.Help().Key, m.keys.Back.Help().Key, m.keys.Quit.Help().Key)))
+	return b.String()
+}
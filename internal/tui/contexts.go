@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+)
+
+// contextList holds the state of the kubeconfig context picker
+type contextList struct {
+	items    []string
+	current  string // context of the kubeconfig's current-context
+	cursor   int
+	previous View  // view to return to
+	err      error // why the contexts could not be listed or switched to
+}
+
+type contextSwitchedMsg struct {
+	context  string
+	client   *kubernetes.Client
+	analyzer *analyzer.PodAnalyzer
+	err      error
+}
+
+// renderTitle renders a view's title followed by the kube context in use
+func (m Model) renderTitle(title string) string {
+	if ctx := m.client.Context(); ctx != "" {
+		title += "  " + mutedStyle.Render("⎈ "+ctx)
+	}
+	return titleStyle.Render(title)
+}
+
+// openContexts opens the context picker on the contexts of the client's
+// kubeconfig, with the cursor on the one in use
+func (m Model) openContexts() (tea.Model, tea.Cmd) {
+	l := contextList{previous: m.view}
+	if m.connect == nil {
+		l.err = fmt.Errorf("switching contexts is not available when replaying or recording a session")
+	} else {
+		l.items, l.current, l.err = kubernetes.Contexts(m.client.Kubeconfig())
+	}
+	for i, name := range l.items {
+		if name == m.client.Context() {
+			l.cursor = i
+		}
+	}
+	m.contexts = l
+	m.view = ViewContexts
+	return m, nil
+}
+
+// handleContextKeys handles key presses in the context picker
+func (m Model) handleContextKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.contexts
+	if delta, ok := m.cursorMove(msg); ok {
+		l.cursor = moveCursor(l.cursor, delta, len(l.items))
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.view = l.previous
+
+	case key.Matches(msg, m.keys.Enter):
+		if m.connect == nil || l.cursor >= len(l.items) {
+			return m, nil
+		}
+		name := l.items[l.cursor]
+		if name == m.client.Context() {
+			m.view = l.previous
+			return m, nil
+		}
+		m.loading = true
+		m.loadingMessage = fmt.Sprintf("Connecting to %s...", name)
+		m.view = ViewLoading
+		return m, tea.Batch(m.spinner.Tick, m.switchContext(name))
+	}
+	return m, nil
+}
+
+// switchContext connects to the cluster of a context
+func (m Model) switchContext(name string) tea.Cmd {
+	connect := m.connect
+	return func() tea.Msg {
+		client, podAnalyzer, err := connect(name)
+		return contextSwitchedMsg{context: name, client: client, analyzer: podAnalyzer, err: err}
+	}
+}
+
+// handleContextSwitched replaces the client and analyzer with those of the
+// new cluster, drops everything loaded from the old one and reloads the view
+// the TUI started on. A failed connection returns to the picker.
+func (m Model) handleContextSwitched(msg contextSwitchedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.contexts.err = fmt.Errorf("failed to switch to %s: %w", msg.context, msg.err)
+		m.view = ViewContexts
+		return m, nil
+	}
+
+	m.client = msg.client
+	m.analyzer = msg.analyzer
+	m.scores = make(map[string]int)
+	m.namespaces = namespaceList{}
+	m.selectedNS = ""
+	m.pods.items = nil
+	m.pods.clearFilter()
+	m.diagnosis.abandon()
+	m.diagnosis = diagnosisView{expandEvents: m.diagnosis.expandEvents, run: m.diagnosis.run}
+	m.logs.close()
+
+	if m.top.active {
+		m.top = topDashboard{active: true, interval: m.top.interval}
+		m.view = ViewTop
+		return m, m.loadTop()
+	}
+	m.loading = true
+	m.view = ViewLoading
+	if m.allNamespaces {
+		m.loadingMessage = "Loading pods..."
+		return m, tea.Batch(m.spinner.Tick, m.loadNamespaces(), m.loadPods())
+	}
+	m.loadingMessage = "Loading namespaces..."
+	return m, tea.Batch(m.spinner.Tick, m.loadNamespaces())
+}
+
+func (m Model) renderContexts() string {
+	var b strings.Builder
+	l := m.contexts
+
+	b.WriteString(m.renderTitle("🔍 pod-doctor - Contexts"))
+	b.WriteString("\n")
+	b.WriteString(subtitleStyle.Render("Switch to the cluster of a kubeconfig context"))
+	b.WriteString("\n\n")
+
+	if l.err != nil {
+		b.WriteString(criticalStyle.Render(l.err.Error()))
+		b.WriteString("\n\n")
+	}
+
+	visibleHeight := m.height - 10
+	if visibleHeight < 5 {
+		visibleHeight = 5
+	}
+	start, end := visibleRange(l.cursor, len(l.items), visibleHeight)
+
+	for i := start; i < end; i++ {
+		name := l.items[i]
+		var notes []string
+		if name == m.client.Context() {
+			notes = append(notes, "in use")
+		}
+		if name == l.current {
+			notes = append(notes, "kubeconfig current")
+		}
+		line := name
+		if len(notes) > 0 {
+			line += "  " + mutedStyle.Render("("+strings.Join(notes, ", ")+")")
+		}
+		if i == l.cursor {
+			b.WriteString(cursorStyle.Render("▸ "))
+			b.WriteString(selectedItemStyle.Render(line))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(listItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: switch • esc: back • ?: help • q: quit"))
+	return b.String()
+}
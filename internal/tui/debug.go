@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+)
+
+// debugStartTimeout bounds creating a debug container and waiting for it
+// to start
+const debugStartTimeout = 2 * time.Minute
+
+// debugReadyMsg reports that a debug container is running, or why not
+type debugReadyMsg struct {
+	namespace string
+	pod       string
+	container string
+	err       error
+}
+
+// debugFinishedMsg reports the end of an attached debug session
+type debugFinishedMsg struct {
+	container string
+	err       error
+}
+
+// handleDebug adds an ephemeral debug container to the diagnosed pod,
+// targeting the container of the current tab, and attaches to it once it
+// runs
+func (m Model) handleDebug() (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	if v.result == nil {
+		return m, nil
+	}
+	if len(v.pending) > 0 {
+		v.notice = "Wait for the diagnosis to finish before opening a debug shell"
+		return m, nil
+	}
+
+	target := ""
+	if c, ok := v.selectedContainer(); ok {
+		target = c.Name
+	}
+	namespace, pod := v.result.Pod.Namespace, v.result.Pod.Name
+
+	v.notice = ""
+	m.loading = true
+	m.loadingMessage = fmt.Sprintf("Starting debug container (%s) in %s...", kubernetes.DefaultDebugImage, pod)
+	m.view = ViewLoading
+	return m, tea.Batch(m.spinner.Tick, m.startDebug(namespace, pod, target))
+}
+
+// startDebug creates the debug container and waits for it to run
+func (m Model) startDebug(namespace, pod, target string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.requestContext(debugStartTimeout)
+		defer cancel()
+
+		if target == "" {
+			p, err := m.client.GetPod(ctx, namespace, pod)
+			if err != nil {
+				return debugReadyMsg{err: err}
+			}
+			target = kubernetes.DebugTarget(p)
+		}
+		name, err := m.client.CreateDebugContainer(ctx, namespace, pod, kubernetes.DebugContainer{Target: target})
+		if err != nil {
+			return debugReadyMsg{err: err}
+		}
+		if err := m.client.WaitForDebugContainer(ctx, namespace, pod, name); err != nil {
+			return debugReadyMsg{err: err}
+		}
+		return debugReadyMsg{namespace: namespace, pod: pod, container: name}
+	}
+}
+
+// handleDebugReady hands the terminal to kubectl attach, or shows the
+// attach command when kubectl is not installed
+func (m Model) handleDebugReady(msg debugReadyMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.view = ViewDiagnosis
+	if msg.err != nil {
+		m.diagnosis.notice = fmt.Sprintf("Debug container failed: %v", msg.err)
+		return m, nil
+	}
+
+	attach := m.client.AttachCommand(msg.namespace, msg.pod, msg.container)
+	kubectl, err := exec.LookPath(attach[0])
+	if err != nil {
+		m.diagnosis.notice = fmt.Sprintf("Debug container %s is running; kubectl not found, attach with: %s", msg.container, strings.Join(attach, " "))
+		return m, nil
+	}
+	return m, tea.ExecProcess(exec.Command(kubectl, attach[1:]...), func(err error) tea.Msg {
+		return debugFinishedMsg{container: msg.container, err: err}
+	})
+}
+
+// handleDebugFinished notes how the debug session ended
+func (m Model) handleDebugFinished(msg debugFinishedMsg) Model {
+	if msg.err != nil {
+		m.diagnosis.notice = fmt.Sprintf("Debug session in %s ended: %v", msg.container, msg.err)
+	} else {
+		m.diagnosis.notice = fmt.Sprintf("Debug session in %s ended", msg.container)
+	}
+	return m
+}
@@ -0,0 +1,589 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+// diagnosisView holds the state of the diagnosis view
+type diagnosisView struct {
+	namespace     string
+	pod           string
+	result        *domain.Diagnosis
+	containerTab  int                   // 0 is the whole pod, n the nth container
+	expandEvents  bool                  // list every warning event instead of grouping by reason
+	notice        string                // outcome of the last debug session or action, shown under the diagnosis
+	confirming    *podAction            // action awaiting confirmation, shown under the diagnosis
+	explaining    bool                  // knowledge base articles of the diagnosis' issues cover the diagnosis
+	explainIndex  int                   // article shown while explaining
+	comparison    *domain.PodComparison // comparison with a healthy replica, covering the diagnosis
+	compareOffset int                   // first line of the comparison shown
+	pending       []string              // diagnosis sections still running
+	run           int                   // identifies the latest diagnosis run; older runs' results are dropped
+	cancel        context.CancelFunc    // cancels the API calls of the running diagnosis
+}
+
+// diagnosisTimeout bounds a diagnosis run from the TUI
+const diagnosisTimeout = 30 * time.Second
+
+// diagnosisProgressMsg carries a partial diagnosis while its remaining
+// sections run; more messages follow on updates
+type diagnosisProgressMsg struct {
+	run      int
+	progress analyzer.Progress
+	updates  <-chan tea.Msg
+}
+
+type diagnosisCompleteMsg struct {
+	run       int
+	diagnosis *domain.Diagnosis
+	err       error
+}
+
+// release cancels what is left of the latest diagnosis run
+func (v *diagnosisView) release() {
+	if v.cancel != nil {
+		v.cancel()
+		v.cancel = nil
+	}
+}
+
+// abandon drops the diagnosis still running, canceling its API calls so it
+// neither keeps the cluster busy nor delivers results to a view that moved on
+func (v *diagnosisView) abandon() {
+	v.release()
+	v.run++
+	v.pending = nil
+}
+
+// show displays a diagnosis, partial or complete, staying on the current
+// tab if the pod still has that container
+func (v *diagnosisView) show(d *domain.Diagnosis) {
+	v.result = d
+	if v.containerTab > len(d.Pod.Containers) {
+		v.containerTab = 0
+	}
+}
+
+// selectedContainer returns the container of the current tab, or false on
+// the pod overview
+func (v diagnosisView) selectedContainer() (domain.ContainerInfo, bool) {
+	if v.result == nil || v.containerTab <= 0 || v.containerTab > len(v.result.Pod.Containers) {
+		return domain.ContainerInfo{}, false
+	}
+	return v.result.Pod.Containers[v.containerTab-1], true
+}
+
+// switchContainerTab moves between the pod overview and its containers,
+// wrapping around at either end
+func (v *diagnosisView) switchContainerTab(delta int) {
+	if v.result == nil {
+		return
+	}
+	tabs := len(v.result.Pod.Containers) + 1
+	v.containerTab = ((v.containerTab+delta)%tabs + tabs) % tabs
+}
+
+// handleDiagnosisKeys handles key presses in the diagnosis view
+func (m Model) handleDiagnosisKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		v.abandon()
+		if m.top.active {
+			// Back to the dashboard, on the pod that was drilled into
+			m.view = ViewTop
+			m.top.cursor = m.top.indexOf(v.namespace, v.pod)
+			return m, m.loadTop()
+		}
+		m.view = ViewPodList
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.loading = true
+		m.loadingMessage = fmt.Sprintf("Diagnosing %s...", v.pod)
+		m.view = ViewLoading
+		return m.startDiagnosis()
+
+	case key.Matches(msg, m.keys.Logs):
+		if v.result != nil {
+			var containers []string
+			for _, c := range v.result.Pod.Containers {
+				containers = append(containers, c.Name)
+			}
+			// Start on the container being drilled into
+			selected := 0
+			if v.containerTab > 0 {
+				selected = v.containerTab - 1
+			}
+			return m.openLogs(v.namespace, v.pod, containers, selected)
+		}
+
+	case key.Matches(msg, m.keys.Tab):
+		v.switchContainerTab(1)
+
+	case key.Matches(msg, m.keys.BackTab):
+		v.switchContainerTab(-1)
+
+	case key.Matches(msg, m.keys.Events):
+		v.expandEvents = !v.expandEvents
+
+	case key.Matches(msg, m.keys.Debug):
+		return m.handleDebug()
+
+	case key.Matches(msg, m.keys.Explain):
+		return m.handleExplain()
+
+	case key.Matches(msg, m.keys.Compare):
+		return m.handleCompare()
+
+	case key.Matches(msg, m.keys.Delete):
+		return m.handleDeletePod()
+
+	case key.Matches(msg, m.keys.Restart):
+		return m.handleRestartWorkload()
+	}
+	return m, nil
+}
+
+// diagnosePod switches to the diagnosis of a pod
+func (m Model) diagnosePod(pod PodItem) (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	v.namespace, v.pod = pod.Namespace, pod.Name
+	v.containerTab = 0
+	v.notice = ""
+	v.comparison = nil
+	m.loading = true
+	m.loadingMessage = fmt.Sprintf("Diagnosing %s...", pod.Name)
+	m.view = ViewLoading
+	return m.startDiagnosis()
+}
+
+// startDiagnosis abandons the diagnosis still running, if any, and
+// diagnoses the view's pod again
+func (m Model) startDiagnosis() (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	v.abandon()
+	ctx, cancel := context.WithCancel(m.ctx)
+	v.cancel = cancel
+	return m, tea.Batch(m.spinner.Tick, m.runDiagnosis(ctx, v.namespace, v.pod))
+}
+
+// runDiagnosis diagnoses a pod in the background. The pod's status is
+// shown as soon as it is known, and each analyzer's findings as it
+// completes, instead of waiting for the slowest one. Once ctx is canceled
+// the run stops and delivers nothing more.
+func (m Model) runDiagnosis(ctx context.Context, namespace, name string) tea.Cmd {
+	run := m.diagnosis.run
+	updates := make(chan tea.Msg)
+	send := func(msg tea.Msg) {
+		select {
+		case updates <- msg:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		defer close(updates)
+
+		diagnoseCtx, cancel := context.WithTimeout(ctx, diagnosisTimeout)
+		defer cancel()
+
+		diagnosis, err := m.analyzer.DiagnoseWithProgress(diagnoseCtx, namespace, name, func(p analyzer.Progress) {
+			send(diagnosisProgressMsg{run: run, progress: p, updates: updates})
+		})
+		send(diagnosisCompleteMsg{run: run, diagnosis: diagnosis, err: err})
+	}()
+	return waitForDiagnosis(updates)
+}
+
+// waitForDiagnosis delivers the next message of a running diagnosis, or
+// nothing once the run ended
+func waitForDiagnosis(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// handleDiagnosisProgress shows a partial diagnosis and waits for the next
+// update. An abandoned run may have sent an update before it was canceled;
+// it is dropped.
+func (m Model) handleDiagnosisProgress(msg diagnosisProgressMsg) (tea.Model, tea.Cmd) {
+	next := waitForDiagnosis(msg.updates)
+	if msg.run != m.diagnosis.run {
+		return m, next
+	}
+
+	m.diagnosis.show(msg.progress.Diagnosis)
+	m.diagnosis.pending = msg.progress.Pending
+	if m.view == ViewLoading {
+		m.loading = false
+		m.view = ViewDiagnosis
+	}
+	return m, next
+}
+
+// handleDiagnosisComplete shows a finished diagnosis and records the pod's
+// health score for the pod list
+func (m Model) handleDiagnosisComplete(msg diagnosisCompleteMsg) (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	if msg.run != v.run {
+		return m, nil
+	}
+	v.release()
+	v.pending = nil
+	if m.view != ViewLoading && m.view != ViewDiagnosis {
+		// The user moved on, e.g. to the logs, while the diagnosis ran
+		if msg.err == nil {
+			v.result = msg.diagnosis
+			m.scores[podKey(msg.diagnosis.Pod.Namespace, msg.diagnosis.Pod.Name)] = msg.diagnosis.HealthScore
+		}
+		return m, nil
+	}
+	m.loading = false
+	if msg.err != nil && m.top.active {
+		// The pod may be gone since the last refresh; stay on the dashboard
+		m.top.err = msg.err
+		m.view = ViewTop
+		return m, m.loadTop()
+	}
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	v.show(msg.diagnosis)
+	m.scores[podKey(msg.diagnosis.Pod.Namespace, msg.diagnosis.Pod.Name)] = msg.diagnosis.HealthScore
+	m.view = ViewDiagnosis
+	if m.onboarding {
+		// Reaching a diagnosis completes the tour
+		return m, completeOnboarding()
+	}
+	return m, nil
+}
+
+func (m Model) renderDiagnosis() string {
+	v := m.diagnosis
+	d := v.result
+	if d == nil {
+		return "No diagnosis available"
+	}
+
+	var b strings.Builder
+
+	// Header
+	b.WriteString(m.renderTitle("🔍 pod-doctor - Diagnosis"))
+	b.WriteString("\n")
+	b.WriteString(subtitleStyle.Render(fmt.Sprintf("%s/%s", d.Pod.Namespace, d.Pod.Name)))
+	b.WriteString("\n\n")
+
+	// Status
+	statusStr := string(d.Status)
+	var statusStyled string
+	switch d.Status {
+	case domain.StatusHealthy:
+		statusStyled = healthyStyle.Render(statusLabel(true, false) + " " + statusStr)
+	case domain.StatusCrashLoop, domain.StatusOOMKilled, domain.StatusError, domain.StatusImagePull:
+		statusStyled = criticalStyle.Render(statusLabel(false, true) + " " + statusStr)
+	default:
+		statusStyled = warningStyle.Render(statusLabel(false, false) + " " + statusStr)
+	}
+	b.WriteString(fmt.Sprintf("Status: %s\n", statusStyled))
+	score := ScoreStyle(d.HealthScore).Render(fmt.Sprintf("%d/100", d.HealthScore))
+	if len(v.pending) > 0 {
+		score += " " + mutedStyle.Render("(so far)")
+	}
+	b.WriteString(fmt.Sprintf("Health Score: %s\n", score))
+	b.WriteString(fmt.Sprintf("Node: %s | Age: %s | Restarts: %d\n",
+		valueOrNA(d.Pod.Node),
+		formatDuration(d.Pod.Age),
+		d.Pod.Restarts))
+	if d.Workload != nil {
+		b.WriteString(fmt.Sprintf("Workload: %s\n", d.Workload.Ref()))
+	}
+	for _, w := range d.Warnings {
+		b.WriteString(mutedStyle.Render(SeverityIcon("info")+" "+w.Message) + "\n")
+	}
+	b.WriteString("\n")
+
+	// Container tabs
+	if len(d.Pod.Containers) > 0 {
+		b.WriteString(v.renderContainerTabs())
+		b.WriteString("\n\n")
+	}
+
+	if c, ok := v.selectedContainer(); ok {
+		b.WriteString(v.renderContainerDetail(c))
+	} else {
+		b.WriteString(v.renderPodIssues())
+		b.WriteString(v.renderEvents())
+	}
+	b.WriteString(m.renderPending())
+
+	// Recommendations
+	if len(d.Recommendations) > 0 {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Bold(true).Render("Recommendations:"))
+		b.WriteString("\n")
+		maxRecs := 5
+		if len(d.Recommendations) < maxRecs {
+			maxRecs = len(d.Recommendations)
+		}
+		for i := 0; i < maxRecs; i++ {
+			rec := d.Recommendations[i]
+			b.WriteString(fmt.Sprintf("  %d. %s\n", i+1, rec.Title))
+			if rec.Command != "" {
+				cmd := rec.Command
+				if len(cmd) > 60 {
+					cmd = cmd[:57] + "..."
+				}
+				b.WriteString(fmt.Sprintf("     %s\n", lipgloss.NewStyle().Foreground(primaryColor).Render("$ "+cmd)))
+			}
+		}
+	}
+
+	if v.notice != "" {
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render(v.notice))
+		b.WriteString("\n")
+	}
+	if v.confirming != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderConfirm())
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("tab: next container • e: expand events • x: explain • C: compare • l: logs • d: debug shell • D: delete pod • R: restart workload • esc: back • r: refresh • ?: help • q: quit"))
+	b.WriteString(m.onboardingHint())
+
+	return b.String()
+}
+
+// renderPending lists the diagnosis sections still running, each with a
+// spinner, while their findings stream in
+func (m Model) renderPending() string {
+	pending := m.diagnosis.pending
+	if len(pending) == 0 {
+		return ""
+	}
+	sections := make([]string, len(pending))
+	for i, name := range pending {
+		sections[i] = m.spinner.View() + " " + mutedStyle.Render(name)
+	}
+	return "\n" + lipgloss.NewStyle().Width(m.width).Render(strings.Join(sections, "  ")) + "\n"
+}
+
+// renderContainerTabs renders the pod overview tab followed by one tab per
+// container, with the selected one highlighted
+func (v diagnosisView) renderContainerTabs() string {
+	d := v.result
+	tabs := []string{"pod"}
+	for _, c := range d.Pod.Containers {
+		tabs = append(tabs, fmt.Sprintf("%s %s", StatusIcon(c.Ready && c.State == "running"), c.Name))
+	}
+
+	rendered := make([]string, len(tabs))
+	for i, tab := range tabs {
+		if i == v.containerTab {
+			rendered[i] = selectedItemStyle.Render("[" + tab + "]")
+		} else {
+			rendered[i] = mutedStyle.Render(" " + tab + " ")
+		}
+	}
+	return strings.Join(rendered, " ")
+}
+
+// renderPodIssues renders the issues of the whole pod, grouped into
+// pod-level issues and per-container issues
+func (v diagnosisView) renderPodIssues() string {
+	var b strings.Builder
+	d := v.result
+
+	if len(d.Issues) == 0 && len(v.pending) > 0 {
+		b.WriteString(mutedStyle.Render("No issues found so far"))
+		b.WriteString("\n")
+		return b.String()
+	}
+	if len(d.Issues) == 0 {
+		b.WriteString(healthyStyle.Render(statusLabel(true, false) + " No issues detected"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	critical, warning, _ := d.IssueCount()
+	b.WriteString(fmt.Sprintf("Issues: %s critical, %s warnings\n",
+		criticalStyle.Render(fmt.Sprintf("%d", critical)),
+		warningStyle.Render(fmt.Sprintf("%d", warning))))
+
+	// Show max 10 issues to fit screen
+	remaining := 10
+	shown := 0
+	groups := d.IssuesByContainer()
+	names := []string{""}
+	for _, c := range d.Pod.Containers {
+		names = append(names, c.Name)
+	}
+	for name := range groups {
+		if name != "" && !containsString(names, name) {
+			names = append(names, name) // init or ephemeral containers
+		}
+	}
+
+	for _, name := range names {
+		issues := groups[name]
+		if len(issues) == 0 || remaining == 0 {
+			continue
+		}
+		heading := "Pod"
+		if name != "" {
+			heading = "Container " + name
+		}
+		b.WriteString(fmt.Sprintf("\n  %s\n", lipgloss.NewStyle().Bold(true).Render(heading)))
+		for _, issue := range issues {
+			if remaining == 0 {
+				break
+			}
+			b.WriteString(renderIssue(issue))
+			remaining--
+			shown++
+		}
+	}
+
+	if len(d.Issues) > shown {
+		b.WriteString(fmt.Sprintf("\n  %s\n", mutedStyle.Render(fmt.Sprintf("... and %d more issues (tab to see them per container)", len(d.Issues)-shown))))
+	}
+	return b.String()
+}
+
+// renderEvents renders the pod's warning events, grouped by reason with
+// their counts unless expanded
+func (v diagnosisView) renderEvents() string {
+	var warnings []domain.EventInfo
+	for _, e := range v.result.Events {
+		if e.Type == "Warning" {
+			warnings = append(warnings, e)
+		}
+	}
+	if len(warnings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("Warning Events:"))
+	b.WriteString("\n")
+
+	maxEvents := 5
+	if v.expandEvents {
+		maxEvents = 10
+		for i, e := range warnings {
+			if i == maxEvents {
+				b.WriteString(fmt.Sprintf("  %s\n", mutedStyle.Render(fmt.Sprintf("... and %d more events", len(warnings)-maxEvents))))
+				break
+			}
+			b.WriteString(fmt.Sprintf("  %s %s %s\n", warningStyle.Render(e.Reason),
+				mutedStyle.Render(e.LastSeen.Format("15:04:05")), truncate(e.Message, 50)))
+		}
+		return b.String()
+	}
+
+	groups := domain.GroupEventsByReason(warnings)
+	for i, g := range groups {
+		if i == maxEvents {
+			b.WriteString(fmt.Sprintf("  %s\n", mutedStyle.Render(fmt.Sprintf("... and %d more reasons", len(groups)-maxEvents))))
+			break
+		}
+		occurrences := fmt.Sprintf("×%d", g.Count)
+		if span := g.LastSeen.Sub(g.FirstSeen); g.Count > 1 && span >= time.Minute {
+			occurrences += " over " + formatDuration(span)
+		}
+		b.WriteString(fmt.Sprintf("  %s %s %s\n", warningStyle.Render(g.Reason),
+			mutedStyle.Render(occurrences), truncate(g.Message, 50)))
+	}
+	return b.String()
+}
+
+// renderContainerDetail renders one container's state, resources, probes
+// and the issues attributed to it
+func (v diagnosisView) renderContainerDetail(c domain.ContainerInfo) string {
+	var b strings.Builder
+
+	state := valueOrNA(c.State)
+	if c.Reason != "" {
+		state += " (" + c.Reason + ")"
+	}
+	if c.State == "terminated" {
+		state += fmt.Sprintf(" exit code %d", c.ExitCode)
+	}
+	b.WriteString(fmt.Sprintf("State: %s | Ready: %s | Restarts: %d\n",
+		state, StatusIcon(c.Ready), c.RestartCount))
+	b.WriteString(fmt.Sprintf("Image: %s\n", c.Image))
+	if c.Message != "" {
+		b.WriteString(fmt.Sprintf("Message: %s\n", mutedStyle.Render(truncate(c.Message, 70))))
+	}
+
+	if r := c.Resources; r != nil {
+		b.WriteString(fmt.Sprintf("Resources: cpu %s/%s • memory %s/%s %s\n",
+			valueOrNA(r.CPURequests), valueOrNA(r.CPULimits),
+			valueOrNA(r.MemoryRequests), valueOrNA(r.MemoryLimits),
+			mutedStyle.Render("(request/limit)")))
+	} else {
+		b.WriteString(fmt.Sprintf("Resources: %s\n", mutedStyle.Render("no requests or limits")))
+	}
+
+	if len(c.Probes) == 0 {
+		b.WriteString(fmt.Sprintf("Probes: %s\n", mutedStyle.Render("none")))
+	} else {
+		b.WriteString("Probes:\n")
+		for _, p := range c.Probes {
+			b.WriteString(fmt.Sprintf("  %-10s %s %s\n", p.Type, p.Handler,
+				mutedStyle.Render(fmt.Sprintf("delay=%ds period=%ds timeout=%ds failures=%d",
+					p.InitialDelay, p.Period, p.Timeout, p.FailureThreshold))))
+		}
+	}
+	b.WriteString("\n")
+
+	issues := v.result.ContainerIssues(c.Name)
+	if len(issues) == 0 {
+		b.WriteString(healthyStyle.Render(statusLabel(true, false) + " No issues for this container"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Issues: %d\n", len(issues)))
+	maxIssues := 8
+	for i, issue := range issues {
+		if i == maxIssues {
+			b.WriteString(fmt.Sprintf("\n  %s\n", mutedStyle.Render(fmt.Sprintf("... and %d more issues", len(issues)-maxIssues))))
+			break
+		}
+		b.WriteString(renderIssue(issue))
+	}
+	return b.String()
+}
+
+// renderIssue renders an issue title with a truncated description
+func renderIssue(issue domain.Issue) string {
+	icon := SeverityIcon(string(issue.Severity))
+	line := fmt.Sprintf("  %s %s\n", icon, issue.Title)
+	if issue.ID != "" {
+		line = fmt.Sprintf("  %s %s %s\n", icon, issue.Title, mutedStyle.Render(issue.ID))
+	}
+	if issue.Description != "" {
+		line += fmt.Sprintf("    %s\n", mutedStyle.Render(truncate(issue.Description, 60)))
+	}
+	return line
+}
+
+// Helper functions
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kb"
+)
+
+// explainIssues returns the issues of the current diagnosis tab, which the
+// explanation pages through
+func (v diagnosisView) explainIssues() []domain.Issue {
+	if c, ok := v.selectedContainer(); ok {
+		return v.result.ContainerIssues(c.Name)
+	}
+	return v.result.Issues
+}
+
+// handleExplain opens the knowledge base articles of the issues on the
+// current diagnosis tab
+func (m Model) handleExplain() (tea.Model, tea.Cmd) {
+	v := &m.diagnosis
+	if v.result == nil {
+		return m, nil
+	}
+	if len(kb.ForIssues(v.explainIssues())) == 0 {
+		v.notice = "No issues to explain"
+		return m, nil
+	}
+	v.notice = ""
+	v.explaining = true
+	v.explainIndex = 0
+	return m, nil
+}
+
+// handleExplainKeys handles keys while explanations cover the diagnosis
+func (m Model) handleExplainKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Explain), key.Matches(msg, m.keys.Back):
+		m.diagnosis.explaining = false
+	case key.Matches(msg, m.keys.Down), key.Matches(msg, m.keys.Tab):
+		m.diagnosis.explainIndex++
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.BackTab):
+		m.diagnosis.explainIndex--
+	}
+	return m, nil
+}
+
+// renderExplain renders the article of one issue type on the current tab
+func (m Model) renderExplain() string {
+	v := m.diagnosis
+	articles := kb.ForIssues(v.explainIssues())
+	if len(articles) == 0 {
+		return m.renderDiagnosis()
+	}
+	// The diagnosis may have changed since explaining started; wrap around
+	index := (v.explainIndex%len(articles) + len(articles)) % len(articles)
+	a := articles[index]
+
+	width := m.width - 6
+	if width < 40 {
+		width = 40
+	}
+	wrap := lipgloss.NewStyle().Width(width)
+
+	var body strings.Builder
+	body.WriteString(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(a.Title))
+	body.WriteString(" " + mutedStyle.Render(a.ID))
+	body.WriteString("\n\n")
+	body.WriteString(wrap.Render(a.Summary))
+	body.WriteString("\n")
+
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Common causes", a.Causes},
+		{"Debugging steps", a.Steps},
+		{"Links", a.Links},
+	}
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		body.WriteString("\n" + lipgloss.NewStyle().Bold(true).Render(section.title) + "\n")
+		for _, item := range section.items {
+			body.WriteString(wrap.Render("  • "+item) + "\n")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("🔍 pod-doctor - Explain"))
+	b.WriteString("\n")
+	b.WriteString(subtitleStyle.Render(fmt.Sprintf("%s/%s • issue type %d/%d",
+		v.result.Pod.Namespace, v.result.Pod.Name, index+1, len(articles))))
+	b.WriteString("\n\n")
+	b.WriteString(panelStyle.Render(strings.TrimRight(body.String(), "\n")))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s: next issue type • %s: previous • %s or %s: close • %s: quit",
+		m.keys.Tab.Help().Key, m.keys.BackTab.Help().Key, m.keys.Explain.Help().Key, m.keys.Back.Help().Key, m.keys.Quit.Help().Key)))
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpViews describes each view in the help overlay
+var helpViews = []struct{ name, description string }{
+	{"Namespaces", "pick a namespace, or press a for the pods of all of them"},
+	{"Pods", "status, readiness, restarts, age and the health score of pods diagnosed so far"},
+	{"Diagnosis", "a pod's issues, warning events and recommendations; tab through its containers"},
+	{"Logs", "container logs with error lines highlighted, optionally followed live"},
+	{"Top", "unhealthy pods of every namespace, worst first, refreshed live (pod-doctor top)"},
+	{"Contexts", "kubeconfig contexts; pick one to switch clusters (K)"},
+}
+
+// helpCategories describes the issue categories shown in diagnoses
+var helpCategories = []struct{ name, description string }{
+	{"container", "crashes, exit codes, image pulls"},
+	{"probes", "liveness, readiness and startup probes"},
+	{"resources", "requests, limits, OOM kills, throttling"},
+	{"scheduling", "why a pod is not placed on a node"},
+	{"node", "node conditions and pressure"},
+	{"network", "services, endpoints and DNS"},
+	{"storage", "volumes, PVCs and mounts"},
+	{"workload", "rollouts and replicas of the owner"},
+	{"security", "image provenance, capabilities and sysctls"},
+	{"conformance", "required labels and annotations"},
+	{"serviceaccount", "projected token audiences and lifetimes"},
+	{"spec", "environment, annotation and object sizes"},
+	{"logs", "error patterns in recent logs"},
+	{"plugin", "checks from analyzer plugins"},
+}
+
+// renderHelp renders the help overlay: every key binding, generated from the
+// KeyMap, next to the views, severity icons and issue categories
+func (m Model) renderHelp() string {
+	heading := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+
+	var keys strings.Builder
+	keys.WriteString(heading.Render("Keys"))
+	keys.WriteString("\n")
+	for _, section := range m.keys.helpSections() {
+		keys.WriteString("\n" + lipgloss.NewStyle().Bold(true).Render(section.title) + "\n")
+		for _, b := range section.bindings {
+			h := b.Help()
+			keys.WriteString(fmt.Sprintf("  %-12s %s\n", h.Key, mutedStyle.Render(h.Desc)))
+		}
+	}
+
+	var about strings.Builder
+	about.WriteString(heading.Render("Views"))
+	about.WriteString("\n")
+	for _, v := range helpViews {
+		about.WriteString(fmt.Sprintf("  %-11s %s\n", v.name, mutedStyle.Render(v.description)))
+	}
+
+	about.WriteString("\n" + heading.Render("Severity") + "\n")
+	about.WriteString(fmt.Sprintf("  %s %s\n", SeverityIcon("critical"), mutedStyle.Render("critical: failing now, or about to")))
+	about.WriteString(fmt.Sprintf("  %s %s\n", SeverityIcon("warning"), mutedStyle.Render("warning: degraded or risky configuration")))
+	about.WriteString(fmt.Sprintf("  %s %s\n", SeverityIcon("info"), mutedStyle.Render("info: worth knowing, no action needed")))
+	about.WriteString(fmt.Sprintf("  %s %s %s\n", StatusIcon(true), StatusIcon(false), mutedStyle.Render("pod or container healthy / unhealthy")))
+
+	about.WriteString("\n" + heading.Render("Categories") + "\n")
+	for _, c := range helpCategories {
+		about.WriteString(fmt.Sprintf("  %-11s %s\n", c.name, mutedStyle.Render(c.description)))
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, keys.String(), "    ", about.String())
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("🔍 pod-doctor - Help"))
+	b.WriteString("\n")
+	b.WriteString(panelStyle.Render(body))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s or %s: close help • %s: quit",
+		m.keys.Help.Help().Key, m.keys.Back.Help().Key, m.keys.Quit.Help().Key)))
+	return b.String()
+}
+
+// handleHelpKeys handles keys while the help overlay is shown
+func (m Model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Help), key.Matches(msg, m.keys.Back):
+		m.showHelp = false
+	}
+	return m, nil
+}
+
+// onboardingHint returns the first-run tip for the current view, built from
+// the KeyMap so it names the actual bindings
+func (m Model) onboardingHint() string {
+	if !m.onboarding {
+		return ""
+	}
+
+	k := m.keys
+	var tip string
+	switch m.view {
+	case ViewNamespaceList:
+		tip = fmt.Sprintf("Welcome! %s to move, %s to open a namespace, %s for all namespaces",
+			keyName(k.Up, k.Down), keyName(k.Enter), keyName(k.AllNamespaces))
+	case ViewPodList:
+		tip = fmt.Sprintf("Next: %s to diagnose a pod, %s to filter, %s to jump to another namespace",
+			keyName(k.Enter), keyName(k.Filter), keyName(k.Jump))
+	case ViewDiagnosis:
+		tip = fmt.Sprintf("That's the tour: %s walks through containers, %s opens logs, %s goes back",
+			keyName(k.Tab), keyName(k.Logs), keyName(k.Back))
+	default:
+		return ""
+	}
+	return "\n" + lipgloss.NewStyle().Foreground(highlightColor).Render(
+		fmt.Sprintf("💡 %s • %s shows every key", tip, keyName(k.Help)))
+}
+
+// keyName returns the keys of bindings as shown in help, e.g. "↑/k"
+func keyName(bindings ...key.Binding) string {
+	names := make([]string, len(bindings))
+	for i, b := range bindings {
+		names[i] = b.Help().Key
+	}
+	return strings.Join(names, " ")
+}
+
+// onboardingMarker is the file recording that the first-run tips were seen
+func onboardingMarker() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".pod-doctor", "tui-onboarded")
+}
+
+// needsOnboarding reports whether the first-run tips should be shown
+func needsOnboarding() bool {
+	path := onboardingMarker()
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return os.IsNotExist(err)
+}
+
+// completeOnboarding records that the tips were seen, so later runs start
+// without them. Failing to write the marker only means the tips show again.
+func completeOnboarding() tea.Cmd {
+	return func() tea.Msg {
+		if path := onboardingMarker(); path != "" {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+				_ = os.WriteFile(path, nil, 0o644)
+			}
+		}
+		return nil
+	}
+}
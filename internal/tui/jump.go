@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxJumpMatches bounds the namespaces listed under the jump box
+const maxJumpMatches = 5
+
+// openJump shows the namespace jump box, which switches the pod list to
+// another namespace without going back to the namespace list
+func (m Model) openJump() (tea.Model, tea.Cmd) {
+	m.jumping = true
+	m.jumpInput.SetValue("")
+	m.jumpInput.Focus()
+	m.jumpMatches = fuzzyFilter(m.namespaces.items, "", maxJumpMatches)
+	m.jumpCursor = 0
+	return m, textinput.Blink
+}
+
+// handleJumpInput handles input while the namespace jump box is open
+func (m Model) handleJumpInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.jumping = false
+		m.jumpInput.Blur()
+		return m, nil
+
+	case "enter":
+		m.jumping = false
+		m.jumpInput.Blur()
+		if m.jumpCursor >= len(m.jumpMatches) {
+			return m, nil
+		}
+		m.selectedNS = m.jumpMatches[m.jumpCursor]
+		m.allNamespaces = false
+		return m.showPods()
+
+	case "up", "ctrl+p":
+		if m.jumpCursor > 0 {
+			m.jumpCursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+n", "tab":
+		if m.jumpCursor < len(m.jumpMatches)-1 {
+			m.jumpCursor++
+		}
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		m.jumpInput, cmd = m.jumpInput.Update(msg)
+		m.jumpMatches = fuzzyFilter(m.namespaces.items, m.jumpInput.Value(), maxJumpMatches)
+		m.jumpCursor = 0
+		return m, cmd
+	}
+}
+
+// renderJumpBox renders the jump input with the best matching namespaces
+func (m Model) renderJumpBox() string {
+	var b strings.Builder
+
+	b.WriteString(filterPromptStyle.Render("Jump to namespace: "))
+	b.WriteString(m.jumpInput.View())
+	b.WriteString("\n")
+
+	if len(m.jumpMatches) == 0 {
+		b.WriteString(mutedStyle.Render("  No matching namespaces"))
+		b.WriteString("\n")
+	}
+	for i, ns := range m.jumpMatches {
+		if i == m.jumpCursor {
+			b.WriteString(cursorStyle.Render("▸ "))
+			b.WriteString(selectedItemStyle.Render(ns))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(listItemStyle.Render(ns))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("  ↑/↓: choose • enter: jump • esc: cancel (%d namespaces)", len(m.namespaces.items))))
+	b.WriteString("\n\n")
+
+	return b.String()
+}
+
+// fuzzyFilter returns up to limit items matching pattern, best match first.
+// An empty pattern matches every item in its original order.
+func fuzzyFilter(items []string, pattern string, limit int) []string {
+	type match struct {
+		item  string
+		score int
+	}
+	var matches []match
+	for _, item := range items {
+		if score, ok := fuzzyScore(pattern, item); ok {
+			matches = append(matches, match{item, score})
+		}
+	}
+
+	// Shorter names win ties, so "prod" ranks above "prod-canary" for "prod"
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].item) < len(matches[j].item)
+	})
+
+	var result []string
+	for i := 0; i < len(matches) && i < limit; i++ {
+		result = append(result, matches[i].item)
+	}
+	return result
+}
+
+// fuzzyScore reports whether the characters of pattern appear in s in
+// order, ignoring case, and scores the match: consecutive characters and
+// characters starting a word (after -, _ or .) score higher, so "pw"
+// prefers "payments-web" over "apps-workers".
+func fuzzyScore(pattern, s string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	s = strings.ToLower(s)
+
+	score := 0
+	p := 0
+	prev := -2
+	for i := 0; i < len(s) && p < len(pattern); i++ {
+		if s[i] != pattern[p] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 5
+		}
+		if i == 0 || strings.ContainsRune("-_.", rune(s[i-1])) {
+			score += 10
+		}
+		prev = i
+		p++
+	}
+	if p < len(pattern) {
+		return 0, false
+	}
+	return score, true
+}
@@ -4,17 +4,35 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines the key bindings for the TUI
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Enter    key.Binding
-	Back     key.Binding
-	Quit     key.Binding
-	Filter   key.Binding
-	Refresh  key.Binding
-	Help     key.Binding
-	Tab      key.Binding
-	PageUp   key.Binding
-	PageDown key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Enter     key.Binding
+	Back      key.Binding
+	Quit      key.Binding
+	Filter    key.Binding
+	Refresh   key.Binding
+	Help      key.Binding
+	Tab       key.Binding
+	BackTab   key.Binding
+	PageUp    key.Binding
+	PageDown  key.Binding
+	Logs      key.Binding
+	Container key.Binding
+	Previous  key.Binding
+	Follow    key.Binding
+	NextMatch key.Binding
+	Events    key.Binding
+	Debug     key.Binding
+	Explain   key.Binding
+	Delete    key.Binding
+	Restart   key.Binding
+	Compare   key.Binding
+	Sort      key.Binding
+	SortOrder key.Binding
+
+	AllNamespaces key.Binding
+	Jump          key.Binding
+	Context       key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -54,7 +72,11 @@ func DefaultKeyMap() KeyMap {
 		),
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
-			key.WithHelp("tab", "switch view"),
+			key.WithHelp("tab", "next container"),
+		),
+		BackTab: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous container"),
 		),
 		PageUp: key.NewBinding(
 			key.WithKeys("pgup", "ctrl+u"),
@@ -64,19 +86,101 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("pgdown", "ctrl+d"),
 			key.WithHelp("pgdn", "page down"),
 		),
+		Logs: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "logs"),
+		),
+		Container: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "next container"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "previous logs"),
+		),
+		Follow: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "follow"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		Events: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "expand events"),
+		),
+		Debug: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "debug shell"),
+		),
+		Explain: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "explain issues"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "delete pod"),
+		),
+		Restart: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "restart workload"),
+		),
+		Compare: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "compare with a healthy replica"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort by next column"),
+		),
+		SortOrder: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "reverse sort"),
+		),
+		AllNamespaces: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "all namespaces"),
+		),
+		Jump: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "jump to namespace"),
+		),
+		Context: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "switch kube context"),
+		),
+	}
+}
+
+// helpSection is a titled group of key bindings in the help overlay
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// helpSections groups every binding for the help overlay and FullHelp
+func (k KeyMap) helpSections() []helpSection {
+	return []helpSection{
+		{"Navigation", []key.Binding{k.Up, k.Down, k.PageUp, k.PageDown}},
+		{"Lists", []key.Binding{k.Enter, k.Back, k.Filter, k.Sort, k.SortOrder, k.Refresh}},
+		{"Namespaces", []key.Binding{k.AllNamespaces, k.Jump, k.Context}},
+		{"Diagnosis", []key.Binding{k.Tab, k.BackTab, k.Events, k.Debug, k.Explain, k.Compare, k.Delete, k.Restart}},
+		{"Logs", []key.Binding{k.Logs, k.Container, k.Previous, k.Follow, k.NextMatch}},
+		{"General", []key.Binding{k.Help, k.Quit}},
 	}
 }
 
 // ShortHelp returns the short help text
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Enter, k.Filter, k.Back, k.Quit}
+	return []key.Binding{k.Up, k.Down, k.Enter, k.Filter, k.Back, k.Help, k.Quit}
 }
 
 // FullHelp returns the full help text
 func (k KeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Up, k.Down, k.PageUp, k.PageDown},
-		{k.Enter, k.Back, k.Filter, k.Refresh},
-		{k.Help, k.Quit},
+	var groups [][]key.Binding
+	for _, section := range k.helpSections() {
+		groups = append(groups, section.bindings)
 	}
+	return groups
 }
@@ -0,0 +1,296 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+)
+
+const (
+	// logTailLines bounds how much history the log viewer fetches
+	logTailLines = 1000
+
+	// logRefreshInterval is how often the log viewer polls while following
+	logRefreshInterval = 2 * time.Second
+)
+
+// logViewer holds the state of the log viewer pane
+type logViewer struct {
+	session    int // distinguishes ticks of the current viewer from earlier ones
+	returnView View
+	namespace  string
+	pod        string
+	containers []string
+	container  int
+	previous   bool
+	follow     bool
+	lines      []string
+	matches    []domain.Severity // severity of the log pattern matched by each line, empty if none
+	err        error
+	viewport   viewport.Model
+
+	// ctx is canceled when the viewer closes, along with its log fetches
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type logsLoadedMsg struct {
+	session   int
+	container string
+	previous  bool
+	content   string
+	err       error
+}
+
+type logTickMsg struct {
+	session int
+}
+
+// openLogs switches to the log viewer for a pod, showing the container at
+// the given index first
+func (m Model) openLogs(namespace, pod string, containers []string, container int) (tea.Model, tea.Cmd) {
+	if len(containers) == 0 {
+		return m, nil
+	}
+
+	m.logs.close()
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.logs = logViewer{
+		session:    m.logs.session + 1,
+		returnView: m.view,
+		namespace:  namespace,
+		pod:        pod,
+		containers: containers,
+		container:  container,
+		follow:     true,
+		viewport:   viewport.New(m.width, m.logViewportHeight()),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	m.view = ViewLogs
+
+	return m, tea.Batch(m.fetchLogs(), m.logTick())
+}
+
+// close cancels the log fetches of the viewer
+func (l *logViewer) close() {
+	if l.cancel != nil {
+		l.cancel()
+		l.cancel = nil
+	}
+}
+
+// handleLogKeys handles key presses in the log viewer
+func (m Model) handleLogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.logs
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		l.close()
+		m.view = l.returnView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		l.follow = false
+		l.viewport.ScrollUp(1)
+
+	case key.Matches(msg, m.keys.Down):
+		l.viewport.ScrollDown(1)
+
+	case key.Matches(msg, m.keys.PageUp):
+		l.follow = false
+		l.viewport.PageUp()
+
+	case key.Matches(msg, m.keys.PageDown):
+		l.viewport.PageDown()
+
+	case key.Matches(msg, m.keys.Container):
+		if len(l.containers) > 1 {
+			l.container = (l.container + 1) % len(l.containers)
+			l.lines, l.matches, l.err = nil, nil, nil
+			l.viewport.SetContent("")
+			return m, m.fetchLogs()
+		}
+
+	case key.Matches(msg, m.keys.Previous):
+		l.previous = !l.previous
+		// Logs of a terminated container do not change, so stop following
+		if l.previous {
+			l.follow = false
+		}
+		l.lines, l.matches, l.err = nil, nil, nil
+		l.viewport.SetContent("")
+		return m, m.fetchLogs()
+
+	case key.Matches(msg, m.keys.Follow):
+		l.follow = !l.follow
+		if l.follow {
+			l.viewport.GotoBottom()
+			return m, m.fetchLogs()
+		}
+
+	case key.Matches(msg, m.keys.NextMatch):
+		l.follow = false
+		l.jumpToNextMatch()
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, m.fetchLogs()
+	}
+
+	return m, nil
+}
+
+// handleLogsLoaded updates the viewer with freshly fetched logs
+func (m Model) handleLogsLoaded(msg logsLoadedMsg) Model {
+	l := &m.logs
+	if msg.session != l.session || msg.container != l.containers[l.container] || msg.previous != l.previous {
+		// Stale response for a viewer or container that is no longer shown
+		return m
+	}
+
+	l.err = msg.err
+	if msg.err != nil {
+		l.lines, l.matches = nil, nil
+		l.viewport.SetContent("")
+		return m
+	}
+
+	l.lines = strings.Split(strings.TrimRight(msg.content, "\n"), "\n")
+	l.matches = make([]domain.Severity, len(l.lines))
+	for i, line := range l.lines {
+		if severity, ok := m.analyzer.MatchLogLine(line); ok {
+			l.matches[i] = severity
+		}
+	}
+
+	l.viewport.SetContent(l.render())
+	if l.follow {
+		l.viewport.GotoBottom()
+	}
+	return m
+}
+
+// handleLogTick refreshes followed logs and schedules the next poll
+func (m Model) handleLogTick(msg logTickMsg) (tea.Model, tea.Cmd) {
+	if msg.session != m.logs.session || m.view != ViewLogs {
+		return m, nil
+	}
+	if m.logs.follow && !m.logs.previous {
+		return m, tea.Batch(m.fetchLogs(), m.logTick())
+	}
+	return m, m.logTick()
+}
+
+// jumpToNextMatch scrolls to the next line matching a log pattern, wrapping
+// around to the top
+func (l *logViewer) jumpToNextMatch() {
+	n := len(l.matches)
+	for i := 1; i <= n; i++ {
+		idx := (l.viewport.YOffset + i) % n
+		if l.matches[idx] != "" {
+			l.viewport.SetYOffset(idx)
+			return
+		}
+	}
+}
+
+// matchCount returns the number of lines matching a log pattern
+func (l *logViewer) matchCount() int {
+	count := 0
+	for _, severity := range l.matches {
+		if severity != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// render highlights lines matching the analyzer's log patterns
+func (l *logViewer) render() string {
+	var b strings.Builder
+	for i, line := range l.lines {
+		switch l.matches[i] {
+		case domain.SeverityCritical:
+			b.WriteString(SeverityIcon(string(l.matches[i])) + " " + criticalStyle.Render(line))
+		case domain.SeverityWarning:
+			b.WriteString(SeverityIcon(string(l.matches[i])) + " " + warningStyle.Render(line))
+		case domain.SeverityInfo:
+			b.WriteString(SeverityIcon(string(l.matches[i])) + " " + line)
+		default:
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// logViewportHeight returns the number of log lines that fit on screen
+func (m Model) logViewportHeight() int {
+	h := m.height - 7
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+func (m Model) fetchLogs() tea.Cmd {
+	l := m.logs
+	container := l.containers[l.container]
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(l.ctx, 10*time.Second)
+		defer cancel()
+
+		content, err := m.client.GetPodLogs(ctx, l.namespace, l.pod, container, logTailLines, l.previous)
+		return logsLoadedMsg{session: l.session, container: container, previous: l.previous, content: content, err: err}
+	}
+}
+
+func (m Model) logTick() tea.Cmd {
+	session := m.logs.session
+	return tea.Tick(logRefreshInterval, func(time.Time) tea.Msg {
+		return logTickMsg{session: session}
+	})
+}
+
+func (m Model) renderLogs() string {
+	var b strings.Builder
+	l := m.logs
+
+	b.WriteString(m.renderTitle("🔍 pod-doctor - Logs"))
+	b.WriteString("\n")
+	b.WriteString(subtitleStyle.Render(fmt.Sprintf("%s/%s", l.namespace, l.pod)))
+	b.WriteString("\n")
+
+	onOff := func(v bool) string {
+		if v {
+			return "on"
+		}
+		return "off"
+	}
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("Container: %s (%d/%d) | Previous: %s | Follow: %s | Matches: %d",
+		l.containers[l.container], l.container+1, len(l.containers),
+		onOff(l.previous), onOff(l.follow), l.matchCount())))
+	b.WriteString("\n\n")
+
+	switch {
+	case l.err != nil:
+		b.WriteString(criticalStyle.Render(fmt.Sprintf("  Cannot fetch logs: %v", l.err)))
+		b.WriteString("\n")
+	case l.lines == nil:
+		b.WriteString(mutedStyle.Render("  Loading logs..."))
+		b.WriteString("\n")
+	default:
+		b.WriteString(l.viewport.View())
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓: scroll • c: container • p: previous • f: follow • n: next match • r: refresh • esc: back • ?: help • q: quit"))
+
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// namespaceList holds the state of the namespace list
+type namespaceList struct {
+	items  []string
+	cursor int
+}
+
+type namespacesLoadedMsg struct {
+	namespaces []string
+	err        error
+}
+
+// handleNamespaceKeys handles key presses in the namespace list
+func (m Model) handleNamespaceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.namespaces
+	if delta, ok := m.cursorMove(msg); ok {
+		l.cursor = moveCursor(l.cursor, delta, len(l.items))
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Enter):
+		if l.cursor < len(l.items) {
+			m.selectedNS = l.items[l.cursor]
+			return m.showPods()
+		}
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.loading = true
+		m.loadingMessage = "Loading namespaces..."
+		m.view = ViewLoading
+		return m, tea.Batch(m.spinner.Tick, m.loadNamespaces())
+
+	case key.Matches(msg, m.keys.AllNamespaces):
+		return m.handleAllNamespaces()
+
+	case key.Matches(msg, m.keys.Jump):
+		return m.openJump()
+
+	case key.Matches(msg, m.keys.Context):
+		return m.openContexts()
+	}
+	return m, nil
+}
+
+// handleNamespacesLoaded shows the loaded namespaces
+func (m Model) handleNamespacesLoaded(msg namespacesLoadedMsg) Model {
+	if m.allNamespaces && m.view != ViewNamespaceList {
+		// Loaded in the background for the jump box; the pod list stays
+		m.namespaces.items = msg.namespaces
+		return m
+	}
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m
+	}
+	m.namespaces = namespaceList{items: msg.namespaces}
+	m.view = ViewNamespaceList
+	return m
+}
+
+func (m Model) loadNamespaces() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.requestContext(10 * time.Second)
+		defer cancel()
+
+		namespaces, err := m.client.GetNamespaces(ctx)
+		return namespacesLoadedMsg{namespaces: namespaces, err: err}
+	}
+}
+
+func (m Model) renderNamespaceList() string {
+	var b strings.Builder
+	l := m.namespaces
+
+	b.WriteString(m.renderTitle("🔍 pod-doctor"))
+	b.WriteString("\n")
+	b.WriteString(subtitleStyle.Render("Select a namespace"))
+	b.WriteString("\n\n")
+	if m.jumping {
+		b.WriteString(m.renderJumpBox())
+	}
+
+	// Calculate visible range
+	visibleHeight := m.height - 10
+	if visibleHeight < 5 {
+		visibleHeight = 5
+	}
+	start, end := visibleRange(l.cursor, len(l.items), visibleHeight)
+
+	for i := start; i < end; i++ {
+		ns := l.items[i]
+		if i == l.cursor {
+			b.WriteString(cursorStyle.Render("▸ "))
+			b.WriteString(selectedItemStyle.Render(ns))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(listItemStyle.Render(ns))
+		}
+		b.WriteString("\n")
+	}
+
+	// Scroll indicator
+	if len(l.items) > visibleHeight {
+		b.WriteString(fmt.Sprintf("\n%s", mutedStyle.Render(fmt.Sprintf("  %d/%d namespaces", l.cursor+1, len(l.items)))))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: select • a: all namespaces • :: jump • K: context • ?: help • q: quit"))
+	b.WriteString(m.onboardingHint())
+
+	return b.String()
+}
@@ -0,0 +1,421 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podSort is a column the pod list can be sorted by
+type podSort int
+
+const (
+	sortNone podSort = iota // the order the API returned
+	sortName
+	sortStatus
+	sortRestarts
+	sortAge
+)
+
+// podSortColumns names the header column of each sort
+var podSortColumns = map[podSort]string{
+	sortName:     "NAME",
+	sortStatus:   "STATUS",
+	sortRestarts: "RESTARTS",
+	sortAge:      "AGE",
+}
+
+// podList holds the state of the pod list
+type podList struct {
+	items       []PodItem
+	filtered    []PodItem // items matching filter, as shown
+	filter      string
+	filtering   bool // the filter input has focus
+	filterInput textinput.Model
+	sortBy      podSort
+	descending  bool
+	cursor      int
+}
+
+type podsLoadedMsg struct {
+	pods []PodItem
+	err  error
+}
+
+// selected returns the pod under the cursor
+func (l podList) selected() (PodItem, bool) {
+	if l.cursor < len(l.filtered) {
+		return l.filtered[l.cursor], true
+	}
+	return PodItem{}, false
+}
+
+// applyFilter filters the pod list and sorts the result
+func (l *podList) applyFilter() {
+	l.cursor = 0
+	filter := strings.ToLower(l.filter)
+	l.filtered = nil
+	for _, pod := range l.items {
+		if filter == "" ||
+			strings.Contains(strings.ToLower(pod.Name), filter) ||
+			strings.Contains(strings.ToLower(pod.Namespace), filter) ||
+			strings.Contains(strings.ToLower(pod.Status), filter) ||
+			strings.Contains(strings.ToLower(pod.Node), filter) {
+			l.filtered = append(l.filtered, pod)
+		}
+	}
+	l.sortShown()
+}
+
+// cycleSort sorts by the next column, in its most useful direction first:
+// names and statuses A to Z, the most restarts and the youngest pods first.
+// After the last column the list returns to the API's order.
+func (l *podList) cycleSort() {
+	l.sortBy = (l.sortBy + 1) % (sortAge + 1)
+	l.descending = l.sortBy == sortRestarts
+	l.resort()
+}
+
+// reverseSort flips the direction of the current sort
+func (l *podList) reverseSort() {
+	if l.sortBy == sortNone {
+		return
+	}
+	l.descending = !l.descending
+	l.resort()
+}
+
+// resort sorts the shown pods again, keeping the cursor on the selected pod
+func (l *podList) resort() {
+	selected, ok := l.selected()
+	if l.sortBy == sortNone {
+		l.applyFilter()
+	} else {
+		l.sortShown()
+	}
+	if !ok {
+		return
+	}
+	for i, pod := range l.filtered {
+		if pod.Namespace == selected.Namespace && pod.Name == selected.Name {
+			l.cursor = i
+			return
+		}
+	}
+}
+
+// sortShown orders the shown pods by the sort column, then by namespace and name
+func (l *podList) sortShown() {
+	if l.sortBy == sortNone {
+		return
+	}
+	sort.SliceStable(l.filtered, func(i, j int) bool {
+		a, b := l.filtered[i], l.filtered[j]
+		var cmp int
+		switch l.sortBy {
+		case sortStatus:
+			cmp = strings.Compare(a.Status, b.Status)
+		case sortRestarts:
+			cmp = int(a.Restarts) - int(b.Restarts)
+		case sortAge:
+			// Older pods have the larger age
+			cmp = b.Created.Compare(a.Created)
+		}
+		if cmp == 0 {
+			cmp = strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+		}
+		if l.descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+// columnHeader returns a column's header, marked with the sort direction if
+// the list is sorted by it
+func (l podList) columnHeader(column string) string {
+	if l.sortBy == sortNone || podSortColumns[l.sortBy] != column {
+		return column
+	}
+	if l.descending {
+		return column + " ▼"
+	}
+	return column + " ▲"
+}
+
+// clearFilter shows every pod again
+func (l *podList) clearFilter() {
+	l.filter = ""
+	l.filterInput.SetValue("")
+	l.applyFilter()
+}
+
+// handlePodListKeys handles key presses in the pod list
+func (m Model) handlePodListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.pods
+	if delta, ok := m.cursorMove(msg); ok {
+		l.cursor = moveCursor(l.cursor, delta, len(l.filtered))
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Filter):
+		l.filtering = true
+		l.filterInput.Focus()
+		return m, textinput.Blink
+
+	case key.Matches(msg, m.keys.Sort):
+		l.cycleSort()
+
+	case key.Matches(msg, m.keys.SortOrder):
+		l.reverseSort()
+
+	case key.Matches(msg, m.keys.Back):
+		m.view = ViewNamespaceList
+		m.allNamespaces = false
+		l.clearFilter()
+
+	case key.Matches(msg, m.keys.Enter):
+		if pod, ok := l.selected(); ok {
+			return m.diagnosePod(pod)
+		}
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.loading = true
+		m.loadingMessage = "Loading pods..."
+		m.view = ViewLoading
+		return m, tea.Batch(m.spinner.Tick, m.loadPods())
+
+	case key.Matches(msg, m.keys.Logs):
+		if pod, ok := l.selected(); ok {
+			return m.openLogs(pod.Namespace, pod.Name, pod.Containers, 0)
+		}
+
+	case key.Matches(msg, m.keys.AllNamespaces):
+		return m.handleAllNamespaces()
+
+	case key.Matches(msg, m.keys.Jump):
+		return m.openJump()
+
+	case key.Matches(msg, m.keys.Context):
+		return m.openContexts()
+	}
+	return m, nil
+}
+
+// handleFilterInput handles input when filtering
+func (m Model) handleFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.pods
+	switch msg.String() {
+	case "esc":
+		l.filtering = false
+		l.clearFilter()
+		return m, nil
+
+	case "enter":
+		l.filtering = false
+		l.filter = l.filterInput.Value()
+		l.applyFilter()
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		l.filterInput, cmd = l.filterInput.Update(msg)
+		l.filter = l.filterInput.Value()
+		l.applyFilter()
+		return m, cmd
+	}
+}
+
+// handleAllNamespaces switches the pod list between the selected namespace
+// and every namespace
+func (m Model) handleAllNamespaces() (tea.Model, tea.Cmd) {
+	m.allNamespaces = !m.allNamespaces
+	if !m.allNamespaces && m.selectedNS == "" {
+		// Started in all-namespaces mode, so there is no namespace to return to
+		m.view = ViewNamespaceList
+		return m, nil
+	}
+	return m.showPods()
+}
+
+// showPods loads a fresh, unfiltered pod list for the selected namespace,
+// or for every namespace in all-namespaces mode
+func (m Model) showPods() (tea.Model, tea.Cmd) {
+	m.pods.clearFilter()
+	m.loading = true
+	m.loadingMessage = "Loading pods..."
+	m.view = ViewLoading
+	return m, tea.Batch(m.spinner.Tick, m.loadPods())
+}
+
+// handlePodsLoaded shows the loaded pods, keeping the current filter
+func (m Model) handlePodsLoaded(msg podsLoadedMsg) Model {
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m
+	}
+	m.pods.items = msg.pods
+	m.pods.applyFilter()
+	m.view = ViewPodList
+	return m
+}
+
+// loadPods lists the pods of the selected namespace, or of every namespace
+// in all-namespaces mode
+func (m Model) loadPods() tea.Cmd {
+	allNamespaces, namespace := m.allNamespaces, m.selectedNS
+	return func() tea.Msg {
+		ctx, cancel := m.requestContext(10 * time.Second)
+		defer cancel()
+
+		var list *corev1.PodList
+		var err error
+		if allNamespaces {
+			list, err = m.client.ListAllPods(ctx)
+		} else {
+			list, err = m.client.ListPods(ctx, namespace, "")
+		}
+		if err != nil {
+			return podsLoadedMsg{err: err}
+		}
+
+		var pods []PodItem
+		for i := range list.Items {
+			pods = append(pods, newPodItem(&list.Items[i]))
+		}
+
+		return podsLoadedMsg{pods: pods}
+	}
+}
+
+// newPodItem summarizes a pod for a list
+func newPodItem(p *corev1.Pod) PodItem {
+	var restarts int32
+	ready := 0
+	total := len(p.Spec.Containers)
+	for _, cs := range p.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+		if cs.Ready {
+			ready++
+		}
+	}
+
+	var containers []string
+	for _, c := range p.Spec.Containers {
+		containers = append(containers, c.Name)
+	}
+
+	return PodItem{
+		Name:       p.Name,
+		Namespace:  p.Namespace,
+		Status:     string(p.Status.Phase),
+		Ready:      fmt.Sprintf("%d/%d", ready, total),
+		Restarts:   restarts,
+		Age:        formatAge(time.Since(p.CreationTimestamp.Time)),
+		Created:    p.CreationTimestamp.Time,
+		Node:       p.Spec.NodeName,
+		Containers: containers,
+	}
+}
+
+func (m Model) renderPodList() string {
+	var b strings.Builder
+	l := m.pods
+
+	b.WriteString(m.renderTitle("🔍 pod-doctor"))
+	b.WriteString("\n")
+	namespace := m.selectedNS
+	if m.allNamespaces {
+		namespace = "all namespaces"
+	}
+	b.WriteString(subtitleStyle.Render(fmt.Sprintf("Namespace: %s", namespaceBadge.Render(namespace))))
+	b.WriteString("\n")
+
+	// Filter bar
+	if m.jumping {
+		b.WriteString(m.renderJumpBox())
+	} else if l.filtering {
+		b.WriteString(filterPromptStyle.Render("Filter: "))
+		b.WriteString(l.filterInput.View())
+		b.WriteString("\n\n")
+	} else if l.filter != "" {
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("Filter: %s", l.filter)))
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString("\n")
+	}
+
+	if len(l.filtered) == 0 {
+		b.WriteString(mutedStyle.Render("  No pods found"))
+		b.WriteString("\n")
+	} else {
+		// Header
+		name, status := l.columnHeader("NAME"), l.columnHeader("STATUS")
+		restarts, age := l.columnHeader("RESTARTS"), l.columnHeader("AGE")
+		header := fmt.Sprintf("  %-40s %-12s %-8s %-10s %-8s %-6s", name, status, "READY", restarts, age, "SCORE")
+		if m.allNamespaces {
+			header = fmt.Sprintf("  %-20s %-40s %-12s %-8s %-10s %-8s %-6s", "NAMESPACE", name, status, "READY", restarts, age, "SCORE")
+		}
+		b.WriteString(mutedStyle.Render(header))
+		b.WriteString("\n")
+
+		// Calculate visible range
+		visibleHeight := m.height - 14
+		if visibleHeight < 5 {
+			visibleHeight = 5
+		}
+		start, end := visibleRange(l.cursor, len(l.filtered), visibleHeight)
+
+		for i := start; i < end; i++ {
+			b.WriteString(m.renderPodLine(l.filtered[i], i == l.cursor))
+			b.WriteString("\n")
+		}
+
+		// Scroll indicator
+		if len(l.filtered) > visibleHeight {
+			b.WriteString(fmt.Sprintf("\n%s", mutedStyle.Render(fmt.Sprintf("  %d/%d pods", l.cursor+1, len(l.filtered)))))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: diagnose • l: logs • /: filter • s/S: sort • a: all namespaces • :: jump • K: context • esc: back • r: refresh • ?: help • q: quit"))
+	b.WriteString(m.onboardingHint())
+
+	return b.String()
+}
+
+func (m Model) renderPodLine(pod PodItem, selected bool) string {
+	// Status icon
+	icon := StatusIcon(pod.Status == "Running" && pod.Restarts < 5)
+
+	// Truncate name if needed
+	name := pod.Name
+	if len(name) > 38 {
+		name = name[:35] + "..."
+	}
+
+	score := "-"
+	if v, ok := m.scores[podKey(pod.Namespace, pod.Name)]; ok {
+		score = fmt.Sprintf("%d", v)
+	}
+
+	line := fmt.Sprintf("%s %-38s %-12s %-8s %-10d %-8s %-6s",
+		icon, name, pod.Status, pod.Ready, pod.Restarts, pod.Age, score)
+	if m.allNamespaces {
+		line = fmt.Sprintf("%s %-20s %-38s %-12s %-8s %-10d %-8s %-6s",
+			icon, truncate(pod.Namespace, 20), name, pod.Status, pod.Ready, pod.Restarts, pod.Age, score)
+	}
+
+	if selected {
+		return cursorStyle.Render("▸") + " " + selectedItemStyle.Render(line)
+	}
+	return "  " + listItemStyle.Render(line)
+}
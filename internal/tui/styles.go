@@ -94,22 +94,86 @@ var (
 	}
 )
 
-// StatusIcon returns an icon for the given status
+// useTextIndicators replaces status icons with text labels like [OK] and [CRIT]
+var useTextIndicators bool
+
+// expandEvents is the initial state of the diagnosis view's event list
+var expandEvents bool
+
+// SetExpandEvents makes the diagnosis view list every warning event instead
+// of grouping them by reason; e toggles it at runtime
+func SetExpandEvents(enabled bool) {
+	expandEvents = enabled
+}
+
+// SetTextIndicators switches status icons to text labels, so states can be
+// told apart without relying on color
+func SetTextIndicators(enabled bool) {
+	useTextIndicators = enabled
+}
+
+// StatusIcon returns an icon for the given status, with the same text
+// labels as statusLabel and SeverityIcon
 func StatusIcon(healthy bool) string {
+	if useTextIndicators {
+		if healthy {
+			return healthyStyle.Render("[OK]  ")
+		}
+		return criticalStyle.Render("[CRIT]")
+	}
 	if healthy {
 		return healthyStyle.Render("●")
 	}
-	return criticalStyle.Render("●")
+	return criticalStyle.Render("✗")
 }
 
 // SeverityIcon returns an icon for the given severity
 func SeverityIcon(severity string) string {
 	switch severity {
 	case "critical":
+		if useTextIndicators {
+			return criticalStyle.Render("[CRIT]")
+		}
 		return criticalStyle.Render("✗")
 	case "warning":
+		if useTextIndicators {
+			return warningStyle.Render("[WARN]")
+		}
 		return warningStyle.Render("!")
 	default:
+		if useTextIndicators {
+			return lipgloss.NewStyle().Foreground(primaryColor).Render("[INFO]")
+		}
 		return lipgloss.NewStyle().Foreground(primaryColor).Render("•")
 	}
 }
+
+// statusLabel returns the marker shown before a diagnosis status
+func statusLabel(healthy, critical bool) string {
+	switch {
+	case !useTextIndicators && healthy:
+		return "●"
+	case !useTextIndicators && critical:
+		return "✗"
+	case !useTextIndicators:
+		return "!"
+	case healthy:
+		return "[OK]"
+	case critical:
+		return "[CRIT]"
+	default:
+		return "[WARN]"
+	}
+}
+
+// ScoreStyle returns the style for a 0-100 health score
+func ScoreStyle(score int) lipgloss.Style {
+	switch {
+	case score >= 80:
+		return healthyStyle
+	case score >= 50:
+		return warningStyle
+	default:
+		return criticalStyle
+	}
+}
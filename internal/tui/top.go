@@ -0,0 +1,318 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultTopInterval is how often the dashboard relists pods
+const DefaultTopInterval = 5 * time.Second
+
+// minRateWindow is how long restarts must be observed before the dashboard
+// rates them over the observed window instead of the pod's lifetime
+const minRateWindow = time.Minute
+
+// topDashboard holds the state of the unhealthy-pod dashboard
+type topDashboard struct {
+	active   bool // started with pod-doctor top
+	interval time.Duration
+	pods     []topPod                 // unhealthy pods, worst first
+	cursor   int                      // selected row
+	seen     map[string]restartSample // first restart count seen per pod, keyed by namespace/name
+	total    int                      // pods in the last refresh
+	updated  time.Time
+	err      error // last refresh error; the previous list stays shown
+}
+
+// topPod is an unhealthy pod on the dashboard
+type topPod struct {
+	PodItem
+	status      domain.PodStatus
+	severity    domain.Severity
+	restartRate float64 // restarts per hour
+}
+
+// restartSample is a pod's restart count at a point in time
+type restartSample struct {
+	restarts int32
+	at       time.Time
+}
+
+type topLoadedMsg struct {
+	pods []corev1.Pod
+	at   time.Time
+	err  error
+}
+
+type topTickMsg struct{}
+
+// loadTop lists the pods of every namespace for the dashboard
+func (m Model) loadTop() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.requestContext(10 * time.Second)
+		defer cancel()
+
+		podList, err := m.client.ListAllPods(ctx)
+		if err != nil {
+			return topLoadedMsg{err: err}
+		}
+		return topLoadedMsg{pods: podList.Items, at: time.Now()}
+	}
+}
+
+// topTick schedules the next dashboard refresh
+func (m Model) topTick() tea.Cmd {
+	return tea.Tick(m.top.interval, func(time.Time) tea.Msg {
+		return topTickMsg{}
+	})
+}
+
+// handleTopKeys handles key presses on the dashboard
+func (m Model) handleTopKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := &m.top
+	if delta, ok := m.cursorMove(msg); ok {
+		t.cursor = moveCursor(t.cursor, delta, len(t.pods))
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Enter):
+		if t.cursor < len(t.pods) {
+			return m.diagnosePod(t.pods[t.cursor].PodItem)
+		}
+
+	case key.Matches(msg, m.keys.Logs):
+		if t.cursor < len(t.pods) {
+			pod := t.pods[t.cursor]
+			return m.openLogs(pod.Namespace, pod.Name, pod.Containers, 0)
+		}
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, m.loadTop()
+
+	case key.Matches(msg, m.keys.Context):
+		return m.openContexts()
+	}
+	return m, nil
+}
+
+// handleTopTick refreshes the dashboard while it is shown; other views keep
+// their data until the user returns to it
+func (m Model) handleTopTick() (tea.Model, tea.Cmd) {
+	if m.view == ViewTop {
+		return m, tea.Batch(m.loadTop(), m.topTick())
+	}
+	return m, m.topTick()
+}
+
+// handleTopLoaded replaces the dashboard's pods, keeping the cursor on the
+// selected pod
+func (m Model) handleTopLoaded(msg topLoadedMsg) Model {
+	t := &m.top
+	if msg.err != nil {
+		t.err = msg.err
+		return m
+	}
+
+	var selected string
+	if t.cursor < len(t.pods) {
+		selected = podKey(t.pods[t.cursor].Namespace, t.pods[t.cursor].Name)
+	}
+
+	if t.seen == nil {
+		t.seen = make(map[string]restartSample)
+	}
+	present := make(map[string]bool, len(msg.pods))
+	var pods []topPod
+	for i := range msg.pods {
+		pod := &msg.pods[i]
+		key := podKey(pod.Namespace, pod.Name)
+		present[key] = true
+		if item, ok := t.rate(pod, msg.at); ok {
+			pods = append(pods, item)
+		}
+	}
+	// Forget deleted pods so the map does not grow forever
+	for key := range t.seen {
+		if !present[key] {
+			delete(t.seen, key)
+		}
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		a, b := pods[i], pods[j]
+		if severityRank(a.severity) != severityRank(b.severity) {
+			return severityRank(a.severity) < severityRank(b.severity)
+		}
+		if a.restartRate != b.restartRate {
+			return a.restartRate > b.restartRate
+		}
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return podKey(a.Namespace, a.Name) < podKey(b.Namespace, b.Name)
+	})
+
+	t.pods = pods
+	t.total = len(msg.pods)
+	t.updated = msg.at
+	t.err = nil
+
+	t.cursor = 0
+	if i := t.indexOfKey(selected); i >= 0 {
+		t.cursor = i
+	}
+	return m
+}
+
+// rate returns a pod as shown on the dashboard, or false if it is healthy
+// and has not restarted since it was first seen
+func (t *topDashboard) rate(pod *corev1.Pod, now time.Time) (topPod, bool) {
+	item := topPod{PodItem: newPodItem(pod), status: analyzer.DetectPodStatus(pod)}
+	key := podKey(pod.Namespace, pod.Name)
+
+	first, ok := t.seen[key]
+	if !ok {
+		first = restartSample{restarts: item.Restarts, at: now}
+		t.seen[key] = first
+	}
+
+	// Rate restarts over the observed window once it is long enough, so
+	// a pod that restarted a lot last week does not top the list today
+	switch window := now.Sub(first.at); {
+	case window >= minRateWindow:
+		item.restartRate = float64(item.Restarts-first.restarts) / window.Hours()
+	case !pod.CreationTimestamp.IsZero():
+		if age := now.Sub(pod.CreationTimestamp.Time); age > 0 {
+			item.restartRate = float64(item.Restarts) / age.Hours()
+		}
+	}
+
+	switch item.status {
+	case domain.StatusCrashLoop, domain.StatusImagePull, domain.StatusOOMKilled, domain.StatusError,
+		domain.StatusEvicted, domain.StatusCreateError, domain.StatusConfigError:
+		item.severity = domain.SeverityCritical
+	case domain.StatusPending, domain.StatusNotReady, domain.StatusInitializing, domain.StatusUnknown:
+		item.severity = domain.SeverityWarning
+	default:
+		// Healthy or terminating: only listed while it keeps restarting
+		if item.Restarts <= first.restarts {
+			return topPod{}, false
+		}
+		item.severity = domain.SeverityWarning
+	}
+	return item, true
+}
+
+// indexOf returns the dashboard row of a pod, or 0 if it is not listed
+func (t topDashboard) indexOf(namespace, name string) int {
+	if i := t.indexOfKey(podKey(namespace, name)); i >= 0 {
+		return i
+	}
+	return 0
+}
+
+// indexOfKey returns the dashboard row of a namespace/name key, or -1
+func (t topDashboard) indexOfKey(key string) int {
+	for i, p := range t.pods {
+		if podKey(p.Namespace, p.Name) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// severityRank orders severities worst first
+func severityRank(s domain.Severity) int {
+	switch s {
+	case domain.SeverityCritical:
+		return 0
+	case domain.SeverityWarning:
+		return 1
+	}
+	return 2
+}
+
+func (m Model) renderTop() string {
+	var b strings.Builder
+	t := m.top
+
+	b.WriteString(m.renderTitle("🔍 pod-doctor - Top"))
+	b.WriteString("\n")
+	if t.updated.IsZero() {
+		b.WriteString(subtitleStyle.Render("Unhealthy pods in all namespaces"))
+	} else {
+		b.WriteString(subtitleStyle.Render(fmt.Sprintf("Unhealthy pods in all namespaces: %d of %d", len(t.pods), t.total)))
+	}
+	b.WriteString("\n")
+
+	status := fmt.Sprintf("Refreshing every %s", t.interval)
+	if !t.updated.IsZero() {
+		status += fmt.Sprintf(" | Updated %s ago", formatAge(time.Since(t.updated)))
+	}
+	b.WriteString(mutedStyle.Render(status))
+	b.WriteString("\n")
+	if t.err != nil {
+		b.WriteString(criticalStyle.Render(fmt.Sprintf("Refresh failed: %v", t.err)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	switch {
+	case t.updated.IsZero() && t.err == nil:
+		b.WriteString(fmt.Sprintf("  %s Loading pods...\n", m.spinner.View()))
+	case t.updated.IsZero():
+		// The first refresh failed; the error is shown above
+	case len(t.pods) == 0:
+		b.WriteString(healthyStyle.Render("  " + StatusIcon(true) + " All pods healthy"))
+		b.WriteString("\n")
+	default:
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("    %-20s %-38s %-26s %-8s %-10s %-10s %-8s",
+			"NAMESPACE", "NAME", "STATUS", "READY", "RESTARTS", "RATE/H", "AGE")))
+		b.WriteString("\n")
+
+		visibleHeight := m.height - 12
+		if visibleHeight < 5 {
+			visibleHeight = 5
+		}
+		start, end := visibleRange(t.cursor, len(t.pods), visibleHeight)
+
+		for i := start; i < end; i++ {
+			b.WriteString(m.renderTopLine(t.pods[i], i == t.cursor))
+			b.WriteString("\n")
+		}
+
+		if len(t.pods) > visibleHeight {
+			b.WriteString(fmt.Sprintf("\n%s", mutedStyle.Render(fmt.Sprintf("  %d/%d pods", t.cursor+1, len(t.pods)))))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: diagnose • l: logs • r: refresh now • K: context • ?: help • q: quit"))
+
+	return b.String()
+}
+
+func (m Model) renderTopLine(pod topPod, selected bool) string {
+	rate := "-"
+	if pod.restartRate > 0 {
+		rate = fmt.Sprintf("%.1f", pod.restartRate)
+	}
+	line := fmt.Sprintf("%-20s %-38s %-26s %-8s %-10d %-10s %-8s",
+		truncate(pod.Namespace, 20), truncate(pod.Name, 38), truncate(string(pod.status), 26),
+		pod.Ready, pod.Restarts, rate, pod.Age)
+
+	icon := SeverityIcon(string(pod.severity))
+	if selected {
+		return cursorStyle.Render("▸") + " " + icon + " " + selectedItemStyle.Render(line)
+	}
+	return "  " + icon + " " + listItemStyle.Render(line)
+}
@@ -1,20 +1,49 @@
 package tui
 
 import (
-	"fmt"
+	"context"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
 	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
 )
 
-// Run starts the TUI with the given kubeconfig path
-func Run(kubeconfigPath string) error {
-	client, err := kubernetes.NewClient(kubeconfigPath)
-	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+// Connector connects to the cluster of a kubeconfig context, returning a
+// client and analyzer set up like the ones the TUI started with
+type Connector func(contextName string) (*kubernetes.Client, *analyzer.PodAnalyzer, error)
+
+// Run starts the TUI using the given Kubernetes client and analyzer. With
+// allNamespaces set it opens on the pods of every namespace instead of the
+// namespace list. connect switches clusters from the context picker; nil
+// turns the picker off.
+func Run(client *kubernetes.Client, podAnalyzer *analyzer.PodAnalyzer, allNamespaces bool, connect Connector) error {
+	model := NewModel(client, podAnalyzer)
+	model.connect = connect
+	model.allNamespaces = allNamespaces
+	model.onboarding = needsOnboarding()
+	return run(model)
+}
+
+// RunTop starts the TUI on the dashboard of unhealthy pods in every
+// namespace, refreshed at the given interval
+func RunTop(client *kubernetes.Client, podAnalyzer *analyzer.PodAnalyzer, interval time.Duration, connect Connector) error {
+	if interval <= 0 {
+		interval = DefaultTopInterval
 	}
+	model := NewModel(client, podAnalyzer)
+	model.view = ViewTop
+	model.top = topDashboard{active: true, interval: interval}
+	model.connect = connect
+	return run(model)
+}
 
-	model := NewModel(client)
+// run runs the TUI program until the user quits, then cancels the API calls
+// its commands still have in flight
+func run(model Model) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	model.ctx = ctx
 
 	p := tea.NewProgram(
 		model,
@@ -22,6 +51,6 @@ func Run(kubeconfigPath string) error {
 		tea.WithMouseCellMotion(),
 	)
 
-	_, err = p.Run()
+	_, err := p.Run()
 	return err
 }
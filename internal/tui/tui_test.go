@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/pavanInnamuri/pod-doctor/internal/analyzer"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testLogLine is what the fake cluster returns as the pod's logs
+const testLogLine = "listening on :8080"
+
+// fakeCluster serves a cluster with one namespace holding one running pod.
+// Other objects are not found, and lists of events are empty.
+func fakeCluster(t *testing.T) *httptest.Server {
+	t.Helper()
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	pod := corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", CreationTimestamp: created},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-a",
+			Containers: []corev1.Container{{Name: "app", Image: "nginx:1.27"}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				Image: "nginx:1.27",
+				Ready: true,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: created}},
+			}},
+		},
+	}
+	routes := map[string]interface{}{
+		"/api/v1/namespaces": corev1.NamespaceList{
+			TypeMeta: metav1.TypeMeta{Kind: "NamespaceList", APIVersion: "v1"},
+			Items:    []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}},
+		},
+		"/api/v1/namespaces/default/pods": corev1.PodList{
+			TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+			Items:    []corev1.Pod{pod},
+		},
+		"/api/v1/namespaces/default/pods/web": pod,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/namespaces/default/pods/web/log":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("starting\n" + testLogLine + "\n"))
+			return
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			writeJSON(w, http.StatusOK, corev1.EventList{TypeMeta: metav1.TypeMeta{Kind: "EventList", APIVersion: "v1"}})
+			return
+		}
+		if obj, ok := routes[r.URL.Path]; ok {
+			writeJSON(w, http.StatusOK, obj)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		})
+	}))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// newTestModel returns a model connected to the fake cluster
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	server := fakeCluster(t)
+	t.Cleanup(server.Close)
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	config := `apiVersion: v1
+kind: Config
+clusters:
+- name: fake
+  cluster:
+    server: ` + server.URL + `
+contexts:
+- name: fake
+  context:
+    cluster: fake
+    user: fake
+current-context: fake
+users:
+- name: fake
+  user: {}
+`
+	if err := os.WriteFile(kubeconfig, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client, err := kubernetes.NewClient(kubeconfig, "", kubernetes.RateLimit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewModel(client, analyzer.NewPodAnalyzer(client))
+}
+
+// waitForText waits until the program has rendered every text. Each wait
+// consumes the output it read, so texts of one screen are waited for
+// together.
+func waitForText(t *testing.T, tm *teatest.TestModel, texts ...string) {
+	t.Helper()
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		for _, text := range texts {
+			if !bytes.Contains(out, []byte(text)) {
+				return false
+			}
+		}
+		return true
+	}, teatest.WithDuration(10*time.Second), teatest.WithCheckInterval(20*time.Millisecond))
+}
+
+// TestViewRouting walks from the namespace list to a pod, its diagnosis and
+// its logs, then back out to the pod list
+func TestViewRouting(t *testing.T) {
+	tm := teatest.NewTestModel(t, newTestModel(t), teatest.WithInitialTermSize(120, 40))
+
+	waitForText(t, tm, "Select a namespace", "default")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Namespace:", "web")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "pod-doctor - Diagnosis")
+
+	tm.Type("l")
+	waitForText(t, tm, testLogLine)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "pod-doctor - Diagnosis")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Namespace: ")
+
+	tm.Type("q")
+	final, ok := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(Model)
+	if !ok {
+		t.Fatalf("final model is %T, want Model", final)
+	}
+	if final.view != ViewPodList {
+		t.Errorf("view = %d, want the pod list (%d)", final.view, ViewPodList)
+	}
+	if final.selectedNS != "default" {
+		t.Errorf("selected namespace = %q, want default", final.selectedNS)
+	}
+	if final.logs.pod != "web" {
+		t.Errorf("logs were opened for %q, want web", final.logs.pod)
+	}
+}
+
+// TestBackFromLogsReturnsToOpeningView checks that closing the logs returns
+// to the view they were opened from, here the pod list
+func TestBackFromLogsReturnsToOpeningView(t *testing.T) {
+	tm := teatest.NewTestModel(t, newTestModel(t), teatest.WithInitialTermSize(120, 40))
+
+	waitForText(t, tm, "Select a namespace", "default")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Namespace:", "web")
+
+	tm.Type("l")
+	waitForText(t, tm, testLogLine)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Type("q")
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(Model)
+	if final.view != ViewPodList {
+		t.Errorf("view = %d, want the pod list (%d)", final.view, ViewPodList)
+	}
+}
@@ -0,0 +1,340 @@
+// pod-doctor scan report: renders /api/report as sortable, filterable
+// tables of pods and issues, with a page per pod. Routes are kept in the
+// URL fragment (#pods, #issues, #pod/<namespace>/<name>) so the back button
+// and links work.
+"use strict";
+
+const severityRank = { critical: 0, warning: 1, info: 2 };
+
+let report = null;
+const state = {
+  pods: { filter: "", unhealthyOnly: true, namespace: "", sort: "score", desc: false },
+  issues: { filter: "", severity: "", category: "", namespace: "", sort: "severity", desc: false },
+};
+
+// el creates an element with attributes and children; strings become text
+// nodes, so nothing from the report is parsed as HTML
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key === "class") node.className = value;
+    else if (key.startsWith("on")) node.addEventListener(key.slice(2), value);
+    else node.setAttribute(key, value);
+  }
+  for (const child of children.flat()) {
+    if (child === null || child === undefined || child === false) continue;
+    node.append(child instanceof Node ? child : String(child));
+  }
+  return node;
+}
+
+function scoreClass(score) {
+  if (score >= 80) return "healthy";
+  if (score >= 50) return "warning";
+  return "critical";
+}
+
+// formatAge formats a duration in nanoseconds like the CLI, e.g. 3h or 2d
+function formatAge(ns) {
+  const s = Math.floor(ns / 1e9);
+  if (s < 60) return s + "s";
+  if (s < 3600) return Math.floor(s / 60) + "m";
+  if (s < 86400) return Math.floor(s / 3600) + "h";
+  return Math.floor(s / 86400) + "d";
+}
+
+function podKey(d) {
+  return d.pod.namespace + "/" + d.pod.name;
+}
+
+function podLink(d) {
+  return "#pod/" + encodeURIComponent(d.pod.namespace) + "/" + encodeURIComponent(d.pod.name);
+}
+
+function isHealthy(d) {
+  return d.issues.length === 0 && d.status === "Healthy";
+}
+
+function countSeverity(d, severity) {
+  return d.issues.filter((i) => i.severity === severity).length;
+}
+
+function namespaces() {
+  return [...new Set(report.diagnoses.map((d) => d.pod.namespace))].sort();
+}
+
+// allIssues flattens the issues of every pod, each with its diagnosis
+function allIssues() {
+  const issues = [];
+  for (const d of report.diagnoses) {
+    for (const issue of d.issues) issues.push({ issue, d });
+  }
+  return issues;
+}
+
+function matches(text, filter) {
+  return filter === "" || text.toLowerCase().includes(filter.toLowerCase());
+}
+
+function compare(a, b) {
+  if (typeof a === "number" && typeof b === "number") return a - b;
+  return String(a).localeCompare(String(b));
+}
+
+// sortableTable renders rows under headers that sort them when clicked.
+// columns are {label, key, value(row), render(row)}.
+function sortableTable(columns, rows, sortState, onClick) {
+  const column = columns.find((c) => c.key === sortState.sort) || columns[0];
+  const sorted = [...rows].sort((a, b) => {
+    const order = compare(column.value(a), column.value(b));
+    return sortState.desc ? -order : order;
+  });
+
+  const header = el("tr", {}, columns.map((c) => {
+    let cls = "sortable";
+    if (c.key === column.key) cls += sortState.desc ? " sorted desc" : " sorted";
+    return el("th", {
+      class: cls,
+      onclick: () => {
+        if (sortState.sort === c.key) sortState.desc = !sortState.desc;
+        else { sortState.sort = c.key; sortState.desc = false; }
+        route();
+      },
+    }, c.label);
+  }));
+  const body = sorted.map((row) => el("tr", { class: "row", onclick: () => onClick(row) },
+    columns.map((c) => el("td", {}, c.render ? c.render(row) : String(c.value(row))))));
+  return el("table", {}, header, body);
+}
+
+function searchInput(value, placeholder, onChange) {
+  const input = el("input", { type: "search", placeholder, value });
+  input.addEventListener("input", () => onChange(input.value));
+  return input;
+}
+
+function select(value, label, options, onChange) {
+  const node = el("select", { "aria-label": label },
+    el("option", { value: "" }, "All " + label),
+    options.map((o) => el("option", { value: o }, o)));
+  node.value = value;
+  node.addEventListener("change", () => onChange(node.value));
+  return node;
+}
+
+// renderFiltered keeps the filter controls in place while the table under
+// them is rendered again, so typing does not lose focus
+function renderFiltered(controls, renderTable) {
+  const results = el("div");
+  const refresh = () => results.replaceChildren(renderTable());
+  const main = document.getElementById("main");
+  main.replaceChildren(el("div", { class: "filters" }, controls(refresh)), results);
+  refresh();
+}
+
+function renderPods() {
+  const s = state.pods;
+  const columns = [
+    { label: "Namespace", key: "namespace", value: (d) => d.pod.namespace },
+    { label: "Pod", key: "name", value: (d) => d.pod.name, render: (d) => el("a", { href: podLink(d) }, d.pod.name) },
+    { label: "Status", key: "status", value: (d) => d.status },
+    { label: "Score", key: "score", value: (d) => d.healthScore, render: (d) => el("span", { class: scoreClass(d.healthScore) }, d.healthScore + "/100") },
+    { label: "Restarts", key: "restarts", value: (d) => d.pod.restarts },
+    { label: "Critical", key: "critical", value: (d) => countSeverity(d, "critical") },
+    { label: "Warnings", key: "warnings", value: (d) => countSeverity(d, "warning") },
+    { label: "Node", key: "node", value: (d) => d.pod.node || "" },
+    { label: "Age", key: "age", value: (d) => d.pod.age, render: (d) => formatAge(d.pod.age) },
+    { label: "Top issue", key: "issue", value: (d) => (d.issues[0] ? d.issues[0].title : "") },
+  ];
+
+  renderFiltered((refresh) => {
+    const checkbox = el("input", { type: "checkbox" });
+    checkbox.checked = s.unhealthyOnly;
+    checkbox.addEventListener("change", () => { s.unhealthyOnly = checkbox.checked; refresh(); });
+    return [
+      searchInput(s.filter, "Filter pods, statuses, nodes or issues...", (v) => { s.filter = v; refresh(); }),
+      select(s.namespace, "namespaces", namespaces(), (v) => { s.namespace = v; refresh(); }),
+      el("label", {}, checkbox, " Unhealthy only"),
+    ];
+  }, () => {
+    const rows = report.diagnoses.filter((d) =>
+      (!s.unhealthyOnly || !isHealthy(d)) &&
+      (s.namespace === "" || d.pod.namespace === s.namespace) &&
+      matches([podKey(d), d.status, d.pod.node || "", ...d.issues.map((i) => i.title)].join(" "), s.filter));
+    if (rows.length === 0) return el("p", { class: "muted" }, "No pods match the filters");
+    return el("div", {},
+      el("p", { class: "muted" }, `${rows.length} of ${report.diagnoses.length} pods`),
+      sortableTable(columns, rows, s, (d) => { location.hash = podLink(d); }));
+  });
+}
+
+function renderIssues() {
+  const s = state.issues;
+  const issues = allIssues();
+  const categories = [...new Set(issues.map((r) => r.issue.category))].sort();
+  const columns = [
+    { label: "Severity", key: "severity", value: (r) => severityRank[r.issue.severity] ?? 3, render: (r) => el("span", { class: r.issue.severity }, r.issue.severity) },
+    { label: "Pod", key: "pod", value: (r) => podKey(r.d), render: (r) => el("a", { href: podLink(r.d) }, podKey(r.d)) },
+    { label: "Category", key: "category", value: (r) => r.issue.category },
+    { label: "ID", key: "id", value: (r) => r.issue.id || "" },
+    { label: "Issue", key: "title", value: (r) => r.issue.title },
+  ];
+
+  renderFiltered((refresh) => [
+    searchInput(s.filter, "Filter issues or pods...", (v) => { s.filter = v; refresh(); }),
+    select(s.severity, "severities", Object.keys(severityRank), (v) => { s.severity = v; refresh(); }),
+    select(s.category, "categories", categories, (v) => { s.category = v; refresh(); }),
+    select(s.namespace, "namespaces", namespaces(), (v) => { s.namespace = v; refresh(); }),
+  ], () => {
+    const rows = issues.filter((r) =>
+      (s.severity === "" || r.issue.severity === s.severity) &&
+      (s.category === "" || r.issue.category === s.category) &&
+      (s.namespace === "" || r.d.pod.namespace === s.namespace) &&
+      matches([podKey(r.d), r.issue.id || "", r.issue.title, r.issue.description].join(" "), s.filter));
+    if (rows.length === 0) return el("p", { class: "muted" }, "No issues match the filters");
+    return el("div", {},
+      el("p", { class: "muted" }, `${rows.length} of ${issues.length} issues`),
+      sortableTable(columns, rows, s, (r) => { location.hash = podLink(r.d); }));
+  });
+}
+
+function detailsList(details) {
+  return Object.keys(details || {}).sort().map((k) => [k + ": " + details[k], el("br")]);
+}
+
+function renderPod(namespace, name) {
+  const d = report.diagnoses.find((x) => x.pod.namespace === namespace && x.pod.name === name);
+  const main = document.getElementById("main");
+  if (!d) {
+    main.replaceChildren(el("p", {}, `Pod ${namespace}/${name} is not in this scan. `, el("a", { href: "#pods" }, "Back to pods")));
+    return;
+  }
+
+  const meta = [
+    "Node: " + (d.pod.node || "N/A"),
+    "Phase: " + d.pod.phase,
+    "Age: " + formatAge(d.pod.age),
+    "Restarts: " + d.pod.restarts,
+  ];
+  if (d.workload) meta.push("Workload: " + d.workload.kind.toLowerCase() + "/" + d.workload.name);
+
+  const sections = [
+    el("p", {}, el("a", { href: "#pods" }, "← All pods")),
+    el("h2", {}, podKey(d), " ", el("span", { class: scoreClass(d.healthScore) }, `${d.status}, score ${d.healthScore}/100`)),
+    el("p", { class: "muted" }, meta.join(" | ")),
+  ];
+  if (d.expectedFailure) sections.push(el("p", { class: "info" }, `Expected failure (${d.expectedFailure})`));
+  for (const w of d.warnings || []) sections.push(el("p", { class: "muted" }, "Incomplete: " + w.message));
+
+  if (d.notReady && d.notReady.length) {
+    sections.push(el("h3", {}, "Why not ready"), el("ul", {}, d.notReady.map((b) =>
+      el("li", {}, el("strong", {}, (b.kind === "readinessGate" ? "readiness gate " : "container ") + b.name), ": " + b.reason,
+        b.message ? [el("br"), el("span", { class: "muted" }, b.message)] : null))));
+  }
+
+  sections.push(el("h3", {}, "Issues"));
+  if (d.issues.length === 0) {
+    sections.push(el("p", { class: "healthy" }, "No issues detected"));
+  } else {
+    sections.push(el("table", {},
+      el("tr", {}, el("th", {}, "Severity"), el("th", {}, "Issue"), el("th", {}, "Details")),
+      d.issues.map((i) => el("tr", {},
+        el("td", { class: i.severity }, i.severity),
+        el("td", {}, el("strong", {}, i.title), i.id ? [" ", el("span", { class: "muted" }, i.id)] : null, el("br"), i.description),
+        el("td", { class: "details-kv" }, detailsList(i.details))))));
+  }
+
+  if (d.recommendations && d.recommendations.length) {
+    sections.push(el("h3", {}, "Recommendations"), el("ol", {}, d.recommendations.map((r) =>
+      el("li", {}, el("strong", {}, r.title), " — " + r.description, r.command ? [el("br"), el("code", {}, r.command)] : null))));
+  }
+
+  if (d.pod.containers && d.pod.containers.length) {
+    sections.push(el("h3", {}, "Containers"), el("table", {},
+      el("tr", {}, ["Name", "Image", "State", "Ready", "Restarts", "Requests / limits"].map((h) => el("th", {}, h))),
+      d.pod.containers.map((c) => {
+        const r = c.resources || {};
+        return el("tr", {},
+          el("td", {}, c.name),
+          el("td", {}, el("code", {}, c.image)),
+          el("td", {}, c.state + (c.reason ? ` (${c.reason})` : "")),
+          el("td", { class: c.ready ? "healthy" : "critical" }, c.ready ? "yes" : "no"),
+          el("td", {}, c.restartCount),
+          el("td", { class: "details-kv" }, c.resources
+            ? `cpu ${r.cpuRequests || "-"}/${r.cpuLimits || "-"}, memory ${r.memoryRequests || "-"}/${r.memoryLimits || "-"}`
+            : "none"));
+      })));
+  }
+
+  if (d.events && d.events.length) {
+    sections.push(el("h3", {}, "Events"), el("table", {},
+      el("tr", {}, ["Type", "Reason", "Message", "Count"].map((h) => el("th", {}, h))),
+      d.events.map((e) => el("tr", {},
+        el("td", { class: e.type === "Warning" ? "warning" : "" }, e.type),
+        el("td", {}, e.reason), el("td", {}, e.message), el("td", {}, e.count)))));
+  }
+
+  if (d.logs && d.logs.errorLines && d.logs.errorLines.length) {
+    sections.push(el("h3", {}, "Log errors"), el("pre", {}, d.logs.errorLines.join("\n")));
+  }
+
+  main.replaceChildren(...sections);
+  window.scrollTo(0, 0);
+}
+
+function renderSummary() {
+  const total = report.diagnoses.length;
+  const healthy = report.diagnoses.filter(isHealthy).length;
+  const expected = report.diagnoses.filter((d) => !isHealthy(d) && d.expectedFailure).length;
+  const issues = allIssues();
+  const critical = issues.filter((r) => r.issue.severity === "critical").length;
+  const average = total ? Math.floor(report.diagnoses.reduce((sum, d) => sum + d.healthScore, 0) / total) : 100;
+
+  const card = (label, value, cls) => el("div", { class: "card" }, el("div", { class: "muted" }, label), el("div", { class: "value " + (cls || "") }, value));
+  const cards = [
+    card("Pods scanned", total),
+    card("Healthy", healthy, "healthy"),
+    card("Unhealthy", total - healthy - expected, "critical"),
+    card("Issues", issues.length),
+    card("Critical issues", critical, "critical"),
+    card("Average health score", average + "/100", scoreClass(average)),
+  ];
+  if (expected) cards.splice(3, 0, card("Expected failures", expected, "info"));
+  document.getElementById("summary").replaceChildren(...cards);
+}
+
+// route renders the view the URL fragment names
+function route() {
+  const hash = decodeURIComponent(location.hash.slice(1));
+  const view = hash.startsWith("pod/") ? "pod" : hash === "issues" ? "issues" : "pods";
+  document.getElementById("tab-pods").classList.toggle("active", view === "pods");
+  document.getElementById("tab-issues").classList.toggle("active", view === "issues");
+
+  if (view === "pod") {
+    const [, namespace, ...name] = location.hash.slice(1).split("/");
+    renderPod(decodeURIComponent(namespace), decodeURIComponent(name.join("/")));
+  } else if (view === "issues") {
+    renderIssues();
+  } else {
+    renderPods();
+  }
+}
+
+fetch("api/report")
+  .then((response) => {
+    if (!response.ok) throw new Error(response.statusText);
+    return response.json();
+  })
+  .then((data) => {
+    report = data;
+    report.diagnoses = report.diagnoses || [];
+    document.title = report.title;
+    document.getElementById("title").textContent = report.title;
+    document.getElementById("generated").textContent = "Generated " + new Date(report.generatedAt).toLocaleString() + " by pod-doctor";
+    renderSummary();
+    window.addEventListener("hashchange", route);
+    route();
+  })
+  .catch((err) => {
+    document.getElementById("main").replaceChildren(el("p", { class: "critical" }, "Failed to load the scan results: " + err.message));
+  });
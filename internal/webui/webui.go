@@ -0,0 +1,112 @@
+// Package webui serves scan results as an interactive local web page, with
+// sortable and filterable tables of pods and issues and a page per pod. The
+// page and its scripts are embedded in the binary and load the results from
+// /api/report, so nothing is fetched from the internet.
+package webui
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pavanInnamuri/pod-doctor/internal/domain"
+	"github.com/pavanInnamuri/pod-doctor/internal/kubernetes"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// Report is the scan the web page shows
+type Report struct {
+	APIVersion   string                        `json:"apiVersion"`
+	Title        string                        `json:"title"`
+	GeneratedAt  time.Time                     `json:"generatedAt"`
+	Diagnoses    []*domain.Diagnosis           `json:"diagnoses"`
+	Dependencies []domain.DependencyFailure    `json:"dependencies,omitempty"`
+	Skipped      []kubernetes.SkippedNamespace `json:"skippedNamespaces,omitempty"`
+}
+
+// Handler serves the web page and the report it shows
+func Handler(report *Report) (http.Handler, error) {
+	static, err := fs.Sub(assets, "assets")
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/api/report", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(data)
+	})
+	return mux, nil
+}
+
+// Serve serves the report on addr until ctx is done. ready is called with
+// the page's URL once the address is listening, so a port of 0 can be used.
+// An address without a host, such as :8080, listens on localhost only; the
+// report names pods, images and log lines, so exposing it to other
+// machines takes an explicit host such as 0.0.0.0:8080.
+func Serve(ctx context.Context, addr string, report *Report, ready func(url string)) error {
+	handler, err := Handler(report)
+	if err != nil {
+		return err
+	}
+	addr = localAddr(addr)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	ready(pageURL(listener.Addr()))
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("failed to serve report: %w", err)
+	}
+}
+
+// localAddr binds an address without a host to localhost
+func localAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host != "" {
+		return addr
+	}
+	return net.JoinHostPort("localhost", port)
+}
+
+// pageURL returns the URL of the page on a listening address, on localhost
+// when it listens on every interface
+func pageURL(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "http://" + addr.String() + "/"
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/"
+}